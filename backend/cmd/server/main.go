@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
 	"net/http"
@@ -39,7 +40,9 @@ type Config struct {
 var (
 	linterRegistry *analyzer.LinterRegistry
 	analysisService *service.AnalysisService
+	organizationService *service.OrganizationService
 	userRepository repository.UserRepository
+	organizationRepository repository.OrganizationRepository
 )
 
 func main() {
@@ -60,11 +63,15 @@ func main() {
 	// Initialize repositories
 	analysisRepo := repository.NewPostgresAnalysisRepository(dbConn)
 	userRepository = repository.NewPostgresUserRepository(dbConn)
+	organizationRepository = repository.NewPostgresOrganizationRepository(dbConn)
 
 	// Initialize linter registry
 	linterRegistry = analyzer.NewLinterRegistry()
 	linterRegistry.RegisterDefaultLinters()
 
+	// Organization settings are looked up for every analysis, so changes need no restart
+	organizationService = service.NewOrganizationService(organizationRepository, linterRegistry)
+
 	// Initialize AI service if enabled
 	var aiService ai.AISuggestionService
 	if config.AiEnabled {
@@ -77,6 +84,7 @@ func main() {
 		analysisRepo,
 		aiService,
 		config.AiEnabled,
+		organizationService,
 	)
 
 	// Set up the server
@@ -316,6 +324,9 @@ func setupRouter(config Config) *gin.Engine {
 		// Code analysis endpoint
 		v1.POST("/analyze", handleAnalyzeCode)
 		
+		// Code formatting endpoint
+		v1.POST("/format", handleFormatCode)
+		
 		// Get analysis results by ID
 		v1.GET("/analysis/:id", handleGetAnalysisById)
 		
@@ -325,6 +336,10 @@ func setupRouter(config Config) *gin.Engine {
 		// Get supported languages
 		v1.GET("/languages", handleGetSupportedLanguages)
 		
+		// Organization settings, such as the quality score weights
+		v1.GET("/organizations/:id/settings", handleGetOrganizationSettings)
+		v1.PUT("/organizations/:id/settings/:key", handleUpdateOrganizationSetting)
+		
 		// Webhook notifications
 		v1.POST("/webhook/notify", handleWebhookNotify)
 	}
@@ -344,10 +359,13 @@ func handleAnalyzeCode(c *gin.Context) {
 		return
 	}
 	
-	// Add user ID if authenticated via API key
+	// Add user ID if authenticated via API key, and the organization whose settings apply
 	requestApiKey := c.GetHeader("X-API-Key")
 	if user, err := userRepository.GetUserByAPIKey(c.Request.Context(), requestApiKey); err == nil && user != nil {
 		request.UserID = user.ID
+		if organizationID, err := organizationRepository.GetOrganizationIDForUser(c.Request.Context(), user.ID); err == nil {
+			request.OrganizationID = organizationID
+		}
 	}
 	
 	// Analyze the code
@@ -363,6 +381,58 @@ func handleAnalyzeCode(c *gin.Context) {
 	c.JSON(http.StatusOK, result)
 }
 
+// Handler for code formatting endpoint
+func handleFormatCode(c *gin.Context) {
+	var request service.FormatRequest
+	
+	// JSON escaping can double the size of the code, options add little
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, 2*service.MaxFormatCodeSize+64*1024)
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"status":  "error",
+			"message": "Invalid request: " + err.Error(),
+		})
+		return
+	}
+	
+	// Format the code
+	result, err := analysisService.FormatCode(c.Request.Context(), request)
+	if err != nil {
+		if errors.Is(err, service.ErrFormattingNotSupported) {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"status":  "error",
+				"message": err.Error(),
+			})
+			return
+		}
+		if errors.Is(err, service.ErrCodeTooLarge) {
+			c.JSON(http.StatusRequestEntityTooLarge, gin.H{
+				"status":  "error",
+				"message": err.Error(),
+			})
+			return
+		}
+		
+		// A missing formatter or one that ran out of time is the server's fault
+		if errors.Is(err, analyzer.ErrFormatterUnavailable) {
+			c.JSON(http.StatusServiceUnavailable, gin.H{
+				"status":  "error",
+				"message": "Formatting failed: " + err.Error(),
+			})
+			return
+		}
+		
+		// Formatters reject code they cannot parse
+		c.JSON(http.StatusUnprocessableEntity, gin.H{
+			"status":  "error",
+			"message": "Formatting failed: " + err.Error(),
+		})
+		return
+	}
+	
+	c.JSON(http.StatusOK, result)
+}
+
 // Handler for getting analysis by ID
 func handleGetAnalysisById(c *gin.Context) {
 	id := c.Param("id")
@@ -460,6 +530,90 @@ func handleGetSupportedLanguages(c *gin.Context) {
 	})
 }
 
+// Handler for getting the settings of an organization
+func handleGetOrganizationSettings(c *gin.Context) {
+	// Settings belong to organizations, so only their members' API keys can read them
+	user, err := userRepository.GetUserByAPIKey(c.Request.Context(), c.GetHeader("X-API-Key"))
+	if err != nil || user == nil {
+		c.JSON(http.StatusForbidden, gin.H{
+			"status":  "error",
+			"message": "A user API key is required",
+		})
+		return
+	}
+	
+	settings, err := organizationService.GetSettingsForMember(c.Request.Context(), c.Param("id"), user.ID)
+	if err != nil {
+		if errors.Is(err, repository.ErrOrganizationNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{
+				"status":  "error",
+				"message": "Organization not found",
+			})
+			return
+		}
+		
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"status":  "error",
+			"message": "Failed to get organization settings: " + err.Error(),
+		})
+		return
+	}
+	
+	c.JSON(http.StatusOK, gin.H{
+		"status":   "success",
+		"settings": settings,
+	})
+}
+
+// Handler for changing a setting of an organization
+func handleUpdateOrganizationSetting(c *gin.Context) {
+	var request struct {
+		Value string `json:"value"`
+	}
+	
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"status":  "error",
+			"message": "Invalid request: " + err.Error(),
+		})
+		return
+	}
+	
+	// Only the organization's admins can change its settings
+	user, err := userRepository.GetUserByAPIKey(c.Request.Context(), c.GetHeader("X-API-Key"))
+	if err != nil || user == nil {
+		c.JSON(http.StatusForbidden, gin.H{
+			"status":  "error",
+			"message": "A user API key is required",
+		})
+		return
+	}
+	
+	err = organizationService.UpdateSetting(c.Request.Context(), c.Param("id"), user.ID, c.Param("key"), request.Value)
+	if err != nil {
+		status := http.StatusInternalServerError
+		switch {
+		case errors.Is(err, service.ErrUnknownSetting), errors.Is(err, service.ErrInvalidSetting):
+			status = http.StatusBadRequest
+		case errors.Is(err, service.ErrNotOrganizationAdmin):
+			status = http.StatusForbidden
+		case errors.Is(err, repository.ErrOrganizationNotFound):
+			status = http.StatusNotFound
+		}
+		
+		c.JSON(status, gin.H{
+			"status":  "error",
+			"message": err.Error(),
+		})
+		return
+	}
+	
+	c.JSON(http.StatusOK, gin.H{
+		"status":  "success",
+		"message": fmt.Sprintf("Updated setting %s", c.Param("key")),
+	})
+}
+
 // Handler for webhook notifications
 func handleWebhookNotify(c *gin.Context) {
 	var request struct {
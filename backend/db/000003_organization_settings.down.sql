@@ -0,0 +1 @@
+DROP TABLE IF EXISTS organization_settings;
\ No newline at end of file
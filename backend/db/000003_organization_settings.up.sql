@@ -0,0 +1,10 @@
+-- Settings organizations choose for their analyses, such as the quality score weights, one row
+-- per setting, keyed like the entries of the analyzers' configuration
+CREATE TABLE IF NOT EXISTS organization_settings (
+    organization_id VARCHAR(36) NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    key VARCHAR(100) NOT NULL,
+    value TEXT NOT NULL,
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_by VARCHAR(36) REFERENCES users(id) ON DELETE SET NULL,
+    PRIMARY KEY (organization_id, key)
+);
\ No newline at end of file
@@ -1,6 +1,7 @@
 package repository
 
 import (
+	"database/sql"
 	"time"
 )
 
@@ -37,6 +38,16 @@ type Organization struct {
 	Active    bool      `db:"active"`
 }
 
+// OrganizationSetting represents a setting an organization chose for its analyses, keyed like
+// the entries of the analyzers' configuration, e.g. "scoreWeights"
+type OrganizationSetting struct {
+	OrganizationID string         `db:"organization_id"`
+	Key            string         `db:"key"`
+	Value          string         `db:"value"`
+	UpdatedAt      time.Time      `db:"updated_at"`
+	UpdatedBy      sql.NullString `db:"updated_by"`
+}
+
 // TeamMember represents a user's membership in an organization
 type TeamMember struct {
 	UserID         string    `db:"user_id"`
@@ -0,0 +1,159 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+var (
+	// ErrOrganizationNotFound is returned when a user belongs to no organization, or not to the
+	// one asked about
+	ErrOrganizationNotFound = errors.New("organization not found")
+)
+
+// OrganizationRepository defines methods for interacting with organizations
+type OrganizationRepository interface {
+	// GetOrganizationIDForUser retrieves the ID of the organization a user belongs to
+	GetOrganizationIDForUser(ctx context.Context, userID string) (string, error)
+	
+	// GetMemberRole retrieves the role of a user in an organization
+	GetMemberRole(ctx context.Context, organizationID, userID string) (string, error)
+	
+	// GetSettings retrieves the settings of an organization, keyed by setting
+	GetSettings(ctx context.Context, organizationID string) (map[string]string, error)
+	
+	// SetSetting creates or replaces a setting of an organization
+	SetSetting(ctx context.Context, setting *OrganizationSetting) error
+	
+	// DeleteSetting deletes a setting of an organization, going back to the server's default
+	DeleteSetting(ctx context.Context, organizationID, key string) error
+}
+
+// PostgresOrganizationRepository is a PostgreSQL implementation of OrganizationRepository
+type PostgresOrganizationRepository struct {
+	db *sqlx.DB
+}
+
+// NewPostgresOrganizationRepository creates a new PostgresOrganizationRepository
+func NewPostgresOrganizationRepository(db *sqlx.DB) *PostgresOrganizationRepository {
+	return &PostgresOrganizationRepository{
+		db: db,
+	}
+}
+
+// GetOrganizationIDForUser retrieves the ID of the organization a user belongs to. Users in
+// several active organizations get the one they joined first.
+func (r *PostgresOrganizationRepository) GetOrganizationIDForUser(ctx context.Context, userID string) (string, error) {
+	query := `
+		SELECT o.id
+		FROM organizations o
+		JOIN team_members tm ON o.id = tm.organization_id
+		WHERE tm.user_id = $1 AND o.active = true
+		ORDER BY tm.joined_at, o.id
+		LIMIT 1
+	`
+	
+	var organizationID string
+	err := r.db.GetContext(ctx, &organizationID, query, userID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return "", ErrOrganizationNotFound
+		}
+		return "", fmt.Errorf("failed to get organization for user: %w", err)
+	}
+	
+	return organizationID, nil
+}
+
+// GetMemberRole retrieves the role of a user in an active organization
+func (r *PostgresOrganizationRepository) GetMemberRole(ctx context.Context, organizationID, userID string) (string, error) {
+	query := `
+		SELECT tm.role
+		FROM team_members tm
+		JOIN organizations o ON o.id = tm.organization_id
+		WHERE tm.organization_id = $1 AND tm.user_id = $2 AND o.active = true
+	`
+	
+	var role string
+	err := r.db.GetContext(ctx, &role, query, organizationID, userID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return "", ErrOrganizationNotFound
+		}
+		return "", fmt.Errorf("failed to get member role: %w", err)
+	}
+	
+	return role, nil
+}
+
+// GetSettings retrieves the settings of an organization, keyed by setting. Organizations
+// without settings get an empty map.
+func (r *PostgresOrganizationRepository) GetSettings(ctx context.Context, organizationID string) (map[string]string, error) {
+	query := `
+		SELECT organization_id, key, value, updated_at, updated_by
+		FROM organization_settings
+		WHERE organization_id = $1
+	`
+	
+	var rows []*OrganizationSetting
+	err := r.db.SelectContext(ctx, &rows, query, organizationID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get organization settings: %w", err)
+	}
+	
+	settings := make(map[string]string, len(rows))
+	for _, row := range rows {
+		settings[row.Key] = row.Value
+	}
+	
+	return settings, nil
+}
+
+// SetSetting creates or replaces a setting of an organization
+func (r *PostgresOrganizationRepository) SetSetting(ctx context.Context, setting *OrganizationSetting) error {
+	query := `
+		INSERT INTO organization_settings (organization_id, key, value, updated_at, updated_by)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (organization_id, key)
+		DO UPDATE SET value = EXCLUDED.value, updated_at = EXCLUDED.updated_at, updated_by = EXCLUDED.updated_by
+	`
+	
+	if setting.UpdatedAt.IsZero() {
+		setting.UpdatedAt = time.Now()
+	}
+	
+	_, err := r.db.ExecContext(
+		ctx,
+		query,
+		setting.OrganizationID,
+		setting.Key,
+		setting.Value,
+		setting.UpdatedAt,
+		setting.UpdatedBy,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to set organization setting: %w", err)
+	}
+	
+	return nil
+}
+
+// DeleteSetting deletes a setting of an organization
+func (r *PostgresOrganizationRepository) DeleteSetting(ctx context.Context, organizationID, key string) error {
+	query := `
+		DELETE FROM organization_settings
+		WHERE organization_id = $1 AND key = $2
+	`
+	
+	_, err := r.db.ExecContext(ctx, query, organizationID, key)
+	if err != nil {
+		return fmt.Errorf("failed to delete organization setting: %w", err)
+	}
+	
+	return nil
+}
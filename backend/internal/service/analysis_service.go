@@ -3,6 +3,7 @@ package service
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"time"
 
@@ -14,10 +15,16 @@ import (
 
 // AnalysisService handles code analysis operations
 type AnalysisService struct {
-	linterRegistry *analyzer.LinterRegistry
-	analysisRepo   repository.AnalysisRepository
-	aiService      ai.AISuggestionService
-	aiEnabled      bool
+	linterRegistry  *analyzer.LinterRegistry
+	secretScanner   *analyzer.SecretScanner
+	securityScanner *analyzer.SecurityScanner
+	metrics         *analyzer.MetricsCalculator
+	scorer          *analyzer.QualityScorer
+	rules           *analyzer.RuleRegistry
+	organizations   *OrganizationService
+	analysisRepo    repository.AnalysisRepository
+	aiService       ai.AISuggestionService
+	aiEnabled       bool
 }
 
 // AnalysisRequest represents a request to analyze code
@@ -27,42 +34,107 @@ type AnalysisRequest struct {
 	Context  string                 `json:"context"`
 	UserID   string                 `json:"user_id,omitempty"`
 	Options  map[string]interface{} `json:"options"`
+	// OrganizationID selects the settings the organization chose, such as the quality score
+	// weights; it is set by the server, never by clients
+	OrganizationID string `json:"-"`
 }
 
 // AnalysisResponse represents the response from a code analysis
 type AnalysisResponse struct {
-	ID          string             `json:"id"`
-	Status      string             `json:"status"`
-	Language    string             `json:"language"`
-	Context     string             `json:"context"`
-	Timestamp   string             `json:"timestamp"`
-	Issues      []analyzer.Issue   `json:"issues"`
-	Suggestions []analyzer.Issue   `json:"suggestions"`
-	AIEnhanced  bool               `json:"ai_enhanced,omitempty"`
+	ID              string           `json:"id"`
+	Status          string           `json:"status"`
+	Language        string           `json:"language"`
+	Context         string           `json:"context"`
+	Timestamp       string           `json:"timestamp"`
+	Issues          []analyzer.Issue `json:"issues"`
+	Suggestions     []analyzer.Issue `json:"suggestions"`
+	AIEnhanced      bool             `json:"ai_enhanced,omitempty"`
+	SecretsDetected bool             `json:"secrets_detected,omitempty"`
+	// Metrics are the size and complexity metrics of the file and of each of its functions
+	Metrics *analyzer.CodeMetrics `json:"metrics,omitempty"`
+	// Quality is the weighted quality score and grade, with the deductions that explain it
+	Quality *analyzer.QualityScore `json:"quality,omitempty"`
+	// Withheld is set when the code contained secrets and was neither stored nor sent to the AI provider
+	Withheld bool `json:"withheld,omitempty"`
 }
 
-// NewAnalysisService creates a new analysis service
+// FormatRequest represents a request to format code
+type FormatRequest struct {
+	Code     string                 `json:"code" binding:"required"`
+	Language string                 `json:"language" binding:"required"`
+	Options  map[string]interface{} `json:"options"`
+}
+
+// FormatResponse represents the response from formatting code
+type FormatResponse struct {
+	Status    string `json:"status"`
+	Language  string `json:"language"`
+	Formatted string `json:"formatted"`
+	Diff      string `json:"diff"`
+	Changed   bool   `json:"changed"`
+}
+
+// MaxFormatCodeSize is the size in bytes of the largest code FormatCode accepts
+const MaxFormatCodeSize = 1 << 20
+
+// ErrFormattingNotSupported is returned when no formatter is available for a language
+var ErrFormattingNotSupported = errors.New("formatting not supported for this language")
+
+// ErrCodeTooLarge is returned when the code to format is larger than MaxFormatCodeSize
+var ErrCodeTooLarge = errors.New("code too large to format")
+
+// NewAnalysisService creates a new analysis service. organizations provides the settings of
+// the organizations analyses run for, and may be nil when there are none.
 func NewAnalysisService(
 	linterRegistry *analyzer.LinterRegistry,
 	analysisRepo repository.AnalysisRepository,
 	aiService ai.AISuggestionService,
 	aiEnabled bool,
+	organizations *OrganizationService,
 ) *AnalysisService {
+	rules := analyzer.NewRuleRegistry()
+	rules.RegisterDefaultRules()
+
 	return &AnalysisService{
 		linterRegistry: linterRegistry,
-		analysisRepo:   analysisRepo,
-		aiService:      aiService,
-		aiEnabled:      aiEnabled,
+		secretScanner:  analyzer.NewSecretScanner(map[string]string{}),
+		securityScanner: analyzer.NewSecurityScanner(map[string]string{
+			"gosecPath":   "gosec",
+			"banditPath":  "bandit",
+			"semgrepPath": "semgrep",
+			"timeout":     "60s",
+		}),
+		metrics:       analyzer.NewMetricsCalculator(map[string]string{}),
+		scorer:        analyzer.NewQualityScorer(map[string]string{}),
+		rules:         rules,
+		organizations: organizations,
+		analysisRepo:  analysisRepo,
+		aiService:     aiService,
+		aiEnabled:     aiEnabled,
 	}
 }
 
 // AnalyzeCode analyzes code and returns the results
 func (s *AnalysisService) AnalyzeCode(ctx context.Context, req AnalysisRequest) (*AnalysisResponse, error) {
+	// The analyzers apply the settings of the organization, looked up for every analysis so that
+	// changes take effect straight away
+	if req.OrganizationID != "" && s.organizations != nil {
+		settings, err := s.organizations.GetSettings(ctx, req.OrganizationID)
+		if err != nil {
+			// Log error but continue with the server's settings
+			fmt.Printf("Error loading organization settings: %v\n", err)
+		}
+		ctx = analyzer.WithOrganization(ctx, req.OrganizationID, settings)
+	}
+
 	// Check if we have a linter for this language
 	linter, ok := s.linterRegistry.GetLinter(req.Language)
 	if !ok {
-		// Fall back to generic analysis
-		return s.performGenericAnalysis(ctx, req)
+		// Fall back to the language-agnostic checks
+		linter, ok = s.linterRegistry.GetFallback()
+		if !ok {
+			return nil, fmt.Errorf("no linter available for %s", req.Language)
+		}
 	}
 
 	// Analyze the code using the appropriate linter
@@ -71,8 +143,56 @@ func (s *AnalysisService) AnalyzeCode(ctx context.Context, req AnalysisRequest)
 		return nil, fmt.Errorf("analysis failed: %w", err)
 	}
 
+	// The stages below see the Python source of a notebook's code cells, as the linter does,
+	// and their issues are moved back to the cells
+	code := req.Code
+	notebook, isNotebook := analyzer.ParseNotebook(req.Language, req.Code, req.Options)
+	if isNotebook {
+		code = notebook.Code()
+	}
+
+	// Security findings come from dedicated tools rather than the language's linter
+	securityIssues, err := s.securityScanner.Scan(ctx, req.Language, code, req.Options)
+	if err != nil {
+		// Log error but continue without security findings
+		fmt.Printf("Error running security scan: %v\n", err)
+	}
+	if isNotebook {
+		securityIssues = notebook.MapIssues(securityIssues)
+	}
+	result.Issues = append(result.Issues, securityIssues...)
+
+	// Secrets are looked for in every language, whatever linter ran, and in every part of a
+	// notebook, not only its code cells
+	var secretIssues []analyzer.Issue
+	if isNotebook {
+		secretIssues = s.secretScanner.ScanNotebook(notebook, req.Options)
+	} else {
+		secretIssues = s.secretScanner.Scan(req.Code, req.Options)
+	}
+	result.Issues = append(result.Issues, secretIssues...)
+
+	// Metrics are computed for every language, and functions over the thresholds are reported
+	metrics, metricIssues := s.metrics.Measure(req.Language, code, req.Options)
+	if isNotebook {
+		notebook.MapMetrics(metrics)
+		metricIssues = notebook.MapIssues(metricIssues)
+	}
+	result.Metadata = metrics
+	result.Issues = append(result.Issues, metricIssues...)
+
+	// Issues get the category, tags, CWE/OWASP IDs and documentation of their rules
+	s.rules.Annotate(req.Language, result.Issues)
+
+	// The score covers the issues of every stage, but not the AI suggestions
+	quality := s.scorer.Score(ctx, code, result.Issues, metrics, req.Options)
+
+	// Code containing secrets can be kept away from storage and the AI provider
+	withheld := len(secretIssues) > 0 && shouldBlockSecrets(req.Options)
+	useAI := s.aiEnabled && shouldUseAI(req.Options) && !withheld
+
 	// If AI suggestions are enabled, enhance with AI
-	if s.aiEnabled && shouldUseAI(req.Options) {
+	if useAI {
 		aiSuggestions, err := s.aiService.GetSuggestions(ctx, req.Code, req.Language, result.Issues)
 		if err != nil {
 			// Log error but continue without AI suggestions
@@ -87,18 +207,22 @@ func (s *AnalysisService) AnalyzeCode(ctx context.Context, req AnalysisRequest)
 	timestamp := time.Now().Format(time.RFC3339)
 	
 	response := &AnalysisResponse{
-		ID:          analysisID,
-		Status:      "success",
-		Language:    req.Language,
-		Context:     req.Context,
-		Timestamp:   timestamp,
-		Issues:      result.Issues,
-		Suggestions: result.Suggestions,
-		AIEnhanced:  s.aiEnabled && shouldUseAI(req.Options),
+		ID:              analysisID,
+		Status:          "success",
+		Language:        req.Language,
+		Context:         req.Context,
+		Timestamp:       timestamp,
+		Issues:          result.Issues,
+		Suggestions:     result.Suggestions,
+		AIEnhanced:      useAI,
+		SecretsDetected: len(secretIssues) > 0,
+		Withheld:        withheld,
+		Metrics:         metrics,
+		Quality:         quality,
 	}
 
 	// Store the results if we have a repository
-	if s.analysisRepo != nil && req.UserID != "" {
+	if s.analysisRepo != nil && req.UserID != "" && !withheld {
 		if err := s.storeAnalysisResult(ctx, response, req); err != nil {
 			// Log error but continue
 			fmt.Printf("Error storing analysis: %v\n", err)
@@ -108,6 +232,31 @@ func (s *AnalysisService) AnalyzeCode(ctx context.Context, req AnalysisRequest)
 	return response, nil
 }
 
+// FormatCode formats code with the formatter for the requested language and returns the
+// formatted source together with a unified diff against the original
+func (s *AnalysisService) FormatCode(ctx context.Context, req FormatRequest) (*FormatResponse, error) {
+	formatter, ok := s.linterRegistry.GetFormatter(req.Language)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrFormattingNotSupported, req.Language)
+	}
+	if len(req.Code) > MaxFormatCodeSize {
+		return nil, fmt.Errorf("%w: %d bytes, the limit is %d", ErrCodeTooLarge, len(req.Code), MaxFormatCodeSize)
+	}
+
+	result, err := analyzer.FormatWith(ctx, formatter, req.Code, req.Options)
+	if err != nil {
+		return nil, err
+	}
+
+	return &FormatResponse{
+		Status:    "success",
+		Language:  req.Language,
+		Formatted: result.Formatted,
+		Diff:      result.Diff,
+		Changed:   result.Changed,
+	}, nil
+}
+
 // GetAnalysisById retrieves an analysis by ID
 func (s *AnalysisService) GetAnalysisById(ctx context.Context, id string) (*AnalysisResponse, error) {
 	if s.analysisRepo == nil {
@@ -177,45 +326,6 @@ func (s *AnalysisService) storeAnalysisResult(ctx context.Context, response *Ana
 	return s.analysisRepo.StoreAnalysis(ctx, analysis)
 }
 
-// performGenericAnalysis performs a generic analysis when no specific linter is available
-func (s *AnalysisService) performGenericAnalysis(ctx context.Context, req AnalysisRequest) (*AnalysisResponse, error) {
-	// Create a simple generic analysis
-	// In a real implementation, this would do more sophisticated analysis
-	
-	issues := []analyzer.Issue{
-		{
-			Line:     1,
-			Message:  "Generic analysis performed - no specific linter available for " + req.Language,
-			Severity: "info",
-			RuleID:   "generic-analysis",
-		},
-	}
-
-	// Create response
-	analysisID := generateAnalysisID()
-	timestamp := time.Now().Format(time.RFC3339)
-	
-	response := &AnalysisResponse{
-		ID:          analysisID,
-		Status:      "success",
-		Language:    req.Language,
-		Context:     req.Context,
-		Timestamp:   timestamp,
-		Issues:      issues,
-		Suggestions: []analyzer.Issue{},
-	}
-
-	// Store the results if we have a repository
-	if s.analysisRepo != nil && req.UserID != "" {
-		if err := s.storeAnalysisResult(ctx, response, req); err != nil {
-			// Log error but continue
-			fmt.Printf("Error storing analysis: %v\n", err)
-		}
-	}
-
-	return response, nil
-}
-
 // shouldUseAI determines if AI suggestions should be used based on options
 func shouldUseAI(options map[string]interface{}) bool {
 	if options == nil {
@@ -229,6 +339,15 @@ func shouldUseAI(options map[string]interface{}) bool {
 	return true // Default to enabled
 }
 
+// shouldBlockSecrets determines if code containing secrets must not be stored or sent to the AI provider
+func shouldBlockSecrets(options map[string]interface{}) bool {
+	if block, ok := options["block_secrets"].(bool); ok {
+		return block
+	}
+
+	return false
+}
+
 // generateAnalysisID generates a unique ID for an analysis
 func generateAnalysisID() string {
 	return "analysis-" + uuid.New().String()
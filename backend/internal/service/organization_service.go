@@ -0,0 +1,96 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/yourusername/codehawk/backend/internal/repository"
+	"github.com/yourusername/codehawk/backend/pkg/analyzer"
+)
+
+// ErrUnknownSetting is returned for settings organizations cannot change
+var ErrUnknownSetting = errors.New("unknown organization setting")
+
+// ErrInvalidSetting is returned when the value of a setting does not pass its check
+var ErrInvalidSetting = errors.New("invalid organization setting")
+
+// ErrNotOrganizationAdmin is returned when a user who is not an admin of an organization
+// changes its settings
+var ErrNotOrganizationAdmin = errors.New("only organization admins can change its settings")
+
+// OrganizationService manages the settings of organizations
+type OrganizationService struct {
+	organizationRepo repository.OrganizationRepository
+	settings         map[string]func(string) error
+}
+
+// NewOrganizationService creates a new organization service. Organization admins can change
+// the quality score weights and, when the registry has the Python linter, its backends. Settings
+// that make the tools read files, such as rulesets, are left to the server's operators.
+func NewOrganizationService(
+	organizationRepo repository.OrganizationRepository,
+	linterRegistry *analyzer.LinterRegistry,
+) *OrganizationService {
+	// Every setting comes with the check its values must pass
+	settings := map[string]func(string) error{
+		"scoreWeights": analyzer.ValidateScoreWeights,
+	}
+	if linter, ok := linterRegistry.GetLinter("python"); ok {
+		if pythonLinter, ok := linter.(*analyzer.PythonLinter); ok {
+			settings["backends"] = pythonLinter.ValidateBackends
+		}
+	}
+
+	return &OrganizationService{
+		organizationRepo: organizationRepo,
+		settings:         settings,
+	}
+}
+
+// GetSettings retrieves the settings of an organization, keyed by setting
+func (s *OrganizationService) GetSettings(ctx context.Context, organizationID string) (map[string]string, error) {
+	return s.organizationRepo.GetSettings(ctx, organizationID)
+}
+
+// GetSettingsForMember retrieves the settings of an organization for one of its members
+func (s *OrganizationService) GetSettingsForMember(ctx context.Context, organizationID, userID string) (map[string]string, error) {
+	if _, err := s.organizationRepo.GetMemberRole(ctx, organizationID, userID); err != nil {
+		return nil, err
+	}
+
+	return s.organizationRepo.GetSettings(ctx, organizationID)
+}
+
+// UpdateSetting changes a setting of an organization on behalf of one of its admins. An empty
+// value deletes the setting, going back to the server's default.
+func (s *OrganizationService) UpdateSetting(ctx context.Context, organizationID, userID, key, value string) error {
+	validate, ok := s.settings[key]
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrUnknownSetting, key)
+	}
+
+	role, err := s.organizationRepo.GetMemberRole(ctx, organizationID, userID)
+	if err != nil {
+		return err
+	}
+	if role != "admin" {
+		return ErrNotOrganizationAdmin
+	}
+
+	if value == "" {
+		return s.organizationRepo.DeleteSetting(ctx, organizationID, key)
+	}
+
+	if err := validate(value); err != nil {
+		return fmt.Errorf("%w: %s: %v", ErrInvalidSetting, key, err)
+	}
+
+	return s.organizationRepo.SetSetting(ctx, &repository.OrganizationSetting{
+		OrganizationID: organizationID,
+		Key:            key,
+		Value:          value,
+		UpdatedBy:      sql.NullString{String: userID, Valid: true},
+	})
+}
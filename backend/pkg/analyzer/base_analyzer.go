@@ -3,6 +3,10 @@ package analyzer
 import (
 	"context"
 	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
 	"strings"
 	"time"
 )
@@ -23,6 +27,28 @@ func NewBaseAnalyzer(config map[string]string) *BaseAnalyzer {
 	}
 }
 
+// organizationContextKey is the context key of the organization an analysis runs for
+type organizationContextKey struct{}
+
+// organization is the organization an analysis runs for, with the settings it chose
+type organization struct {
+	id       string
+	settings map[string]string
+}
+
+// WithOrganization returns a copy of ctx for the analyses of an organization. Analyzers look up
+// the entries of their configuration that organizations may choose in settings first, then in
+// the configuration entry of OrganizationKey, and only then in the configuration itself.
+func WithOrganization(ctx context.Context, organizationID string, settings map[string]string) context.Context {
+	return context.WithValue(ctx, organizationContextKey{}, organization{id: organizationID, settings: settings})
+}
+
+// OrganizationKey is the configuration entry holding the value of key for the analyses of an
+// organization
+func OrganizationKey(key, organizationID string) string {
+	return key + "." + organizationID
+}
+
 // GetTimeout extracts the timeout from the configuration or returns a default value
 func (b *BaseAnalyzer) GetTimeout() time.Duration {
 	if timeoutStr, ok := b.Config["timeout"]; ok && timeoutStr != "" {
@@ -33,6 +59,156 @@ func (b *BaseAnalyzer) GetTimeout() time.Duration {
 	return 10 * time.Second // Default timeout
 }
 
+// GetStringOption returns a string option from the request options, falling back to the
+// analyzer configuration and finally to the provided default value
+func (b *BaseAnalyzer) GetStringOption(options map[string]interface{}, key, defaultValue string) string {
+	if value, ok := options[key].(string); ok && value != "" {
+		return value
+	}
+	if value, ok := b.Config[key]; ok && value != "" {
+		return value
+	}
+	return defaultValue
+}
+
+// GetBoolOption returns a boolean option from the request options, falling back to the
+// analyzer configuration and finally to the provided default value
+func (b *BaseAnalyzer) GetBoolOption(options map[string]interface{}, key string, defaultValue bool) bool {
+	if value, ok := options[key].(bool); ok {
+		return value
+	}
+	if value, ok := b.Config[key]; ok && value != "" {
+		if parsed, err := strconv.ParseBool(value); err == nil {
+			return parsed
+		}
+	}
+	return defaultValue
+}
+
+// GetIntOption returns an integer option from the request options, falling back to the
+// analyzer configuration and finally to the provided default value
+func (b *BaseAnalyzer) GetIntOption(options map[string]interface{}, key string, defaultValue int) int {
+	switch value := options[key].(type) {
+	case int:
+		return value
+	case float64:
+		// JSON numbers decode as float64
+		return int(value)
+	case string:
+		if parsed, err := strconv.Atoi(value); err == nil {
+			return parsed
+		}
+	}
+	if value, ok := b.Config[key]; ok && value != "" {
+		if parsed, err := strconv.Atoi(value); err == nil {
+			return parsed
+		}
+	}
+	return defaultValue
+}
+
+// GetListOption returns a list option from the request options, which may be given either as
+// a list or as a comma-separated string, falling back to the analyzer configuration and
+// finally to the provided default value
+func (b *BaseAnalyzer) GetListOption(options map[string]interface{}, key string, defaultValue []string) []string {
+	var values []string
+	switch value := options[key].(type) {
+	case []string:
+		values = value
+	case []interface{}:
+		for _, item := range value {
+			if s, ok := item.(string); ok {
+				values = append(values, s)
+			}
+		}
+	case string:
+		values = strings.Split(value, ",")
+	}
+	
+	if len(values) == 0 {
+		if value, ok := b.Config[key]; ok && value != "" {
+			values = strings.Split(value, ",")
+		}
+	}
+	
+	result := make([]string, 0, len(values))
+	for _, value := range values {
+		if trimmed := strings.TrimSpace(value); trimmed != "" {
+			result = append(result, trimmed)
+		}
+	}
+	
+	if len(result) == 0 {
+		return defaultValue
+	}
+	return result
+}
+
+// GetOrganizationSetting returns the value of key chosen for the organization the analysis
+// of ctx runs for, if there is one: the organization's own setting, or else the configuration
+// entry of OrganizationKey
+func (b *BaseAnalyzer) GetOrganizationSetting(ctx context.Context, key string) (string, bool) {
+	org, ok := ctx.Value(organizationContextKey{}).(organization)
+	if !ok || org.id == "" {
+		return "", false
+	}
+	if value, ok := org.settings[key]; ok && value != "" {
+		return value, true
+	}
+	if value, ok := b.Config[OrganizationKey(key, org.id)]; ok && value != "" {
+		return value, true
+	}
+	return "", false
+}
+
+// GetOrganizationOption returns the value of key chosen for the organization the analysis of
+// ctx runs for, falling back to the analyzer configuration and finally to the provided default
+// value. Request options are not consulted.
+func (b *BaseAnalyzer) GetOrganizationOption(ctx context.Context, key, defaultValue string) string {
+	if value, ok := b.GetOrganizationSetting(ctx, key); ok {
+		return value
+	}
+	return b.GetStringOption(nil, key, defaultValue)
+}
+
+// GetFilesOption returns the additional files submitted with the code under the "files" option,
+// keyed by their slash-separated path relative to the project root
+func (b *BaseAnalyzer) GetFilesOption(options map[string]interface{}) map[string]string {
+	files := make(map[string]string)
+	switch value := options["files"].(type) {
+	case map[string]string:
+		for path, content := range value {
+			files[path] = content
+		}
+	case map[string]interface{}:
+		for path, content := range value {
+			if s, ok := content.(string); ok {
+				files[path] = s
+			}
+		}
+	}
+	return files
+}
+
+// WriteFiles writes submitted files below dir, rejecting paths that would escape it
+func (b *BaseAnalyzer) WriteFiles(dir string, files map[string]string) error {
+	for path, content := range files {
+		cleaned := filepath.Clean(filepath.FromSlash(path))
+		if filepath.IsAbs(cleaned) || cleaned == "." || cleaned == ".." || strings.HasPrefix(cleaned, ".."+string(filepath.Separator)) {
+			return fmt.Errorf("invalid file path: %s", path)
+		}
+		
+		target := filepath.Join(dir, cleaned)
+		if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+			return fmt.Errorf("failed to create directory for %s: %w", path, err)
+		}
+		if err := ioutil.WriteFile(target, []byte(content), 0644); err != nil {
+			return fmt.Errorf("failed to write %s: %w", path, err)
+		}
+	}
+	return nil
+}
+
 // WrapError wraps an error with additional context
 func (b *BaseAnalyzer) WrapError(err error, context string) error {
 	if err == nil {
@@ -0,0 +1,712 @@
+package analyzer
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// cDefinePattern matches the NAME or NAME=value of a -D or -U argument
+var cDefinePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*(=.*)?$`)
+
+// cStdPattern matches the value of a -std= argument, e.g. c++17, gnu11 or iso9899:2011
+var cStdPattern = regexp.MustCompile(`^[A-Za-z0-9+:]+$`)
+
+// cLineSplicePattern matches a backslash, also spelled with the ??/ trigraph, continuing a line.
+// Clang also accepts whitespace between the backslash and the line break.
+var cLineSplicePattern = regexp.MustCompile(`(?:\\|\?\?/)[ \t\v\f]*\r?\n`)
+
+// cDirectivePattern matches the # starting a preprocessor directive, also spelled with the %:
+// digraph or the ??= trigraph
+var cDirectivePattern = regexp.MustCompile(`#|%:|\?\?=`)
+
+// cHasIncludePattern matches the operators testing whether a file can be included
+var cHasIncludePattern = regexp.MustCompile(`\b__has_(?:include|include_next|embed)\b`)
+
+// cIdentifierPattern matches an identifier at the start of the text
+var cIdentifierPattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*`)
+
+// cFileDirectives are the directives that read the file they name
+var cFileDirectives = map[string]bool{"include": true, "include_next": true, "import": true, "embed": true}
+
+// ClangTidyLinter implements the Linter interface for C and C++ using clang-tidy. One instance
+// is registered per language.
+type ClangTidyLinter struct {
+	*BaseAnalyzer
+	language      string
+	clangTidyPath string
+}
+
+// NewCLinter creates a new C linter
+func NewCLinter(config map[string]string) *ClangTidyLinter {
+	return newClangTidyLinter("c", config)
+}
+
+// NewCppLinter creates a new C++ linter
+func NewCppLinter(config map[string]string) *ClangTidyLinter {
+	return newClangTidyLinter("cpp", config)
+}
+
+// newClangTidyLinter creates a clang-tidy linter for the given language
+func newClangTidyLinter(language string, config map[string]string) *ClangTidyLinter {
+	// Default clang-tidy path
+	clangTidyPath := "clang-tidy"
+	if path, ok := config["clangTidyPath"]; ok && path != "" {
+		clangTidyPath = path
+	}
+	
+	return &ClangTidyLinter{
+		BaseAnalyzer:  NewBaseAnalyzer(config),
+		language:      language,
+		clangTidyPath: clangTidyPath,
+	}
+}
+
+// Language returns the identifier for the supported language
+func (l *ClangTidyLinter) Language() string {
+	return l.language
+}
+
+// Analyze analyzes the provided code and returns issues found
+func (l *ClangTidyLinter) Analyze(ctx context.Context, code string, options map[string]interface{}) (*AnalysisResult, error) {
+	return l.BaseAnalyzer.CommonAnalyzeWrapper(
+		ctx,
+		code,
+		options,
+		l.findIssues,
+		l.SuggestFixes,
+	)
+}
+
+// compileCommand is an entry of a JSON compilation database
+type compileCommand struct {
+	Directory string   `json:"directory"`
+	File      string   `json:"file"`
+	Command   string   `json:"command,omitempty"`
+	Arguments []string `json:"arguments,omitempty"`
+	Output    string   `json:"output,omitempty"`
+}
+
+// findIssues analyzes the code and returns issues
+func (l *ClangTidyLinter) findIssues(ctx context.Context, code string, options map[string]interface{}) ([]Issue, error) {
+	// Create a temporary project holding the sources and the compilation database
+	tmpDir, err := ioutil.TempDir("", "codehawk-clang-tidy")
+	if err != nil {
+		return nil, l.WrapError(err, "failed to create temporary directory")
+	}
+	defer os.RemoveAll(tmpDir)
+	
+	// Headers and other translation units of a multi-file submission are written alongside the
+	// code. Submitted .clang-tidy files are dropped, as their ExtraArgs reach the compiler.
+	sourcePath := l.sourcePath(options)
+	files := l.GetFilesOption(options)
+	submittedDatabase, hasDatabase := files["compile_commands.json"]
+	delete(files, "compile_commands.json")
+	for path := range files {
+		if filepath.Base(path) == ".clang-tidy" {
+			delete(files, path)
+		}
+	}
+	files[sourcePath] = code
+	
+	if err := checkCIncludes(files); err != nil {
+		return nil, l.WrapError(err, "unsupported source")
+	}
+	
+	// A .clang-tidy configuration can be set up per deployment, but not per request
+	if config := l.GetStringOption(nil, "clangTidyConfig", ""); config != "" {
+		files[".clang-tidy"] = config
+	}
+	
+	if err := l.WriteFiles(tmpDir, files); err != nil {
+		return nil, l.WrapError(err, "failed to write source files")
+	}
+	
+	sourceFile := filepath.Join(tmpDir, filepath.FromSlash(sourcePath))
+	
+	var commands []compileCommand
+	if hasDatabase {
+		commands, err = relocateCompileCommands(submittedDatabase, files, tmpDir, l.compiler())
+		if err != nil {
+			return nil, l.WrapError(err, "failed to read compile_commands.json")
+		}
+		
+		// Build directories such as "build/" are not part of the submission but must exist
+		for _, command := range commands {
+			if err := os.MkdirAll(command.Directory, 0755); err != nil {
+				return nil, l.WrapError(err, "failed to create build directory")
+			}
+		}
+	}
+	
+	// The analyzed file needs an entry even when the submitted database lacks one
+	found := false
+	for _, command := range commands {
+		file := command.File
+		if !filepath.IsAbs(file) {
+			file = filepath.Join(command.Directory, file)
+		}
+		if sameFile(file, sourceFile) {
+			found = true
+			break
+		}
+	}
+	if !found {
+		commands = append(commands, l.compileCommand(tmpDir, sourceFile, options))
+	}
+	
+	database, err := json.MarshalIndent(commands, "", "  ")
+	if err != nil {
+		return nil, l.WrapError(err, "failed to encode compile_commands.json")
+	}
+	if err := ioutil.WriteFile(filepath.Join(tmpDir, "compile_commands.json"), database, 0644); err != nil {
+		return nil, l.WrapError(err, "failed to write compile_commands.json")
+	}
+	
+	return l.runClangTidy(ctx, tmpDir, sourceFile, code, options)
+}
+
+// sourcePath returns the path of the analyzed code relative to the project root
+func (l *ClangTidyLinter) sourcePath(options map[string]interface{}) string {
+	if path := l.GetStringOption(options, "path", ""); path != "" {
+		return filepath.ToSlash(filepath.Clean(path))
+	}
+	if l.language == "c" {
+		return "main.c"
+	}
+	return "main.cpp"
+}
+
+// compiler returns the compiler named in generated compilation database entries
+func (l *ClangTidyLinter) compiler() string {
+	if l.language == "c" {
+		return "clang"
+	}
+	return "clang++"
+}
+
+// compileCommand generates a compilation database entry for the analyzed file from the std,
+// includeDirs and defines options. Include directories must lie within the submitted project,
+// and arbitrary compilerFlags can only be configured for the linter, not per request.
+func (l *ClangTidyLinter) compileCommand(tmpDir, sourceFile string, options map[string]interface{}) compileCommand {
+	std := l.GetStringOption(options, "std", "c++17")
+	if l.language == "c" {
+		std = l.GetStringOption(options, "std", "c17")
+	}
+	if !cStdPattern.MatchString(std) {
+		fmt.Printf("Warning: ignoring invalid std %q\n", std)
+		std = "c++17"
+		if l.language == "c" {
+			std = "c17"
+		}
+	}
+	
+	arguments := []string{l.compiler(), "-std=" + std, "-I" + tmpDir}
+	for _, dir := range l.GetListOption(options, "includeDirs", nil) {
+		// Include directories are relative to the submitted project
+		resolved := filepath.Join(tmpDir, filepath.FromSlash(dir))
+		if filepath.IsAbs(dir) || !withinDir(resolved, tmpDir) {
+			fmt.Printf("Warning: ignoring include directory outside the project: %s\n", dir)
+			continue
+		}
+		arguments = append(arguments, "-I"+resolved)
+	}
+	for _, define := range l.GetListOption(options, "defines", nil) {
+		if !cDefinePattern.MatchString(define) {
+			fmt.Printf("Warning: ignoring invalid define %q\n", define)
+			continue
+		}
+		arguments = append(arguments, "-D"+define)
+	}
+	arguments = append(arguments, l.GetListOption(nil, "compilerFlags", nil)...)
+	arguments = append(arguments, "-c", sourceFile)
+	
+	return compileCommand{
+		Directory: tmpDir,
+		File:      sourceFile,
+		Arguments: arguments,
+	}
+}
+
+// relocateCompileCommands rewrites a submitted compilation database so that it refers to the
+// temporary project. The original project root is recovered by matching absolute file paths
+// against the submitted files, and every occurrence of it is replaced with tmpDir. Only the
+// arguments accepted by filterCompileArguments are kept, and entries for files outside the
+// project are dropped.
+func relocateCompileCommands(database string, files map[string]string, tmpDir, compiler string) ([]compileCommand, error) {
+	var commands []compileCommand
+	if err := json.Unmarshal([]byte(database), &commands); err != nil {
+		return nil, err
+	}
+	
+	root := ""
+	for _, command := range commands {
+		file := filepath.ToSlash(command.File)
+		if !strings.HasPrefix(file, "/") {
+			file = strings.TrimSuffix(filepath.ToSlash(command.Directory), "/") + "/" + file
+		}
+		for path := range files {
+			if strings.HasSuffix(file, "/"+path) {
+				root = strings.TrimSuffix(file, "/"+path)
+				break
+			}
+		}
+		if root != "" {
+			break
+		}
+	}
+	
+	relocate := func(value string) string {
+		if root == "" {
+			return value
+		}
+		return strings.ReplaceAll(value, root, tmpDir)
+	}
+	
+	relocated := make([]compileCommand, 0, len(commands))
+	for _, command := range commands {
+		directory := relocate(command.Directory)
+		if !filepath.IsAbs(directory) {
+			directory = filepath.Join(tmpDir, directory)
+		}
+		file := relocate(command.File)
+		if !filepath.IsAbs(file) {
+			file = filepath.Join(directory, file)
+		}
+		if !withinDir(directory, tmpDir) || !withinDir(file, tmpDir) {
+			continue
+		}
+		
+		arguments := command.Arguments
+		if len(arguments) == 0 {
+			arguments = splitCommandLine(command.Command)
+		}
+		for j, argument := range arguments {
+			arguments[j] = relocate(argument)
+		}
+		
+		relocated = append(relocated, compileCommand{
+			Directory: filepath.Clean(directory),
+			File:      filepath.Clean(file),
+			Arguments: filterCompileArguments(arguments, directory, tmpDir, compiler, file),
+		})
+	}
+	
+	return relocated, nil
+}
+
+// filterCompileArguments reduces the arguments of a submitted compile command to the ones that
+// cannot make the compiler load or read anything outside the project: defines, include
+// directories within tmpDir and the language standard. The compiler is replaced by the given
+// one and the analyzed file is passed last.
+func filterCompileArguments(arguments []string, directory, tmpDir, compiler, file string) []string {
+	filtered := []string{compiler}
+	for i := 1; i < len(arguments); i++ {
+		argument := arguments[i]
+		
+		// Flags may be given their value in the next argument, e.g. "-I include"
+		if (argument == "-D" || argument == "-U" || argument == "-I") && i+1 < len(arguments) {
+			i++
+			argument += arguments[i]
+		}
+		
+		switch {
+		case strings.HasPrefix(argument, "-D"), strings.HasPrefix(argument, "-U"):
+			if cDefinePattern.MatchString(argument[2:]) {
+				filtered = append(filtered, argument)
+			}
+		case strings.HasPrefix(argument, "-I"):
+			dir := argument[2:]
+			if !filepath.IsAbs(dir) {
+				dir = filepath.Join(directory, dir)
+			}
+			if withinDir(dir, tmpDir) {
+				filtered = append(filtered, "-I"+filepath.Clean(dir))
+			}
+		case strings.HasPrefix(argument, "-std="):
+			if cStdPattern.MatchString(strings.TrimPrefix(argument, "-std=")) {
+				filtered = append(filtered, argument)
+			}
+		}
+	}
+	return append(filtered, "-c", file)
+}
+
+// splitCommandLine splits the command string of a compilation database entry into arguments,
+// following the shell's quoting rules
+func splitCommandLine(command string) []string {
+	arguments := make([]string, 0)
+	var current strings.Builder
+	inArgument := false
+	var quote rune
+	escaped := false
+	for _, r := range command {
+		switch {
+		case escaped:
+			current.WriteRune(r)
+			escaped = false
+		case r == '\\' && quote != '\'':
+			escaped = true
+			inArgument = true
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			} else {
+				current.WriteRune(r)
+			}
+		case r == '"' || r == '\'':
+			quote = r
+			inArgument = true
+		case r == ' ' || r == '\t' || r == '\n':
+			if inArgument {
+				arguments = append(arguments, current.String())
+				current.Reset()
+				inArgument = false
+			}
+		default:
+			current.WriteRune(r)
+			inArgument = true
+		}
+	}
+	if inArgument {
+		arguments = append(arguments, current.String())
+	}
+	return arguments
+}
+
+// checkCIncludes rejects the code that would make the compiler read the server: #include,
+// #include_next, #import and #embed directives and __has_include and __has_embed tests naming
+// absolute paths or paths leaving the project, and any of these whose operand is not a plain
+// file name, since macros could build either. Line continuations are joined first, but comments
+// and strings are not skipped, so nothing can be hidden from the check behind text the scan
+// would misread.
+func checkCIncludes(files map[string]string) error {
+	for path, content := range files {
+		spliced, offsets := cSpliceLines(content)
+		
+		// Errors point at the line the directive or operator starts on
+		check := func(at, start int, name string) error {
+			line := strings.Count(content[:offsets[at]], "\n") + 1
+			value, ok := cHeaderName(spliced[cSkipSpace(spliced, start):])
+			if !ok {
+				return fmt.Errorf("%s:%d: %s only accepts a file name in quotes or angle brackets", path, line, name)
+			}
+			if !cInsideProject(value) {
+				return fmt.Errorf("%s:%d: %s can only name files inside the project", path, line, name)
+			}
+			return nil
+		}
+		
+		for _, match := range cDirectivePattern.FindAllStringIndex(spliced, -1) {
+			start := cSkipSpace(spliced, match[1])
+			name := cIdentifierPattern.FindString(spliced[start:])
+			if !cFileDirectives[name] {
+				continue
+			}
+			if err := check(match[0], start+len(name), "#"+name); err != nil {
+				return err
+			}
+		}
+		
+		// Without parentheses the operators are only tested for, as in #ifdef __has_include
+		for _, match := range cHasIncludePattern.FindAllStringIndex(spliced, -1) {
+			open := cSkipSpace(spliced, match[1])
+			if open == len(spliced) || spliced[open] != '(' {
+				continue
+			}
+			if err := check(match[0], open+1, spliced[match[0]:match[1]]); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// cSpliceLines joins the lines continued with a backslash, as the preprocessor does before it
+// reads directives, and returns the offset in content of each byte of the result
+func cSpliceLines(content string) (string, []int) {
+	var spliced strings.Builder
+	offsets := make([]int, 0, len(content)+1)
+	last := 0
+	for _, match := range append(cLineSplicePattern.FindAllStringIndex(content, -1), []int{len(content), len(content)}) {
+		spliced.WriteString(content[last:match[0]])
+		for i := last; i < match[0]; i++ {
+			offsets = append(offsets, i)
+		}
+		last = match[1]
+	}
+	return spliced.String(), append(offsets, len(content))
+}
+
+// cSkipSpace returns the offset of the first byte from start that is not whitespace within a
+// line or part of a block comment, which the preprocessor reads as a space
+func cSkipSpace(text string, start int) int {
+	for start < len(text) {
+		switch {
+		case strings.IndexByte(" \t\v\f\r", text[start]) >= 0:
+			start++
+		case strings.HasPrefix(text[start:], "/*"):
+			end := strings.Index(text[start+2:], "*/")
+			if end < 0 {
+				return len(text)
+			}
+			start += end + 4
+		default:
+			return start
+		}
+	}
+	return start
+}
+
+// cHeaderName returns the file name of a "name" or <name> operand at the start of text
+func cHeaderName(text string) (string, bool) {
+	if text == "" {
+		return "", false
+	}
+	closing := map[byte]byte{'"': '"', '<': '>'}[text[0]]
+	if closing == 0 {
+		return "", false
+	}
+	end := strings.IndexAny(text[1:], string(closing)+"\n")
+	if end < 0 || text[1+end] != closing {
+		return "", false
+	}
+	return text[1 : 1+end], true
+}
+
+// cInsideProject reports whether an included file name cannot leave the project. Include
+// directories may be the project root, so names must not climb above the directory they are
+// looked up in at all.
+func cInsideProject(name string) bool {
+	clean := filepath.ToSlash(filepath.Clean(name))
+	return !filepath.IsAbs(name) && clean != ".." && !strings.HasPrefix(clean, "../")
+}
+
+// withinDir reports whether path is dir or lies below it
+func withinDir(path, dir string) bool {
+	rel, err := filepath.Rel(dir, path)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) && !filepath.IsAbs(rel)
+}
+
+// clangTidyDiagnosticMessage is the message of a clang-tidy diagnostic or note. Offsets are
+// byte offsets into the file.
+type clangTidyDiagnosticMessage struct {
+	Message      string `yaml:"Message"`
+	FilePath     string `yaml:"FilePath"`
+	FileOffset   int    `yaml:"FileOffset"`
+	Replacements []struct {
+		FilePath        string `yaml:"FilePath"`
+		Offset          int    `yaml:"Offset"`
+		Length          int    `yaml:"Length"`
+		ReplacementText string `yaml:"ReplacementText"`
+	} `yaml:"Replacements"`
+}
+
+// clangTidyFixes is the YAML document written by clang-tidy's --export-fixes, which holds
+// every diagnostic whether or not it has a fix
+type clangTidyFixes struct {
+	MainSourceFile string `yaml:"MainSourceFile"`
+	Diagnostics    []struct {
+		DiagnosticName    string                       `yaml:"DiagnosticName"`
+		DiagnosticMessage clangTidyDiagnosticMessage   `yaml:"DiagnosticMessage"`
+		Notes             []clangTidyDiagnosticMessage `yaml:"Notes"`
+		Level             string                       `yaml:"Level"`
+		BuildDirectory    string                       `yaml:"BuildDirectory"`
+	} `yaml:"Diagnostics"`
+}
+
+// runClangTidy runs clang-tidy on the analyzed file and converts its exported fixes
+func (l *ClangTidyLinter) runClangTidy(ctx context.Context, tmpDir, sourceFile, code string, options map[string]interface{}) ([]Issue, error) {
+	fixesFile := filepath.Join(tmpDir, "clang-tidy-fixes.yaml")
+	
+	args := []string{
+		"-p", tmpDir,
+		"--export-fixes=" + fixesFile,
+		"--quiet",
+	}
+	
+	// Checks given per request override the configured .clang-tidy, which in turn overrides the
+	// configured defaults, e.g. "bugprone-*,-bugprone-easily-swappable-parameters"
+	checks := ""
+	if _, ok := options["checks"]; ok {
+		checks = strings.Join(l.GetListOption(options, "checks", nil), ",")
+	} else if _, err := os.Stat(filepath.Join(tmpDir, ".clang-tidy")); os.IsNotExist(err) {
+		checks = l.GetStringOption(nil, "checks", "")
+	}
+	if checks != "" {
+		args = append(args, "--checks="+checks)
+	}
+	
+	args = append(args, sourceFile)
+	
+	cmd := exec.CommandContext(ctx, l.clangTidyPath, args...)
+	cmd.Dir = tmpDir
+	
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	
+	// clang-tidy exits non-zero when the code does not compile, but still reports the
+	// compiler errors as clang-diagnostic-error diagnostics
+	runErr := cmd.Run()
+	
+	content, err := ioutil.ReadFile(fixesFile)
+	if os.IsNotExist(err) {
+		if runErr != nil {
+			return nil, l.WrapError(fmt.Errorf("failed to run clang-tidy: %w, stderr: %s", runErr, stderr.String()), "clang-tidy execution error")
+		}
+		// Nothing is exported when there are no diagnostics
+		return []Issue{}, nil
+	}
+	if err != nil {
+		return nil, l.WrapError(err, "failed to read clang-tidy fixes")
+	}
+	
+	var fixes clangTidyFixes
+	if err := yaml.Unmarshal(content, &fixes); err != nil {
+		return nil, l.WrapError(err, "failed to parse clang-tidy fixes")
+	}
+	
+	issues := make([]Issue, 0, len(fixes.Diagnostics))
+	for _, diagnostic := range fixes.Diagnostics {
+		message := diagnostic.DiagnosticMessage
+		
+		// Diagnostics in headers and other submitted files are not reported
+		if !sameFile(clangTidyPath(message.FilePath, tmpDir), sourceFile) {
+			continue
+		}
+		
+		line, column := offsetToPosition(code, message.FileOffset)
+		issue := Issue{
+			Line:     line,
+			Column:   &column,
+			Message:  message.Message,
+			Severity: clangTidySeverity(diagnostic.DiagnosticName, diagnostic.Level),
+			RuleID:   diagnostic.DiagnosticName,
+			Context:  clangTidyDocURL(diagnostic.DiagnosticName),
+		}
+		
+		// Fixes attached to the diagnostic are applied by clang-tidy --fix; fixes on notes
+		// are alternatives the user has to choose between
+		if fix := l.replacementFix(message, tmpDir, sourceFile, code, "Apply clang-tidy fix for "+diagnostic.DiagnosticName); fix != nil {
+			issue.Fix = fix
+		}
+		for _, note := range diagnostic.Notes {
+			if fix := l.replacementFix(note, tmpDir, sourceFile, code, note.Message); fix != nil {
+				issue.Suggestions = append(issue.Suggestions, *fix)
+			}
+		}
+		
+		issues = append(issues, issue)
+	}
+	
+	return issues, nil
+}
+
+// replacementFix converts the replacements of a diagnostic message into a single fix. Fixes
+// that also edit other files cannot be expressed against the code and are dropped.
+func (l *ClangTidyLinter) replacementFix(message clangTidyDiagnosticMessage, tmpDir, sourceFile, code, description string) *IssueFix {
+	if len(message.Replacements) == 0 {
+		return nil
+	}
+	
+	edits := make([]textEdit, 0, len(message.Replacements))
+	for _, replacement := range message.Replacements {
+		if !sameFile(clangTidyPath(replacement.FilePath, tmpDir), sourceFile) {
+			return nil
+		}
+		edits = append(edits, textEdit{
+			start: replacement.Offset,
+			end:   replacement.Offset + replacement.Length,
+			text:  replacement.ReplacementText,
+		})
+	}
+	
+	// clang-tidy may export the same replacement twice when several checks alias it
+	sort.SliceStable(edits, func(i, j int) bool {
+		return edits[i].start < edits[j].start
+	})
+	unique := edits[:0]
+	for i, edit := range edits {
+		if i > 0 && edit == edits[i-1] {
+			continue
+		}
+		unique = append(unique, edit)
+	}
+	
+	fix, err := buildRangeFix(code, description, unique)
+	if err != nil {
+		return nil
+	}
+	return fix
+}
+
+// clangTidyPath resolves a path from the exported fixes, which may be relative to the build directory
+func clangTidyPath(path, tmpDir string) string {
+	if path == "" || filepath.IsAbs(path) {
+		return path
+	}
+	return filepath.Join(tmpDir, path)
+}
+
+// clangTidySeverity maps a clang-tidy check to a CodeHawk severity. Compiler errors and
+// static analyzer findings are likely bugs; style checks are suggestions.
+func clangTidySeverity(check, level string) string {
+	if level == "Error" || check == "clang-diagnostic-error" {
+		return "error"
+	}
+	
+	switch {
+	case strings.HasPrefix(check, "clang-analyzer-"),
+		strings.HasPrefix(check, "bugprone-"),
+		strings.HasPrefix(check, "cert-"),
+		strings.HasPrefix(check, "concurrency-"),
+		strings.HasPrefix(check, "clang-diagnostic-"):
+		return "warning"
+	case strings.HasPrefix(check, "readability-"),
+		strings.HasPrefix(check, "modernize-"),
+		strings.HasPrefix(check, "google-"),
+		strings.HasPrefix(check, "llvm-"):
+		return "suggestion"
+	}
+	return "info"
+}
+
+// clangTidyDocURL returns the documentation page of a clang-tidy check
+func clangTidyDocURL(check string) string {
+	if strings.HasPrefix(check, "clang-diagnostic-") {
+		return ""
+	}
+	
+	// Checks are documented under their module, e.g. bugprone-use-after-move is bugprone/use-after-move
+	module := check
+	name := ""
+	if dash := strings.Index(check, "-"); dash >= 0 {
+		module = check[:dash]
+		name = check[dash+1:]
+	}
+	if module == "clang" && strings.HasPrefix(name, "analyzer-") {
+		module = "clang-analyzer"
+		name = strings.TrimPrefix(name, "analyzer-")
+	}
+	if name == "" {
+		return ""
+	}
+	return fmt.Sprintf("https://clang.llvm.org/extra/clang-tidy/checks/%s/%s.html", module, name)
+}
+
+// SuggestFixes attempts to generate fixes for the identified issues
+func (l *ClangTidyLinter) SuggestFixes(ctx context.Context, code string, issues []Issue) ([]Issue, error) {
+	// clang-tidy's exported fixes are attached to the issues they belong to
+	return []Issue{}, nil
+}
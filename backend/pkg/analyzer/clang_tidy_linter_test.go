@@ -0,0 +1,380 @@
+package analyzer
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// fakeClangTidy returns a clang-tidy stand-in that exports the given fixes, or none when fixes
+// is empty, and copies the compilation database it was run with to database
+func fakeClangTidy(t *testing.T, fixes, database string) string {
+	t.Helper()
+	script := "for arg in \"$@\"; do\n  case \"$arg\" in\n    --export-fixes=*)\n"
+	if fixes != "" {
+		script += "      cat > \"${arg#--export-fixes=}\" <<'CODEHAWK_EOF'\n" + fixes + "\nCODEHAWK_EOF\n"
+	}
+	script += "      ;;\n  esac\ndone\ncp compile_commands.json '" + database + "'"
+	return fakeScript(t, script)
+}
+
+func TestSplitCommandLine(t *testing.T) {
+	tests := []struct {
+		command string
+		want    []string
+	}{
+		{"clang++ -c main.cpp", []string{"clang++", "-c", "main.cpp"}},
+		{`cc  -DNAME="a b" -I'inc dir' main.c`, []string{"cc", "-DNAME=a b", "-Iinc dir", "main.c"}},
+		{`cc -DQ=\"x\" 'it'\''s'`, []string{"cc", `-DQ="x"`, "it's"}},
+		{`cc ""`, []string{"cc", ""}},
+	}
+	
+	for _, tt := range tests {
+		if got := splitCommandLine(tt.command); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("splitCommandLine(%q) = %q, want %q", tt.command, got, tt.want)
+		}
+	}
+}
+
+func TestWithinDir(t *testing.T) {
+	tests := []struct {
+		path string
+		want bool
+	}{
+		{"/tmp/project", true},
+		{"/tmp/project/include", true},
+		{"/tmp/project/../project/src", true},
+		{"/tmp/project-other", false},
+		{"/tmp", false},
+		{"/usr/include", false},
+	}
+	
+	for _, tt := range tests {
+		if got := withinDir(tt.path, "/tmp/project"); got != tt.want {
+			t.Errorf("withinDir(%q) = %v, want %v", tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestFilterCompileArguments(t *testing.T) {
+	tests := []struct {
+		name      string
+		arguments []string
+		want      []string
+	}{
+		{
+			name:      "defines, includes and std are kept",
+			arguments: []string{"/usr/bin/g++", "-DDEBUG", "-D", "LEVEL=2", "-UNDEBUG", "-Iinclude", "-I", "/tmp/project/third_party", "-std=c++20", "-O2", "-o", "main.o", "-c", "main.cpp"},
+			want:      []string{"clang++", "-DDEBUG", "-DLEVEL=2", "-UNDEBUG", "-I/tmp/project/build/include", "-I/tmp/project/third_party", "-std=c++20", "-c", "/tmp/project/main.cpp"},
+		},
+		{
+			name:      "includes outside the project are dropped",
+			arguments: []string{"cc", "-I/usr/include", "-I../../etc", "-isystem/usr/include"},
+			want:      []string{"clang++", "-c", "/tmp/project/main.cpp"},
+		},
+		{
+			name:      "plugins and response files are dropped",
+			arguments: []string{"cc", "-fplugin=/tmp/evil.so", "-Xclang", "-load", "@/etc/flags", "-include/etc/passwd", "--config=/tmp/x.cfg"},
+			want:      []string{"clang++", "-c", "/tmp/project/main.cpp"},
+		},
+		{
+			name:      "invalid values are dropped",
+			arguments: []string{"cc", "-D1BAD", "-std=c++17 -fplugin=x", "-D"},
+			want:      []string{"clang++", "-c", "/tmp/project/main.cpp"},
+		},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := filterCompileArguments(tt.arguments, "/tmp/project/build", "/tmp/project", "clang++", "/tmp/project/main.cpp")
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("filterCompileArguments() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckCIncludes(t *testing.T) {
+	tests := []struct {
+		name    string
+		files   map[string]string
+		wantErr string
+	}{
+		{"system and project headers", map[string]string{"src/main.c": "#include <stdio.h>\n#include \"util.h\"\n#include \"lib/../util.h\"\n"}, ""},
+		{"other directives", map[string]string{"main.cpp": "#define X 1\n#if __has_include(<optional>)\n#endif\n#ifdef __has_include\n#endif\nint include = 1;\n"}, ""},
+		{"absolute include", map[string]string{"main.c": "int x;\n#include \"/etc/passwd\"\n"}, "main.c:2: #include can only name files inside the project"},
+		{"system header leaving its directory", map[string]string{"main.c": "#include <../../etc/shadow>"}, "inside the project"},
+		{"include leaving the project", map[string]string{"src/main.c": "#include \"../../secrets.h\""}, "inside the project"},
+		{"embedded file", map[string]string{"main.c": "static const char k[] = {\n#embed \"/proc/self/environ\"\n};"}, "#embed can only name files"},
+		{"include_next", map[string]string{"a.h": "#include_next </etc/hosts>"}, "#include_next"},
+		{"import", map[string]string{"main.cpp": "#  import \"/etc/hosts\""}, "#import"},
+		{"probe", map[string]string{"main.c": "#if __has_embed ( \"/etc/shadow\" )\n#endif"}, "__has_embed can only name files"},
+		{"macro operand", map[string]string{"main.c": "#define F \"/etc/passwd\"\n#include F\n"}, "main.c:2: #include only accepts a file name"},
+		{"comment before the operand", map[string]string{"main.c": "#include /* x */ \"/etc/passwd\""}, "inside the project"},
+		{"comment inside the directive", map[string]string{"main.c": "# /* x\n */ include \"/etc/passwd\""}, "inside the project"},
+		{"continued line", map[string]string{"main.c": "int x;\n#inc\\\nlude \"/etc/passwd\""}, "main.c:2: #include"},
+		{"digraph", map[string]string{"main.cpp": "%:include \"/etc/passwd\""}, "inside the project"},
+		{"trigraphs", map[string]string{"main.c": "??=inc??/\nlude \"/etc/passwd\""}, "inside the project"},
+		{"header", map[string]string{"main.c": "#include \"a.h\"", "a.h": "#include \"/etc/passwd\""}, "a.h:1"},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkCIncludes(tt.files)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("checkCIncludes() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("checkCIncludes() error = %v, want one mentioning %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestRelocateCompileCommands(t *testing.T) {
+	files := map[string]string{"src/main.cpp": "", "include/util.h": ""}
+	database := `[
+		{"directory": "/home/dev/app/build", "file": "/home/dev/app/src/main.cpp", "command": "g++ -I/home/dev/app/include -DAPP -c /home/dev/app/src/main.cpp"},
+		{"directory": "/home/dev/app/build", "file": "../src/main.cpp", "arguments": ["g++", "-std=c++14", "-c", "../src/main.cpp"]},
+		{"directory": "/usr/src", "file": "/usr/src/other.cpp", "command": "g++ -c other.cpp"}
+	]`
+	
+	commands, err := relocateCompileCommands(database, files, "/tmp/project", "clang++")
+	if err != nil {
+		t.Fatalf("relocateCompileCommands() error = %v", err)
+	}
+	
+	want := []compileCommand{
+		{
+			Directory: "/tmp/project/build",
+			File:      "/tmp/project/src/main.cpp",
+			Arguments: []string{"clang++", "-I/tmp/project/include", "-DAPP", "-c", "/tmp/project/src/main.cpp"},
+		},
+		{
+			Directory: "/tmp/project/build",
+			File:      "/tmp/project/src/main.cpp",
+			Arguments: []string{"clang++", "-std=c++14", "-c", "/tmp/project/src/main.cpp"},
+		},
+	}
+	if !reflect.DeepEqual(commands, want) {
+		t.Errorf("relocateCompileCommands() = %+v, want %+v", commands, want)
+	}
+	
+	if _, err := relocateCompileCommands("{", files, "/tmp/project", "clang++"); err == nil {
+		t.Error("relocateCompileCommands() accepted an invalid database")
+	}
+}
+
+func TestClangTidyCompileCommand(t *testing.T) {
+	tests := []struct {
+		name     string
+		language string
+		config   map[string]string
+		options  map[string]interface{}
+		want     []string
+	}{
+		{
+			name:     "C++ defaults",
+			language: "cpp",
+			want:     []string{"clang++", "-std=c++17", "-I/tmp/project", "-c", "/tmp/project/main.cpp"},
+		},
+		{
+			name:     "C options",
+			language: "c",
+			options: map[string]interface{}{
+				"std":         "gnu11",
+				"includeDirs": "include,../outside,/usr/include",
+				"defines":     []interface{}{"DEBUG", "LEVEL=2", "BAD NAME"},
+			},
+			want: []string{"clang", "-std=gnu11", "-I/tmp/project", "-I/tmp/project/include", "-DDEBUG", "-DLEVEL=2", "-c", "/tmp/project/main.cpp"},
+		},
+		{
+			name:     "invalid std falls back to the default",
+			language: "cpp",
+			options:  map[string]interface{}{"std": "c++17 -fplugin=x.so"},
+			want:     []string{"clang++", "-std=c++17", "-I/tmp/project", "-c", "/tmp/project/main.cpp"},
+		},
+		{
+			name:     "compiler flags come from the configuration only",
+			language: "cpp",
+			config:   map[string]string{"compilerFlags": "-Wall"},
+			options:  map[string]interface{}{"compilerFlags": "-fplugin=/tmp/evil.so"},
+			want:     []string{"clang++", "-std=c++17", "-I/tmp/project", "-Wall", "-c", "/tmp/project/main.cpp"},
+		},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := tt.config
+			if config == nil {
+				config = map[string]string{}
+			}
+			l := newClangTidyLinter(tt.language, config)
+			
+			command := l.compileCommand("/tmp/project", "/tmp/project/main.cpp", tt.options)
+			if command.Directory != "/tmp/project" || command.File != "/tmp/project/main.cpp" {
+				t.Errorf("command = %+v, want directory /tmp/project and file main.cpp", command)
+			}
+			if !reflect.DeepEqual(command.Arguments, tt.want) {
+				t.Errorf("arguments = %q, want %q", command.Arguments, tt.want)
+			}
+		})
+	}
+}
+
+func TestClangTidyFindIssues(t *testing.T) {
+	code := "int main() {\n  int *p = 0;\n  return 0;\n}\n"
+	fixes := `MainSourceFile: main.cpp
+Diagnostics:
+  - DiagnosticName: modernize-use-nullptr
+    DiagnosticMessage:
+      Message: use nullptr
+      FilePath: main.cpp
+      FileOffset: 24
+      Replacements:
+        - FilePath: main.cpp
+          Offset: 24
+          Length: 1
+          ReplacementText: nullptr
+        - FilePath: main.cpp
+          Offset: 24
+          Length: 1
+          ReplacementText: nullptr
+    Level: Warning
+  - DiagnosticName: clang-analyzer-deadcode.DeadStores
+    DiagnosticMessage:
+      Message: Value stored to 'p' during its initialization is never read
+      FilePath: main.cpp
+      FileOffset: 20
+      Replacements: []
+    Notes:
+      - Message: remove the variable
+        FilePath: main.cpp
+        FileOffset: 15
+        Replacements:
+          - FilePath: main.cpp
+            Offset: 13
+            Length: 14
+            ReplacementText: ''
+    Level: Warning
+  - DiagnosticName: readability-identifier-naming
+    DiagnosticMessage:
+      Message: invalid case style
+      FilePath: util.h
+      FileOffset: 0
+      Replacements: []
+    Level: Warning`
+	
+	database := filepath.Join(t.TempDir(), "compile_commands.json")
+	l := NewCppLinter(map[string]string{"clangTidyPath": fakeClangTidy(t, fixes, database)})
+	
+	options := map[string]interface{}{
+		"files": map[string]interface{}{
+			"util.h":                "#pragma once\n",
+			"util.cpp":              "#include \"util.h\"\n",
+			".clang-tidy":           "ExtraArgs: ['-fplugin=/tmp/evil.so']\n",
+			"compile_commands.json": `[{"directory": "/home/dev/app", "file": "/home/dev/app/util.cpp", "command": "g++ -fplugin=/tmp/evil.so -DAPP -c util.cpp"}]`,
+		},
+	}
+	issues, err := l.findIssues(context.Background(), code, options)
+	if err != nil {
+		t.Fatalf("findIssues() error = %v", err)
+	}
+	
+	if len(issues) != 2 {
+		t.Fatalf("got %d issues, want 2: %+v", len(issues), issues)
+	}
+	
+	nullptr := issues[0]
+	if nullptr.RuleID != "modernize-use-nullptr" || nullptr.Line != 2 || nullptr.Severity != "suggestion" || nullptr.Context != "https://clang.llvm.org/extra/clang-tidy/checks/modernize/use-nullptr.html" {
+		t.Errorf("issue = %+v, want modernize-use-nullptr at line 2", nullptr)
+	}
+	if nullptr.Fix == nil {
+		t.Fatal("got no fix for modernize-use-nullptr")
+	}
+	fixed, err := ApplyFix(code, nullptr.Line, *nullptr.Fix)
+	if want := "int main() {\n  int *p = nullptr;\n  return 0;\n}\n"; err != nil || fixed != want {
+		t.Errorf("fixed code = %q (%v), want %q", fixed, err, want)
+	}
+	
+	deadStore := issues[1]
+	if deadStore.Severity != "warning" || deadStore.Fix != nil || len(deadStore.Suggestions) != 1 {
+		t.Errorf("issue = %+v, want a warning with one suggestion and no fix", deadStore)
+	}
+	
+	// The generated database replaces the submitted one, whose entry is filtered
+	content, err := ioutil.ReadFile(database)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var commands []compileCommand
+	if err := json.Unmarshal(content, &commands); err != nil {
+		t.Fatal(err)
+	}
+	if len(commands) != 2 {
+		t.Fatalf("got %d compile commands, want 2: %s", len(commands), content)
+	}
+	for _, command := range commands {
+		if strings.Contains(strings.Join(command.Arguments, " "), "plugin") {
+			t.Errorf("compile command kept a plugin: %q", command.Arguments)
+		}
+	}
+	if filepath.Base(commands[1].File) != "main.cpp" {
+		t.Errorf("last compile command is for %s, want main.cpp", commands[1].File)
+	}
+}
+
+func TestClangTidyNoDiagnostics(t *testing.T) {
+	database := filepath.Join(t.TempDir(), "compile_commands.json")
+	l := NewCLinter(map[string]string{"clangTidyPath": fakeClangTidy(t, "", database)})
+	
+	issues, err := l.findIssues(context.Background(), "int main(void) { return 0; }\n", nil)
+	if err != nil || len(issues) != 0 {
+		t.Errorf("findIssues() = %+v, %v, want no issues", issues, err)
+	}
+}
+
+func TestClangTidySeverity(t *testing.T) {
+	tests := []struct {
+		check string
+		level string
+		want  string
+	}{
+		{"clang-diagnostic-error", "Error", "error"},
+		{"clang-diagnostic-unused-variable", "Warning", "warning"},
+		{"bugprone-use-after-move", "Warning", "warning"},
+		{"readability-else-after-return", "Warning", "suggestion"},
+		{"performance-unnecessary-copy-initialization", "Warning", "info"},
+	}
+	
+	for _, tt := range tests {
+		if got := clangTidySeverity(tt.check, tt.level); got != tt.want {
+			t.Errorf("clangTidySeverity(%q, %q) = %q, want %q", tt.check, tt.level, got, tt.want)
+		}
+	}
+}
+
+func TestClangTidyDocURL(t *testing.T) {
+	tests := []struct {
+		check string
+		want  string
+	}{
+		{"bugprone-use-after-move", "https://clang.llvm.org/extra/clang-tidy/checks/bugprone/use-after-move.html"},
+		{"clang-analyzer-core.NullDereference", "https://clang.llvm.org/extra/clang-tidy/checks/clang-analyzer/core.NullDereference.html"},
+		{"clang-diagnostic-error", ""},
+		{"misc", ""},
+	}
+	
+	for _, tt := range tests {
+		if got := clangTidyDocURL(tt.check); got != tt.want {
+			t.Errorf("clangTidyDocURL(%q) = %q, want %q", tt.check, got, tt.want)
+		}
+	}
+}
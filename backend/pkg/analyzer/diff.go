@@ -0,0 +1,271 @@
+package analyzer
+
+import (
+	"fmt"
+	"strings"
+)
+
+// diffHunk describes a changed region between two versions of a text. Lines are 0-based
+// indexes into the line slices the diff was computed from; the end indexes are exclusive.
+type diffHunk struct {
+	oldStart int
+	oldEnd   int
+	newStart int
+	newEnd   int
+}
+
+// splitLines splits text into lines that keep their trailing newline
+func splitLines(text string) []string {
+	lines := strings.SplitAfter(text, "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+// diffMaxEdits bounds the edit script diffLines searches for. The frontiers it records grow with
+// the square of the number of edits, so texts that differ by more are reported as one hunk.
+const diffMaxEdits = 1000
+
+// diffLines computes the changed regions between two line slices using Myers' algorithm
+func diffLines(a, b []string) []diffHunk {
+	n, m := len(a), len(b)
+	if n+m == 0 {
+		return nil
+	}
+	
+	// Lines shared at both ends are never part of a hunk, which keeps the search to the middle
+	prefix := 0
+	for prefix < n && prefix < m && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < n-prefix && suffix < m-prefix && a[n-1-suffix] == b[m-1-suffix] {
+		suffix++
+	}
+	
+	oldKept := make([]bool, n)
+	newKept := make([]bool, m)
+	for i := 0; i < prefix; i++ {
+		oldKept[i], newKept[i] = true, true
+	}
+	for i := 1; i <= suffix; i++ {
+		oldKept[n-i], newKept[m-i] = true, true
+	}
+	myersKept(a[prefix:n-suffix], b[prefix:m-suffix], oldKept[prefix:n-suffix], newKept[prefix:m-suffix])
+	
+	// Group the runs of changed lines into hunks
+	hunks := make([]diffHunk, 0)
+	i, j := 0, 0
+	for i < n || j < m {
+		if i < n && j < m && oldKept[i] && newKept[j] {
+			i++
+			j++
+			continue
+		}
+		
+		hunk := diffHunk{oldStart: i, newStart: j}
+		for i < n && !oldKept[i] {
+			i++
+		}
+		for j < m && !newKept[j] {
+			j++
+		}
+		hunk.oldEnd = i
+		hunk.newEnd = j
+		hunks = append(hunks, hunk)
+	}
+	
+	return hunks
+}
+
+// myersKept marks the lines of a and b that a shortest edit script keeps unchanged. When the
+// script would need more than diffMaxEdits edits, no line is marked.
+func myersKept(a, b []string, oldKept, newKept []bool) {
+	n, m := len(a), len(b)
+	max := n + m
+	if max == 0 {
+		return
+	}
+	
+	offset := max + 1
+	v := make([]int, 2*max+3)
+	
+	// Find the shortest edit script, recording the part of the frontier each step reads: step d
+	// only looks at the diagonals -d..d
+	trace := make([][]int, 0)
+	found := false
+	for d := 0; d <= max && !found; d++ {
+		if d > diffMaxEdits {
+			return
+		}
+		trace = append(trace, append([]int(nil), v[offset-d:offset+d+1]...))
+		
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && v[offset+k-1] < v[offset+k+1]) {
+				x = v[offset+k+1]
+			} else {
+				x = v[offset+k-1] + 1
+			}
+			y := x - k
+			
+			for x < n && y < m && a[x] == b[y] {
+				x++
+				y++
+			}
+			
+			v[offset+k] = x
+			if x >= n && y >= m {
+				found = true
+				break
+			}
+		}
+	}
+	
+	// Walk the trace backwards, marking which lines on each side are unchanged
+	x, y := n, m
+	for d := len(trace) - 1; d >= 0; d-- {
+		frontier := trace[d]
+		at := func(k int) int {
+			if k < -d || k > d {
+				return 0
+			}
+			return frontier[d+k]
+		}
+		k := x - y
+		
+		var prevK int
+		if k == -d || (k != d && at(k-1) < at(k+1)) {
+			prevK = k + 1
+		} else {
+			prevK = k - 1
+		}
+		
+		prevX := at(prevK)
+		prevY := prevX - prevK
+		
+		for x > prevX && y > prevY {
+			x--
+			y--
+			oldKept[x] = true
+			newKept[y] = true
+		}
+		
+		if d > 0 {
+			x, y = prevX, prevY
+		}
+	}
+}
+
+// hunkFixes converts the differences between code and its rewritten form into range-based fixes,
+// one per hunk, keyed by the 1-based line the hunk starts on
+func hunkFixes(code, rewritten, description string) ([]int, []*IssueFix) {
+	oldLines := splitLines(code)
+	newLines := splitLines(rewritten)
+	
+	// Byte offset at which each old line starts
+	lineOffsets := make([]int, len(oldLines)+1)
+	for i, line := range oldLines {
+		lineOffsets[i+1] = lineOffsets[i] + len(line)
+	}
+	
+	lines := make([]int, 0)
+	fixes := make([]*IssueFix, 0)
+	for _, hunk := range diffLines(oldLines, newLines) {
+		start := lineOffsets[hunk.oldStart]
+		end := lineOffsets[hunk.oldEnd]
+		replacement := strings.Join(newLines[hunk.newStart:hunk.newEnd], "")
+		
+		fix, err := buildRangeFix(code, description, []textEdit{{start: start, end: end, text: replacement}})
+		if err != nil {
+			continue
+		}
+		
+		lines = append(lines, hunk.oldStart+1)
+		fixes = append(fixes, fix)
+	}
+	
+	return lines, fixes
+}
+
+// UnifiedDiff renders the differences between two texts as a unified diff with three lines of
+// context. An empty string is returned when the texts are identical.
+func UnifiedDiff(name, original, updated string) string {
+	const context = 3
+	
+	oldLines := splitLines(original)
+	newLines := splitLines(updated)
+	hunks := diffLines(oldLines, newLines)
+	if len(hunks) == 0 {
+		return ""
+	}
+	
+	var out strings.Builder
+	out.WriteString("--- a/" + name + "\n")
+	out.WriteString("+++ b/" + name + "\n")
+	
+	writeLine := func(prefix, line string) {
+		out.WriteString(prefix + line)
+		if !strings.HasSuffix(line, "\n") {
+			out.WriteString("\n\\ No newline at end of file\n")
+		}
+	}
+	
+	for i := 0; i < len(hunks); {
+		// Merge hunks whose context would overlap
+		j := i
+		for j+1 < len(hunks) && hunks[j+1].oldStart-hunks[j].oldEnd <= 2*context {
+			j++
+		}
+		
+		oldStart := hunks[i].oldStart - context
+		if oldStart < 0 {
+			oldStart = 0
+		}
+		newStart := hunks[i].newStart - (hunks[i].oldStart - oldStart)
+		
+		oldEnd := hunks[j].oldEnd + context
+		if oldEnd > len(oldLines) {
+			oldEnd = len(oldLines)
+		}
+		newEnd := hunks[j].newEnd + (oldEnd - hunks[j].oldEnd)
+		
+		out.WriteString(fmt.Sprintf("@@ -%s +%s @@\n",
+			hunkRange(oldStart, oldEnd-oldStart),
+			hunkRange(newStart, newEnd-newStart),
+		))
+		
+		cursor := oldStart
+		for _, hunk := range hunks[i : j+1] {
+			for ; cursor < hunk.oldStart; cursor++ {
+				writeLine(" ", oldLines[cursor])
+			}
+			for _, line := range oldLines[hunk.oldStart:hunk.oldEnd] {
+				writeLine("-", line)
+			}
+			for _, line := range newLines[hunk.newStart:hunk.newEnd] {
+				writeLine("+", line)
+			}
+			cursor = hunk.oldEnd
+		}
+		for ; cursor < oldEnd; cursor++ {
+			writeLine(" ", oldLines[cursor])
+		}
+		
+		i = j + 1
+	}
+	
+	return out.String()
+}
+
+// hunkRange formats the 0-based start and length of a hunk as a unified diff range
+func hunkRange(start, length int) string {
+	if length == 0 {
+		return fmt.Sprintf("%d,0", start)
+	}
+	if length == 1 {
+		return fmt.Sprintf("%d", start+1)
+	}
+	return fmt.Sprintf("%d,%d", start+1, length)
+}
@@ -0,0 +1,278 @@
+package analyzer
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestDiffLines(t *testing.T) {
+	tests := []struct {
+		name string
+		a    []string
+		b    []string
+		want []diffHunk
+	}{
+		{"identical", []string{"a\n", "b\n"}, []string{"a\n", "b\n"}, []diffHunk{}},
+		{"both empty", nil, nil, nil},
+		{"insertion", []string{"a\n", "c\n"}, []string{"a\n", "b\n", "c\n"}, []diffHunk{{1, 1, 1, 2}}},
+		{"deletion", []string{"a\n", "b\n", "c\n"}, []string{"a\n", "c\n"}, []diffHunk{{1, 2, 1, 1}}},
+		{"replacement", []string{"a\n", "b\n", "c\n"}, []string{"a\n", "B\n", "c\n"}, []diffHunk{{1, 2, 1, 2}}},
+		{"separate hunks", []string{"a\n", "b\n", "c\n", "d\n"}, []string{"A\n", "b\n", "c\n", "D\n"}, []diffHunk{{0, 1, 0, 1}, {3, 4, 3, 4}}},
+		{"everything added", nil, []string{"a\n"}, []diffHunk{{0, 0, 0, 1}}},
+		{"everything removed", []string{"a\n"}, nil, []diffHunk{{0, 1, 0, 0}}},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := diffLines(tt.a, tt.b); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("diffLines() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDiffLinesLargeInputs(t *testing.T) {
+	lines := func(n int, line func(i int) string) []string {
+		result := make([]string, n)
+		for i := range result {
+			result[i] = line(i)
+		}
+		return result
+	}
+	original := lines(3000, func(i int) string { return fmt.Sprintf("x%d()\n", i) })
+	
+	tests := []struct {
+		name      string
+		b         []string
+		wantHunks int
+	}{
+		{
+			name:      "scattered edits",
+			b:         lines(3000, func(i int) string { return fmt.Sprintf("x%d()%s\n", i, strings.Repeat(";", boolInt(i%6 == 0))) }),
+			wantHunks: 500,
+		},
+		{
+			name:      "reindented",
+			b:         lines(3000, func(i int) string { return fmt.Sprintf("\tx%d()\n", i) }),
+			wantHunks: 1,
+		},
+		{
+			name: "reindented middle",
+			b: lines(3000, func(i int) string {
+				return fmt.Sprintf("%sx%d()\n", strings.Repeat("\t", boolInt(i >= 10 && i < 2990)), i)
+			}),
+			wantHunks: 1,
+		},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hunks := diffLines(original, tt.b)
+			if len(hunks) != tt.wantHunks {
+				t.Fatalf("got %d hunks, want %d", len(hunks), tt.wantHunks)
+			}
+			
+			// Applying the hunks to the original gives the other side
+			rebuilt := make([]string, 0, len(tt.b))
+			cursor := 0
+			for _, hunk := range hunks {
+				rebuilt = append(rebuilt, original[cursor:hunk.oldStart]...)
+				rebuilt = append(rebuilt, tt.b[hunk.newStart:hunk.newEnd]...)
+				cursor = hunk.oldEnd
+			}
+			rebuilt = append(rebuilt, original[cursor:]...)
+			if !reflect.DeepEqual(rebuilt, tt.b) {
+				t.Error("hunks do not turn the original into the other side")
+			}
+		})
+	}
+}
+
+// boolInt returns 1 for true and 0 for false
+func boolInt(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+func TestHunkFixes(t *testing.T) {
+	tests := []struct {
+		name      string
+		code      string
+		rewritten string
+		wantLines []int
+	}{
+		{
+			name:      "unchanged",
+			code:      "x = 1\n",
+			rewritten: "x = 1\n",
+			wantLines: []int{},
+		},
+		{
+			name:      "one hunk",
+			code:      "x=1\ny = 2\n",
+			rewritten: "x = 1\ny = 2\n",
+			wantLines: []int{1},
+		},
+		{
+			name:      "two hunks",
+			code:      "def f( a ):\n    return a\n\n\n\n\nx=f(1)\n",
+			rewritten: "def f(a):\n    return a\n\n\nx = f(1)\n",
+			wantLines: []int{1, 5},
+		},
+		{
+			name:      "missing final newline",
+			code:      "x = 1",
+			rewritten: "x = 1\n",
+			wantLines: []int{1},
+		},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lines, fixes := hunkFixes(tt.code, tt.rewritten, "Format code")
+			if !reflect.DeepEqual(lines, tt.wantLines) {
+				t.Fatalf("hunk lines = %v, want %v", lines, tt.wantLines)
+			}
+			
+			// Applying the fixes from the last one reproduces the rewritten code
+			fixed := tt.code
+			for i := len(fixes) - 1; i >= 0; i-- {
+				var err error
+				fixed, err = ApplyFix(fixed, lines[i], *fixes[i])
+				if err != nil {
+					t.Fatalf("ApplyFix() error = %v", err)
+				}
+			}
+			if fixed != tt.rewritten {
+				t.Errorf("fixed code = %q, want %q", fixed, tt.rewritten)
+			}
+		})
+	}
+}
+
+func TestFixCoversLine(t *testing.T) {
+	tests := []struct {
+		name    string
+		fix     IssueFix
+		covered []int
+		missed  []int
+	}{
+		{
+			name:   "line replacement without a range",
+			fix:    IssueFix{},
+			missed: []int{1},
+		},
+		{
+			name:    "insertion",
+			fix:     IssueFix{Range: &FixRange{StartLine: 3, StartColumn: 1, EndLine: 3, EndColumn: 1}},
+			covered: []int{3},
+			missed:  []int{2, 4},
+		},
+		{
+			name:    "whole lines",
+			fix:     IssueFix{Range: &FixRange{StartLine: 2, StartColumn: 1, EndLine: 4, EndColumn: 1}},
+			covered: []int{2, 3},
+			missed:  []int{1, 4},
+		},
+		{
+			name:    "ending within a line",
+			fix:     IssueFix{Range: &FixRange{StartLine: 2, StartColumn: 5, EndLine: 4, EndColumn: 3}},
+			covered: []int{2, 3, 4},
+			missed:  []int{5},
+		},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, line := range tt.covered {
+				if !fixCoversLine(&tt.fix, line) {
+					t.Errorf("fixCoversLine(%d) = false, want true", line)
+				}
+			}
+			for _, line := range tt.missed {
+				if fixCoversLine(&tt.fix, line) {
+					t.Errorf("fixCoversLine(%d) = true, want false", line)
+				}
+			}
+		})
+	}
+}
+
+func TestUnifiedDiff(t *testing.T) {
+	tests := []struct {
+		name     string
+		original string
+		updated  string
+		want     string
+	}{
+		{
+			name:     "identical",
+			original: "a\nb\n",
+			updated:  "a\nb\n",
+			want:     "",
+		},
+		{
+			name:     "changed line with context",
+			original: "a\nb\nc\n",
+			updated:  "a\nB\nc\n",
+			want:     "--- a/code.py\n+++ b/code.py\n@@ -1,3 +1,3 @@\n a\n-b\n+B\n c\n",
+		},
+		{
+			name:     "distant hunks stay separate",
+			original: "1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n",
+			updated:  "one\n2\n3\n4\n5\n6\n7\n8\n9\nten\n",
+			want: "--- a/code.py\n+++ b/code.py\n" +
+				"@@ -1,4 +1,4 @@\n-1\n+one\n 2\n 3\n 4\n" +
+				"@@ -7,4 +7,4 @@\n 7\n 8\n 9\n-10\n+ten\n",
+		},
+		{
+			name:     "close hunks are merged",
+			original: "1\n2\n3\n4\n5\n",
+			updated:  "one\n2\n3\n4\nfive\n",
+			want:     "--- a/code.py\n+++ b/code.py\n@@ -1,5 +1,5 @@\n-1\n+one\n 2\n 3\n 4\n-5\n+five\n",
+		},
+		{
+			name:     "missing final newline",
+			original: "a",
+			updated:  "a\n",
+			want:     "--- a/code.py\n+++ b/code.py\n@@ -1 +1 @@\n-a\n\\ No newline at end of file\n+a\n",
+		},
+		{
+			name:     "insertion into empty text",
+			original: "",
+			updated:  "a\n",
+			want:     "--- a/code.py\n+++ b/code.py\n@@ -0,0 +1 @@\n+a\n",
+		},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := UnifiedDiff("code.py", tt.original, tt.updated); got != tt.want {
+				t.Errorf("UnifiedDiff() =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHunkRange(t *testing.T) {
+	tests := []struct {
+		start  int
+		length int
+		want   string
+	}{
+		{0, 0, "0,0"},
+		{4, 0, "4,0"},
+		{4, 1, "5"},
+		{4, 3, "5,3"},
+	}
+	
+	for _, tt := range tests {
+		if got := hunkRange(tt.start, tt.length); got != tt.want {
+			t.Errorf("hunkRange(%d, %d) = %q, want %q", tt.start, tt.length, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,135 @@
+package analyzer
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// DockerfileLinter implements the Linter interface for Dockerfiles
+type DockerfileLinter struct {
+	*BaseAnalyzer
+	hadolintPath string
+}
+
+// NewDockerfileLinter creates a new Dockerfile linter
+func NewDockerfileLinter(config map[string]string) *DockerfileLinter {
+	// Default hadolint path
+	hadolintPath := "hadolint"
+	if path, ok := config["hadolintPath"]; ok && path != "" {
+		hadolintPath = path
+	}
+	
+	return &DockerfileLinter{
+		BaseAnalyzer: NewBaseAnalyzer(config),
+		hadolintPath: hadolintPath,
+	}
+}
+
+// Language returns the identifier for the supported language
+func (l *DockerfileLinter) Language() string {
+	return "dockerfile"
+}
+
+// Analyze analyzes the provided code and returns issues found
+func (l *DockerfileLinter) Analyze(ctx context.Context, code string, options map[string]interface{}) (*AnalysisResult, error) {
+	return l.BaseAnalyzer.CommonAnalyzeWrapper(
+		ctx,
+		code,
+		options,
+		l.findIssues,
+		l.SuggestFixes,
+	)
+}
+
+// findIssues runs hadolint on the Dockerfile and parses its output
+func (l *DockerfileLinter) findIssues(ctx context.Context, code string, options map[string]interface{}) ([]Issue, error) {
+	args := []string{
+		"--format", "json",
+		"--no-fail",
+	}
+	
+	// Rules can be ignored per request, e.g. "DL3008,SC2086"
+	for _, rule := range l.GetListOption(options, "hadolintIgnore", nil) {
+		args = append(args, "--ignore", rule)
+	}
+	
+	// Base images from other registries are reported by DL3026
+	for _, registry := range l.GetListOption(options, "trustedRegistries", nil) {
+		args = append(args, "--trusted-registry", registry)
+	}
+	
+	args = append(args, "-")
+	
+	cmd := exec.CommandContext(ctx, l.hadolintPath, args...)
+	
+	var stdout, stderr bytes.Buffer
+	cmd.Stdin = strings.NewReader(code)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	
+	if err := cmd.Run(); err != nil {
+		return nil, l.WrapError(fmt.Errorf("failed to run hadolint: %w, stderr: %s", err, stderr.String()), "hadolint execution error")
+	}
+	
+	type HadolintIssue struct {
+		Code    string `json:"code"`
+		Column  int    `json:"column"`
+		Level   string `json:"level"`
+		Line    int    `json:"line"`
+		Message string `json:"message"`
+	}
+	
+	var hadolintIssues []HadolintIssue
+	if err := json.Unmarshal(stdout.Bytes(), &hadolintIssues); err != nil {
+		return nil, l.WrapError(err, "failed to parse hadolint output")
+	}
+	
+	issues := make([]Issue, 0, len(hadolintIssues))
+	for _, hadolintIssue := range hadolintIssues {
+		var severity string
+		switch hadolintIssue.Level {
+		case "error":
+			severity = "error"
+		case "warning":
+			severity = "warning"
+		case "style":
+			severity = "suggestion"
+		default:
+			severity = "info"
+		}
+		
+		// DL rules are hadolint's own; SC rules come from shellcheck on RUN instructions
+		docURL := "https://github.com/hadolint/hadolint/wiki/" + hadolintIssue.Code
+		if strings.HasPrefix(hadolintIssue.Code, "SC") {
+			docURL = "https://www.shellcheck.net/wiki/" + hadolintIssue.Code
+		}
+		
+		issue := Issue{
+			Line:     hadolintIssue.Line,
+			Message:  hadolintIssue.Message,
+			Severity: severity,
+			RuleID:   hadolintIssue.Code,
+			Context:  docURL,
+		}
+		
+		// hadolint reports column 1 for instruction-level findings
+		if hadolintIssue.Column > 1 {
+			column := hadolintIssue.Column
+			issue.Column = &column
+		}
+		
+		issues = append(issues, issue)
+	}
+	
+	return issues, nil
+}
+
+// SuggestFixes attempts to generate fixes for the identified issues
+func (l *DockerfileLinter) SuggestFixes(ctx context.Context, code string, issues []Issue) ([]Issue, error) {
+	// hadolint does not produce fixes
+	return []Issue{}, nil
+}
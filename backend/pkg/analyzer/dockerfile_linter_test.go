@@ -0,0 +1,51 @@
+package analyzer
+
+import (
+	"context"
+	"testing"
+)
+
+func TestDockerfileFindIssues(t *testing.T) {
+	output := `[
+		{"code": "DL3007", "column": 1, "file": "-", "level": "warning", "line": 1, "message": "Using latest is prone to errors if the image will ever update."},
+		{"code": "SC2086", "column": 12, "file": "-", "level": "info", "line": 3, "message": "Double quote to prevent globbing and word splitting."},
+		{"code": "DL3059", "column": 1, "file": "-", "level": "style", "line": 4, "message": "Multiple consecutive RUN instructions."}
+	]`
+	l := NewDockerfileLinter(map[string]string{"hadolintPath": fakeTool(t, output)})
+	
+	issues, err := l.findIssues(context.Background(), "FROM alpine:latest\n", nil)
+	if err != nil {
+		t.Fatalf("findIssues() error = %v", err)
+	}
+	
+	tests := []struct {
+		ruleID   string
+		line     int
+		column   int
+		severity string
+		context  string
+	}{
+		{"DL3007", 1, 0, "warning", "https://github.com/hadolint/hadolint/wiki/DL3007"},
+		{"SC2086", 3, 12, "info", "https://www.shellcheck.net/wiki/SC2086"},
+		{"DL3059", 4, 0, "suggestion", "https://github.com/hadolint/hadolint/wiki/DL3059"},
+	}
+	if len(issues) != len(tests) {
+		t.Fatalf("got %d issues, want %d: %+v", len(issues), len(tests), issues)
+	}
+	for i, tt := range tests {
+		t.Run(tt.ruleID, func(t *testing.T) {
+			issue := issues[i]
+			if issue.RuleID != tt.ruleID || issue.Line != tt.line || issue.Severity != tt.severity || issue.Context != tt.context {
+				t.Errorf("issue = %+v, want %s at line %d (%s, %s)", issue, tt.ruleID, tt.line, tt.severity, tt.context)
+			}
+			
+			// Instruction-level findings carry no column
+			if tt.column == 0 && issue.Column != nil {
+				t.Errorf("column = %d, want none", *issue.Column)
+			}
+			if tt.column != 0 && (issue.Column == nil || *issue.Column != tt.column) {
+				t.Errorf("column = %v, want %d", issue.Column, tt.column)
+			}
+		})
+	}
+}
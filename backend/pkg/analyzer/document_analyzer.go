@@ -0,0 +1,438 @@
+package analyzer
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var (
+	// markdownFence matches the opening or closing line of a fenced code block
+	markdownFence = regexp.MustCompile("^([ \t]*)(`{3,}|~{3,})[ \t]*(.*)$")
+	
+	// htmlScript matches a script element with its attributes and contents
+	htmlScript = regexp.MustCompile(`(?is)<script\b([^>]*)>(.*?)</script\s*>`)
+	
+	// htmlComment matches an HTML comment, which may hide script elements
+	htmlComment = regexp.MustCompile(`(?s)<!--.*?-->`)
+	
+	// skipComment matches the comment marking the next block as intentionally broken
+	skipComment = regexp.MustCompile(`<!--\s*codehawk:skip\s*-->\s*$`)
+	
+	// scriptAttribute matches the type and lang attributes of a script element
+	scriptAttribute = regexp.MustCompile(`(?i)\b(type|lang)\s*=\s*["']?([^"'\s>]+)`)
+	
+	// scriptSource matches the src attribute of a script element
+	scriptSource = regexp.MustCompile(`(?i)\bsrc\s*=`)
+)
+
+// blockLanguages maps the languages named by fenced code blocks to the linters' languages
+var blockLanguages = map[string]string{
+	"py":         "python",
+	"python3":    "python",
+	"js":         "javascript",
+	"mjs":        "javascript",
+	"cjs":        "javascript",
+	"node":       "javascript",
+	"jsx":        "javascript",
+	"ts":         "typescript",
+	"tsx":        "typescript",
+	"vue":        "javascript",
+	"svelte":     "javascript",
+	"golang":     "go",
+	"rs":         "rust",
+	"sh":         "shell",
+	"bash":       "shell",
+	"zsh":        "shell",
+	"docker":     "dockerfile",
+	"k8s":        "kubernetes",
+	"hcl":        "terraform",
+	"tf":         "terraform",
+	"postgres":   "sql",
+	"postgresql": "sql",
+	"h":          "c",
+	"c++":        "cpp",
+	"cc":         "cpp",
+	"cxx":        "cpp",
+	"hpp":        "cpp",
+	"md":         "markdown",
+	"htm":        "html",
+}
+
+// componentBlocks are the languages of fenced code blocks whose linter tells them apart by
+// file extension, such as JSX for the JavaScript linter
+var componentBlocks = map[string]bool{
+	"jsx":    true,
+	"tsx":    true,
+	"vue":    true,
+	"svelte": true,
+}
+
+// scriptLanguages maps the type or lang attribute of a script element to the linters' languages;
+// scripts of other types, such as JSON data or templates, are not code
+var scriptLanguages = map[string]string{
+	"":                       "javascript",
+	"module":                 "javascript",
+	"text/javascript":        "javascript",
+	"application/javascript": "javascript",
+	"js":                     "javascript",
+	"text/typescript":        "typescript",
+	"application/typescript": "typescript",
+	"ts":                     "typescript",
+}
+
+// embeddedBlock is a block of code embedded in a document. The code of fenced blocks has the
+// fence's indentation removed, so each of its lines is located in the document separately.
+type embeddedBlock struct {
+	language    string
+	code        string
+	lineOffsets []int  // offset in the document of each line of the code
+	end         int    // offset in the document of the end of the code
+	indent      string // indentation of the fence, removed from the lines of the code
+	path        string // file name the block is analyzed as, for component blocks
+}
+
+// DocumentAnalyzer implements the Linter interface for Markdown and HTML documents. The code
+// embedded in a document, in fenced code blocks or script elements, is analyzed by the linter
+// registered for its language, and the issues are reported at their place in the document.
+type DocumentAnalyzer struct {
+	*BaseAnalyzer
+	language string
+	registry *LinterRegistry
+}
+
+// NewMarkdownAnalyzer creates an analyzer for the code blocks of Markdown documents
+func NewMarkdownAnalyzer(config map[string]string, registry *LinterRegistry) *DocumentAnalyzer {
+	return newDocumentAnalyzer("markdown", config, registry)
+}
+
+// NewHTMLAnalyzer creates an analyzer for the scripts of HTML documents
+func NewHTMLAnalyzer(config map[string]string, registry *LinterRegistry) *DocumentAnalyzer {
+	return newDocumentAnalyzer("html", config, registry)
+}
+
+// newDocumentAnalyzer creates a document analyzer for the given language
+func newDocumentAnalyzer(language string, config map[string]string, registry *LinterRegistry) *DocumentAnalyzer {
+	return &DocumentAnalyzer{
+		BaseAnalyzer: NewBaseAnalyzer(config),
+		language:     language,
+		registry:     registry,
+	}
+}
+
+// Language returns the identifier for the supported language
+func (a *DocumentAnalyzer) Language() string {
+	return a.language
+}
+
+// Analyze analyzes the code embedded in the document. Blocks in a language without a linter
+// are left alone, and so are the blocks marked as intentionally broken: fenced blocks with one
+// of the "skipMarkers" in their info string, e.g. ```go nolint, and blocks preceded by a
+// <!-- codehawk:skip --> comment.
+func (a *DocumentAnalyzer) Analyze(ctx context.Context, code string, options map[string]interface{}) (*AnalysisResult, error) {
+	ctx, cancel := a.CreateTimeoutContext(ctx)
+	defer cancel()
+	
+	var blocks []embeddedBlock
+	if a.language == "markdown" {
+		markers := a.GetListOption(options, "skipMarkers", []string{"nolint", "skip", "ignore", "compile_fail"})
+		blocks = markdownBlocks(code, markers)
+	} else {
+		blocks = scriptBlocks(code)
+	}
+	
+	result := &AnalysisResult{
+		Issues:      make([]Issue, 0),
+		Suggestions: make([]Issue, 0),
+	}
+	for _, block := range blocks {
+		linter, ok := a.registry.GetLinter(block.language)
+		if !ok {
+			continue
+		}
+		
+		// The options apply to every block, except the document's own file name
+		blockOptions := make(map[string]interface{}, len(options))
+		for key, value := range options {
+			if key != "path" {
+				blockOptions[key] = value
+			}
+		}
+		if block.path != "" {
+			blockOptions["path"] = block.path
+		}
+		
+		blockResult, err := linter.Analyze(ctx, block.code, blockOptions)
+		if err != nil {
+			// Log the error but continue with the other blocks
+			line, _ := offsetToPosition(code, block.lineOffsets[0])
+			fmt.Printf("Warning: analysis of the %s block at line %d failed: %v\n", block.language, line, err)
+			continue
+		}
+		for _, issue := range blockResult.Issues {
+			result.Issues = append(result.Issues, block.mapIssue(code, issue))
+		}
+		for _, suggestion := range blockResult.Suggestions {
+			result.Suggestions = append(result.Suggestions, block.mapIssue(code, suggestion))
+		}
+	}
+	
+	return result, nil
+}
+
+// SuggestFixes returns no fixes of its own; the fixes of the embedded code come with the
+// issues of each block's linter
+func (a *DocumentAnalyzer) SuggestFixes(ctx context.Context, code string, issues []Issue) ([]Issue, error) {
+	return []Issue{}, nil
+}
+
+// markdownBlocks extracts the fenced code blocks of a Markdown document, and the scripts
+// outside of them
+func markdownBlocks(document string, skipMarkers []string) []embeddedBlock {
+	skipped := make(map[string]bool, len(skipMarkers))
+	for _, marker := range skipMarkers {
+		skipped[strings.ToLower(marker)] = true
+	}
+	
+	blocks := make([]embeddedBlock, 0)
+	lines := strings.SplitAfter(document, "\n")
+	// A final line break does not start another line, which would end an unclosed block
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	
+	// Fenced blocks are blanked out of the text searched for scripts, without turning into
+	// whitespace that would let a skip comment reach past them
+	var prose strings.Builder
+	offset := 0
+	for i := 0; i < len(lines); i++ {
+		opening := markdownFence.FindStringSubmatch(strings.TrimRight(lines[i], "\r\n"))
+		if opening == nil {
+			prose.WriteString(lines[i])
+			offset += len(lines[i])
+			continue
+		}
+		indent, fence := opening[1], opening[2]
+		
+		// The info string names the language first, then any attributes
+		info := strings.FieldsFunc(strings.ToLower(opening[3]), func(r rune) bool {
+			return r == ' ' || r == '\t' || r == ',' || r == '{' || r == '}'
+		})
+		language, path := "", ""
+		skip := skipComment.MatchString(document[:offset])
+		for j, word := range info {
+			word = strings.TrimPrefix(word, ".")
+			if j == 0 {
+				language = word
+				if alias, ok := blockLanguages[word]; ok {
+					language = alias
+				}
+				if componentBlocks[word] {
+					path = "code." + word
+				}
+			} else if skipped[word] {
+				skip = true
+			}
+		}
+		
+		block := embeddedBlock{language: language, indent: indent, path: path}
+		var code strings.Builder
+		start := offset
+		offset += len(lines[i])
+		closed := false
+		for i++; i < len(lines); i++ {
+			line := lines[i]
+			closing := markdownFence.FindStringSubmatch(strings.TrimRight(line, "\r\n"))
+			if closing != nil && closing[2][0] == fence[0] && len(closing[2]) >= len(fence) && closing[3] == "" {
+				block.end = offset + len(closing[1])
+				offset += len(line)
+				closed = true
+				break
+			}
+			
+			// Lines lose up to the fence's indentation
+			removed := 0
+			for removed < len(indent) && removed < len(line) && (line[removed] == ' ' || line[removed] == '\t') {
+				removed++
+			}
+			block.lineOffsets = append(block.lineOffsets, offset+removed)
+			code.WriteString(strings.TrimRight(line[removed:], "\n"))
+			code.WriteString("\n")
+			offset += len(line)
+		}
+		if !closed {
+			block.end = len(document)
+		}
+		prose.WriteString(strings.Repeat("\x00", offset-start))
+		
+		block.code = code.String()
+		
+		// Vue and Svelte components whose scripts are TypeScript go to the TypeScript linter
+		if (path == "code.vue" || path == "code.svelte") && componentUsesTypeScript(block.code) {
+			block.language = "typescript"
+		}
+		
+		if !skip && language != "" && strings.TrimSpace(block.code) != "" {
+			blocks = append(blocks, block)
+		}
+	}
+	
+	return append(blocks, scriptBlocks(prose.String())...)
+}
+
+// componentUsesTypeScript reports whether a script of a Vue or Svelte component is written in
+// TypeScript, e.g. <script setup lang="ts">
+func componentUsesTypeScript(component string) bool {
+	for _, match := range htmlScript.FindAllStringSubmatch(component, -1) {
+		for _, attribute := range scriptAttribute.FindAllStringSubmatch(match[1], -1) {
+			if strings.EqualFold(attribute[1], "lang") && (strings.EqualFold(attribute[2], "ts") || strings.EqualFold(attribute[2], "typescript")) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// scriptBlocks extracts the scripts of an HTML document, except for those in comments and
+// those loaded from elsewhere
+func scriptBlocks(document string) []embeddedBlock {
+	comments := htmlComment.FindAllStringIndex(document, -1)
+	
+	blocks := make([]embeddedBlock, 0)
+	for _, match := range htmlScript.FindAllStringSubmatchIndex(document, -1) {
+		commented := false
+		for _, comment := range comments {
+			if match[0] >= comment[0] && match[0] < comment[1] {
+				commented = true
+				break
+			}
+		}
+		if commented || skipComment.MatchString(document[:match[0]]) {
+			continue
+		}
+		
+		attributes := document[match[2]:match[3]]
+		code := document[match[4]:match[5]]
+		if strings.TrimSpace(code) == "" || scriptSource.MatchString(attributes) {
+			continue
+		}
+		
+		scriptType := ""
+		for _, attribute := range scriptAttribute.FindAllStringSubmatch(attributes, -1) {
+			scriptType = strings.ToLower(attribute[2])
+		}
+		language, ok := scriptLanguages[scriptType]
+		if !ok {
+			continue
+		}
+		
+		// Scripts are taken verbatim, so their lines follow each other in the document
+		block := embeddedBlock{
+			language:    language,
+			code:        code,
+			lineOffsets: []int{match[4]},
+			end:         match[5],
+		}
+		for i := 0; i < len(code); i++ {
+			if code[i] == '\n' {
+				block.lineOffsets = append(block.lineOffsets, match[4]+i+1)
+			}
+		}
+		blocks = append(blocks, block)
+	}
+	
+	return blocks
+}
+
+// documentOffset maps an offset in the code of the block to the document
+func (b *embeddedBlock) documentOffset(offset int) int {
+	line, _ := offsetToPosition(b.code, offset)
+	if line > len(b.lineOffsets) {
+		return b.end
+	}
+	return b.lineOffsets[line-1] + offset - positionToOffset(b.code, line, 1)
+}
+
+// mapIssue moves an issue found in the block, with its fix and related locations, to its
+// place in the document
+func (b *embeddedBlock) mapIssue(document string, issue Issue) Issue {
+	if issue.Fix != nil {
+		issue.Fix = b.mapFix(document, issue.Line, *issue.Fix)
+	}
+	
+	issue.Line, issue.Column = b.mapPosition(document, issue.Line, issue.Column)
+	if len(issue.RelatedLocations) > 0 {
+		related := make([]RelatedLocation, len(issue.RelatedLocations))
+		for i, location := range issue.RelatedLocations {
+			column := &location.Column
+			if location.Column == 0 {
+				column = nil
+			}
+			location.Line, column = b.mapPosition(document, location.Line, column)
+			if column != nil {
+				location.Column = *column
+			}
+			related[i] = location
+		}
+		issue.RelatedLocations = related
+	}
+	
+	return issue
+}
+
+// mapPosition maps a line and optional column of the block to the document. Issues of the
+// whole block are reported on its first line.
+func (b *embeddedBlock) mapPosition(document string, line int, column *int) (int, *int) {
+	if line < 1 {
+		line = 1
+	}
+	if line > len(b.lineOffsets) {
+		line = len(b.lineOffsets)
+	}
+	
+	col := 1
+	if column != nil {
+		col = *column
+	}
+	mappedLine, mappedColumn := offsetToPosition(document, b.documentOffset(positionToOffset(b.code, line, col)))
+	if column == nil {
+		return mappedLine, nil
+	}
+	return mappedLine, &mappedColumn
+}
+
+// mapFix turns a fix of the block into a fix of the document. Fixes without a range replace
+// the line of the block rather than the line of the document, so they are given one.
+func (b *embeddedBlock) mapFix(document string, line int, fix IssueFix) *IssueFix {
+	var start, end int
+	if fix.Range == nil {
+		if line < 1 || line > len(b.lineOffsets) {
+			return nil
+		}
+		start = positionToOffset(b.code, line, 1)
+		end = start + len(strings.SplitN(b.code[start:], "\n", 2)[0])
+	} else {
+		start = positionToOffset(b.code, fix.Range.StartLine, fix.Range.StartColumn)
+		end = positionToOffset(b.code, fix.Range.EndLine, fix.Range.EndColumn)
+	}
+	
+	// New lines of a fenced block are indented like the fence
+	replacement := fix.Replacement
+	if b.indent != "" {
+		replacement = strings.ReplaceAll(replacement, "\n", "\n"+b.indent)
+	}
+	
+	startLine, startColumn := offsetToPosition(document, b.documentOffset(start))
+	endLine, endColumn := offsetToPosition(document, b.documentOffset(end))
+	return &IssueFix{
+		Description: fix.Description,
+		Replacement: replacement,
+		Range: &FixRange{
+			StartLine:   startLine,
+			StartColumn: startColumn,
+			EndLine:     endLine,
+			EndColumn:   endColumn,
+		},
+	}
+}
@@ -0,0 +1,364 @@
+package analyzer
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+// stubLinter returns the same issues for every block it analyzes and records the blocks
+type stubLinter struct {
+	language string
+	issues   []Issue
+	err      error
+	codes    []string
+	options  []map[string]interface{}
+}
+
+func (l *stubLinter) Language() string {
+	return l.language
+}
+
+func (l *stubLinter) Analyze(ctx context.Context, code string, options map[string]interface{}) (*AnalysisResult, error) {
+	l.codes = append(l.codes, code)
+	l.options = append(l.options, options)
+	if l.err != nil {
+		return nil, l.err
+	}
+	issues := make([]Issue, len(l.issues))
+	copy(issues, l.issues)
+	return &AnalysisResult{Issues: issues}, nil
+}
+
+func (l *stubLinter) SuggestFixes(ctx context.Context, code string, issues []Issue) ([]Issue, error) {
+	return []Issue{}, nil
+}
+
+// blockSummary is the part of an embedded block the extraction tests compare
+type blockSummary struct {
+	language string
+	code     string
+	path     string
+}
+
+func summarizeBlocks(blocks []embeddedBlock) []blockSummary {
+	summaries := make([]blockSummary, 0, len(blocks))
+	for _, block := range blocks {
+		summaries = append(summaries, blockSummary{block.language, block.code, block.path})
+	}
+	return summaries
+}
+
+func TestMarkdownBlocks(t *testing.T) {
+	defaultMarkers := []string{"nolint", "skip", "ignore", "compile_fail"}
+	
+	tests := []struct {
+		name     string
+		document string
+		markers  []string
+		want     []blockSummary
+	}{
+		{
+			name:     "fenced block",
+			document: "```go\npackage main\n```\n",
+			want:     []blockSummary{{"go", "package main\n", ""}},
+		},
+		{
+			name:     "language alias",
+			document: "Intro\n```py\nx = 1\n```\n",
+			want:     []blockSummary{{"python", "x = 1\n", ""}},
+		},
+		{
+			name:     "indented fence loses its indentation",
+			document: "- item\n\n   ```js\n   let a\n     b\n   ```\n",
+			want:     []blockSummary{{"javascript", "let a\n  b\n", ""}},
+		},
+		{
+			name:     "fence closed by its own character",
+			document: "~~~python\n```\n~~~\n",
+			want:     []blockSummary{{"python", "```\n", ""}},
+		},
+		{
+			name:     "fence closed by one at least as long",
+			document: "````go\n```\nx\n````\n",
+			want:     []blockSummary{{"go", "```\nx\n", ""}},
+		},
+		{
+			name:     "skip markers in the info string",
+			document: "```go nolint\nbad\n```\n```rust,compile_fail\nbad\n```\n```{.python .skip}\nbad\n```\n",
+			want:     []blockSummary{},
+		},
+		{
+			name:     "custom skip markers",
+			document: "```go wip\nbad\n```\n```go nolint\nok\n```\n",
+			markers:  []string{"WIP"},
+			want:     []blockSummary{{"go", "ok\n", ""}},
+		},
+		{
+			name:     "skip comment only skips the next block",
+			document: "<!-- codehawk:skip -->\n```go\nbad\n```\n```go\nok\n```\n",
+			want:     []blockSummary{{"go", "ok\n", ""}},
+		},
+		{
+			name:     "blocks without a language or code",
+			document: "```\nplain text\n```\n```go\n\n```\n",
+			want:     []blockSummary{},
+		},
+		{
+			name:     "unclosed fence runs to the end",
+			document: "```sh\necho hi\n",
+			want:     []blockSummary{{"shell", "echo hi\n", ""}},
+		},
+		{
+			name:     "components are analyzed under a file name",
+			document: "```tsx\nconst a = <b />\n```\n```svelte\n<script>\nlet a = 1\n</script>\n```\n",
+			want: []blockSummary{
+				{"typescript", "const a = <b />\n", "code.tsx"},
+				{"javascript", "<script>\nlet a = 1\n</script>\n", "code.svelte"},
+			},
+		},
+		{
+			name:     "vue component with a typescript script",
+			document: "```vue\n<template><p/></template>\n<script setup lang=\"ts\">\nconst a: number = 1\n</script>\n```\n",
+			want:     []blockSummary{{"typescript", "<template><p/></template>\n<script setup lang=\"ts\">\nconst a: number = 1\n</script>\n", "code.vue"}},
+		},
+		{
+			name:     "scripts outside fenced blocks",
+			document: "Text\n<script>\nlet a = 1\n</script>\n```html\n<script>b()</script>\n```\n",
+			want: []blockSummary{
+				{"html", "<script>b()</script>\n", ""},
+				{"javascript", "\nlet a = 1\n", ""},
+			},
+		},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			markers := tt.markers
+			if markers == nil {
+				markers = defaultMarkers
+			}
+			got := summarizeBlocks(markdownBlocks(tt.document, markers))
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestScriptBlocks(t *testing.T) {
+	tests := []struct {
+		name     string
+		document string
+		want     []blockSummary
+	}{
+		{
+			name:     "module",
+			document: `<script type="module">a()</script>`,
+			want:     []blockSummary{{"javascript", "a()", ""}},
+		},
+		{
+			name:     "typescript",
+			document: `<script lang="ts">let a: number</script>`,
+			want:     []blockSummary{{"typescript", "let a: number", ""}},
+		},
+		{
+			name:     "upper case",
+			document: `<SCRIPT TYPE="text/javascript">a()</SCRIPT >`,
+			want:     []blockSummary{{"javascript", "a()", ""}},
+		},
+		{
+			name:     "data and templates",
+			document: `<script type="application/json">{}</script><script type="text/x-template"><div></div></script>`,
+			want:     []blockSummary{},
+		},
+		{
+			name:     "external scripts",
+			document: `<script src="x.js"></script><script src="y.js">z()</script>`,
+			want:     []blockSummary{},
+		},
+		{
+			name:     "commented out",
+			document: "<!-- <script>a()</script> -->",
+			want:     []blockSummary{},
+		},
+		{
+			name:     "skip comment",
+			document: "<!-- codehawk:skip -->\n<script>bad(</script><script>ok()</script>",
+			want:     []blockSummary{{"javascript", "ok()", ""}},
+		},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := summarizeBlocks(scriptBlocks(tt.document))
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestComponentUsesTypeScript(t *testing.T) {
+	tests := []struct {
+		component string
+		want      bool
+	}{
+		{`<script setup lang="ts">`, false},
+		{`<script setup lang="ts">let a</script>`, true},
+		{`<script lang='typescript'></script>`, true},
+		{`<script LANG=TS></script>`, true},
+		{`<script>let a</script><script lang="ts">let b</script>`, true},
+		{`<script>let a</script>`, false},
+		{`<script type="ts">let a</script>`, false},
+		{`<template lang="ts"></template>`, false},
+	}
+	
+	for _, tt := range tests {
+		if got := componentUsesTypeScript(tt.component); got != tt.want {
+			t.Errorf("componentUsesTypeScript(%q) = %v, want %v", tt.component, got, tt.want)
+		}
+	}
+}
+
+func TestDocumentAnalyzerMarkdown(t *testing.T) {
+	document := "# Title\n\n1. Step\n\n   ```go\n   func main() {\n   \tx := 1\n   }\n   ```\n\n```python\nprint(1)\n```\n\n```rust\nfn main() {}\n```\n\n```text\nnot code\n```\n\n```vue\n<script lang=\"ts\">\nlet a = 1\n</script>\n```\n"
+	
+	column := func(c int) *int { return &c }
+	goLinter := &stubLinter{language: "go", issues: []Issue{
+		{
+			Line:             2,
+			Column:           column(2),
+			RuleID:           "unused",
+			RelatedLocations: []RelatedLocation{{Line: 1, Column: 6, Message: "main"}, {Line: 3, Message: "end"}},
+			Fix:              &IssueFix{Range: &FixRange{StartLine: 2, StartColumn: 1, EndLine: 3, EndColumn: 1}},
+		},
+		{Line: 3, RuleID: "append", Fix: &IssueFix{Replacement: "}\nfunc other() {}"}},
+		{RuleID: "file-level"},
+	}}
+	pythonLinter := &stubLinter{language: "python", issues: []Issue{{Line: 1, Column: column(1), RuleID: "T201"}}}
+	rustLinter := &stubLinter{language: "rust", err: errors.New("rustc not found")}
+	typescriptLinter := &stubLinter{language: "typescript"}
+	
+	registry := NewLinterRegistry()
+	for _, linter := range []*stubLinter{goLinter, pythonLinter, rustLinter, typescriptLinter} {
+		registry.Register(linter)
+	}
+	
+	result, err := NewMarkdownAnalyzer(nil, registry).Analyze(context.Background(), document, map[string]interface{}{"path": "README.md", "maxLineLength": 100})
+	if err != nil {
+		t.Fatal(err)
+	}
+	
+	if want := []string{"func main() {\n\tx := 1\n}\n"}; !reflect.DeepEqual(goLinter.codes, want) {
+		t.Errorf("go linter got %q, want %q", goLinter.codes, want)
+	}
+	if want := []map[string]interface{}{{"maxLineLength": 100}}; !reflect.DeepEqual(goLinter.options, want) {
+		t.Errorf("go linter got options %v, want %v", goLinter.options, want)
+	}
+	if len(rustLinter.codes) != 1 {
+		t.Errorf("rust linter got %d blocks, want 1", len(rustLinter.codes))
+	}
+	if len(typescriptLinter.options) != 1 || typescriptLinter.options[0]["path"] != "code.vue" {
+		t.Errorf("typescript linter got options %v, want the vue component's path", typescriptLinter.options)
+	}
+	
+	tests := []struct {
+		ruleID      string
+		wantLine    int
+		wantColumn  *int
+		wantRelated []RelatedLocation
+		wantFixed   string
+	}{
+		{
+			ruleID:      "unused",
+			wantLine:    7,
+			wantColumn:  column(5),
+			wantRelated: []RelatedLocation{{Line: 6, Column: 9, Message: "main"}, {Line: 8, Message: "end"}},
+			wantFixed:   "# Title\n\n1. Step\n\n   ```go\n   func main() {\n   }\n   ```\n",
+		},
+		{
+			ruleID:    "append",
+			wantLine:  8,
+			wantFixed: "# Title\n\n1. Step\n\n   ```go\n   func main() {\n   \tx := 1\n   }\n   func other() {}\n   ```\n",
+		},
+		{
+			ruleID:   "file-level",
+			wantLine: 6,
+		},
+		{
+			ruleID:     "T201",
+			wantLine:   12,
+			wantColumn: column(1),
+		},
+	}
+	
+	if len(result.Issues) != len(tests) {
+		t.Fatalf("got %d issues, want %d: %+v", len(result.Issues), len(tests), result.Issues)
+	}
+	for i, tt := range tests {
+		t.Run(tt.ruleID, func(t *testing.T) {
+			issue := result.Issues[i]
+			if issue.RuleID != tt.ruleID || issue.Line != tt.wantLine || !reflect.DeepEqual(issue.Column, tt.wantColumn) {
+				t.Errorf("got %s at %d:%v, want %s at %d:%v", issue.RuleID, issue.Line, issue.Column, tt.ruleID, tt.wantLine, tt.wantColumn)
+			}
+			if !reflect.DeepEqual(issue.RelatedLocations, tt.wantRelated) {
+				t.Errorf("got related locations %+v, want %+v", issue.RelatedLocations, tt.wantRelated)
+			}
+			if tt.wantFixed == "" {
+				if issue.Fix != nil {
+					t.Errorf("got fix %+v, want none", issue.Fix)
+				}
+				return
+			}
+			if issue.Fix == nil {
+				t.Fatal("got no fix")
+			}
+			fixed, err := ApplyFix(document, issue.Line, *issue.Fix)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if fixed[:len(tt.wantFixed)] != tt.wantFixed {
+				t.Errorf("got fixed document\n%s\nwant it to start with\n%s", fixed, tt.wantFixed)
+			}
+		})
+	}
+}
+
+func TestDocumentAnalyzerHTML(t *testing.T) {
+	document := "<html>\n<script type=\"module\">\nconst a = 1;\n  let b = 2;\n</script>\n<script lang=\"ts\">let c: number</script>\n</html>\n"
+	
+	column := 3
+	javascriptLinter := &stubLinter{language: "javascript", issues: []Issue{
+		{Line: 3, Column: &column, RuleID: "prefer-const", Fix: &IssueFix{Replacement: "const", Range: &FixRange{StartLine: 3, StartColumn: 3, EndLine: 3, EndColumn: 6}}},
+	}}
+	registry := NewLinterRegistry()
+	registry.Register(javascriptLinter)
+	
+	result, err := NewHTMLAnalyzer(nil, registry).Analyze(context.Background(), document, map[string]interface{}{"path": "index.html"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"\nconst a = 1;\n  let b = 2;\n"}; !reflect.DeepEqual(javascriptLinter.codes, want) {
+		t.Errorf("javascript linter got %q, want %q", javascriptLinter.codes, want)
+	}
+	if _, ok := javascriptLinter.options[0]["path"]; ok {
+		t.Errorf("javascript linter got the document's path: %v", javascriptLinter.options[0])
+	}
+	
+	if len(result.Issues) != 1 {
+		t.Fatalf("got %d issues, want 1: %+v", len(result.Issues), result.Issues)
+	}
+	issue := result.Issues[0]
+	if issue.Line != 4 || issue.Column == nil || *issue.Column != 3 {
+		t.Errorf("got issue at %d:%v, want 4:3", issue.Line, issue.Column)
+	}
+	fixed, err := ApplyFix(document, issue.Line, *issue.Fix)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "<html>\n<script type=\"module\">\nconst a = 1;\n  const b = 2;\n</script>\n"; fixed[:len(want)] != want {
+		t.Errorf("got fixed document\n%s\nwant it to start with\n%s", fixed, want)
+	}
+}
@@ -0,0 +1,156 @@
+package analyzer
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// textEdit is a single replacement of the byte range [start, end) of a source text
+type textEdit struct {
+	start int
+	end   int
+	text  string
+}
+
+// positionToOffset converts a 1-based line and character column into a byte offset in code.
+// Positions past the end of a line or of the code are clamped.
+func positionToOffset(code string, line, column int) int {
+	if line < 1 {
+		return 0
+	}
+	
+	offset := 0
+	for current := 1; current < line; current++ {
+		next := strings.IndexByte(code[offset:], '\n')
+		if next < 0 {
+			return len(code)
+		}
+		offset += next + 1
+	}
+	
+	lineEnd := strings.IndexByte(code[offset:], '\n')
+	if lineEnd < 0 {
+		lineEnd = len(code) - offset
+	}
+	lineText := code[offset : offset+lineEnd]
+	
+	// Walk the line character by character to honour multi-byte runes
+	for col := 1; col < column; col++ {
+		if len(lineText) == 0 {
+			return offset
+		}
+		_, size := utf8.DecodeRuneInString(lineText)
+		lineText = lineText[size:]
+		offset += size
+	}
+	
+	return offset
+}
+
+// offsetToPosition converts a byte offset in code into a 1-based line and character column
+func offsetToPosition(code string, offset int) (int, int) {
+	if offset < 0 {
+		offset = 0
+	}
+	if offset > len(code) {
+		offset = len(code)
+	}
+	
+	prefix := code[:offset]
+	line := strings.Count(prefix, "\n") + 1
+	lineStart := strings.LastIndexByte(prefix, '\n') + 1
+	
+	return line, utf8.RuneCountInString(prefix[lineStart:]) + 1
+}
+
+// buildRangeFix folds a set of edits into one fix covering the smallest range that contains
+// all of them, so that multi-edit fixes can be expressed as a single IssueFix
+func buildRangeFix(code, description string, edits []textEdit) (*IssueFix, error) {
+	if len(edits) == 0 {
+		return nil, fmt.Errorf("fix has no edits")
+	}
+	
+	sorted := make([]textEdit, len(edits))
+	copy(sorted, edits)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].start < sorted[j].start
+	})
+	
+	start := sorted[0].start
+	end := start
+	for _, edit := range sorted {
+		if edit.start < 0 || edit.end > len(code) || edit.start > edit.end {
+			return nil, fmt.Errorf("edit range %d-%d is outside the source", edit.start, edit.end)
+		}
+		if edit.start < end {
+			return nil, fmt.Errorf("overlapping edits at offset %d", edit.start)
+		}
+		end = edit.end
+	}
+	
+	// Apply the edits to the covered region
+	var replacement strings.Builder
+	cursor := start
+	for _, edit := range sorted {
+		replacement.WriteString(code[cursor:edit.start])
+		replacement.WriteString(edit.text)
+		cursor = edit.end
+	}
+	replacement.WriteString(code[cursor:end])
+	
+	startLine, startColumn := offsetToPosition(code, start)
+	endLine, endColumn := offsetToPosition(code, end)
+	
+	return &IssueFix{
+		Description: description,
+		Replacement: replacement.String(),
+		Range: &FixRange{
+			StartLine:   startLine,
+			StartColumn: startColumn,
+			EndLine:     endLine,
+			EndColumn:   endColumn,
+		},
+	}, nil
+}
+
+// fixCoversLine reports whether a range-based fix touches the given line
+func fixCoversLine(fix *IssueFix, line int) bool {
+	if fix.Range == nil {
+		return false
+	}
+	
+	// Pure insertions touch only the line they are inserted before
+	if fix.Range.StartLine == fix.Range.EndLine {
+		return line == fix.Range.StartLine
+	}
+	
+	// A range ending at the start of a line does not touch that line
+	end := fix.Range.EndLine
+	if fix.Range.EndColumn == 1 {
+		end--
+	}
+	
+	return line >= fix.Range.StartLine && line <= end
+}
+
+// ApplyFix applies a fix to code. Fixes without a range replace the given line.
+func ApplyFix(code string, line int, fix IssueFix) (string, error) {
+	if fix.Range == nil {
+		lines := strings.Split(code, "\n")
+		if line <= 0 || line > len(lines) {
+			return "", fmt.Errorf("line %d is outside the source", line)
+		}
+		lines[line-1] = fix.Replacement
+		return strings.Join(lines, "\n"), nil
+	}
+	
+	start := positionToOffset(code, fix.Range.StartLine, fix.Range.StartColumn)
+	end := positionToOffset(code, fix.Range.EndLine, fix.Range.EndColumn)
+	if start > end {
+		return "", fmt.Errorf("fix range is inverted")
+	}
+	
+	return code[:start] + fix.Replacement + code[end:], nil
+}
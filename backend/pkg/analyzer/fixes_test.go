@@ -0,0 +1,211 @@
+package analyzer
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestPositionToOffset(t *testing.T) {
+	code := "ab\ncdé\nf"
+	tests := []struct {
+		name   string
+		line   int
+		column int
+		want   int
+	}{
+		{"start of code", 1, 1, 0},
+		{"within the first line", 1, 2, 1},
+		{"start of the second line", 2, 1, 3},
+		{"after a multi-byte character", 2, 4, 7},
+		{"past the end of a line", 1, 10, 2},
+		{"last line", 3, 2, 9},
+		{"past the last line", 5, 1, 9},
+		{"line before the code", 0, 1, 0},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := positionToOffset(code, tt.line, tt.column); got != tt.want {
+				t.Errorf("positionToOffset(%d, %d) = %d, want %d", tt.line, tt.column, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOffsetToPosition(t *testing.T) {
+	code := "ab\ncdé\nf"
+	tests := []struct {
+		offset     int
+		wantLine   int
+		wantColumn int
+	}{
+		{0, 1, 1},
+		{2, 1, 3},
+		{3, 2, 1},
+		{7, 2, 4},
+		{9, 3, 2},
+		{-1, 1, 1},
+		{100, 3, 2},
+	}
+	
+	for _, tt := range tests {
+		line, column := offsetToPosition(code, tt.offset)
+		if line != tt.wantLine || column != tt.wantColumn {
+			t.Errorf("offsetToPosition(%d) = %d:%d, want %d:%d", tt.offset, line, column, tt.wantLine, tt.wantColumn)
+		}
+	}
+}
+
+func TestBuildRangeFix(t *testing.T) {
+	code := "import os\nimport sys\nx = 1\n"
+	tests := []struct {
+		name      string
+		edits     []textEdit
+		wantErr   bool
+		wantFixed string
+		wantRange FixRange
+	}{
+		{
+			name:      "single edit",
+			edits:     []textEdit{{start: 0, end: 10, text: ""}},
+			wantFixed: "import sys\nx = 1\n",
+			wantRange: FixRange{StartLine: 1, StartColumn: 1, EndLine: 2, EndColumn: 1},
+		},
+		{
+			name: "edits are folded into one range in order",
+			edits: []textEdit{
+				{start: 21, end: 22, text: "y"},
+				{start: 7, end: 9, text: "re"},
+			},
+			wantFixed: "import re\nimport sys\ny = 1\n",
+			wantRange: FixRange{StartLine: 1, StartColumn: 8, EndLine: 3, EndColumn: 2},
+		},
+		{
+			name:      "insertion",
+			edits:     []textEdit{{start: 27, end: 27, text: "y = 2\n"}},
+			wantFixed: "import os\nimport sys\nx = 1\ny = 2\n",
+			wantRange: FixRange{StartLine: 4, StartColumn: 1, EndLine: 4, EndColumn: 1},
+		},
+		{
+			name:    "overlapping edits",
+			edits:   []textEdit{{start: 0, end: 5, text: ""}, {start: 3, end: 8, text: ""}},
+			wantErr: true,
+		},
+		{
+			name:    "edit outside the code",
+			edits:   []textEdit{{start: 20, end: 40, text: ""}},
+			wantErr: true,
+		},
+		{
+			name:    "no edits",
+			wantErr: true,
+		},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fix, err := buildRangeFix(code, "fix", tt.edits)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("buildRangeFix() = %+v, want an error", fix)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("buildRangeFix() error = %v", err)
+			}
+			if *fix.Range != tt.wantRange {
+				t.Errorf("range = %+v, want %+v", *fix.Range, tt.wantRange)
+			}
+			
+			fixed, err := ApplyFix(code, fix.Range.StartLine, *fix)
+			if err != nil {
+				t.Fatalf("ApplyFix() error = %v", err)
+			}
+			if fixed != tt.wantFixed {
+				t.Errorf("fixed code = %q, want %q", fixed, tt.wantFixed)
+			}
+		})
+	}
+}
+
+func TestApplyFix(t *testing.T) {
+	code := "a = 1\nb = 2\nc = 3"
+	tests := []struct {
+		name    string
+		line    int
+		fix     IssueFix
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "line replacement",
+			line: 2,
+			fix:  IssueFix{Replacement: "b = 20"},
+			want: "a = 1\nb = 20\nc = 3",
+		},
+		{
+			name: "range replacement",
+			line: 1,
+			fix:  IssueFix{Replacement: "x", Range: &FixRange{StartLine: 1, StartColumn: 1, EndLine: 1, EndColumn: 2}},
+			want: "x = 1\nb = 2\nc = 3",
+		},
+		{
+			name: "range spanning lines",
+			line: 1,
+			fix:  IssueFix{Replacement: "", Range: &FixRange{StartLine: 1, StartColumn: 6, EndLine: 3, EndColumn: 6}},
+			want: "a = 1",
+		},
+		{
+			name:    "line outside the code",
+			line:    4,
+			fix:     IssueFix{Replacement: "d = 4"},
+			wantErr: true,
+		},
+		{
+			name:    "inverted range",
+			line:    1,
+			fix:     IssueFix{Replacement: "", Range: &FixRange{StartLine: 2, StartColumn: 1, EndLine: 1, EndColumn: 1}},
+			wantErr: true,
+		},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ApplyFix(code, tt.line, tt.fix)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ApplyFix() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && got != tt.want {
+				t.Errorf("ApplyFix() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetListOption(t *testing.T) {
+	b := NewBaseAnalyzer(map[string]string{"backends": "pylint, ruff"})
+	tests := []struct {
+		name    string
+		options map[string]interface{}
+		want    []string
+	}{
+		{"list of strings", map[string]interface{}{"backends": []string{"mypy"}}, []string{"mypy"}},
+		{"decoded JSON list", map[string]interface{}{"backends": []interface{}{"ruff", 1, " mypy "}}, []string{"ruff", "mypy"}},
+		{"comma-separated string", map[string]interface{}{"backends": "ruff,,mypy"}, []string{"ruff", "mypy"}},
+		{"configuration fallback", map[string]interface{}{}, []string{"pylint", "ruff"}},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := b.GetListOption(tt.options, "backends", nil)
+			if strings.Join(got, "|") != strings.Join(tt.want, "|") {
+				t.Errorf("GetListOption() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+	
+	if got := b.GetListOption(nil, "missing", []string{"default"}); len(got) != 1 || got[0] != "default" {
+		t.Errorf("GetListOption() without a value = %v, want the default", got)
+	}
+}
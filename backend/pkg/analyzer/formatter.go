@@ -0,0 +1,74 @@
+package analyzer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+)
+
+// ErrFormatterUnavailable is returned by FormatWith when the formatter could not do its work: its
+// binary is missing or it ran out of time. Other errors mean the formatter rejected the code.
+var ErrFormatterUnavailable = errors.New("formatter unavailable")
+
+// Formatter is implemented by linters that can reformat source code
+type Formatter interface {
+	// Language returns the identifier for the supported language
+	Language() string
+	
+	// FormatCode returns the formatted version of code. Recognised options are
+	// "lineLength" and "quoteStyle" ("single" or "double"), where the tool supports them.
+	FormatCode(ctx context.Context, code string, options map[string]interface{}) (string, error)
+}
+
+// FormatResult represents the result of formatting code
+type FormatResult struct {
+	Formatted string `json:"formatted"`
+	Diff      string `json:"diff"`
+	Changed   bool   `json:"changed"`
+}
+
+// FormatWith formats code with the given formatter and diffs the result against the original
+func FormatWith(ctx context.Context, formatter Formatter, code string, options map[string]interface{}) (*FormatResult, error) {
+	formatted, err := formatter.FormatCode(ctx, code, options)
+	if err != nil {
+		if errors.Is(err, exec.ErrNotFound) || errors.Is(err, os.ErrNotExist) || errors.Is(err, context.DeadlineExceeded) {
+			return nil, fmt.Errorf("%w: formatting %s code failed: %v", ErrFormatterUnavailable, formatter.Language(), err)
+		}
+		return nil, fmt.Errorf("formatting %s code failed: %w", formatter.Language(), err)
+	}
+	
+	return &FormatResult{
+		Formatted: formatted,
+		Diff:      UnifiedDiff(formatFileName(formatter.Language()), code, formatted),
+		Changed:   formatted != code,
+	}, nil
+}
+
+// formatFileName returns the file name used in diff headers for a language
+func formatFileName(language string) string {
+	switch language {
+	case "go":
+		return "main.go"
+	case "python":
+		return "code.py"
+	case "javascript":
+		return "code.js"
+	case "typescript":
+		return "code.ts"
+	case "rust":
+		return "main.rs"
+	default:
+		return "code"
+	}
+}
+
+// formatterRunError describes a failed run of the formatter name. A run the deadline of ctx cut
+// short reports the deadline rather than the signal that killed it.
+func formatterRunError(ctx context.Context, name string, err error, stderr string) error {
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		err = ctxErr
+	}
+	return fmt.Errorf("failed to run %s: %w, stderr: %s", name, err, stderr)
+}
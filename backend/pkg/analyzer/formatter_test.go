@@ -0,0 +1,92 @@
+package analyzer
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+// fakeFormatter formats code by applying a function to it
+type fakeFormatter struct {
+	language string
+	format   func(string) (string, error)
+}
+
+func (f *fakeFormatter) Language() string {
+	return f.language
+}
+
+func (f *fakeFormatter) FormatCode(ctx context.Context, code string, options map[string]interface{}) (string, error) {
+	return f.format(code)
+}
+
+func TestFormatWith(t *testing.T) {
+	tests := []struct {
+		name        string
+		formatter   *fakeFormatter
+		code        string
+		wantChanged bool
+		wantDiff    string
+		wantErr     bool
+	}{
+		{
+			name: "reformatted code",
+			formatter: &fakeFormatter{language: "go", format: func(code string) (string, error) {
+				return strings.ReplaceAll(code, "x:=1", "x := 1"), nil
+			}},
+			code:        "x:=1\n",
+			wantChanged: true,
+			wantDiff:    "--- a/main.go\n+++ b/main.go\n@@ -1 +1 @@\n-x:=1\n+x := 1\n",
+		},
+		{
+			name: "already formatted code",
+			formatter: &fakeFormatter{language: "python", format: func(code string) (string, error) {
+				return code, nil
+			}},
+			code: "x = 1\n",
+		},
+		{
+			name: "formatter failure",
+			formatter: &fakeFormatter{language: "rust", format: func(code string) (string, error) {
+				return "", errors.New("rustfmt: syntax error")
+			}},
+			code:    "fn main( {\n",
+			wantErr: true,
+		},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := FormatWith(context.Background(), tt.formatter, tt.code, nil)
+			if tt.wantErr {
+				if err == nil || !strings.Contains(err.Error(), tt.formatter.language) {
+					t.Fatalf("FormatWith() error = %v, want an error naming the language", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("FormatWith() error = %v", err)
+			}
+			if result.Changed != tt.wantChanged || result.Diff != tt.wantDiff {
+				t.Errorf("FormatWith() = %+v, want changed %v and diff %q", result, tt.wantChanged, tt.wantDiff)
+			}
+		})
+	}
+}
+
+func TestFormatFileName(t *testing.T) {
+	tests := map[string]string{
+		"go":         "main.go",
+		"python":     "code.py",
+		"javascript": "code.js",
+		"typescript": "code.ts",
+		"cobol":      "code",
+	}
+	
+	for language, want := range tests {
+		if got := formatFileName(language); got != want {
+			t.Errorf("formatFileName(%q) = %q, want %q", language, got, want)
+		}
+	}
+}
@@ -0,0 +1,292 @@
+package analyzer
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// genericTodoPattern matches TODO-style markers; the marker words come from the todoMarkers option
+const genericTodoPattern = `\b(%s)\b[:(]?\s*(.*)`
+
+// GenericAnalyzer implements language-agnostic checks. It is used for languages without a
+// dedicated linter, so it only relies on the layout of the text.
+type GenericAnalyzer struct {
+	*BaseAnalyzer
+}
+
+// NewGenericAnalyzer creates a new language-agnostic analyzer
+func NewGenericAnalyzer(config map[string]string) *GenericAnalyzer {
+	return &GenericAnalyzer{
+		BaseAnalyzer: NewBaseAnalyzer(config),
+	}
+}
+
+// Language returns the identifier for the supported language
+func (a *GenericAnalyzer) Language() string {
+	return "generic"
+}
+
+// Analyze analyzes the provided code and returns issues found
+func (a *GenericAnalyzer) Analyze(ctx context.Context, code string, options map[string]interface{}) (*AnalysisResult, error) {
+	return a.BaseAnalyzer.CommonAnalyzeWrapper(
+		ctx,
+		code,
+		options,
+		a.findIssues,
+		a.SuggestFixes,
+	)
+}
+
+// genericCheck collects the issues found in one file
+type genericCheck struct {
+	code     string
+	issues   []Issue
+	disabled map[string]bool
+}
+
+// report records an issue unless its rule has been disabled
+func (c *genericCheck) report(line, column int, ruleID, severity, message string, fix *IssueFix) {
+	if c.disabled[ruleID] {
+		return
+	}
+	
+	issue := Issue{
+		Line:     line,
+		Message:  message,
+		Severity: severity,
+		RuleID:   ruleID,
+		Fix:      fix,
+	}
+	if column > 0 {
+		issue.Column = &column
+	}
+	c.issues = append(c.issues, issue)
+}
+
+// edit builds a fix replacing the byte range [start, end) of the code
+func (c *genericCheck) edit(description string, start, end int, text string) *IssueFix {
+	fix, err := buildRangeFix(c.code, description, []textEdit{{start: start, end: end, text: text}})
+	if err != nil {
+		return nil
+	}
+	return fix
+}
+
+// findIssues runs every enabled check over the code
+func (a *GenericAnalyzer) findIssues(ctx context.Context, code string, options map[string]interface{}) ([]Issue, error) {
+	check := &genericCheck{
+		code:     code,
+		issues:   make([]Issue, 0),
+		disabled: make(map[string]bool),
+	}
+	for _, rule := range a.GetListOption(options, "disabledRules", nil) {
+		check.disabled[rule] = true
+	}
+	
+	if code == "" {
+		return check.issues, nil
+	}
+	
+	a.checkEncoding(check)
+	a.checkLines(check, options)
+	a.checkNesting(check, options)
+	
+	// Missing final newline
+	if !strings.HasSuffix(code, "\n") {
+		lines := strings.Count(code, "\n") + 1
+		check.report(lines, 0, "missing-final-newline", "suggestion",
+			"File does not end with a newline",
+			check.edit("Add a final newline", len(code), len(code), "\n"))
+	}
+	
+	// Very long files
+	maxFileLines := a.GetIntOption(options, "maxFileLines", 1000)
+	if lines := strings.Count(strings.TrimSuffix(code, "\n"), "\n") + 1; maxFileLines > 0 && lines > maxFileLines {
+		check.report(1, 0, "file-too-long", "info",
+			fmt.Sprintf("File has %d lines, more than the limit of %d; consider splitting it", lines, maxFileLines), nil)
+	}
+	
+	return check.issues, nil
+}
+
+// checkEncoding reports byte order marks, invalid UTF-8 and mixed line endings
+func (a *GenericAnalyzer) checkEncoding(check *genericCheck) {
+	code := check.code
+	
+	if strings.HasPrefix(code, "\ufeff") {
+		check.report(1, 1, "byte-order-mark", "warning",
+			"File starts with a UTF-8 byte order mark, which many tools do not expect",
+			check.edit("Remove the byte order mark", 0, len("\ufeff"), ""))
+	}
+	
+	crlf := strings.Count(code, "\r\n")
+	lf := strings.Count(code, "\n") - crlf
+	firstCRLF := true
+	
+	for i, line := range strings.Split(code, "\n") {
+		if !utf8.ValidString(line) {
+			// Report the first invalid byte of the line
+			column := 1
+			for offset, r := range line {
+				if r == utf8.RuneError {
+					if _, size := utf8.DecodeRuneInString(line[offset:]); size == 1 {
+						break
+					}
+				}
+				column++
+			}
+			check.report(i+1, column, "invalid-encoding", "error",
+				"Line contains bytes that are not valid UTF-8", nil)
+		}
+		
+		if crlf > 0 && lf > 0 && strings.HasSuffix(line, "\r") && firstCRLF {
+			firstCRLF = false
+			check.report(i+1, 0, "mixed-line-endings", "warning",
+				fmt.Sprintf("File mixes CRLF (%d lines) and LF (%d lines) line endings", crlf, lf), nil)
+		}
+	}
+}
+
+// checkLines runs the per-line checks: length, trailing whitespace, indentation and TODO markers
+func (a *GenericAnalyzer) checkLines(check *genericCheck, options map[string]interface{}) {
+	maxLineLength := a.GetIntOption(options, "maxLineLength", 120)
+	tabWidth := a.GetIntOption(options, "tabWidth", 4)
+	
+	markers := a.GetListOption(options, "todoMarkers", []string{"TODO", "FIXME", "XXX", "HACK"})
+	for i, marker := range markers {
+		markers[i] = regexp.QuoteMeta(marker)
+	}
+	todoPattern := regexp.MustCompile(fmt.Sprintf(genericTodoPattern, strings.Join(markers, "|")))
+	
+	// The indentation style of the file is the one used by most indented lines
+	tabLines, spaceLines := 0, 0
+	for _, line := range strings.Split(check.code, "\n") {
+		if strings.HasPrefix(line, "\t") {
+			tabLines++
+		} else if strings.HasPrefix(line, " ") && strings.TrimSpace(line) != "" {
+			spaceLines++
+		}
+	}
+	
+	offset := 0
+	for i, line := range strings.Split(check.code, "\n") {
+		lineNumber := i + 1
+		lineStart := offset
+		offset += len(line) + 1
+		line = strings.TrimSuffix(line, "\r")
+		
+		// Line length, counting a tab as tabWidth columns
+		if width := displayWidth(line, tabWidth); maxLineLength > 0 && width > maxLineLength {
+			check.report(lineNumber, maxLineLength+1, "line-too-long", "suggestion",
+				fmt.Sprintf("Line is %d characters long, more than the limit of %d", width, maxLineLength), nil)
+		}
+		
+		// Trailing whitespace
+		if trimmed := strings.TrimRight(line, " \t"); len(trimmed) < len(line) {
+			check.report(lineNumber, utf8.RuneCountInString(trimmed)+1, "trailing-whitespace", "suggestion",
+				"Line has trailing whitespace",
+				check.edit("Remove trailing whitespace", lineStart+len(trimmed), lineStart+len(line), ""))
+		}
+		
+		// Mixed indentation, within the line or against the rest of the file
+		indent := line[:len(line)-len(strings.TrimLeft(line, " \t"))]
+		if indent != "" && indent != line {
+			switch {
+			case strings.Contains(indent, "\t") && strings.Contains(indent, " "):
+				check.report(lineNumber, 1, "mixed-indentation", "warning",
+					"Line is indented with a mix of tabs and spaces", nil)
+			case indent[0] == '\t' && spaceLines > tabLines:
+				check.report(lineNumber, 1, "mixed-indentation", "warning",
+					"Line is indented with tabs, but most of the file uses spaces", nil)
+			case indent[0] == ' ' && tabLines > spaceLines:
+				check.report(lineNumber, 1, "mixed-indentation", "warning",
+					"Line is indented with spaces, but most of the file uses tabs", nil)
+			}
+		}
+		
+		// TODO markers
+		if match := todoPattern.FindStringSubmatchIndex(line); match != nil {
+			marker := line[match[2]:match[3]]
+			text := strings.TrimSpace(line[match[4]:match[5]])
+			message := fmt.Sprintf("%s comment", marker)
+			if text != "" {
+				message = fmt.Sprintf("%s: %s", marker, text)
+			}
+			check.report(lineNumber, utf8.RuneCountInString(line[:match[0]])+1, "todo-comment", "info", message, nil)
+		}
+	}
+}
+
+// checkNesting estimates nesting depth from indentation and reports each block that goes
+// deeper than the maxNestingDepth option
+func (a *GenericAnalyzer) checkNesting(check *genericCheck, options map[string]interface{}) {
+	maxDepth := a.GetIntOption(options, "maxNestingDepth", 5)
+	tabWidth := a.GetIntOption(options, "tabWidth", 4)
+	if maxDepth <= 0 {
+		return
+	}
+	
+	lines := strings.Split(check.code, "\n")
+	widths := make([]int, len(lines))
+	
+	// The indentation unit is the smallest indentation in the file
+	unit := 0
+	for i, line := range lines {
+		line = strings.TrimSuffix(line, "\r")
+		if strings.TrimSpace(line) == "" {
+			widths[i] = -1
+			continue
+		}
+		widths[i] = displayWidth(line[:len(line)-len(strings.TrimLeft(line, " \t"))], tabWidth)
+		if widths[i] > 0 && (unit == 0 || widths[i] < unit) {
+			unit = widths[i]
+		}
+	}
+	if unit == 0 {
+		return
+	}
+	
+	// Single-space indentation is usually alignment rather than nesting
+	if unit < 2 {
+		unit = 2
+	}
+	
+	inDeepBlock := false
+	for i, width := range widths {
+		if width < 0 {
+			continue
+		}
+		depth := width / unit
+		if depth > maxDepth && !inDeepBlock {
+			check.report(i+1, width+1, "deep-nesting", "warning",
+				fmt.Sprintf("Code is nested %d levels deep, more than the limit of %d; consider extracting a function or returning early", depth, maxDepth), nil)
+		}
+		inDeepBlock = depth > maxDepth
+	}
+}
+
+// displayWidth returns the number of columns text occupies, expanding tabs to the next tab stop
+func displayWidth(text string, tabWidth int) int {
+	if tabWidth <= 0 {
+		tabWidth = 1
+	}
+	
+	width := 0
+	for _, r := range text {
+		if r == '\t' {
+			width += tabWidth - width%tabWidth
+		} else {
+			width++
+		}
+	}
+	return width
+}
+
+// SuggestFixes attempts to generate fixes for the identified issues
+func (a *GenericAnalyzer) SuggestFixes(ctx context.Context, code string, issues []Issue) ([]Issue, error) {
+	// Fixes for whitespace issues are attached to the issues they belong to
+	return []Issue{}, nil
+}
@@ -0,0 +1,164 @@
+package analyzer
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestGenericFindIssues(t *testing.T) {
+	tests := []struct {
+		name      string
+		code      string
+		options   map[string]interface{}
+		want      []string
+		wantFixed string
+	}{
+		{
+			name: "clean file",
+			code: "first line\n    indented\n",
+			want: []string{},
+		},
+		{
+			name: "empty file",
+			code: "",
+			want: []string{},
+		},
+		{
+			name:      "missing final newline",
+			code:      "a\nb",
+			want:      []string{"missing-final-newline"},
+			wantFixed: "a\nb\n",
+		},
+		{
+			name:      "trailing whitespace",
+			code:      "a  \t\nb\n",
+			want:      []string{"trailing-whitespace"},
+			wantFixed: "a\nb\n",
+		},
+		{
+			name:      "byte order mark",
+			code:      "\ufeffa\n",
+			want:      []string{"byte-order-mark"},
+			wantFixed: "a\n",
+		},
+		{
+			name: "invalid encoding and mixed line endings",
+			code: "a\r\nb\xff\nc\r\n",
+			want: []string{"mixed-line-endings", "invalid-encoding"},
+		},
+		{
+			name:    "long lines count tabs",
+			code:    "\tabcdef\n    abcdef\n",
+			options: map[string]interface{}{"maxLineLength": 10, "tabWidth": 8},
+			want:    []string{"line-too-long"},
+		},
+		{
+			name: "mixed indentation",
+			code: "a\n  b\n  c\n\td\n \te\n",
+			want: []string{"mixed-indentation", "mixed-indentation"},
+		},
+		{
+			name:    "todo markers",
+			code:    "// TODO: remove\n// NOTE(x) keep\n// todo lowercase\n",
+			options: map[string]interface{}{"todoMarkers": "TODO,NOTE"},
+			want:    []string{"todo-comment", "todo-comment"},
+		},
+		{
+			name:    "deep nesting is reported once per block",
+			code:    "a\n  b\n    c\n      d\n        e\n    f\n      g\n",
+			options: map[string]interface{}{"maxNestingDepth": 2},
+			want:    []string{"deep-nesting", "deep-nesting"},
+		},
+		{
+			name:    "file too long",
+			code:    "a\nb\nc\n",
+			options: map[string]interface{}{"maxFileLines": 2},
+			want:    []string{"file-too-long"},
+		},
+		{
+			name:    "disabled rules",
+			code:    "a \nb",
+			options: map[string]interface{}{"disabledRules": "trailing-whitespace,missing-final-newline"},
+			want:    []string{},
+		},
+	}
+	
+	a := NewGenericAnalyzer(map[string]string{})
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			issues, err := a.findIssues(context.Background(), tt.code, tt.options)
+			if err != nil {
+				t.Fatalf("findIssues() error = %v", err)
+			}
+			if len(issues) != len(tt.want) {
+				t.Fatalf("got %d issues, want %v: %+v", len(issues), tt.want, issues)
+			}
+			for i, issue := range issues {
+				if issue.RuleID != tt.want[i] {
+					t.Errorf("issue %d = %s (%s), want %s", i, issue.RuleID, issue.Message, tt.want[i])
+				}
+			}
+			
+			if tt.wantFixed != "" {
+				if issues[0].Fix == nil {
+					t.Fatal("got no fix")
+				}
+				fixed, err := ApplyFix(tt.code, issues[0].Line, *issues[0].Fix)
+				if err != nil || fixed != tt.wantFixed {
+					t.Errorf("fixed code = %q (%v), want %q", fixed, err, tt.wantFixed)
+				}
+			}
+		})
+	}
+}
+
+func TestGenericIssuePositions(t *testing.T) {
+	a := NewGenericAnalyzer(map[string]string{})
+	issues, err := a.findIssues(context.Background(), "ok\nbad\xffbyte\n// FIXME: later\n", nil)
+	if err != nil {
+		t.Fatalf("findIssues() error = %v", err)
+	}
+	
+	tests := []struct {
+		ruleID string
+		line   int
+		column int
+		text   string
+	}{
+		{"invalid-encoding", 2, 4, ""},
+		{"todo-comment", 3, 4, "FIXME: later"},
+	}
+	if len(issues) != len(tests) {
+		t.Fatalf("got %d issues, want %d: %+v", len(issues), len(tests), issues)
+	}
+	for i, tt := range tests {
+		issue := issues[i]
+		if issue.RuleID != tt.ruleID || issue.Line != tt.line || issue.Column == nil || *issue.Column != tt.column {
+			t.Errorf("issue %d = %+v, want %s at %d:%d", i, issue, tt.ruleID, tt.line, tt.column)
+		}
+		if tt.text != "" && !strings.Contains(issue.Message, tt.text) {
+			t.Errorf("issue %d message = %q, want it to contain %q", i, issue.Message, tt.text)
+		}
+	}
+}
+
+func TestDisplayWidth(t *testing.T) {
+	tests := []struct {
+		text     string
+		tabWidth int
+		want     int
+	}{
+		{"abc", 4, 3},
+		{"\tx", 4, 5},
+		{"ab\tx", 4, 5},
+		{"äö", 4, 2},
+		{"\t", 0, 1},
+	}
+	
+	for _, tt := range tests {
+		if got := displayWidth(tt.text, tt.tabWidth); got != tt.want {
+			t.Errorf("displayWidth(%q, %d) = %d, want %d", tt.text, tt.tabWidth, got, tt.want)
+		}
+	}
+}
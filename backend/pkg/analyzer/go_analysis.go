@@ -0,0 +1,180 @@
+package analyzer
+
+import (
+	"context"
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"os"
+	"sort"
+
+	"github.com/yourusername/codehawk/backend/pkg/analyzer/gorules"
+	"golang.org/x/tools/go/analysis"
+)
+
+// goRulePack is a group of go/analysis passes that can be turned off with a boolean option
+type goRulePack struct {
+	option    string
+	analyzers []*analysis.Analyzer
+}
+
+// goRulePacks are CodeHawk's own rule packs for Go
+var goRulePacks = []goRulePack{
+	{option: "concurrencyRules", analyzers: gorules.Concurrency},
+	{option: "errorRules", analyzers: gorules.ErrorHandling},
+}
+
+// runRulePacks runs the enabled rule packs on the code. Diagnostics use their category as the
+// rule ID, so rules can be turned off with the disabledRules option. Code that does not fully
+// type-check, for example because it uses packages that are not available offline, is still
+// analyzed with the type information that could be worked out.
+func (l *GoLinter) runRulePacks(ctx context.Context, check *goTypeCheck, options map[string]interface{}) ([]Issue, error) {
+	analyzers := make([]*analysis.Analyzer, 0)
+	for _, pack := range goRulePacks {
+		if l.GetBoolOption(options, pack.option, true) {
+			analyzers = append(analyzers, pack.analyzers...)
+		}
+	}
+	if len(analyzers) == 0 {
+		return []Issue{}, nil
+	}
+	
+	fset, file, code := check.fset, check.file, check.code
+	
+	disabled := make(map[string]bool)
+	for _, rule := range l.GetListOption(options, "disabledRules", nil) {
+		disabled[rule] = true
+	}
+	
+	issues := make([]Issue, 0)
+	results := make(map[*analysis.Analyzer]interface{})
+	
+	// run runs an analyzer after the analyzers it requires; only the rule packs' own diagnostics are kept
+	var run func(a *analysis.Analyzer, report bool) error
+	run = func(a *analysis.Analyzer, report bool) error {
+		if _, done := results[a]; done {
+			return nil
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		
+		resultOf := make(map[*analysis.Analyzer]interface{})
+		for _, required := range a.Requires {
+			if err := run(required, false); err != nil {
+				return err
+			}
+			resultOf[required] = results[required]
+		}
+		
+		pass := &analysis.Pass{
+			Analyzer:   a,
+			Fset:       fset,
+			Files:      []*ast.File{file},
+			Pkg:        check.pkg,
+			TypesInfo:  check.info,
+			TypesSizes: types.SizesFor("gc", "amd64"),
+			ResultOf:   resultOf,
+			ReadFile: func(filename string) ([]byte, error) {
+				if filename == goSourceFile {
+					return []byte(code), nil
+				}
+				return nil, os.ErrNotExist
+			},
+			Report: func(d analysis.Diagnostic) {
+				if report {
+					if issue, ok := goDiagnosticIssue(fset, code, a, d); ok && !disabled[issue.RuleID] {
+						issues = append(issues, issue)
+					}
+				}
+			},
+			// The rule packs work on a single package and do not use facts
+			ImportObjectFact:  func(types.Object, analysis.Fact) bool { return false },
+			ImportPackageFact: func(*types.Package, analysis.Fact) bool { return false },
+			ExportObjectFact:  func(types.Object, analysis.Fact) {},
+			ExportPackageFact: func(analysis.Fact) {},
+			AllObjectFacts:    func() []analysis.ObjectFact { return nil },
+			AllPackageFacts:   func() []analysis.PackageFact { return nil },
+		}
+		
+		result, err := runGoAnalyzer(a, pass)
+		if err != nil {
+			return fmt.Errorf("%s: %w", a.Name, err)
+		}
+		results[a] = result
+		return nil
+	}
+	
+	for _, a := range analyzers {
+		if err := run(a, true); err != nil {
+			return nil, err
+		}
+	}
+	
+	sort.SliceStable(issues, func(i, j int) bool {
+		return issues[i].Line < issues[j].Line
+	})
+	return issues, nil
+}
+
+// runGoAnalyzer runs an analyzer, turning a panic into an error. Analyzers are written for
+// well-typed code and may not expect the gaps the type information of other code has.
+func runGoAnalyzer(a *analysis.Analyzer, pass *analysis.Pass) (result interface{}, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+	return a.Run(pass)
+}
+
+// goDiagnosticIssue converts a diagnostic into an issue, turning its suggested fixes into range fixes
+func goDiagnosticIssue(fset *token.FileSet, code string, a *analysis.Analyzer, d analysis.Diagnostic) (Issue, bool) {
+	position := fset.Position(d.Pos)
+	if position.Filename != "main.go" {
+		return Issue{}, false
+	}
+	
+	ruleID := d.Category
+	if ruleID == "" {
+		ruleID = a.Name
+	}
+	
+	column := position.Column
+	issue := Issue{
+		Line:     position.Line,
+		Column:   &column,
+		Message:  d.Message,
+		Severity: "warning",
+		RuleID:   ruleID,
+		Context:  a.Doc,
+	}
+	
+	for _, suggested := range d.SuggestedFixes {
+		edits := make([]textEdit, 0, len(suggested.TextEdits))
+		for _, edit := range suggested.TextEdits {
+			end := edit.End
+			if !end.IsValid() {
+				end = edit.Pos
+			}
+			edits = append(edits, textEdit{
+				start: fset.Position(edit.Pos).Offset,
+				end:   fset.Position(end).Offset,
+				text:  string(edit.NewText),
+			})
+		}
+		
+		fix, err := buildRangeFix(code, suggested.Message, edits)
+		if err != nil {
+			continue
+		}
+		if issue.Fix == nil {
+			issue.Fix = fix
+		} else {
+			issue.Suggestions = append(issue.Suggestions, *fix)
+		}
+	}
+	
+	return issue, true
+}
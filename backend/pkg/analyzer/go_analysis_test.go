@@ -0,0 +1,208 @@
+package analyzer
+
+import (
+	"context"
+	"testing"
+)
+
+func TestGoRulePacksPartialTypes(t *testing.T) {
+	tests := []struct {
+		name string
+		code string
+		want []string // rule IDs of the expected issues
+	}{
+		{
+			name: "unresolved import",
+			code: `package main
+
+import (
+	"errors"
+
+	"github.com/redis/go-redis/v9"
+)
+
+var ErrMissing = errors.New("missing")
+
+func get(client *redis.Client, key string) error {
+	return client.Get(key).Err()
+}
+
+func lookup(client *redis.Client, key string) error {
+	err := get(client, key)
+	if err == redis.Nil {
+		return ErrMissing
+	}
+	if err.Error() == "connection refused" {
+		return nil
+	}
+	return err
+}
+`,
+			want: []string{"error-string-compare"},
+		},
+		{
+			name: "package used without an import",
+			code: `package main
+
+import "fmt"
+
+func getAnalysis(db *sqlx.DB, id string) (string, error) {
+	var name string
+	err := db.Get(&name, "SELECT name FROM analyses WHERE id = $1", id)
+	return name, err
+}
+
+func handleGetAnalysisById(db *sqlx.DB, id string) error {
+	_, err := getAnalysis(db, id)
+	if err != nil {
+		if err.Error() == "sql: no rows in result set" {
+			return fmt.Errorf("analysis not found")
+		}
+		return err
+	}
+	return nil
+}
+`,
+			want: []string{"error-string-compare"},
+		},
+		{
+			name: "type from another file of the package",
+			code: `package ai
+
+import "time"
+
+type CachedService struct {
+	inner AISuggestionService
+}
+
+func (s *CachedService) cleanup() {
+	ticker := time.NewTicker(time.Minute)
+	go func() {
+		for range ticker.C {
+			s.inner.Reset()
+		}
+	}()
+}
+`,
+			want: []string{"unstopped-ticker", "goroutine-leak"},
+		},
+	}
+	
+	l := NewGoLinter(map[string]string{})
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			check, err := newGoTypeCheck(tt.code, goLanguageVersion)
+			if err != nil {
+				t.Fatalf("newGoTypeCheck() error = %v", err)
+			}
+			issues, err := l.runRulePacks(context.Background(), check, nil)
+			if err != nil {
+				t.Fatalf("runRulePacks() error = %v", err)
+			}
+			if len(issues) != len(tt.want) {
+				t.Fatalf("got %d issues, want %v: %+v", len(issues), tt.want, issues)
+			}
+			for i, issue := range issues {
+				if issue.RuleID != tt.want[i] {
+					t.Errorf("issue %d = %s, want %s", i, issue.RuleID, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestGoRulePacksLoopVarCapture(t *testing.T) {
+	code := `package main
+
+func process(items []string, handle func(string)) {
+	for _, item := range items {
+		go func() {
+			handle(item)
+		}()
+	}
+}
+`
+	tests := []struct {
+		name    string
+		options map[string]interface{}
+		want    []string // rule IDs of the expected issues
+	}{
+		{"default version", nil, nil},
+		{"shared loop variables", map[string]interface{}{"goVersion": "1.21"}, []string{"loop-var-capture"}},
+		{"per-iteration loop variables", map[string]interface{}{"goVersion": "1.22"}, nil},
+	}
+	
+	l := NewGoLinter(map[string]string{})
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			version, err := goVersion(l.BaseAnalyzer, tt.options)
+			if err != nil {
+				t.Fatalf("goVersion() error = %v", err)
+			}
+			check, err := newGoTypeCheck(code, version)
+			if err != nil {
+				t.Fatalf("newGoTypeCheck() error = %v", err)
+			}
+			issues, err := l.runRulePacks(context.Background(), check, nil)
+			if err != nil {
+				t.Fatalf("runRulePacks() error = %v", err)
+			}
+			if len(issues) != len(tt.want) {
+				t.Fatalf("got %d issues, want %v: %+v", len(issues), tt.want, issues)
+			}
+			for i, issue := range issues {
+				if issue.RuleID != tt.want[i] {
+					t.Errorf("issue %d = %s, want %s", i, issue.RuleID, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestGoVersion(t *testing.T) {
+	l := NewGoLinter(map[string]string{})
+	tests := []struct {
+		name    string
+		options map[string]interface{}
+		want    string
+		wantErr bool
+	}{
+		{"default version", nil, goLanguageVersion, false},
+		{"selected version", map[string]interface{}{"goVersion": "1.20"}, "1.20", false},
+		{"newer than the toolchain", map[string]interface{}{"goVersion": "1.30"}, "", true},
+		{"injected go.mod directives", map[string]interface{}{"goVersion": "1.22\nreplace a => ../a"}, "", true},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := goVersion(l.BaseAnalyzer, tt.options)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("goVersion() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("goVersion() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGoImportName(t *testing.T) {
+	tests := map[string]string{
+		"github.com/jmoiron/sqlx":       "sqlx",
+		"github.com/redis/go-redis/v9":  "redis",
+		"gopkg.in/yaml.v3":              "yaml",
+		"github.com/mattn/go-sqlite3":   "sqlite3",
+		"github.com/aws/aws-sdk-go":     "aws_sdk",
+		"github.com/google/uuid":        "uuid",
+		"example.com/v2":                "example_com",
+		"github.com/segmentio/kafka-go": "kafka",
+	}
+	
+	for path, want := range tests {
+		t.Run(path, func(t *testing.T) {
+			if got := goImportName(path); got != want {
+				t.Errorf("goImportName(%q) = %q, want %q", path, got, want)
+			}
+		})
+	}
+}
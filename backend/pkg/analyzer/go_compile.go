@@ -0,0 +1,210 @@
+package analyzer
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"go/parser"
+	"go/scanner"
+	"go/token"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// goCompilerOutputPattern matches compiler diagnostics such as "./main.go:12:2: undefined: foo"
+var goCompilerOutputPattern = regexp.MustCompile(`^(?:\./)?([^\s:]+\.go):(\d+)(?::(\d+))?: (.*)$`)
+
+// checkSyntax parses the code and reports every syntax error as an issue
+func (l *GoLinter) checkSyntax(code string) []Issue {
+	fset := token.NewFileSet()
+	_, err := parser.ParseFile(fset, "main.go", code, parser.AllErrors)
+	if err == nil {
+		return nil
+	}
+	
+	errorList, ok := err.(scanner.ErrorList)
+	if !ok {
+		return []Issue{{
+			Line:     1,
+			Message:  err.Error(),
+			Severity: "error",
+			RuleID:   "syntax-error",
+		}}
+	}
+	
+	issues := make([]Issue, 0, len(errorList))
+	for _, syntaxErr := range errorList {
+		column := syntaxErr.Pos.Column
+		issues = append(issues, Issue{
+			Line:     syntaxErr.Pos.Line,
+			Column:   &column,
+			Message:  syntaxErr.Msg,
+			Severity: "error",
+			RuleID:   "syntax-error",
+		})
+	}
+	
+	return issues
+}
+
+// buildTimeout returns the deadline of go build, which is separate from the linters' timeout
+// since a build with a cold cache can take most of it
+func (l *GoLinter) buildTimeout() time.Duration {
+	if timeout, err := time.ParseDuration(l.GetStringOption(nil, "buildTimeout", "")); err == nil {
+		return timeout
+	}
+	return 60 * time.Second
+}
+
+// runCompiler builds the module in dir and converts compiler errors into issues
+func (l *GoLinter) runCompiler(ctx context.Context, dir string) ([]Issue, error) {
+	ctx, cancel := context.WithTimeout(ctx, l.buildTimeout())
+	defer cancel()
+	
+	// -e reports all errors instead of stopping after the first ten
+	cmd := exec.CommandContext(
+		ctx,
+		l.GetStringOption(nil, "goPath", "go"),
+		"build",
+		"-gcflags=-e",
+		"-o", os.DevNull,
+		"./...",
+	)
+	
+	cmd.Dir = dir
+	// Submitted code must never trigger network access
+	cmd.Env = append(os.Environ(), "GOFLAGS=-mod=mod", "GOPROXY=off", "GOWORK=off", "CGO_ENABLED=0")
+	
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	
+	err := cmd.Run()
+	if err == nil {
+		return []Issue{}, nil
+	}
+	// Exit code 1 is normal when the build fails
+	if !strings.Contains(err.Error(), "exit status 1") {
+		return nil, l.WrapError(fmt.Errorf("failed to run go build: %w, stderr: %s", err, stderr.String()), "go build execution error")
+	}
+	
+	return parseGoCompilerOutput(stderr.String()), nil
+}
+
+// parseGoCompilerOutput converts the output of go build into issues
+func parseGoCompilerOutput(output string) []Issue {
+	issues := make([]Issue, 0)
+	
+	for _, line := range strings.Split(output, "\n") {
+		if strings.TrimSpace(line) == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		
+		// Indented lines elaborate on the previous error, e.g. "have (int)" / "want (string)"
+		if strings.HasPrefix(line, "\t") && len(issues) > 0 {
+			last := &issues[len(issues)-1]
+			last.Message = last.Message + "\n" + strings.TrimSpace(line)
+			continue
+		}
+		
+		// Snippets without a main function are still worth analyzing
+		if strings.Contains(line, "function main is undeclared in the main package") {
+			continue
+		}
+		
+		// Imports that cannot be resolved offline only limit the analysis
+		if strings.Contains(line, "no required module provides package") || strings.Contains(line, "cannot find module providing package") || strings.Contains(line, "is not in std") {
+			issues = append(issues, Issue{
+				Line:     goCompilerLine(line),
+				Message:  "Dependency unavailable for analysis: " + line,
+				Severity: "warning",
+				RuleID:   "missing-dependency",
+			})
+			continue
+		}
+		
+		matches := goCompilerOutputPattern.FindStringSubmatch(line)
+		if matches == nil || filepath.Base(matches[1]) != "main.go" {
+			continue
+		}
+		
+		lineNum, _ := strconv.Atoi(matches[2])
+		issue := Issue{
+			Line:     lineNum,
+			Message:  matches[4],
+			Severity: "error",
+			RuleID:   "compile-error",
+		}
+		
+		if matches[3] != "" {
+			column, _ := strconv.Atoi(matches[3])
+			issue.Column = &column
+		}
+		
+		issues = append(issues, issue)
+	}
+	
+	return issues
+}
+
+// goCompilerLine extracts the main.go line a go command message refers to, defaulting to 1
+func goCompilerLine(message string) int {
+	if matches := goCompilerOutputPattern.FindStringSubmatch(message); matches != nil {
+		if line, err := strconv.Atoi(matches[2]); err == nil {
+			return line
+		}
+	}
+	return 1
+}
+
+// hasErrorSeverity reports whether any of the issues is an error
+func hasErrorSeverity(issues []Issue) bool {
+	for _, issue := range issues {
+		if issue.Severity == "error" {
+			return true
+		}
+	}
+	return false
+}
+
+// skippedStageIssue notes that one analysis stage could not be run on the code
+func skippedStageIssue(stage string, err error) Issue {
+	return Issue{
+		Line:     1,
+		Message:  fmt.Sprintf("The %s was skipped: %v", stage, err),
+		Severity: "info",
+		RuleID:   "analysis-skipped",
+	}
+}
+
+// missingTool returns an error when the tool at path, or found on the PATH, cannot be run
+func missingTool(name, path string) error {
+	if _, err := exec.LookPath(path); err != nil {
+		return fmt.Errorf("%s is not installed on the server", name)
+	}
+	return nil
+}
+
+// missingGoTool is missingTool for tools that load the code through the go command, which
+// goErr reports as missing
+func missingGoTool(name, path string, goErr error) error {
+	if goErr != nil {
+		return goErr
+	}
+	return missingTool(name, path)
+}
+
+// skippedAnalysisIssue notes that the stages which need the code to build were not run
+func skippedAnalysisIssue(reason string) Issue {
+	return Issue{
+		Line:     1,
+		Message:  fmt.Sprintf("golangci-lint, staticcheck, taint analysis and the Go rule packs were skipped because %s; fix the errors above and re-run the analysis", reason),
+		Severity: "info",
+		RuleID:   "analysis-skipped",
+	}
+}
@@ -0,0 +1,139 @@
+package analyzer
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestGoCheckSyntax(t *testing.T) {
+	tests := []struct {
+		name          string
+		code          string
+		wantFirstLine int
+		wantMin       int
+	}{
+		{
+			name: "valid code",
+			code: "package main\n\nfunc main() {}\n",
+		},
+		{
+			name:          "missing brace",
+			code:          "package main\n\nfunc main() {\n\tx := 1\n",
+			wantFirstLine: 4,
+			wantMin:       1,
+		},
+		{
+			name:          "every error is reported",
+			code:          "package main\n\nfunc main() {\n\tx := )\n\ty := )\n}\n",
+			wantFirstLine: 4,
+			wantMin:       2,
+		},
+	}
+	
+	l := NewGoLinter(map[string]string{})
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			issues := l.checkSyntax(tt.code)
+			if tt.wantMin == 0 {
+				if len(issues) != 0 {
+					t.Fatalf("got issues for valid code: %+v", issues)
+				}
+				return
+			}
+			if len(issues) < tt.wantMin {
+				t.Fatalf("got %d issues, want at least %d: %+v", len(issues), tt.wantMin, issues)
+			}
+			if issues[0].Line != tt.wantFirstLine {
+				t.Errorf("first issue on line %d, want %d", issues[0].Line, tt.wantFirstLine)
+			}
+			for i, issue := range issues {
+				if issue.RuleID != "syntax-error" || issue.Severity != "error" || issue.Column == nil {
+					t.Errorf("issue %d = %+v, want a syntax error with a column", i, issue)
+				}
+			}
+		})
+	}
+}
+
+func TestParseGoCompilerOutput(t *testing.T) {
+	tests := []struct {
+		name   string
+		output string
+		want   []Issue
+	}{
+		{
+			name:   "compile error with column",
+			output: "# example.com/snippet\n./main.go:12:2: undefined: foo\n",
+			want:   []Issue{{Line: 12, Message: "undefined: foo", Severity: "error", RuleID: "compile-error"}},
+		},
+		{
+			name: "details of a type mismatch",
+			output: "./main.go:7:9: cannot use x (variable of type int) as string value in return statement\n" +
+				"\thave (int)\n\twant (string)\n",
+			want: []Issue{{Line: 7, Message: "cannot use x (variable of type int) as string value in return statement\nhave (int)\nwant (string)", Severity: "error", RuleID: "compile-error"}},
+		},
+		{
+			name:   "missing main is ignored",
+			output: "runtime.main_main·f: function main is undeclared in the main package\n",
+			want:   []Issue{},
+		},
+		{
+			name:   "unavailable dependency",
+			output: "main.go:5:2: no required module provides package github.com/gin-gonic/gin; to add it:\n",
+			want:   []Issue{{Line: 5, Message: "Dependency unavailable for analysis: main.go:5:2: no required module provides package github.com/gin-gonic/gin; to add it:", Severity: "warning", RuleID: "missing-dependency"}},
+		},
+		{
+			name:   "errors in other files are skipped",
+			output: "./other.go:3:1: syntax error\n",
+			want:   []Issue{},
+		},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := parseGoCompilerOutput(tt.output)
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %d issues, want %d: %+v", len(got), len(tt.want), got)
+			}
+			for i, issue := range got {
+				want := tt.want[i]
+				if issue.Line != want.Line || issue.Message != want.Message || issue.Severity != want.Severity || issue.RuleID != want.RuleID {
+					t.Errorf("issue %d = %+v, want %+v", i, issue, want)
+				}
+			}
+		})
+	}
+}
+
+func TestSkippedAnalysisIssue(t *testing.T) {
+	issue := skippedAnalysisIssue("the code does not compile")
+	if issue.RuleID != "analysis-skipped" || issue.Severity != "info" {
+		t.Errorf("issue = %+v, want an analysis-skipped info", issue)
+	}
+	for _, stage := range []string{"golangci-lint", "staticcheck", "taint analysis", "rule packs", "the code does not compile"} {
+		if !strings.Contains(issue.Message, stage) {
+			t.Errorf("message %q does not mention %q", issue.Message, stage)
+		}
+	}
+}
+
+func TestGoBuildTimeout(t *testing.T) {
+	tests := []struct {
+		name   string
+		config map[string]string
+		want   time.Duration
+	}{
+		{"default", map[string]string{}, 60 * time.Second},
+		{"configured", map[string]string{"buildTimeout": "90s"}, 90 * time.Second},
+		{"invalid", map[string]string{"buildTimeout": "soon"}, 60 * time.Second},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NewGoLinter(tt.config).buildTimeout(); got != tt.want {
+				t.Errorf("buildTimeout() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
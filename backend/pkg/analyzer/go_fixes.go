@@ -0,0 +1,454 @@
+package analyzer
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"regexp"
+	"strings"
+
+	"golang.org/x/tools/go/ast/astutil"
+)
+
+var (
+	// unusedVariablePattern matches the compiler's unused variable errors across Go versions
+	unusedVariablePattern = regexp.MustCompile(`^(?:declared and not used: (\w+)|(\w+) declared (?:but|and) not used)`)
+	
+	// unusedDeclPattern matches the unused linter, e.g. "func `helper` is unused"
+	unusedDeclPattern = regexp.MustCompile("^(func|var|const|type) `(\\w+)` is unused")
+	
+	// renamePattern matches naming linters, e.g. "var userId should be userID"
+	renamePattern = regexp.MustCompile(`\b(\w+) should be (\w+)\b`)
+)
+
+// golangciReplacement is the fix golangci-lint attaches to an issue, as applied by --fix
+type golangciReplacement struct {
+	NeedOnlyDelete bool     `json:"NeedOnlyDelete"`
+	NewLines       []string `json:"NewLines"`
+	Inline         *struct {
+		StartCol  int    `json:"StartCol"`
+		Length    int    `json:"Length"`
+		NewString string `json:"NewString"`
+	} `json:"Inline"`
+}
+
+// golangciFix converts a golangci-lint replacement for lines [from, to] into a range-based fix
+func golangciFix(code string, replacement *golangciReplacement, from, to int) *IssueFix {
+	if replacement == nil {
+		return nil
+	}
+	if to < from {
+		to = from
+	}
+	
+	lines := splitLines(code)
+	if from < 1 || to > len(lines) {
+		return nil
+	}
+	
+	lineStart := positionToOffset(code, from, 1)
+	lineEnd := lineStart
+	for i := from - 1; i < to; i++ {
+		lineEnd += len(lines[i])
+	}
+	
+	var edit textEdit
+	switch {
+	case replacement.Inline != nil:
+		// Inline columns are 0-based byte offsets within the line
+		start := lineStart + replacement.Inline.StartCol
+		edit = textEdit{start: start, end: start + replacement.Inline.Length, text: replacement.Inline.NewString}
+	case replacement.NeedOnlyDelete:
+		edit = textEdit{start: lineStart, end: lineEnd}
+	default:
+		text := strings.Join(replacement.NewLines, "\n")
+		if len(replacement.NewLines) > 0 {
+			text += "\n"
+		}
+		edit = textEdit{start: lineStart, end: lineEnd, text: text}
+	}
+	
+	fix, err := buildRangeFix(code, "Apply golangci-lint fix", []textEdit{edit})
+	if err != nil {
+		return nil
+	}
+	return fix
+}
+
+// goFixSession validates candidate fixes against the type-checked code. Candidates are checked
+// with the same importer, so only fixes that do not add type errors are offered.
+type goFixSession struct {
+	code      string
+	fset      *token.FileSet
+	typeCheck *goTypeCheck
+	file      *ast.File
+	info      *types.Info
+	pkg       *types.Package
+	errors    int
+}
+
+// newGoFixSession starts a fix session on the type-checked code
+func newGoFixSession(check *goTypeCheck) *goFixSession {
+	return &goFixSession{
+		code:      check.code,
+		fset:      check.fset,
+		typeCheck: check,
+		file:      check.file,
+		info:      check.info,
+		pkg:       check.pkg,
+		errors:    len(check.errors),
+	}
+}
+
+// check parses and type-checks code, returning the number of type errors
+func (s *goFixSession) check(code string) (int, error) {
+	file, err := parser.ParseFile(s.fset, goSourceFile, code, parser.ParseComments)
+	if err != nil {
+		return 0, err
+	}
+	
+	_, _, errs := s.typeCheck.check(file)
+	return len(errs), nil
+}
+
+// validate reports whether applying fix leaves the code parsing and type-checking with fewer
+// errors than before, or no more when the fix addresses a lint finding rather than an error
+func (s *goFixSession) validate(fix *IssueFix, fixesError bool) bool {
+	fixed, err := ApplyFix(s.code, 0, *fix)
+	if err != nil {
+		return false
+	}
+	
+	errs, err := s.check(fixed)
+	if err != nil {
+		return false
+	}
+	
+	if fixesError {
+		return errs < s.errors
+	}
+	return errs <= s.errors
+}
+
+// offset returns the byte offset of pos
+func (s *goFixSession) offset(pos token.Pos) int {
+	return s.fset.Position(pos).Offset
+}
+
+// identAt finds the identifier called name on line, preferring the one at column
+func (s *goFixSession) identAt(line int, column *int, name string) *ast.Ident {
+	var found *ast.Ident
+	ast.Inspect(s.file, func(n ast.Node) bool {
+		ident, ok := n.(*ast.Ident)
+		if !ok || ident.Name != name {
+			return true
+		}
+		
+		position := s.fset.Position(ident.Pos())
+		if position.Line != line {
+			return true
+		}
+		
+		if found == nil || (column != nil && position.Column == *column) {
+			found = ident
+		}
+		return true
+	})
+	return found
+}
+
+// lineIndent returns the indentation of the line containing pos
+func (s *goFixSession) lineIndent(pos token.Pos) string {
+	offset := s.offset(pos)
+	lineStart := strings.LastIndexByte(s.code[:offset], '\n') + 1
+	return getIndentation(s.code[lineStart:])
+}
+
+// fixUnusedVariable replaces an unused local variable with the blank identifier at its
+// declaration only, leaving any other identifier on the line untouched
+func (s *goFixSession) fixUnusedVariable(issue Issue, name string) (string, []textEdit) {
+	ident := s.identAt(issue.Line, issue.Column, name)
+	if ident == nil {
+		return "", nil
+	}
+	
+	path, _ := astutil.PathEnclosingInterval(s.file, ident.Pos(), ident.End())
+	if len(path) < 2 {
+		return "", nil
+	}
+	
+	switch parent := path[1].(type) {
+	case *ast.AssignStmt:
+		// A type switch binding is removed entirely: switch x := v.(type) -> switch v.(type)
+		if len(path) > 2 {
+			if _, ok := path[2].(*ast.TypeSwitchStmt); ok {
+				return "Remove unused type switch variable", []textEdit{{
+					start: s.offset(parent.Pos()),
+					end:   s.offset(parent.Rhs[0].Pos()),
+				}}
+			}
+		}
+		
+		edits := []textEdit{{start: s.offset(ident.Pos()), end: s.offset(ident.End()), text: "_"}}
+		
+		// When every name becomes blank, := no longer declares anything
+		allBlank := true
+		for _, lhs := range parent.Lhs {
+			if id, ok := lhs.(*ast.Ident); !ok || (id != ident && id.Name != "_") {
+				allBlank = false
+			}
+		}
+		if allBlank && parent.Tok == token.DEFINE {
+			tokOffset := s.offset(parent.TokPos)
+			edits = append(edits, textEdit{start: tokOffset, end: tokOffset + 2, text: "="})
+		}
+		
+		return "Replace unused variable with blank identifier", edits
+		
+	case *ast.RangeStmt:
+		if parent.Key == ident && parent.Value == nil {
+			return "Remove unused range variable", []textEdit{{
+				start: s.offset(parent.Key.Pos()),
+				end:   s.offset(parent.X.Pos()) - len("range "),
+			}}
+		}
+		return "Replace unused range variable with blank identifier", []textEdit{{
+			start: s.offset(ident.Pos()),
+			end:   s.offset(ident.End()),
+			text:  "_",
+		}}
+		
+	case *ast.ValueSpec:
+		decl, ok := path[2].(*ast.GenDecl)
+		if !ok || len(decl.Specs) != 1 || len(parent.Names) != 1 || len(path) < 4 {
+			return "", nil
+		}
+		stmt, ok := path[3].(*ast.DeclStmt)
+		if !ok {
+			return "", nil
+		}
+		
+		// var x = f() keeps the call for its side effects; var x T is dropped
+		if len(parent.Values) > 0 {
+			values := make([]string, 0, len(parent.Values))
+			blanks := make([]string, 0, len(parent.Values))
+			for _, value := range parent.Values {
+				values = append(values, s.code[s.offset(value.Pos()):s.offset(value.End())])
+				blanks = append(blanks, "_")
+			}
+			return "Replace unused variable with blank assignment", []textEdit{{
+				start: s.offset(stmt.Pos()),
+				end:   s.offset(stmt.End()),
+				text:  strings.Join(blanks, ", ") + " = " + strings.Join(values, ", "),
+			}}
+		}
+		
+		return "Remove unused variable declaration", []textEdit{s.deleteLines(stmt)}
+	}
+	
+	return "", nil
+}
+
+// fixUnusedDecl removes an unused package-level declaration together with its doc comment
+func (s *goFixSession) fixUnusedDecl(kind, name string) (string, []textEdit) {
+	for _, decl := range s.file.Decls {
+		switch d := decl.(type) {
+		case *ast.FuncDecl:
+			if kind == "func" && d.Recv == nil && d.Name.Name == name {
+				return fmt.Sprintf("Remove unused function %s", name), []textEdit{s.deleteLines(d)}
+			}
+		case *ast.GenDecl:
+			for _, spec := range d.Specs {
+				if !specDeclares(spec, name) {
+					continue
+				}
+				if len(d.Specs) == 1 {
+					return fmt.Sprintf("Remove unused %s %s", kind, name), []textEdit{s.deleteLines(d)}
+				}
+				return fmt.Sprintf("Remove unused %s %s", kind, name), []textEdit{s.deleteLines(spec)}
+			}
+		}
+	}
+	return "", nil
+}
+
+// specDeclares reports whether a spec declares exactly the given name
+func specDeclares(spec ast.Spec, name string) bool {
+	switch sp := spec.(type) {
+	case *ast.TypeSpec:
+		return sp.Name.Name == name
+	case *ast.ValueSpec:
+		return len(sp.Names) == 1 && sp.Names[0].Name == name
+	}
+	return false
+}
+
+// deleteLines returns an edit removing the lines spanned by node, including its doc comment
+func (s *goFixSession) deleteLines(node ast.Node) textEdit {
+	start := node.Pos()
+	switch n := node.(type) {
+	case *ast.FuncDecl:
+		if n.Doc != nil {
+			start = n.Doc.Pos()
+		}
+	case *ast.GenDecl:
+		if n.Doc != nil {
+			start = n.Doc.Pos()
+		}
+	case *ast.ValueSpec:
+		if n.Doc != nil {
+			start = n.Doc.Pos()
+		}
+	case *ast.TypeSpec:
+		if n.Doc != nil {
+			start = n.Doc.Pos()
+		}
+	}
+	
+	startOffset := strings.LastIndexByte(s.code[:s.offset(start)], '\n') + 1
+	endOffset := s.offset(node.End())
+	if next := strings.IndexByte(s.code[endOffset:], '\n'); next >= 0 {
+		endOffset += next + 1
+	} else {
+		endOffset = len(s.code)
+	}
+	
+	// Don't leave two blank lines where the declaration used to be
+	if strings.HasSuffix(s.code[:startOffset], "\n\n") && strings.HasPrefix(s.code[endOffset:], "\n") {
+		endOffset++
+	}
+	
+	return textEdit{start: startOffset, end: endOffset}
+}
+
+// fixUncheckedError wraps a call whose error result is discarded in an if statement that
+// returns the error, provided the enclosing function itself returns an error
+func (s *goFixSession) fixUncheckedError(issue Issue) (string, []textEdit) {
+	lineStart := s.fset.File(s.file.Pos()).LineStart(issue.Line)
+	
+	var stmt *ast.ExprStmt
+	ast.Inspect(s.file, func(n ast.Node) bool {
+		if es, ok := n.(*ast.ExprStmt); ok && stmt == nil && s.fset.Position(es.Pos()).Line == issue.Line {
+			if _, isCall := es.X.(*ast.CallExpr); isCall {
+				stmt = es
+			}
+		}
+		return stmt == nil
+	})
+	if stmt == nil || stmt.Pos() < lineStart {
+		return "", nil
+	}
+	
+	// Find the signature of the enclosing function
+	path, _ := astutil.PathEnclosingInterval(s.file, stmt.Pos(), stmt.End())
+	var signature *types.Signature
+	for _, node := range path {
+		switch fn := node.(type) {
+		case *ast.FuncLit:
+			signature, _ = s.info.TypeOf(fn).(*types.Signature)
+		case *ast.FuncDecl:
+			if obj := s.info.Defs[fn.Name]; obj != nil {
+				signature, _ = obj.Type().(*types.Signature)
+			}
+		}
+		if signature != nil {
+			break
+		}
+	}
+	
+	errorType := types.Universe.Lookup("error").Type()
+	if signature == nil || signature.Results().Len() == 0 ||
+		!types.Identical(signature.Results().At(signature.Results().Len()-1).Type(), errorType) {
+		return "", nil
+	}
+	
+	// Bind the error, discarding any other results of the call
+	call := stmt.X.(*ast.CallExpr)
+	lhs := []string{"err"}
+	if tuple, ok := s.info.TypeOf(call).(*types.Tuple); ok {
+		if tuple.Len() == 0 || !types.Identical(tuple.At(tuple.Len()-1).Type(), errorType) {
+			return "", nil
+		}
+		lhs = make([]string, tuple.Len())
+		for i := range lhs {
+			lhs[i] = "_"
+		}
+		lhs[len(lhs)-1] = "err"
+	} else if !types.Identical(s.info.TypeOf(call), errorType) {
+		return "", nil
+	}
+	
+	qualifier := types.RelativeTo(s.pkg)
+	returns := make([]string, 0, signature.Results().Len())
+	for i := 0; i < signature.Results().Len()-1; i++ {
+		returns = append(returns, goZeroValue(signature.Results().At(i).Type(), qualifier))
+	}
+	returns = append(returns, "err")
+	
+	indent := s.lineIndent(stmt.Pos())
+	callText := s.code[s.offset(call.Pos()):s.offset(call.End())]
+	replacement := fmt.Sprintf("if %s := %s; err != nil {\n%s\treturn %s\n%s}",
+		strings.Join(lhs, ", "), callText, indent, strings.Join(returns, ", "), indent)
+	
+	return "Handle the returned error", []textEdit{{
+		start: s.offset(stmt.Pos()),
+		end:   s.offset(stmt.End()),
+		text:  replacement,
+	}}
+}
+
+// fixRename renames the object declared or used as oldName on the issue's line, updating
+// every reference to that object and nothing else
+func (s *goFixSession) fixRename(issue Issue, oldName, newName string) (string, []textEdit) {
+	ident := s.identAt(issue.Line, issue.Column, oldName)
+	if ident == nil {
+		return "", nil
+	}
+	
+	target := s.info.Defs[ident]
+	if target == nil {
+		target = s.info.Uses[ident]
+	}
+	if target == nil {
+		return "", nil
+	}
+	
+	edits := make([]textEdit, 0)
+	for id, obj := range s.info.Defs {
+		if obj == target {
+			edits = append(edits, textEdit{start: s.offset(id.Pos()), end: s.offset(id.End()), text: newName})
+		}
+	}
+	for id, obj := range s.info.Uses {
+		if obj == target {
+			edits = append(edits, textEdit{start: s.offset(id.Pos()), end: s.offset(id.End()), text: newName})
+		}
+	}
+	
+	return fmt.Sprintf("Rename %s to %s", oldName, newName), edits
+}
+
+// goZeroValue returns the Go source for the zero value of t
+func goZeroValue(t types.Type, qualifier types.Qualifier) string {
+	if _, ok := t.(*types.TypeParam); ok {
+		return "*new(" + types.TypeString(t, qualifier) + ")"
+	}
+	
+	switch u := t.Underlying().(type) {
+	case *types.Basic:
+		switch {
+		case u.Info()&types.IsBoolean != 0:
+			return "false"
+		case u.Info()&types.IsNumeric != 0:
+			return "0"
+		case u.Info()&types.IsString != 0:
+			return `""`
+		}
+	case *types.Struct, *types.Array:
+		return types.TypeString(t, qualifier) + "{}"
+	}
+
+	return "nil"
+}
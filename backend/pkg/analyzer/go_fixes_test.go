@@ -0,0 +1,164 @@
+package analyzer
+
+import (
+	"testing"
+)
+
+func TestGoGenerateFixForIssue(t *testing.T) {
+	intPtr := func(i int) *int { return &i }
+	
+	tests := []struct {
+		name  string
+		code  string
+		issue Issue
+		want  string // the fixed code, or empty when no fix is expected
+	}{
+		{
+			name:  "unused variable in a multi-value assignment",
+			code:  "package main\n\nimport \"strconv\"\n\nfunc main() {\n\tn, err := strconv.Atoi(\"1\")\n\tprintln(n)\n}\n",
+			issue: Issue{Line: 6, Column: intPtr(5), Message: "declared and not used: err", Severity: "error", RuleID: "compile-error"},
+			want:  "package main\n\nimport \"strconv\"\n\nfunc main() {\n\tn, _ := strconv.Atoi(\"1\")\n\tprintln(n)\n}\n",
+		},
+		{
+			name:  "only variable of a short declaration",
+			code:  "package main\n\nfunc f() int { return 1 }\n\nfunc main() {\n\tx := f()\n}\n",
+			issue: Issue{Line: 6, Column: intPtr(2), Message: "declared and not used: x", Severity: "error", RuleID: "compile-error"},
+			want:  "package main\n\nfunc f() int { return 1 }\n\nfunc main() {\n\t_ = f()\n}\n",
+		},
+		{
+			name:  "unused range key",
+			code:  "package main\n\nfunc main() {\n\tfor i := range []int{1} {\n\t}\n}\n",
+			issue: Issue{Line: 4, Column: intPtr(6), Message: "declared and not used: i", Severity: "error", RuleID: "compile-error"},
+			want:  "package main\n\nfunc main() {\n\tfor range []int{1} {\n\t}\n}\n",
+		},
+		{
+			name:  "unused var declaration without a value",
+			code:  "package main\n\nfunc main() {\n\tvar x int\n\tprintln()\n}\n",
+			issue: Issue{Line: 4, Column: intPtr(6), Message: "x declared and not used", Severity: "error", RuleID: "compile-error"},
+			want:  "package main\n\nfunc main() {\n\tprintln()\n}\n",
+		},
+		{
+			name:  "unused function with its doc comment",
+			code:  "package main\n\n// helper helps\nfunc helper() {}\n\nfunc main() {}\n",
+			issue: Issue{Line: 4, Message: "func `helper` is unused", Severity: "warning", RuleID: "unused"},
+			want:  "package main\n\nfunc main() {}\n",
+		},
+		{
+			name:  "unchecked error is returned",
+			code:  "package main\n\nimport \"os\"\n\nfunc run() (int, error) {\n\tos.Remove(\"x\")\n\treturn 1, nil\n}\n\nfunc main() { run() }\n",
+			issue: Issue{Line: 6, Message: "Error return value of `os.Remove` is not checked", Severity: "warning", RuleID: "errcheck"},
+			want:  "package main\n\nimport \"os\"\n\nfunc run() (int, error) {\n\tif err := os.Remove(\"x\"); err != nil {\n\t\treturn 0, err\n\t}\n\treturn 1, nil\n}\n\nfunc main() { run() }\n",
+		},
+		{
+			name:  "unchecked error in a function without an error result",
+			code:  "package main\n\nimport \"os\"\n\nfunc main() {\n\tos.Remove(\"x\")\n}\n",
+			issue: Issue{Line: 6, Message: "Error return value of `os.Remove` is not checked", Severity: "warning", RuleID: "errcheck"},
+		},
+		{
+			name:  "rename updates every reference",
+			code:  "package main\n\nfunc main() {\n\tuserId := 1\n\tprintln(userId)\n}\n",
+			issue: Issue{Line: 4, Column: intPtr(2), Message: "var userId should be userID", Severity: "warning", RuleID: "stylecheck"},
+			want:  "package main\n\nfunc main() {\n\tuserID := 1\n\tprintln(userID)\n}\n",
+		},
+		{
+			name:  "rename from a non-naming rule",
+			code:  "package main\n\nfunc main() {\n\tuserId := 1\n\tprintln(userId)\n}\n",
+			issue: Issue{Line: 4, Column: intPtr(2), Message: "var userId should be userID", Severity: "warning", RuleID: "gocritic"},
+		},
+	}
+	
+	l := NewGoLinter(map[string]string{})
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			check, err := newGoTypeCheck(tt.code, goLanguageVersion)
+			if err != nil {
+				t.Fatalf("newGoTypeCheck() error = %v", err)
+			}
+			
+			suggestion := l.generateFixForIssue(newGoFixSession(check), tt.issue)
+			if tt.want == "" {
+				if suggestion.Fix != nil {
+					t.Fatalf("got fix %+v, want none", suggestion.Fix)
+				}
+				return
+			}
+			if suggestion.Fix == nil {
+				t.Fatal("got no fix")
+			}
+			
+			fixed, err := ApplyFix(tt.code, tt.issue.Line, *suggestion.Fix)
+			if err != nil {
+				t.Fatalf("ApplyFix() error = %v", err)
+			}
+			if fixed != tt.want {
+				t.Errorf("fixed code =\n%s\nwant\n%s", fixed, tt.want)
+			}
+		})
+	}
+}
+
+func TestGolangciFix(t *testing.T) {
+	code := "package main\n\nimport \"fmt\"\n\nfunc main() {\n\tfmt.Println(\"a\" + \"b\")\n}\n"
+	tests := []struct {
+		name        string
+		replacement *golangciReplacement
+		from, to    int
+		want        string
+	}{
+		{
+			name: "inline replacement",
+			replacement: &golangciReplacement{Inline: &struct {
+				StartCol  int    `json:"StartCol"`
+				Length    int    `json:"Length"`
+				NewString string `json:"NewString"`
+			}{StartCol: 13, Length: 9, NewString: "\"ab\""}},
+			from: 6,
+			want: "package main\n\nimport \"fmt\"\n\nfunc main() {\n\tfmt.Println(\"ab\")\n}\n",
+		},
+		{
+			name:        "deletion",
+			replacement: &golangciReplacement{NeedOnlyDelete: true},
+			from:        6,
+			want:        "package main\n\nimport \"fmt\"\n\nfunc main() {\n}\n",
+		},
+		{
+			name:        "new lines",
+			replacement: &golangciReplacement{NewLines: []string{"func main() {", "\tfmt.Println(\"ab\")"}},
+			from:        5,
+			to:          6,
+			want:        "package main\n\nimport \"fmt\"\n\nfunc main() {\n\tfmt.Println(\"ab\")\n}\n",
+		},
+		{
+			name:        "lines outside the code",
+			replacement: &golangciReplacement{NeedOnlyDelete: true},
+			from:        20,
+		},
+		{
+			name: "no replacement",
+			from: 6,
+		},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fix := golangciFix(code, tt.replacement, tt.from, tt.to)
+			if tt.want == "" {
+				if fix != nil {
+					t.Fatalf("got fix %+v, want none", fix)
+				}
+				return
+			}
+			if fix == nil {
+				t.Fatal("got no fix")
+			}
+			
+			fixed, err := ApplyFix(code, tt.from, *fix)
+			if err != nil {
+				t.Fatalf("ApplyFix() error = %v", err)
+			}
+			if fixed != tt.want {
+				t.Errorf("fixed code = %q, want %q", fixed, tt.want)
+			}
+		})
+	}
+}
@@ -9,7 +9,6 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
-	"regexp"
 	"strings"
 )
 
@@ -47,125 +46,199 @@ func (l *GoLinter) Language() string {
 
 // Analyze analyzes the provided code and returns issues found
 func (l *GoLinter) Analyze(ctx context.Context, code string, options map[string]interface{}) (*AnalysisResult, error) {
+	// The build and the linters have deadlines of their own, so findIssues gets the caller's
+	// context rather than one already running the linters' timeout. SuggestFixes likewise gets
+	// a timeout counted from the end of findIssues, which a slow build could otherwise use up.
+	// The fixes reuse the type-check findIssues made for the taint analysis and the rule packs.
+	var check *goTypeCheck
 	return l.BaseAnalyzer.CommonAnalyzeWrapper(
 		ctx,
 		code,
 		options,
-		l.findIssues,
-		l.SuggestFixes,
+		func(_ context.Context, code string, options map[string]interface{}) ([]Issue, error) {
+			var issues []Issue
+			var err error
+			issues, check, err = l.findIssues(ctx, code, options)
+			return issues, err
+		},
+		func(_ context.Context, code string, issues []Issue) ([]Issue, error) {
+			ctx, cancel := l.CreateTimeoutContext(ctx)
+			defer cancel()
+			return l.suggestFixes(ctx, code, issues, check, options)
+		},
 	)
 }
 
-// findIssues analyzes the code and returns issues
-func (l *GoLinter) findIssues(ctx context.Context, code string, options map[string]interface{}) ([]Issue, error) {
+// findIssues analyzes the code and returns issues, along with the type-check of the code when
+// the analysis got as far as making one
+func (l *GoLinter) findIssues(ctx context.Context, code string, options map[string]interface{}) ([]Issue, *goTypeCheck, error) {
+	skipOnError := l.GetBoolOption(options, "skipAnalysisOnCompileError", true)
+	version, err := goVersion(l.BaseAnalyzer, options)
+	if err != nil {
+		return nil, nil, l.WrapError(err, "unsupported Go module")
+	}
+	
+	// Code that does not parse cannot be built or linted
+	if syntaxIssues := l.checkSyntax(code); len(syntaxIssues) > 0 {
+		return append(syntaxIssues, skippedAnalysisIssue("the code has syntax errors")), nil, nil
+	}
+	
 	// Create a temporary directory for Go module
 	tmpDir, err := ioutil.TempDir("", "codehawk-go")
 	if err != nil {
-		return nil, l.WrapError(err, "failed to create temporary directory")
+		return nil, nil, l.WrapError(err, "failed to create temporary directory")
 	}
 	defer os.RemoveAll(tmpDir)
 	
 	// Set up a minimal Go module
-	err = l.setupGoModule(tmpDir)
+	err = l.setupGoModule(tmpDir, version)
 	if err != nil {
-		return nil, l.WrapError(err, "failed to set up Go module")
+		return nil, nil, l.WrapError(err, "failed to set up Go module")
 	}
 	
 	// Create a temporary file for the code
 	tmpFile := filepath.Join(tmpDir, "main.go")
 	if err := ioutil.WriteFile(tmpFile, []byte(code), 0644); err != nil {
-		return nil, l.WrapError(err, "failed to write to temporary file")
+		return nil, nil, l.WrapError(err, "failed to write to temporary file")
+	}
+	
+	// Stages whose tool is not installed are skipped with a note rather than failing. The
+	// linters load the code through the go command, so they need it as well.
+	var skippedIssues []Issue
+	goErr := missingTool("go", l.GetStringOption(nil, "goPath", "go"))
+	
+	// Type-check the code first; the linters below depend on it compiling
+	var compileIssues []Issue
+	if goErr != nil {
+		skippedIssues = append(skippedIssues, skippedStageIssue("build", goErr))
+	} else if compileIssues, err = l.runCompiler(ctx, tmpDir); err != nil {
+		// Log the error but continue with the linters
+		fmt.Printf("Warning: go build failed: %v\n", err)
 	}
 	
+	if hasErrorSeverity(compileIssues) && skipOnError {
+		return append(compileIssues, skippedAnalysisIssue("the code does not compile")), nil, nil
+	}
+	
+	// The linters share the configured timeout, counted from the end of the build
+	ctx, cancel := l.CreateTimeoutContext(ctx)
+	defer cancel()
+	
 	// Run both linters and combine results
-	golangciIssues, err := l.runGolangciLint(ctx, tmpDir)
-	if err != nil {
+	var golangciIssues []Issue
+	if err = missingGoTool("golangci-lint", l.golangciLintPath, goErr); err != nil {
+		skippedIssues = append(skippedIssues, skippedStageIssue("golangci-lint analysis", err))
+	} else if golangciIssues, err = l.runGolangciLint(ctx, tmpDir, code); err != nil {
 		// Log the error but continue with other linters
 		fmt.Printf("Warning: golangci-lint failed: %v\n", err)
 	}
 	
-	staticcheckIssues, err := l.runStaticcheck(ctx, tmpDir)
-	if err != nil {
+	var staticcheckIssues []Issue
+	if err = missingGoTool("staticcheck", l.staticcheckPath, goErr); err != nil {
+		skippedIssues = append(skippedIssues, skippedStageIssue("staticcheck analysis", err))
+	} else if staticcheckIssues, err = l.runStaticcheck(ctx, tmpDir); err != nil {
 		// Log the error but continue
 		fmt.Printf("Warning: staticcheck failed: %v\n", err)
 	}
 	
-	// Combine issues
-	issues := append(golangciIssues, staticcheckIssues...)
-	
-	return issues, nil
-}
-
-// SuggestFixes attempts to generate fixes for the identified issues
-func (l *GoLinter) SuggestFixes(ctx context.Context, code string, issues []Issue) ([]Issue, error) {
-	// Create a temporary directory for Go module
-	tmpDir, err := ioutil.TempDir("", "codehawk-go-fix")
-	if err != nil {
-		return nil, l.WrapError(err, "failed to create temporary directory")
+	// The taint analysis, the rule packs and the fixes share one type-check of the code. None of
+	// them can be interrupted part way, so the context is checked before each.
+	var check *goTypeCheck
+	if err = ctx.Err(); err == nil {
+		check, err = newGoTypeCheck(code, version)
 	}
-	defer os.RemoveAll(tmpDir)
-	
-	// Set up a minimal Go module
-	err = l.setupGoModule(tmpDir)
 	if err != nil {
-		return nil, l.WrapError(err, "failed to set up Go module")
+		issues := append(compileIssues, golangciIssues...)
+		issues = append(issues, staticcheckIssues...)
+		issues = append(issues, skippedIssues...)
+		return append(issues, skippedStageIssue("taint analysis and Go rule pack analysis", err)), nil, nil
 	}
 	
-	// Create a temporary file for the code
-	tmpFile := filepath.Join(tmpDir, "main.go")
-	if err := ioutil.WriteFile(tmpFile, []byte(code), 0644); err != nil {
-		return nil, l.WrapError(err, "failed to write to temporary file")
+	// Follow request input to SQL queries, commands and templates
+	var taintIssues []Issue
+	if l.GetBoolOption(options, "taintAnalysis", true) {
+		if err = ctx.Err(); err == nil {
+			taintIssues, err = l.runTaintAnalysis(ctx, check)
+		}
+		if err != nil {
+			taintIssues = []Issue{skippedStageIssue("taint analysis", err)}
+		}
 	}
 	
-	// Run gofmt to get properly formatted code
-	cmd := exec.CommandContext(
-		ctx,
-		"gofmt",
-		"-w",
-		tmpFile,
-	)
-	
-	var stderr bytes.Buffer
-	cmd.Stderr = &stderr
-	
-	if err := cmd.Run(); err != nil {
-		return nil, l.WrapError(fmt.Errorf("failed to run gofmt: %w, stderr: %s", err, stderr.String()), "gofmt execution error")
+	// CodeHawk's own rule packs
+	var ruleIssues []Issue
+	if err = ctx.Err(); err == nil {
+		ruleIssues, err = l.runRulePacks(ctx, check, options)
 	}
-	
-	// Read the formatted code
-	formattedCode, err := ioutil.ReadFile(tmpFile)
 	if err != nil {
-		return nil, l.WrapError(err, "failed to read formatted code")
+		ruleIssues = []Issue{skippedStageIssue("Go rule pack analysis", err)}
 	}
 	
+	// Combine issues
+	issues := append(compileIssues, golangciIssues...)
+	issues = append(issues, staticcheckIssues...)
+	issues = append(issues, taintIssues...)
+	issues = append(issues, ruleIssues...)
+	issues = append(issues, skippedIssues...)
+	
+	return issues, check, nil
+}
+
+// SuggestFixes attempts to generate fixes for the identified issues
+func (l *GoLinter) SuggestFixes(ctx context.Context, code string, issues []Issue) ([]Issue, error) {
+	return l.suggestFixes(ctx, code, issues, nil, nil)
+}
+
+// suggestFixes generates fixes for the identified issues, type-checking the code as the Go
+// version options select unless check already holds its type-check
+func (l *GoLinter) suggestFixes(ctx context.Context, code string, issues []Issue, check *goTypeCheck, options map[string]interface{}) ([]Issue, error) {
 	// Generate suggestions based on the formatted code and known issue patterns
 	suggestions := make([]Issue, 0)
-	formattedLines := strings.Split(string(formattedCode), "\n")
-	originalLines := strings.Split(code, "\n")
-	
-	// First, add suggestions based on formatting differences
-	for i := 0; i < len(originalLines) && i < len(formattedLines); i++ {
-		if originalLines[i] != formattedLines[i] {
-			lineNum := i + 1 // Convert to 1-based line numbering
-			
-			suggestion := Issue{
-				Line:     lineNum,
-				Message:  "Code formatting issue",
-				Severity: "suggestion",
-				RuleID:   "gofmt",
-				Fix: &IssueFix{
-					Description: "Format code according to gofmt",
-					Replacement: formattedLines[i],
-				},
-			}
-			
-			suggestions = append(suggestions, suggestion)
+	
+	// First, add suggestions based on formatting differences. Without gofmt only these are
+	// lost; the fixes below do not depend on it.
+	formattedCode, err := l.FormatCode(ctx, code, nil)
+	if err != nil {
+		fmt.Printf("Warning: gofmt suggestions skipped: %v\n", err)
+		formattedCode = code
+	}
+	hunkLines, fixes := hunkFixes(code, formattedCode, "Format code according to gofmt")
+	for i, fix := range fixes {
+		suggestion := Issue{
+			Line:     hunkLines[i],
+			Message:  "Code formatting issue",
+			Severity: "suggestion",
+			RuleID:   "gofmt",
+			Fix:      fix,
+		}
+		
+		suggestions = append(suggestions, suggestion)
+	}
+	
+	// Then, add type-checked suggestions for issues the linters could not fix themselves
+	if check == nil {
+		if ctx.Err() != nil {
+			return suggestions, nil
+		}
+		version, err := goVersion(l.BaseAnalyzer, options)
+		if err != nil {
+			return suggestions, nil
+		}
+		if check, err = newGoTypeCheck(code, version); err != nil {
+			return suggestions, nil
 		}
 	}
+	session := newGoFixSession(check)
 	
-	// Then, add specific suggestions for common issues
 	for _, issue := range issues {
-		suggestion := l.generateFixForIssue(issue, code)
+		if issue.Fix != nil {
+			continue
+		}
+		if ctx.Err() != nil {
+			break
+		}
+		
+		suggestion := l.generateFixForIssue(session, issue)
 		if suggestion.Fix != nil {
 			suggestions = append(suggestions, suggestion)
 		}
@@ -174,13 +247,43 @@ func (l *GoLinter) SuggestFixes(ctx context.Context, code string, issues []Issue
 	return suggestions, nil
 }
 
-// setupGoModule sets up a minimal Go module for linting
-func (l *GoLinter) setupGoModule(dir string) error {
-	// Create go.mod file
-	goModContent := `module codehawk.temp
+// FormatCode formats code with gofmt, or with goimports when the "formatter" option selects it
+func (l *GoLinter) FormatCode(ctx context.Context, code string, options map[string]interface{}) (string, error) {
+	ctx, cancel := l.CreateTimeoutContext(ctx)
+	defer cancel()
+	
+	formatter := l.GetStringOption(options, "formatter", "gofmt")
+	
+	var cmd *exec.Cmd
+	switch formatter {
+	case "gofmt":
+		args := []string{}
+		if l.GetBoolOption(options, "simplify", false) {
+			args = append(args, "-s")
+		}
+		cmd = exec.CommandContext(ctx, l.GetStringOption(nil, "gofmtPath", "gofmt"), args...)
+	case "goimports":
+		cmd = exec.CommandContext(ctx, l.GetStringOption(nil, "goimportsPath", "goimports"))
+	default:
+		return "", fmt.Errorf("unknown Go formatter: %s", formatter)
+	}
+	
+	var stdout, stderr bytes.Buffer
+	cmd.Stdin = strings.NewReader(code)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	
+	if err := cmd.Run(); err != nil {
+		return "", l.WrapError(formatterRunError(ctx, formatter, err, stderr.String()), formatter+" execution error")
+	}
+	
+	return stdout.String(), nil
+}
 
-go 1.17
-`
+// setupGoModule sets up a minimal Go module of the given Go version for linting
+func (l *GoLinter) setupGoModule(dir, version string) error {
+	// Create go.mod file
+	goModContent := "module codehawk.temp\n\ngo " + version + "\n"
 	goModPath := filepath.Join(dir, "go.mod")
 	if err := ioutil.WriteFile(goModPath, []byte(goModContent), 0644); err != nil {
 		return fmt.Errorf("failed to write go.mod: %w", err)
@@ -189,8 +292,8 @@ go 1.17
 	return nil
 }
 
-// runGolangciLint runs golangci-lint and parses its output
-func (l *GoLinter) runGolangciLint(ctx context.Context, dir string) ([]Issue, error) {
+// runGolangciLint runs golangci-lint and parses its output, keeping the fixes it suggests
+func (l *GoLinter) runGolangciLint(ctx context.Context, dir string, code string) ([]Issue, error) {
 	// Prepare golangci-lint command
 	cmd := exec.CommandContext(
 		ctx,
@@ -216,15 +319,20 @@ func (l *GoLinter) runGolangciLint(ctx context.Context, dir string) ([]Issue, er
 	
 	// Parse the JSON output
 	type GolangciLintIssue struct {
-		FromLinter  string `json:"from_linter"`
-		Text        string `json:"text"`
+		FromLinter  string   `json:"from_linter"`
+		Text        string   `json:"text"`
 		SourceLines []string `json:"source_lines"`
 		Pos         struct {
 			Filename string `json:"filename"`
 			Line     int    `json:"line"`
 			Column   int    `json:"column"`
 		} `json:"pos"`
-		Severity string `json:"severity,omitempty"`
+		Severity    string               `json:"severity,omitempty"`
+		Replacement *golangciReplacement `json:"Replacement"`
+		LineRange   *struct {
+			From int `json:"From"`
+			To   int `json:"To"`
+		} `json:"LineRange"`
 	}
 	
 	type GolangciLintResult struct {
@@ -244,6 +352,11 @@ func (l *GoLinter) runGolangciLint(ctx context.Context, dir string) ([]Issue, er
 			continue
 		}
 		
+		// Type errors are reported by the compile phase
+		if lintIssue.FromLinter == "typecheck" {
+			continue
+		}
+		
 		// Map golangci-lint severity to CodeHawk severity
 		severity := l.BaseAnalyzer.MapSeverity(lintIssue.Severity)
 		if severity == "info" {
@@ -262,6 +375,15 @@ func (l *GoLinter) runGolangciLint(ctx context.Context, dir string) ([]Issue, er
 			Context:  strings.Join(lintIssue.SourceLines, "\n"),
 		}
 		
+		// Use the fix golangci-lint would apply with --fix, when it has one
+		if lintIssue.Replacement != nil {
+			from, to := lintIssue.Pos.Line, lintIssue.Pos.Line
+			if lintIssue.LineRange != nil {
+				from, to = lintIssue.LineRange.From, lintIssue.LineRange.To
+			}
+			issue.Fix = golangciFix(code, lintIssue.Replacement, from, to)
+		}
+		
 		issues = append(issues, issue)
 	}
 	
@@ -341,9 +463,8 @@ func (l *GoLinter) runStaticcheck(ctx context.Context, dir string) ([]Issue, err
 	return issues, nil
 }
 
-// generateFixForIssue attempts to generate a fix for a specific issue
-func (l *GoLinter) generateFixForIssue(issue Issue, code string) Issue {
-	// Create a deep copy of the issue
+// generateFixForIssue attempts to generate a type-checked fix for a specific issue
+func (l *GoLinter) generateFixForIssue(session *goFixSession, issue Issue) Issue {
 	suggestion := Issue{
 		Line:     issue.Line,
 		Column:   issue.Column,
@@ -352,99 +473,45 @@ func (l *GoLinter) generateFixForIssue(issue Issue, code string) Issue {
 		RuleID:   issue.RuleID,
 	}
 	
-	// Get the problematic line
-	lines := strings.Split(code, "\n")
-	if issue.Line <= 0 || issue.Line > len(lines) {
-		return suggestion
-	}
+	var description string
+	var edits []textEdit
 	
-	lineIdx := issue.Line - 1
-	lineText := lines[lineIdx]
+	if matches := unusedVariablePattern.FindStringSubmatch(issue.Message); matches != nil {
+		name := matches[1]
+		if name == "" {
+			name = matches[2]
+		}
+		description, edits = session.fixUnusedVariable(issue, name)
+	} else if matches := unusedDeclPattern.FindStringSubmatch(issue.Message); matches != nil {
+		description, edits = session.fixUnusedDecl(matches[1], matches[2])
+	} else if issue.RuleID == "errcheck" {
+		description, edits = session.fixUncheckedError(issue)
+	} else if matches := renamePattern.FindStringSubmatch(issue.Message); matches != nil && isNamingRule(issue.RuleID) {
+		description, edits = session.fixRename(issue, matches[1], matches[2])
+	}
 	
-	// Generate fix based on rule ID
-	ruleID := issue.RuleID
+	if len(edits) == 0 {
+		return suggestion
+	}
 	
-	switch {
-	case ruleID == "gofmt":
-		// Already handled by gofmt
+	fix, err := buildRangeFix(session.code, description, edits)
+	if err != nil {
 		return suggestion
-		
-	case ruleID == "unused" || strings.Contains(issue.Message, "unused variable"):
-		// Try to find the variable name
-		varNameRegex := regexp.MustCompile(`'([^']+)'`)
-		matches := varNameRegex.FindStringSubmatch(issue.Message)
-		if len(matches) >= 2 {
-			varName := matches[1]
-			// Replace variable with blank identifier
-			suggestion.Fix = &IssueFix{
-				Description: "Replace unused variable with blank identifier",
-				Replacement: strings.Replace(lineText, varName, "_", 1),
-			}
-		}
-		
-	case ruleID == "ineffassign" || strings.Contains(issue.Message, "ineffectual assignment"):
-		// Try to find the variable name
-		varNameRegex := regexp.MustCompile(`to ([^ ]+)`)
-		matches := varNameRegex.FindStringSubmatch(issue.Message)
-		if len(matches) >= 2 {
-			varName := matches[1]
-			// Comment out the ineffectual assignment
-			suggestion.Fix = &IssueFix{
-				Description: "Comment out ineffectual assignment",
-				Replacement: "// " + lineText + " // Ineffectual assignment to " + varName,
-			}
-		}
-		
-	case strings.Contains(ruleID, "SA") || strings.Contains(issue.Message, "should check errors"):
-		// Staticcheck error handling suggestion
-		if strings.Contains(lineText, "=") && !strings.Contains(lineText, "if err") {
-			// Try to add error checking
-			suggestion.Fix = &IssueFix{
-				Description: "Add error checking",
-				Replacement: lineText + "\nif err != nil {\n\treturn err\n}",
-			}
-		}
-		
-	case strings.Contains(ruleID, "ST") || strings.Contains(issue.Message, "should use a simple channel send/receive"):
-		// Staticcheck simplify channel operations
-		if strings.Contains(lineText, "select {") {
-			// Simplify select with single case
-			suggestion.Fix = &IssueFix{
-				Description: "Simplify channel operation",
-				Replacement: "// Replace select with single case by using a simple channel operation",
-			}
-		}
-		
-	case strings.Contains(issue.Message, "exported") && strings.Contains(issue.Message, "comment"):
-		// Add comment for exported declarations
-		if strings.Contains(lineText, "func ") {
-			funcName := strings.TrimSpace(strings.Split(strings.Split(lineText, "func ")[1], "(")[0])
-			suggestion.Fix = &IssueFix{
-				Description: "Add comment for exported function",
-				Replacement: "// " + funcName + " is a function that does something\n" + lineText,
-			}
-		} else if strings.Contains(lineText, "type ") {
-			typeName := strings.TrimSpace(strings.Split(strings.Split(lineText, "type ")[1], " ")[0])
-			suggestion.Fix = &IssueFix{
-				Description: "Add comment for exported type",
-				Replacement: "// " + typeName + " represents something\n" + lineText,
-			}
-		}
-		
-	case strings.Contains(issue.Message, "should be"):
-		// Variable naming suggestions
-		words := strings.Split(issue.Message, " ")
-		for i, word := range words {
-			if word == "should" && i > 0 && i < len(words)-2 {
-				varName := words[i-1]
-				suggestion.Fix = &IssueFix{
-					Description: "Rename variable to follow Go naming conventions",
-					Replacement: strings.ReplaceAll(lineText, varName, words[i+2]),
-				}
-				break
-			}
-		}
+	}
+	
+	// Only offer fixes that still compile, and that resolve the error they target
+	if session.validate(fix, issue.Severity == "error") {
+		suggestion.Fix = fix
 	}
 	
 	return suggestion
-}
\ No newline at end of file
+}
+
+// isNamingRule reports whether a rule reports identifier naming problems
+func isNamingRule(ruleID string) bool {
+	switch ruleID {
+	case "golint", "revive", "stylecheck", "ST1003":
+		return true
+	}
+	return false
+}
@@ -0,0 +1,163 @@
+package analyzer
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestGoAnalyzeSuggestionsAfterSlowBuild(t *testing.T) {
+	code := "package main\n\nfunc main() {\n\tx  := 1\n\t_ = x\n}\n"
+	
+	// The build takes longer than the linters' timeout, which must not cut gofmt short
+	l := NewGoLinter(map[string]string{
+		"timeout":          "1s",
+		"goPath":           fakeScript(t, "sleep 1.5"),
+		"golangciLintPath": fakeTool(t, `{"Issues": []}`),
+		"staticcheckPath":  fakeTool(t, ""),
+		"gofmtPath":        fakeScript(t, "sed 's/x  :=/x :=/'"),
+	})
+	options := map[string]interface{}{"taintAnalysis": false, "concurrencyRules": false, "errorRules": false}
+	
+	result, err := l.Analyze(context.Background(), code, options)
+	if err != nil {
+		t.Fatalf("Analyze() error = %v", err)
+	}
+	if len(result.Suggestions) != 1 || result.Suggestions[0].RuleID != "gofmt" {
+		t.Fatalf("got suggestions %+v, want the gofmt fix", result.Suggestions)
+	}
+	fixed, err := ApplyFix(code, result.Suggestions[0].Line, *result.Suggestions[0].Fix)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "package main\n\nfunc main() {\n\tx := 1\n\t_ = x\n}\n"; fixed != want {
+		t.Errorf("got fixed code %q, want %q", fixed, want)
+	}
+}
+
+func TestGoAnalyzeWithoutTools(t *testing.T) {
+	code := "package main\n\nfunc main() {}\n"
+	missing := filepath.Join(t.TempDir(), "missing")
+	
+	tests := []struct {
+		name   string
+		config map[string]string
+		want   []string
+	}{
+		{
+			name:   "no go command",
+			config: map[string]string{"goPath": missing, "golangciLintPath": fakeTool(t, `{"Issues": []}`), "staticcheckPath": fakeTool(t, "")},
+			want: []string{
+				"The build was skipped: go is not installed on the server",
+				"The golangci-lint analysis was skipped: go is not installed on the server",
+				"The staticcheck analysis was skipped: go is not installed on the server",
+			},
+		},
+		{
+			name:   "no linters",
+			config: map[string]string{"goPath": fakeScript(t, "exit 0"), "golangciLintPath": missing, "staticcheckPath": missing},
+			want: []string{
+				"The golangci-lint analysis was skipped: golangci-lint is not installed on the server",
+				"The staticcheck analysis was skipped: staticcheck is not installed on the server",
+			},
+		},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := NewGoLinter(tt.config)
+			options := map[string]interface{}{"taintAnalysis": false, "concurrencyRules": false, "errorRules": false}
+			
+			issues, _, err := l.findIssues(context.Background(), code, options)
+			if err != nil {
+				t.Fatalf("findIssues() error = %v", err)
+			}
+			var got []string
+			for _, issue := range issues {
+				if issue.RuleID == "analysis-skipped" {
+					got = append(got, issue.Message)
+				}
+			}
+			if strings.Join(got, "\n") != strings.Join(tt.want, "\n") {
+				t.Errorf("got skipped stages %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGoSuggestFixesWithoutGofmt(t *testing.T) {
+	code := "package main\n\nfunc f() int { return 1 }\n\nfunc main() {\n\tx := f()\n}\n"
+	column := 2
+	issues := []Issue{{Line: 6, Column: &column, Message: "declared and not used: x", Severity: "error", RuleID: "compile-error"}}
+	
+	l := NewGoLinter(map[string]string{"gofmtPath": fakeScript(t, "echo 'gofmt: broken' >&2; exit 2")})
+	suggestions, err := l.SuggestFixes(context.Background(), code, issues)
+	if err != nil {
+		t.Fatalf("SuggestFixes() error = %v", err)
+	}
+	if len(suggestions) != 1 || suggestions[0].RuleID != "compile-error" || suggestions[0].Fix == nil {
+		t.Fatalf("got suggestions %+v, want the fix of the unused variable", suggestions)
+	}
+	fixed, err := ApplyFix(code, suggestions[0].Line, *suggestions[0].Fix)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "package main\n\nfunc f() int { return 1 }\n\nfunc main() {\n\t_ = f()\n}\n"; fixed != want {
+		t.Errorf("got fixed code %q, want %q", fixed, want)
+	}
+}
+
+func TestGoFormatCodeErrors(t *testing.T) {
+	tests := []struct {
+		name            string
+		config          map[string]string
+		options         map[string]interface{}
+		wantErr         string
+		wantUnavailable bool
+	}{
+		{
+			name:    "code gofmt rejects",
+			config:  map[string]string{"gofmtPath": fakeScript(t, "echo 'expected declaration' >&2; exit 2")},
+			wantErr: "gofmt execution error",
+		},
+		{
+			name:    "code goimports rejects",
+			config:  map[string]string{"goimportsPath": fakeScript(t, "echo 'expected declaration' >&2; exit 2")},
+			options: map[string]interface{}{"formatter": "goimports"},
+			wantErr: "goimports execution error",
+		},
+		{
+			name:            "missing binary",
+			config:          map[string]string{"gofmtPath": filepath.Join(t.TempDir(), "gofmt")},
+			wantErr:         "gofmt execution error",
+			wantUnavailable: true,
+		},
+		{
+			name:            "binary not on the path",
+			config:          map[string]string{"goimportsPath": "codehawk-no-such-goimports"},
+			options:         map[string]interface{}{"formatter": "goimports"},
+			wantErr:         "goimports execution error",
+			wantUnavailable: true,
+		},
+		{
+			name:            "timeout",
+			config:          map[string]string{"gofmtPath": fakeScript(t, "exec sleep 5"), "timeout": "100ms"},
+			wantErr:         "deadline exceeded",
+			wantUnavailable: true,
+		},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := FormatWith(context.Background(), NewGoLinter(tt.config), "package main\n", tt.options)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("FormatWith() error = %v, want one mentioning %q", err, tt.wantErr)
+			}
+			if errors.Is(err, ErrFormatterUnavailable) != tt.wantUnavailable {
+				t.Errorf("got unavailable %v, want %v: %v", errors.Is(err, ErrFormatterUnavailable), tt.wantUnavailable, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,240 @@
+package analyzer
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/scanner"
+	"go/token"
+)
+
+// goFunctionMetrics computes the metrics of the functions in Go code with go/ast, together with
+// the Halstead volume of the whole file. It reports false when the code does not parse.
+func goFunctionMetrics(code string, lines []lineKind) ([]FunctionMetrics, float64, bool) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "main.go", code, 0)
+	if err != nil {
+		return nil, 0, false
+	}
+	
+	functions := make([]FunctionMetrics, 0)
+	for _, decl := range file.Decls {
+		fn, ok := decl.(*ast.FuncDecl)
+		if !ok || fn.Body == nil {
+			continue
+		}
+		
+		name := fn.Name.Name
+		if fn.Recv != nil && len(fn.Recv.List) > 0 {
+			name = goReceiverName(fn.Recv.List[0].Type) + "." + name
+		}
+		
+		parameters := 0
+		for _, field := range fn.Type.Params.List {
+			if len(field.Names) == 0 {
+				parameters++
+			}
+			parameters += len(field.Names)
+		}
+		
+		start := fset.Position(fn.Pos())
+		end := fset.Position(fn.End())
+		cognitive := &goCognitive{}
+		cognitive.visit(fn.Body, 0)
+		cyclomatic := goCyclomaticComplexity(fn.Body)
+		loc := countLinesOfCode(lines, start.Line, end.Line)
+		
+		functions = append(functions, FunctionMetrics{
+			Name:                 name,
+			Line:                 start.Line,
+			EndLine:              end.Line,
+			LinesOfCode:          loc,
+			Parameters:           parameters,
+			CyclomaticComplexity: cyclomatic,
+			CognitiveComplexity:  cognitive.complexity,
+			NestingDepth:         cognitive.maxNesting,
+			MaintainabilityIndex: maintainabilityIndex(goHalsteadVolume(code[start.Offset:end.Offset]), cyclomatic, loc),
+		})
+	}
+	
+	return functions, goHalsteadVolume(code), true
+}
+
+// goReceiverName returns the name of a method's receiver type
+func goReceiverName(expr ast.Expr) string {
+	switch t := expr.(type) {
+	case *ast.StarExpr:
+		return goReceiverName(t.X)
+	case *ast.IndexExpr:
+		return goReceiverName(t.X)
+	case *ast.IndexListExpr:
+		return goReceiverName(t.X)
+	case *ast.Ident:
+		return t.Name
+	}
+	return "?"
+}
+
+// goHalsteadVolume returns the Halstead volume of Go source. Names and literals are operands,
+// and keywords and operators are operators.
+func goHalsteadVolume(src string) float64 {
+	h := newHalstead()
+	fset := token.NewFileSet()
+	file := fset.AddFile("", fset.Base(), len(src))
+	
+	var s scanner.Scanner
+	s.Init(file, []byte(src), nil, 0)
+	for {
+		_, tok, lit := s.Scan()
+		switch {
+		case tok == token.EOF:
+			return h.volume()
+		case tok == token.SEMICOLON && lit == "\n":
+			// Semicolons inserted at the end of lines are not in the source
+		case tok.IsLiteral():
+			h.operands[lit]++
+		case tok.IsOperator() || tok.IsKeyword():
+			h.operators[tok.String()]++
+		}
+	}
+}
+
+// goCyclomaticComplexity counts the paths through a function body: one, plus one for each
+// condition, loop, case and logical operator
+func goCyclomaticComplexity(body ast.Node) int {
+	complexity := 1
+	ast.Inspect(body, func(node ast.Node) bool {
+		switch n := node.(type) {
+		case *ast.IfStmt, *ast.ForStmt, *ast.RangeStmt:
+			complexity++
+		case *ast.CaseClause:
+			if n.List != nil {
+				complexity++
+			}
+		case *ast.CommClause:
+			if n.Comm != nil {
+				complexity++
+			}
+		case *ast.BinaryExpr:
+			if n.Op == token.LAND || n.Op == token.LOR {
+				complexity++
+			}
+		}
+		return true
+	})
+	return complexity
+}
+
+// goCognitive computes the cognitive complexity of a Go function body: control structures add
+// one plus their nesting level, else branches, labelled jumps and each run of the same logical
+// operator add one, and function literals nest their bodies
+type goCognitive struct {
+	complexity int
+	maxNesting int
+}
+
+// visit walks a node whose control structures are at the given nesting level
+func (c *goCognitive) visit(node ast.Node, nesting int) {
+	if node == nil {
+		return
+	}
+	if nesting > c.maxNesting {
+		c.maxNesting = nesting
+	}
+	
+	ast.Inspect(node, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.IfStmt:
+			c.ifStmt(n, nesting, false)
+			return false
+		case *ast.ForStmt:
+			c.complexity += 1 + nesting
+			c.visitAll(nesting, n.Init, n.Cond, n.Post)
+			c.visit(n.Body, nesting+1)
+			return false
+		case *ast.RangeStmt:
+			c.complexity += 1 + nesting
+			c.visitAll(nesting, n.X)
+			c.visit(n.Body, nesting+1)
+			return false
+		case *ast.SwitchStmt:
+			c.complexity += 1 + nesting
+			c.visitAll(nesting, n.Init, n.Tag)
+			c.visit(n.Body, nesting+1)
+			return false
+		case *ast.TypeSwitchStmt:
+			c.complexity += 1 + nesting
+			c.visitAll(nesting, n.Init, n.Assign)
+			c.visit(n.Body, nesting+1)
+			return false
+		case *ast.SelectStmt:
+			c.complexity += 1 + nesting
+			c.visit(n.Body, nesting+1)
+			return false
+		case *ast.FuncLit:
+			c.visit(n.Body, nesting+1)
+			return false
+		case *ast.BranchStmt:
+			if n.Label != nil {
+				c.complexity++
+			}
+		case *ast.BinaryExpr:
+			if n.Op == token.LAND || n.Op == token.LOR {
+				c.logical(n, nesting)
+				return false
+			}
+		}
+		return true
+	})
+}
+
+// visitAll visits the optional parts of a statement
+func (c *goCognitive) visitAll(nesting int, nodes ...ast.Node) {
+	for _, node := range nodes {
+		c.visit(node, nesting)
+	}
+}
+
+// ifStmt scores an if statement; an else if only adds one, as its nesting is the if's
+func (c *goCognitive) ifStmt(stmt *ast.IfStmt, nesting int, elseIf bool) {
+	if elseIf {
+		c.complexity++
+	} else {
+		c.complexity += 1 + nesting
+	}
+	c.visitAll(nesting, stmt.Init, stmt.Cond)
+	c.visit(stmt.Body, nesting+1)
+	
+	switch els := stmt.Else.(type) {
+	case *ast.IfStmt:
+		c.ifStmt(els, nesting, true)
+	case *ast.BlockStmt:
+		c.complexity++
+		c.visit(els, nesting+1)
+	}
+}
+
+// logical scores a chain of && and || operators, adding one for each change of operator
+func (c *goCognitive) logical(expr *ast.BinaryExpr, nesting int) {
+	operators := make([]token.Token, 0)
+	operands := make([]ast.Expr, 0)
+	var flatten func(e ast.Expr)
+	flatten = func(e ast.Expr) {
+		if b, ok := e.(*ast.BinaryExpr); ok && (b.Op == token.LAND || b.Op == token.LOR) {
+			flatten(b.X)
+			operators = append(operators, b.Op)
+			flatten(b.Y)
+			return
+		}
+		operands = append(operands, e)
+	}
+	flatten(expr)
+	
+	for i, op := range operators {
+		if i == 0 || op != operators[i-1] {
+			c.complexity++
+		}
+	}
+	for _, operand := range operands {
+		c.visit(operand, nesting)
+	}
+}
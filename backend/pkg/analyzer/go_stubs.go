@@ -0,0 +1,393 @@
+package analyzer
+
+import (
+	"fmt"
+	"go/ast"
+	"go/build"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// goStubs are minimal declarations of common third-party packages, so that code using them can
+// be type-checked for the taint analysis and the rule packs. Submitted code is analyzed offline,
+// so the real packages are not available; the stubs only declare the API that handlers commonly use.
+var goStubs = map[string]string{
+	"github.com/gin-gonic/gin": `package gin
+
+import (
+	"context"
+	"mime/multipart"
+	"net/http"
+	"time"
+)
+
+type H map[string]any
+
+type HandlerFunc func(*Context)
+
+type Param struct{ Key, Value string }
+
+type Params []Param
+
+func (ps Params) ByName(name string) string { return "" }
+
+type ResponseWriter interface {
+	http.ResponseWriter
+	Status() int
+	Size() int
+	Written() bool
+}
+
+type Error struct{ Err error }
+
+func (e *Error) Error() string { return "" }
+
+type Context struct {
+	Request *http.Request
+	Writer  ResponseWriter
+	Params  Params
+	Keys    map[string]any
+	Errors  []*Error
+}
+
+var _ context.Context = (*Context)(nil)
+
+func (c *Context) Deadline() (time.Time, bool)                   { return time.Time{}, false }
+func (c *Context) Done() <-chan struct{}                         { return nil }
+func (c *Context) Err() error                                    { return nil }
+func (c *Context) Value(key any) any                             { return nil }
+func (c *Context) Copy() *Context                                { return c }
+func (c *Context) Next()                                         {}
+func (c *Context) Abort()                                        {}
+func (c *Context) IsAborted() bool                               { return false }
+func (c *Context) AbortWithStatus(code int)                      {}
+func (c *Context) AbortWithStatusJSON(code int, obj any)         {}
+func (c *Context) AbortWithError(code int, err error) *Error     { return nil }
+func (c *Context) Error(err error) *Error                        { return nil }
+func (c *Context) Set(key string, value any)                     {}
+func (c *Context) Get(key string) (any, bool)                    { return nil, false }
+func (c *Context) MustGet(key string) any                        { return nil }
+func (c *Context) GetString(key string) string                   { return "" }
+func (c *Context) GetInt(key string) int                         { return 0 }
+func (c *Context) GetBool(key string) bool                       { return false }
+func (c *Context) Param(key string) string                       { return "" }
+func (c *Context) Query(key string) string                       { return "" }
+func (c *Context) DefaultQuery(key, defaultValue string) string  { return "" }
+func (c *Context) GetQuery(key string) (string, bool)            { return "", false }
+func (c *Context) QueryArray(key string) []string                { return nil }
+func (c *Context) GetQueryArray(key string) ([]string, bool)     { return nil, false }
+func (c *Context) QueryMap(key string) map[string]string         { return nil }
+func (c *Context) PostForm(key string) string                    { return "" }
+func (c *Context) DefaultPostForm(key, defaultValue string) string { return "" }
+func (c *Context) GetPostForm(key string) (string, bool)         { return "", false }
+func (c *Context) PostFormArray(key string) []string             { return nil }
+func (c *Context) PostFormMap(key string) map[string]string      { return nil }
+func (c *Context) FormFile(name string) (*multipart.FileHeader, error) { return nil, nil }
+func (c *Context) MultipartForm() (*multipart.Form, error)       { return nil, nil }
+func (c *Context) GetHeader(key string) string                   { return "" }
+func (c *Context) GetRawData() ([]byte, error)                   { return nil, nil }
+func (c *Context) Cookie(name string) (string, error)            { return "", nil }
+func (c *Context) ClientIP() string                              { return "" }
+func (c *Context) ContentType() string                           { return "" }
+func (c *Context) FullPath() string                              { return "" }
+func (c *Context) Bind(obj any) error                            { return nil }
+func (c *Context) BindJSON(obj any) error                        { return nil }
+func (c *Context) BindQuery(obj any) error                       { return nil }
+func (c *Context) BindUri(obj any) error                         { return nil }
+func (c *Context) ShouldBind(obj any) error                      { return nil }
+func (c *Context) ShouldBindJSON(obj any) error                  { return nil }
+func (c *Context) ShouldBindQuery(obj any) error                 { return nil }
+func (c *Context) ShouldBindUri(obj any) error                   { return nil }
+func (c *Context) ShouldBindHeader(obj any) error                { return nil }
+func (c *Context) Status(code int)                               {}
+func (c *Context) Header(key, value string)                      {}
+func (c *Context) SetCookie(name, value string, maxAge int, path, domain string, secure, httpOnly bool) {}
+func (c *Context) JSON(code int, obj any)                        {}
+func (c *Context) IndentedJSON(code int, obj any)                {}
+func (c *Context) XML(code int, obj any)                         {}
+func (c *Context) HTML(code int, name string, obj any)           {}
+func (c *Context) String(code int, format string, values ...any) {}
+func (c *Context) Data(code int, contentType string, data []byte) {}
+func (c *Context) Redirect(code int, location string)            {}
+func (c *Context) File(filepath string)                          {}
+
+type IRoutes interface {
+	Use(...HandlerFunc) IRoutes
+}
+
+type RouterGroup struct{}
+
+func (g *RouterGroup) Use(middleware ...HandlerFunc) IRoutes                 { return nil }
+func (g *RouterGroup) Group(path string, handlers ...HandlerFunc) *RouterGroup { return g }
+func (g *RouterGroup) Handle(method, path string, handlers ...HandlerFunc) IRoutes { return nil }
+func (g *RouterGroup) Any(path string, handlers ...HandlerFunc) IRoutes      { return nil }
+func (g *RouterGroup) GET(path string, handlers ...HandlerFunc) IRoutes      { return nil }
+func (g *RouterGroup) POST(path string, handlers ...HandlerFunc) IRoutes     { return nil }
+func (g *RouterGroup) PUT(path string, handlers ...HandlerFunc) IRoutes      { return nil }
+func (g *RouterGroup) PATCH(path string, handlers ...HandlerFunc) IRoutes    { return nil }
+func (g *RouterGroup) DELETE(path string, handlers ...HandlerFunc) IRoutes   { return nil }
+func (g *RouterGroup) OPTIONS(path string, handlers ...HandlerFunc) IRoutes  { return nil }
+func (g *RouterGroup) Static(path, root string) IRoutes                      { return nil }
+
+type Engine struct{ RouterGroup }
+
+func New() *Engine                                             { return nil }
+func Default() *Engine                                         { return nil }
+func (e *Engine) Run(addr ...string) error                     { return nil }
+func (e *Engine) ServeHTTP(w http.ResponseWriter, r *http.Request) {}
+func (e *Engine) LoadHTMLGlob(pattern string)                  {}
+func (e *Engine) NoRoute(handlers ...HandlerFunc)              {}
+
+const (
+	DebugMode   = "debug"
+	ReleaseMode = "release"
+	TestMode    = "test"
+)
+
+func SetMode(value string) {}
+`,
+	
+	"github.com/jmoiron/sqlx": `package sqlx
+
+import (
+	"context"
+	"database/sql"
+)
+
+type DB struct{ *sql.DB }
+
+type Tx struct{ *sql.Tx }
+
+type Rows struct{ *sql.Rows }
+
+func (r *Rows) StructScan(dest any) error { return nil }
+
+type Row struct{}
+
+func (r *Row) Scan(dest ...any) error       { return nil }
+func (r *Row) StructScan(dest any) error    { return nil }
+
+type Stmt struct{ *sql.Stmt }
+
+func Connect(driverName, dataSourceName string) (*DB, error) { return nil, nil }
+func Open(driverName, dataSourceName string) (*DB, error)    { return nil, nil }
+func NewDb(db *sql.DB, driverName string) *DB                { return nil }
+func In(query string, args ...any) (string, []any, error)    { return "", nil, nil }
+
+func (db *DB) Beginx() (*Tx, error)                                    { return nil, nil }
+func (db *DB) BeginTxx(ctx context.Context, opts *sql.TxOptions) (*Tx, error) { return nil, nil }
+func (db *DB) Rebind(query string) string                              { return query }
+func (db *DB) Queryx(query string, args ...any) (*Rows, error)         { return nil, nil }
+func (db *DB) QueryRowx(query string, args ...any) *Row                { return nil }
+func (db *DB) MustExec(query string, args ...any) sql.Result           { return nil }
+func (db *DB) Preparex(query string) (*Stmt, error)                    { return nil, nil }
+func (db *DB) NamedExec(query string, arg any) (sql.Result, error)     { return nil, nil }
+func (db *DB) NamedQuery(query string, arg any) (*Rows, error)         { return nil, nil }
+func (db *DB) Get(dest any, query string, args ...any) error           { return nil }
+func (db *DB) Select(dest any, query string, args ...any) error        { return nil }
+func (db *DB) QueryxContext(ctx context.Context, query string, args ...any) (*Rows, error) { return nil, nil }
+func (db *DB) QueryRowxContext(ctx context.Context, query string, args ...any) *Row        { return nil }
+func (db *DB) MustExecContext(ctx context.Context, query string, args ...any) sql.Result   { return nil }
+func (db *DB) NamedExecContext(ctx context.Context, query string, arg any) (sql.Result, error) { return nil, nil }
+func (db *DB) GetContext(ctx context.Context, dest any, query string, args ...any) error    { return nil }
+func (db *DB) SelectContext(ctx context.Context, dest any, query string, args ...any) error { return nil }
+
+func (tx *Tx) Queryx(query string, args ...any) (*Rows, error)         { return nil, nil }
+func (tx *Tx) QueryRowx(query string, args ...any) *Row                { return nil }
+func (tx *Tx) MustExec(query string, args ...any) sql.Result           { return nil }
+func (tx *Tx) NamedExec(query string, arg any) (sql.Result, error)     { return nil, nil }
+func (tx *Tx) Get(dest any, query string, args ...any) error           { return nil }
+func (tx *Tx) Select(dest any, query string, args ...any) error        { return nil }
+func (tx *Tx) QueryxContext(ctx context.Context, query string, args ...any) (*Rows, error) { return nil, nil }
+func (tx *Tx) GetContext(ctx context.Context, dest any, query string, args ...any) error    { return nil }
+func (tx *Tx) SelectContext(ctx context.Context, dest any, query string, args ...any) error { return nil }
+`,
+	
+	"github.com/lib/pq": `package pq
+
+func QuoteIdentifier(name string) string { return name }
+func QuoteLiteral(literal string) string { return literal }
+func Array(a any) any                    { return a }
+`,
+}
+
+// goVersionSuffixPattern matches the major version element of a module path, e.g. "v2" or ".v3"
+var goVersionSuffixPattern = regexp.MustCompile(`^v\d+$|\.v\d+$`)
+
+// goStubImporter resolves the stubbed packages and the standard library. Every other package,
+// which is all other third-party packages, is replaced by an empty one, so that only the code
+// using it fails to type-check. The host's GOPATH and module cache are never looked at, so the
+// result does not depend on what happens to be installed on the server.
+type goStubImporter struct {
+	fset     *token.FileSet
+	std      *goStdImporter
+	packages map[string]*types.Package
+	missing  []string
+}
+
+// newGoStubImporter creates an importer that takes the standard library from goStdLibrary
+func newGoStubImporter(fset *token.FileSet) *goStubImporter {
+	return &goStubImporter{
+		fset:     fset,
+		std:      goStdLibrary,
+		packages: make(map[string]*types.Package),
+	}
+}
+
+// Import returns the stub for path when there is one and the real package otherwise
+func (i *goStubImporter) Import(path string) (*types.Package, error) {
+	if pkg, ok := i.packages[path]; ok {
+		return pkg, nil
+	}
+	
+	source, ok := goStubs[path]
+	if !ok {
+		pkg, err := i.std.Import(path)
+		if err != nil {
+			pkg = types.NewPackage(path, goImportName(path))
+			pkg.MarkComplete()
+			i.missing = append(i.missing, path)
+		}
+		i.packages[path] = pkg
+		return pkg, nil
+	}
+	
+	file, err := parser.ParseFile(i.fset, path+"/stub.go", source, 0)
+	if err != nil {
+		return nil, err
+	}
+	
+	config := types.Config{Importer: i}
+	pkg, err := config.Check(path, i.fset, []*ast.File{file}, nil)
+	if err != nil {
+		return nil, err
+	}
+	
+	i.packages[path] = pkg
+	return pkg, nil
+}
+
+// goStdLibrary is the standard library shared by every analysis, so that each package is only
+// loaded from source once per process
+var goStdLibrary = newGoStdImporter(build.Default.GOROOT)
+
+// goStdImporter type-checks standard library packages from GOROOT/src and caches them. Only the
+// declarations are checked. Positions in the cached packages belong to the importer's own file
+// set; the analyses only ever look up positions in the analyzed code.
+type goStdImporter struct {
+	context  build.Context
+	fset     *token.FileSet
+	sizes    types.Sizes
+	mu       sync.Mutex
+	packages map[string]*types.Package
+}
+
+// newGoStdImporter creates an importer for the standard library in goroot. The build context
+// has no GOPATH and cannot call the go command, which would otherwise find packages in the
+// server's own module, and cgo is off so that the pure Go variants of packages are loaded.
+func newGoStdImporter(goroot string) *goStdImporter {
+	context := build.Default
+	context.GOROOT = goroot
+	context.GOPATH = ""
+	context.CgoEnabled = false
+	context.JoinPath = filepath.Join
+	
+	return &goStdImporter{
+		context:  context,
+		fset:     token.NewFileSet(),
+		sizes:    types.SizesFor("gc", context.GOARCH),
+		packages: make(map[string]*types.Package),
+	}
+}
+
+// available returns an error when the standard library sources are not installed
+func (i *goStdImporter) available() error {
+	dir := filepath.Join(i.context.GOROOT, "src")
+	if info, err := os.Stat(filepath.Join(dir, "builtin")); err != nil || !info.IsDir() {
+		return fmt.Errorf("the Go standard library sources are not installed in %s", dir)
+	}
+	return nil
+}
+
+// Import returns the standard library package at path
+func (i *goStdImporter) Import(path string) (*types.Package, error) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	return i.load(path, "")
+}
+
+// ImportFrom lets the standard library packages import vendored packages while being loaded.
+// The type-checker calls it in preference to Import, so it is only called from load, with the
+// lock held.
+func (i *goStdImporter) ImportFrom(path, dir string, _ types.ImportMode) (*types.Package, error) {
+	return i.load(path, dir)
+}
+
+// load type-checks the package that path names when imported from dir
+func (i *goStdImporter) load(path, dir string) (*types.Package, error) {
+	if path == "unsafe" {
+		return types.Unsafe, nil
+	}
+	
+	bp, err := i.context.Import(path, dir, 0)
+	if err != nil {
+		return nil, err
+	}
+	if !bp.Goroot {
+		return nil, fmt.Errorf("%s is not part of the standard library", path)
+	}
+	
+	if pkg, ok := i.packages[bp.ImportPath]; ok {
+		if pkg == nil {
+			return nil, fmt.Errorf("import cycle through %s", bp.ImportPath)
+		}
+		return pkg, nil
+	}
+	i.packages[bp.ImportPath] = nil
+	
+	files := make([]*ast.File, 0, len(bp.GoFiles))
+	for _, name := range bp.GoFiles {
+		file, err := parser.ParseFile(i.fset, filepath.Join(bp.Dir, name), nil, parser.SkipObjectResolution)
+		if err != nil {
+			delete(i.packages, bp.ImportPath)
+			return nil, err
+		}
+		files = append(files, file)
+	}
+	
+	config := types.Config{
+		Importer:         i,
+		Sizes:            i.sizes,
+		IgnoreFuncBodies: true,
+		FakeImportC:      true,
+		// The declarations of a package are usable even if something in it does not check
+		Error: func(error) {},
+	}
+	pkg, err := config.Check(bp.ImportPath, i.fset, files, nil)
+	if pkg == nil {
+		delete(i.packages, bp.ImportPath)
+		return nil, err
+	}
+	
+	i.packages[bp.ImportPath] = pkg
+	return pkg, nil
+}
+
+// goImportName guesses the name of the package at path, which is the last element of the path
+// without a major version or a "go-" prefix or "-go" suffix
+func goImportName(path string) string {
+	elements := strings.Split(path, "/")
+	name := elements[len(elements)-1]
+	if len(elements) > 1 && goVersionSuffixPattern.MatchString(name) && !strings.Contains(name, ".") {
+		name = elements[len(elements)-2]
+	}
+	name = goVersionSuffixPattern.ReplaceAllString(name, "")
+	name = strings.TrimSuffix(strings.TrimPrefix(name, "go-"), "-go")
+	return strings.NewReplacer("-", "_", ".", "_").Replace(name)
+}
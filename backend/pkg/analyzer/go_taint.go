@@ -0,0 +1,662 @@
+package analyzer
+
+import (
+	"context"
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"sort"
+	"strings"
+
+	"golang.org/x/tools/go/ssa"
+)
+
+// goTaintRule describes one kind of injection found by the taint analysis. The message is
+// formatted with the name of the sink.
+type goTaintRule struct {
+	id      string
+	cwe     string
+	message string
+}
+
+var (
+	goTaintSQLInjection = &goTaintRule{
+		id:      "taint-sql-injection",
+		cwe:     "CWE-89",
+		message: "Request input flows into the SQL query passed to %s; pass it as a query parameter instead of building the query from it",
+	}
+	goTaintCommandInjection = &goTaintRule{
+		id:      "taint-command-injection",
+		cwe:     "CWE-78",
+		message: "Request input flows into the command run by %s; validate it against an allowlist and never pass it to a shell",
+	}
+	goTaintXSS = &goTaintRule{
+		id:      "taint-xss",
+		cwe:     "CWE-79",
+		message: "Request input is rendered by %s without HTML escaping; render it with html/template and do not mark it as safe",
+	}
+	goTaintTemplateInjection = &goTaintRule{
+		id:      "taint-template-injection",
+		cwe:     "CWE-1336",
+		message: "Request input is parsed as a template by %s; pass it to the template as data instead",
+	}
+)
+
+// goTaintSink is a function whose arguments must not receive request input
+type goTaintSink struct {
+	rule *goTaintRule
+	// args are the indices of the checked arguments, not counting the receiver. Variadic
+	// arguments arrive as a single slice.
+	args []int
+}
+
+// goTaintSinks maps functions, keyed by package path, receiver type and name, to the arguments they check
+var goTaintSinks = func() map[string]goTaintSink {
+	sinks := map[string]goTaintSink{
+		"os/exec.Command":                        {goTaintCommandInjection, []int{0, 1}},
+		"os/exec.CommandContext":                 {goTaintCommandInjection, []int{1, 2}},
+		"os.StartProcess":                        {goTaintCommandInjection, []int{0, 1}},
+		"syscall.Exec":                           {goTaintCommandInjection, []int{0, 1}},
+		"html/template.Template.Parse":           {goTaintTemplateInjection, []int{0}},
+		"text/template.Template.Parse":           {goTaintTemplateInjection, []int{0}},
+		"text/template.Template.Execute":         {goTaintXSS, []int{1}},
+		"text/template.Template.ExecuteTemplate": {goTaintXSS, []int{2}},
+	}
+	
+	for _, receiver := range []string{"database/sql.DB", "database/sql.Tx", "database/sql.Conn"} {
+		for _, method := range []string{"Query", "QueryRow", "Exec", "Prepare"} {
+			sinks[receiver+"."+method] = goTaintSink{goTaintSQLInjection, []int{0}}
+			sinks[receiver+"."+method+"Context"] = goTaintSink{goTaintSQLInjection, []int{1}}
+		}
+	}
+	
+	for _, receiver := range []string{"github.com/jmoiron/sqlx.DB", "github.com/jmoiron/sqlx.Tx"} {
+		for _, method := range []string{"Queryx", "QueryRowx", "MustExec", "Preparex", "NamedExec", "NamedQuery"} {
+			sinks[receiver+"."+method] = goTaintSink{goTaintSQLInjection, []int{0}}
+			sinks[receiver+"."+method+"Context"] = goTaintSink{goTaintSQLInjection, []int{1}}
+		}
+		for _, method := range []string{"Get", "Select"} {
+			sinks[receiver+"."+method] = goTaintSink{goTaintSQLInjection, []int{1}}
+			sinks[receiver+"."+method+"Context"] = goTaintSink{goTaintSQLInjection, []int{2}}
+		}
+	}
+	
+	return sinks
+}()
+
+// goTaintSafeHTMLTypes are the html/template types that disable escaping for the converted value
+var goTaintSafeHTMLTypes = map[string]bool{
+	"HTML": true, "HTMLAttr": true, "JS": true, "JSStr": true, "CSS": true, "URL": true, "Srcset": true,
+}
+
+// goTaintSanitizers return values that are safe to use in any sink
+var goTaintSanitizers = map[string]bool{
+	"html.EscapeString":                 true,
+	"html/template.HTMLEscapeString":    true,
+	"html/template.JSEscapeString":      true,
+	"text/template.HTMLEscapeString":    true,
+	"net/url.QueryEscape":               true,
+	"net/url.PathEscape":                true,
+	"strconv.Quote":                     true,
+	"github.com/lib/pq.QuoteIdentifier": true,
+	"github.com/lib/pq.QuoteLiteral":    true,
+	"net/http.Request.Context":          true,
+}
+
+// goTaintGinContext is the key prefix of gin.Context methods
+const goTaintGinContext = "github.com/gin-gonic/gin.Context."
+
+// goTaintGinGetters are the gin.Context methods returning request input
+var goTaintGinGetters = map[string]bool{
+	"Param": true, "Query": true, "DefaultQuery": true, "GetQuery": true, "QueryArray": true,
+	"GetQueryArray": true, "QueryMap": true, "GetQueryMap": true, "PostForm": true,
+	"DefaultPostForm": true, "GetPostForm": true, "PostFormArray": true, "GetPostFormArray": true,
+	"PostFormMap": true, "GetPostFormMap": true, "FormFile": true, "MultipartForm": true,
+	"GetHeader": true, "GetRawData": true, "Cookie": true,
+}
+
+// taintStep is one hop of the path from a source to a sink; prev points back towards the source
+type taintStep struct {
+	pos  token.Pos
+	desc string
+	prev *taintStep
+}
+
+// goTaintAnalysis tracks request input through the SSA form of a package. Tainted values are
+// mapped to the last step of the path that reached them; memory is tracked per allocation, so a
+// struct is tainted as a whole when any of its fields is.
+type goTaintAnalysis struct {
+	fset       *token.FileSet
+	pkg        *ssa.Package
+	callStarts map[token.Pos]token.Pos
+	tainted    map[ssa.Value]*taintStep
+	results    map[*ssa.Function]*taintStep
+	reported   map[ssa.Instruction]bool
+	issues     []Issue
+	changed    bool
+}
+
+// runTaintAnalysis reports request input that reaches SQL queries, commands and templates. The
+// code is type-checked against the standard library and stubs of gin and sqlx; functions that use
+// other third-party packages do not type-check and are left out.
+func (l *GoLinter) runTaintAnalysis(ctx context.Context, check *goTypeCheck) ([]Issue, error) {
+	ssaPkg, err := goTaintSSA(check)
+	if err != nil {
+		return nil, err
+	}
+	file, info := check.file, check.info
+	
+	analysis := &goTaintAnalysis{
+		fset:       check.fset,
+		pkg:        ssaPkg,
+		callStarts: make(map[token.Pos]token.Pos),
+		tainted:    make(map[ssa.Value]*taintStep),
+		results:    make(map[*ssa.Function]*taintStep),
+		reported:   make(map[ssa.Instruction]bool),
+		issues:     make([]Issue, 0),
+	}
+	
+	// SSA positions calls at their opening parenthesis; issues point at the start of the call
+	ast.Inspect(file, func(node ast.Node) bool {
+		if call, ok := node.(*ast.CallExpr); ok {
+			analysis.callStarts[call.Lparen] = call.Pos()
+		}
+		return true
+	})
+	
+	// Every declared function and method is analyzed, whether or not anything calls it
+	functions := make([]*ssa.Function, 0)
+	var addFunction func(fn *ssa.Function)
+	addFunction = func(fn *ssa.Function) {
+		if fn == nil || fn.Blocks == nil {
+			return
+		}
+		functions = append(functions, fn)
+		for _, anonymous := range fn.AnonFuncs {
+			addFunction(anonymous)
+		}
+	}
+	for _, decl := range file.Decls {
+		if funcDecl, ok := decl.(*ast.FuncDecl); ok {
+			if obj, ok := info.Defs[funcDecl.Name].(*types.Func); ok {
+				addFunction(ssaPkg.Prog.FuncValue(obj))
+			}
+		}
+	}
+	if init, ok := ssaPkg.Members["init"].(*ssa.Function); ok {
+		addFunction(init)
+	}
+	
+	// Every *http.Request parameter is a source
+	for _, fn := range functions {
+		for _, param := range fn.Params {
+			if goTaintIsType(param.Type(), "net/http", "Request") {
+				analysis.taint(param, &taintStep{
+					pos:  param.Pos(),
+					desc: fmt.Sprintf("untrusted request %s received", param.Name()),
+				})
+			}
+		}
+	}
+	
+	// Propagate until nothing changes; the taint of a value only ever grows
+	for analysis.changed = true; analysis.changed; {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		
+		analysis.changed = false
+		for _, fn := range functions {
+			for _, block := range fn.Blocks {
+				for _, instr := range block.Instrs {
+					analysis.visit(fn, instr)
+				}
+			}
+		}
+	}
+	
+	sort.SliceStable(analysis.issues, func(i, j int) bool {
+		return analysis.issues[i].Line < analysis.issues[j].Line
+	})
+	return analysis.issues, nil
+}
+
+// goTaintSSA builds the SSA form of the code. Functions that are not well typed are built without
+// a body, and package variables whose initializers are not are left uninitialized, so that the
+// rest of the code can still be analyzed.
+func goTaintSSA(check *goTypeCheck) (pkg *ssa.Package, err error) {
+	// The builder expects well-typed code, and what is left of the rest may still surprise it
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("failed to build SSA: %v", r)
+		}
+	}()
+	
+	file := *check.file
+	file.Decls = make([]ast.Decl, len(check.file.Decls))
+	for i, decl := range check.file.Decls {
+		if funcDecl, ok := decl.(*ast.FuncDecl); ok && funcDecl.Body != nil && !check.wellTyped(funcDecl) {
+			external := *funcDecl
+			external.Body = nil
+			decl = &external
+		}
+		file.Decls[i] = decl
+	}
+	
+	info := *check.info
+	info.InitOrder = make([]*types.Initializer, 0, len(check.info.InitOrder))
+	for _, initializer := range check.info.InitOrder {
+		if check.wellTyped(initializer.Rhs) {
+			info.InitOrder = append(info.InitOrder, initializer)
+		}
+	}
+	
+	// Every package the code depends on needs an SSA package, although only the code is built
+	prog := ssa.NewProgram(check.fset, ssa.BuilderMode(0))
+	created := make(map[*types.Package]bool)
+	var createAll func(pkgs []*types.Package)
+	createAll = func(pkgs []*types.Package) {
+		for _, p := range pkgs {
+			if !created[p] {
+				created[p] = true
+				prog.CreatePackage(p, nil, nil, true)
+				createAll(p.Imports())
+			}
+		}
+	}
+	createAll(check.pkg.Imports())
+	
+	pkg = prog.CreatePackage(check.pkg, []*ast.File{&file}, &info, false)
+	pkg.Build()
+	return pkg, nil
+}
+
+// visit propagates taint through a single instruction
+func (a *goTaintAnalysis) visit(fn *ssa.Function, instr ssa.Instruction) {
+	switch instr := instr.(type) {
+	case *ssa.Store:
+		if step := a.tainted[instr.Val]; step != nil {
+			// Assignments to variables are part of the path; stores into temporaries are not
+			if alloc, ok := goTaintRoot(instr.Addr).(*ssa.Alloc); ok && alloc.Comment != "" && alloc.Comment != "varargs" {
+				step = a.step(instr.Pos(), "assigned to "+alloc.Comment, step)
+			}
+			a.taintMemory(instr.Addr, step)
+		}
+		
+	case *ssa.MapUpdate:
+		if step := a.firstTainted(instr.Key, instr.Value); step != nil {
+			a.taintMemory(instr.Map, step)
+		}
+		
+	case *ssa.Send:
+		if step := a.tainted[instr.X]; step != nil {
+			a.taintMemory(instr.Chan, step)
+		}
+		
+	case *ssa.Return:
+		if step := a.firstTainted(instr.Results...); step != nil && a.results[fn] == nil {
+			a.results[fn] = a.step(instr.Pos(), "returned from "+fn.Name(), step)
+			a.changed = true
+		}
+		
+	case *ssa.MakeClosure:
+		callee := instr.Fn.(*ssa.Function)
+		for i, binding := range instr.Bindings {
+			if step := a.tainted[binding]; step != nil && i < len(callee.FreeVars) {
+				a.taint(callee.FreeVars[i], step)
+			}
+		}
+		
+	case ssa.CallInstruction:
+		a.visitCall(instr)
+		
+	case *ssa.UnOp:
+		step := a.tainted[instr.X]
+		if step == nil && instr.Op == token.MUL {
+			step = a.tainted[goTaintRoot(instr.X)]
+		}
+		a.taint(instr, step)
+		
+	case *ssa.FieldAddr:
+		if goTaintIsType(instr.X.Type(), "github.com/gin-gonic/gin", "Context") {
+			a.taintGinField(instr, instr.Field, instr.X.Type())
+			return
+		}
+		a.taint(instr, a.tainted[instr.X])
+		
+	case *ssa.Field:
+		if goTaintIsType(instr.X.Type(), "github.com/gin-gonic/gin", "Context") {
+			a.taintGinField(instr, instr.Field, instr.X.Type())
+			return
+		}
+		a.taint(instr, a.tainted[instr.X])
+		
+	case *ssa.Index:
+		a.taint(instr, a.tainted[instr.X])
+		
+	case *ssa.IndexAddr:
+		a.taint(instr, a.tainted[instr.X])
+		
+	case *ssa.Lookup:
+		a.taint(instr, a.tainted[instr.X])
+		
+	case *ssa.ChangeType:
+		a.visitConversion(instr, instr.X)
+		
+	case *ssa.Convert:
+		a.visitConversion(instr, instr.X)
+		
+	case *ssa.BinOp:
+		if step := a.firstTainted(instr.X, instr.Y); step != nil {
+			if instr.Op == token.ADD {
+				step = a.step(instr.Pos(), "concatenated into a string", step)
+			}
+			a.taint(instr, step)
+		}
+		
+	case *ssa.Alloc, *ssa.MakeMap, *ssa.MakeSlice, *ssa.MakeChan:
+		// New values start out clean
+		
+	case ssa.Value:
+		for _, operand := range instr.(ssa.Instruction).Operands(nil) {
+			if *operand != nil {
+				if step := a.tainted[*operand]; step != nil {
+					a.taint(instr, step)
+					return
+				}
+			}
+		}
+	}
+}
+
+// visitConversion propagates taint through a conversion and reports conversions of request
+// input to the html/template types that bypass escaping
+func (a *goTaintAnalysis) visitConversion(instr ssa.Value, x ssa.Value) {
+	step := a.tainted[x]
+	if step == nil {
+		return
+	}
+	
+	if named := goTaintNamed(instr.Type()); named != nil && named.Obj().Pkg() != nil &&
+		named.Obj().Pkg().Path() == "html/template" && goTaintSafeHTMLTypes[named.Obj().Name()] {
+		a.report(instr.(ssa.Instruction), instr.Pos(), goTaintXSS, "template."+named.Obj().Name(), step)
+		return
+	}
+	
+	a.taint(instr, step)
+}
+
+// taintGinField makes reads of gin.Context.Request and gin.Context.Params sources
+func (a *goTaintAnalysis) taintGinField(instr ssa.Value, field int, contextType types.Type) {
+	structType, ok := goTaintNamed(contextType).Underlying().(*types.Struct)
+	if !ok || field >= structType.NumFields() {
+		return
+	}
+	
+	name := structType.Field(field).Name()
+	if name == "Request" || name == "Params" {
+		a.taint(instr, a.step(instr.Pos(), "untrusted input read from gin.Context."+name, nil))
+	}
+}
+
+// visitCall propagates taint through calls and checks the arguments of sinks
+func (a *goTaintAnalysis) visitCall(call ssa.CallInstruction) {
+	common := call.Common()
+	result := call.Value()
+	pos := call.Pos()
+	
+	if builtin, ok := common.Value.(*ssa.Builtin); ok {
+		step := a.firstTainted(common.Args...)
+		if step == nil {
+			return
+		}
+		if builtin.Name() == "copy" && len(common.Args) > 0 {
+			a.taintMemory(common.Args[0], step)
+		} else if result != nil {
+			a.taint(result, step)
+		}
+		return
+	}
+	
+	// Functions of the analyzed package are followed into their bodies
+	callee := common.StaticCallee()
+	if callee != nil && callee.Pkg == a.pkg && callee.Blocks != nil {
+		for i, arg := range common.Args {
+			if step := a.tainted[arg]; step != nil && i < len(callee.Params) {
+				desc := fmt.Sprintf("passed to %s as %s", callee.Name(), callee.Params[i].Name())
+				a.taint(callee.Params[i], a.step(pos, desc, step))
+			}
+		}
+		if step := a.results[callee]; step != nil && result != nil {
+			a.taint(result, step)
+		}
+		return
+	}
+	
+	var method *types.Func
+	var receiver ssa.Value
+	args := common.Args
+	if common.IsInvoke() {
+		method = common.Method
+		receiver = common.Value
+	} else if callee != nil {
+		method, _ = callee.Object().(*types.Func)
+		if callee.Signature.Recv() != nil && len(args) > 0 {
+			receiver = args[0]
+			args = args[1:]
+		}
+	}
+	
+	key, name := "", "a function value"
+	if method != nil {
+		key, name = goTaintFuncKey(method)
+	}
+	
+	// Sinks report the flow; their results are not request input
+	if sink, ok := goTaintSinks[key]; ok {
+		for _, index := range sink.args {
+			if index < len(args) {
+				if step := a.tainted[args[index]]; step != nil {
+					a.report(call, pos, sink.rule, name, step)
+					break
+				}
+			}
+		}
+		return
+	}
+	
+	if goTaintSanitizers[key] {
+		return
+	}
+	
+	// gin.Context is not itself request input, but its getters and Bind methods return it
+	if strings.HasPrefix(key, goTaintGinContext) {
+		methodName := method.Name()
+		switch {
+		case goTaintGinGetters[methodName] && result != nil:
+			a.taint(result, a.step(pos, "untrusted input read by "+name, nil))
+		case (strings.HasPrefix(methodName, "Bind") || strings.HasPrefix(methodName, "ShouldBind")) && len(args) > 0:
+			a.taintMemory(args[0], a.step(pos, "request body bound by "+name, nil))
+		}
+		return
+	}
+	
+	// Other functions pass taint from any input to their result and to what their arguments point to
+	step := a.firstTainted(args...)
+	if step == nil && receiver != nil {
+		step = a.tainted[receiver]
+	}
+	if step == nil {
+		return
+	}
+	
+	step = a.step(pos, "passed through "+name, step)
+	if result != nil {
+		a.taint(result, step)
+	}
+	for _, arg := range args {
+		if goTaintMutable(arg) {
+			a.taintMemory(arg, step)
+		}
+	}
+	
+	// Writers such as strings.Builder and bytes.Buffer accumulate what is written to them
+	if receiver != nil && !common.IsInvoke() && goTaintMutable(receiver) &&
+		(strings.HasPrefix(method.Name(), "Write") || method.Name() == "Add" || method.Name() == "Set") {
+		a.taintMemory(receiver, step)
+	}
+}
+
+// taint marks v as tainted by step unless it already is, or its type cannot carry an injection
+func (a *goTaintAnalysis) taint(v ssa.Value, step *taintStep) {
+	if v == nil || step == nil || a.tainted[v] != nil || !goTaintable(v.Type()) {
+		return
+	}
+	a.tainted[v] = step
+	a.changed = true
+}
+
+// taintMemory taints a pointer, slice or map together with the variable it refers to
+func (a *goTaintAnalysis) taintMemory(v ssa.Value, step *taintStep) {
+	a.taint(v, step)
+	
+	root := goTaintRoot(v)
+	a.taint(root, step)
+	
+	// A pointer loaded from a variable, as for variables captured by closures
+	if load, ok := root.(*ssa.UnOp); ok && load.Op == token.MUL {
+		a.taint(goTaintRoot(load.X), step)
+	}
+}
+
+// firstTainted returns the step of the first tainted value
+func (a *goTaintAnalysis) firstTainted(values ...ssa.Value) *taintStep {
+	for _, v := range values {
+		if step := a.tainted[v]; step != nil {
+			return step
+		}
+	}
+	return nil
+}
+
+// step extends a path with a hop at pos. Hops without a position in the code are left out.
+func (a *goTaintAnalysis) step(pos token.Pos, desc string, prev *taintStep) *taintStep {
+	if !pos.IsValid() && prev != nil {
+		return prev
+	}
+	if start, ok := a.callStarts[pos]; ok {
+		pos = start
+	}
+	return &taintStep{pos: pos, desc: desc, prev: prev}
+}
+
+// report records a flow into a sink, with the path from the source as related locations
+func (a *goTaintAnalysis) report(instr ssa.Instruction, pos token.Pos, rule *goTaintRule, sink string, step *taintStep) {
+	if a.reported[instr] {
+		return
+	}
+	a.reported[instr] = true
+	
+	if start, ok := a.callStarts[pos]; ok {
+		pos = start
+	}
+	position := a.fset.Position(pos)
+	
+	path := make([]RelatedLocation, 0)
+	for s := step; s != nil; s = s.prev {
+		location := a.fset.Position(s.pos)
+		if !s.pos.IsValid() || location.Filename != goSourceFile {
+			continue
+		}
+		if len(path) > 0 && path[0].Line == location.Line && path[0].Message == s.desc {
+			continue
+		}
+		path = append([]RelatedLocation{{Line: location.Line, Column: location.Column, Message: s.desc}}, path...)
+	}
+	
+	column := position.Column
+	a.issues = append(a.issues, Issue{
+		Line:             position.Line,
+		Column:           &column,
+		Message:          fmt.Sprintf(rule.message, sink),
+		Severity:         "error",
+		RuleID:           rule.id,
+		Context:          "https://cwe.mitre.org/data/definitions/" + strings.TrimPrefix(rule.cwe, "CWE-") + ".html",
+		Category:         CategorySecurity,
+		CWE:              rule.cwe,
+		Confidence:       "medium",
+		RelatedLocations: path,
+	})
+}
+
+// goTaintRoot returns the variable a pointer, slice or interface value refers to
+func goTaintRoot(v ssa.Value) ssa.Value {
+	for {
+		switch value := v.(type) {
+		case *ssa.FieldAddr:
+			v = value.X
+		case *ssa.IndexAddr:
+			v = value.X
+		case *ssa.Slice:
+			v = value.X
+		case *ssa.MakeInterface:
+			v = value.X
+		case *ssa.ChangeType:
+			v = value.X
+		default:
+			return v
+		}
+	}
+}
+
+// goTaintable reports whether values of type t can carry an injection; numbers and booleans cannot
+func goTaintable(t types.Type) bool {
+	if basic, ok := t.Underlying().(*types.Basic); ok {
+		return basic.Info()&(types.IsNumeric|types.IsBoolean) == 0
+	}
+	return true
+}
+
+// goTaintMutable reports whether a call can write request input through the value
+func goTaintMutable(v ssa.Value) bool {
+	switch v.Type().Underlying().(type) {
+	case *types.Pointer, *types.Slice, *types.Map:
+		return true
+	case *types.Interface:
+		if value, ok := v.(*ssa.MakeInterface); ok {
+			return goTaintMutable(value.X)
+		}
+	}
+	return false
+}
+
+// goTaintNamed returns the named type of t, looking through a pointer
+func goTaintNamed(t types.Type) *types.Named {
+	if pointer, ok := t.(*types.Pointer); ok {
+		t = pointer.Elem()
+	}
+	named, _ := t.(*types.Named)
+	return named
+}
+
+// goTaintIsType reports whether t is the named type, or a pointer to it
+func goTaintIsType(t types.Type, pkgPath, name string) bool {
+	named := goTaintNamed(t)
+	return named != nil && named.Obj().Pkg() != nil && named.Obj().Pkg().Path() == pkgPath && named.Obj().Name() == name
+}
+
+// goTaintFuncKey returns the lookup key of a function, such as "database/sql.DB.Query", and
+// the name used in messages, such as "sql.DB.Query"
+func goTaintFuncKey(fn *types.Func) (string, string) {
+	name := fn.Name()
+	if recv := fn.Type().(*types.Signature).Recv(); recv != nil {
+		if named := goTaintNamed(recv.Type()); named != nil {
+			name = named.Obj().Name() + "." + name
+		}
+	}
+	if fn.Pkg() == nil {
+		return name, name
+	}
+	return fn.Pkg().Path() + "." + name, fn.Pkg().Name() + "." + name
+}
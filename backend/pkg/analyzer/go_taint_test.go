@@ -0,0 +1,268 @@
+package analyzer
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestGoTaintAnalysis(t *testing.T) {
+	tests := []struct {
+		name     string
+		code     string
+		want     []string
+		wantLine int
+	}{
+		{
+			name: "query built from a request parameter",
+			code: `package main
+
+import (
+	"database/sql"
+	"net/http"
+)
+
+var db *sql.DB
+
+func handler(w http.ResponseWriter, r *http.Request) {
+	name := r.URL.Query().Get("name")
+	query := "SELECT * FROM users WHERE name = '" + name + "'"
+	rows, _ := db.Query(query)
+	defer rows.Close()
+}
+`,
+			want:     []string{"taint-sql-injection"},
+			wantLine: 13,
+		},
+		{
+			name: "query parameter",
+			code: `package main
+
+import (
+	"database/sql"
+	"net/http"
+)
+
+var db *sql.DB
+
+func handler(w http.ResponseWriter, r *http.Request) {
+	rows, _ := db.Query("SELECT * FROM users WHERE name = $1", r.FormValue("name"))
+	defer rows.Close()
+}
+`,
+			want: []string{},
+		},
+		{
+			name: "numbers cannot carry an injection",
+			code: `package main
+
+import (
+	"database/sql"
+	"fmt"
+	"net/http"
+	"strconv"
+)
+
+var db *sql.DB
+
+func handler(w http.ResponseWriter, r *http.Request) {
+	id, _ := strconv.Atoi(r.FormValue("id"))
+	db.Exec(fmt.Sprintf("DELETE FROM users WHERE id = %d", id))
+}
+`,
+			want: []string{},
+		},
+		{
+			name: "command through a helper function",
+			code: `package main
+
+import (
+	"net/http"
+	"os/exec"
+)
+
+func archive(dir string) error {
+	return exec.Command("sh", "-c", "tar czf out.tgz "+dir).Run()
+}
+
+func handler(w http.ResponseWriter, r *http.Request) {
+	archive(r.FormValue("dir"))
+}
+`,
+			want:     []string{"taint-command-injection"},
+			wantLine: 9,
+		},
+		{
+			name: "request input marked as safe HTML",
+			code: `package main
+
+import (
+	"html/template"
+	"net/http"
+)
+
+var page = template.Must(template.New("page").Parse("{{.}}"))
+
+func handler(w http.ResponseWriter, r *http.Request) {
+	page.Execute(w, template.HTML(r.FormValue("bio")))
+}
+`,
+			want:     []string{"taint-xss"},
+			wantLine: 11,
+		},
+		{
+			name: "escaped input",
+			code: `package main
+
+import (
+	"html"
+	"html/template"
+	"net/http"
+)
+
+var page = template.Must(template.New("page").Parse("{{.}}"))
+
+func handler(w http.ResponseWriter, r *http.Request) {
+	page.Execute(w, template.HTML(html.EscapeString(r.FormValue("bio"))))
+}
+`,
+			want: []string{},
+		},
+		{
+			name: "gin handler",
+			code: `package main
+
+import (
+	"fmt"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jmoiron/sqlx"
+)
+
+var db *sqlx.DB
+
+func handler(c *gin.Context) {
+	var users []string
+	db.Select(&users, fmt.Sprintf("SELECT name FROM users WHERE team = '%s'", c.Query("team")))
+}
+`,
+			want:     []string{"taint-sql-injection"},
+			wantLine: 14,
+		},
+	}
+	
+	l := NewGoLinter(map[string]string{})
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			check, err := newGoTypeCheck(tt.code, goLanguageVersion)
+			if err != nil {
+				t.Fatalf("newGoTypeCheck() error = %v", err)
+			}
+			issues, err := l.runTaintAnalysis(context.Background(), check)
+			if err != nil {
+				t.Fatalf("runTaintAnalysis() error = %v", err)
+			}
+			if len(issues) != len(tt.want) {
+				t.Fatalf("got %d issues, want %v: %+v", len(issues), tt.want, issues)
+			}
+			for i, issue := range issues {
+				if issue.RuleID != tt.want[i] || issue.Category != CategorySecurity || issue.CWE == "" {
+					t.Errorf("issue %d = %+v, want a security issue %s", i, issue, tt.want[i])
+				}
+			}
+			if len(issues) > 0 && issues[0].Line != tt.wantLine {
+				t.Errorf("issue at line %d, want %d", issues[0].Line, tt.wantLine)
+			}
+		})
+	}
+}
+
+func TestGoTaintPath(t *testing.T) {
+	code := `package main
+
+import (
+	"net/http"
+	"os/exec"
+)
+
+func handler(w http.ResponseWriter, r *http.Request) {
+	host := r.FormValue("host")
+	exec.Command("ping", "-c", "1", host).Run()
+}
+`
+	check, err := newGoTypeCheck(code, goLanguageVersion)
+	if err != nil {
+		t.Fatalf("newGoTypeCheck() error = %v", err)
+	}
+	issues, err := NewGoLinter(map[string]string{}).runTaintAnalysis(context.Background(), check)
+	if err != nil {
+		t.Fatalf("runTaintAnalysis() error = %v", err)
+	}
+	if len(issues) != 1 {
+		t.Fatalf("got %d issues, want 1: %+v", len(issues), issues)
+	}
+	
+	issue := issues[0]
+	if issue.Line != 10 || issue.Column == nil || *issue.Column != 2 {
+		t.Errorf("issue at %d:%v, want 10:2", issue.Line, issue.Column)
+	}
+	if !strings.Contains(issue.Message, "exec.Command") {
+		t.Errorf("message = %q, want it to name exec.Command", issue.Message)
+	}
+	
+	// The path starts at the request parameter and ends where the input is read
+	path := issue.RelatedLocations
+	if len(path) < 2 {
+		t.Fatalf("related locations = %+v, want the path from the source", path)
+	}
+	if path[0].Line != 8 || !strings.Contains(path[0].Message, "untrusted request r") {
+		t.Errorf("path starts with %+v, want the request parameter on line 8", path[0])
+	}
+	if last := path[len(path)-1]; last.Line != 9 || !strings.Contains(last.Message, "FormValue") {
+		t.Errorf("path ends with %+v, want the FormValue call on line 9", last)
+	}
+}
+
+func TestGoTaintPartialTypes(t *testing.T) {
+	code := `package main
+
+import (
+	"database/sql"
+	"net/http"
+
+	"github.com/redis/go-redis/v9"
+)
+
+var db *sql.DB
+
+var cache = redis.NewClient(nil)
+
+func cached(w http.ResponseWriter, r *http.Request) {
+	cache.Get(r.Context(), r.FormValue("key"))
+}
+
+func missing(w http.ResponseWriter, r *http.Request) {
+	undefinedHelper(r.FormValue("id"))
+}
+
+func handler(w http.ResponseWriter, r *http.Request) {
+	db.Query("SELECT * FROM users WHERE name = '" + r.FormValue("name") + "'")
+}
+`
+	check, err := newGoTypeCheck(code, goLanguageVersion)
+	if err != nil {
+		t.Fatalf("newGoTypeCheck() error = %v", err)
+	}
+	if len(check.errors) == 0 {
+		t.Fatal("got no type errors, want the undefined helper reported")
+	}
+	
+	// The functions that do not type-check are left out, and the handler is still analyzed
+	issues, err := NewGoLinter(map[string]string{}).runTaintAnalysis(context.Background(), check)
+	if err != nil {
+		t.Fatalf("runTaintAnalysis() error = %v", err)
+	}
+	if len(issues) != 1 || issues[0].RuleID != "taint-sql-injection" || issues[0].Line != 23 {
+		t.Errorf("got issues %+v, want the SQL injection on line 23", issues)
+	}
+}
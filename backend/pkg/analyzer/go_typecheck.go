@@ -0,0 +1,179 @@
+package analyzer
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"go/types"
+)
+
+// goSourceFile is the name the analyzed code is parsed under
+const goSourceFile = "main.go"
+
+// goPackagePath is the import path the analyzed code is type-checked as
+const goPackagePath = "codehawk/analysis"
+
+// goLanguageVersion is the Go version the analyzed code is built, scanned and type-checked as
+// unless the "goVersion" option selects another, which decides the language features it may use
+const goLanguageVersion = "1.22"
+
+// goVersions are the Go versions a request may select for the analyzed code. Before Go 1.22 the
+// iterations of a loop share its variables, which the rule packs then report captures of.
+var goVersions = map[string]bool{
+	"1.16": true,
+	"1.17": true,
+	"1.18": true,
+	"1.19": true,
+	"1.20": true,
+	"1.21": true,
+	"1.22": true,
+}
+
+// goVersion returns the Go version the "goVersion" option selects for the analyzed code
+func goVersion(b *BaseAnalyzer, options map[string]interface{}) (string, error) {
+	version := b.GetStringOption(options, "goVersion", goLanguageVersion)
+	if !goVersions[version] {
+		return "", fmt.Errorf("unknown Go version %q", version)
+	}
+	return version, nil
+}
+
+// goTypeCheck is the analyzed code parsed and type-checked once for the taint analysis, the rule
+// packs and the fix session, which would otherwise each repeat it. Type errors do not stop the
+// check, so the stages work with the information that could be worked out. The importer is kept
+// for the fix session, which checks every candidate fix.
+type goTypeCheck struct {
+	code     string
+	version  string
+	fset     *token.FileSet
+	importer *goStubImporter
+	file     *ast.File
+	pkg      *types.Package
+	info     *types.Info
+	errors   []types.Error
+}
+
+// newGoTypeCheck parses and type-checks code as Go version
+func newGoTypeCheck(code, version string) (*goTypeCheck, error) {
+	// Without the standard library nothing would type-check
+	if err := goStdLibrary.available(); err != nil {
+		return nil, err
+	}
+	
+	fset := token.NewFileSet()
+	check := &goTypeCheck{
+		code:     code,
+		version:  version,
+		fset:     fset,
+		importer: newGoStubImporter(fset),
+	}
+	
+	file, err := parser.ParseFile(fset, goSourceFile, code, parser.ParseComments)
+	if err != nil {
+		return nil, err
+	}
+	
+	check.file = file
+	check.pkg, check.info, check.errors = check.check(file)
+	return check, nil
+}
+
+// check type-checks file with the shared importer, returning the type errors
+func (c *goTypeCheck) check(file *ast.File) (*types.Package, *types.Info, []types.Error) {
+	info := &types.Info{
+		Types:      make(map[ast.Expr]types.TypeAndValue),
+		Defs:       make(map[*ast.Ident]types.Object),
+		Uses:       make(map[*ast.Ident]types.Object),
+		Implicits:  make(map[ast.Node]types.Object),
+		Instances:  make(map[*ast.Ident]types.Instance),
+		Selections: make(map[*ast.SelectorExpr]*types.Selection),
+		Scopes:     make(map[ast.Node]*types.Scope),
+	}
+	
+	errs := make([]types.Error, 0)
+	config := &types.Config{
+		Importer:  c.importer,
+		GoVersion: "go" + c.version,
+		// Keep going after the first error so that partial information is available
+		Error: func(err error) {
+			if typeErr, ok := err.(types.Error); ok {
+				errs = append(errs, typeErr)
+			}
+		},
+	}
+	
+	pkg, _ := config.Check(goPackagePath, c.fset, []*ast.File{file}, info)
+	return pkg, info, errs
+}
+
+// wellTyped reports whether node has no type errors and uses nothing of invalid type, so that
+// it can be compiled to SSA. Uses of invalid values are not errors of their own, and expressions
+// of invalid type are left out of the type information, so the identifiers are checked as well.
+func (c *goTypeCheck) wellTyped(node ast.Node) bool {
+	for _, err := range c.errors {
+		if node.Pos() <= err.Pos && err.Pos < node.End() {
+			return false
+		}
+	}
+	
+	valid := true
+	ast.Inspect(node, func(n ast.Node) bool {
+		if expr, ok := n.(ast.Expr); ok && valid {
+			if tv, ok := c.info.Types[expr]; ok && goHasInvalidType(tv.Type, make(map[types.Type]bool)) {
+				valid = false
+			}
+		}
+		if ident, ok := n.(*ast.Ident); ok && valid {
+			switch obj := c.info.ObjectOf(ident).(type) {
+			case nil, *types.PkgName, *types.Label, *types.Builtin:
+				// These have no type of their own
+			default:
+				if goHasInvalidType(obj.Type(), make(map[types.Type]bool)) {
+					valid = false
+				}
+			}
+		}
+		return valid
+	})
+	return valid
+}
+
+// goHasInvalidType reports whether t is or is built from the invalid type. Named types are not
+// looked into, as only their use through fields shows in the expressions that are checked.
+func goHasInvalidType(t types.Type, seen map[types.Type]bool) bool {
+	if t == nil || seen[t] {
+		return false
+	}
+	seen[t] = true
+	
+	switch t := t.(type) {
+	case *types.Basic:
+		return t.Kind() == types.Invalid
+	case *types.Pointer:
+		return goHasInvalidType(t.Elem(), seen)
+	case *types.Slice:
+		return goHasInvalidType(t.Elem(), seen)
+	case *types.Array:
+		return goHasInvalidType(t.Elem(), seen)
+	case *types.Chan:
+		return goHasInvalidType(t.Elem(), seen)
+	case *types.Map:
+		return goHasInvalidType(t.Key(), seen) || goHasInvalidType(t.Elem(), seen)
+	case *types.Tuple:
+		for i := 0; i < t.Len(); i++ {
+			if goHasInvalidType(t.At(i).Type(), seen) {
+				return true
+			}
+		}
+	case *types.Signature:
+		return goHasInvalidType(t.Params(), seen) || goHasInvalidType(t.Results(), seen)
+	case *types.Struct:
+		for i := 0; i < t.NumFields(); i++ {
+			if goHasInvalidType(t.Field(i).Type(), seen) {
+				return true
+			}
+		}
+	}
+	return false
+}
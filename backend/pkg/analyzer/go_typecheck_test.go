@@ -0,0 +1,120 @@
+package analyzer
+
+import (
+	"go/ast"
+	"go/build"
+	"strings"
+	"testing"
+)
+
+func TestGoTypeCheckWellTyped(t *testing.T) {
+	code := `package main
+
+import "github.com/redis/go-redis/v9"
+
+type store struct {
+	client *redis.Client
+	db     *sqlx.DB
+}
+
+func typed(n int) int {
+	return n * 2
+}
+
+func undefined() {
+	helper()
+}
+
+func fieldOfUndefinedPackage(s *store) {
+	s.db.Close()
+}
+
+func unresolvedImport(s *store) {
+	s.client.Close()
+}
+`
+	check, err := newGoTypeCheck(code, goLanguageVersion)
+	if err != nil {
+		t.Fatalf("newGoTypeCheck() error = %v", err)
+	}
+	
+	want := map[string]bool{
+		"typed":                   true,
+		"undefined":               false,
+		"fieldOfUndefinedPackage": false,
+		"unresolvedImport":        false,
+	}
+	for _, decl := range check.file.Decls {
+		if funcDecl, ok := decl.(*ast.FuncDecl); ok {
+			t.Run(funcDecl.Name.Name, func(t *testing.T) {
+				if got := check.wellTyped(funcDecl); got != want[funcDecl.Name.Name] {
+					t.Errorf("wellTyped() = %v, want %v", got, want[funcDecl.Name.Name])
+				}
+			})
+		}
+	}
+}
+
+func TestGoTypeCheckInvalidCode(t *testing.T) {
+	if _, err := newGoTypeCheck("package main\nfunc {", goLanguageVersion); err == nil {
+		t.Error("newGoTypeCheck() accepted code that does not parse")
+	}
+}
+
+func TestGoTypeCheckLanguageVersion(t *testing.T) {
+	code := `package main
+
+func main() {
+	for i := range 3 {
+		_ = min(i, 1)
+	}
+	for range func(yield func() bool) {} {
+	}
+}
+`
+	check, err := newGoTypeCheck(code, goLanguageVersion)
+	if err != nil {
+		t.Fatalf("newGoTypeCheck() error = %v", err)
+	}
+	
+	// Ranging over integers came with Go 1.22, over functions with Go 1.23
+	if len(check.errors) != 1 || check.fset.Position(check.errors[0].Pos).Line != 7 {
+		t.Errorf("got type errors %v, want one on the range over a function", check.errors)
+	}
+}
+
+func TestGoStdImporter(t *testing.T) {
+	std := newGoStdImporter(build.Default.GOROOT)
+	if err := std.available(); err != nil {
+		t.Skipf("standard library sources not installed: %v", err)
+	}
+	
+	first, err := std.Import("net/http")
+	if err != nil {
+		t.Fatalf("Import(net/http) error = %v", err)
+	}
+	if first.Scope().Lookup("Request") == nil {
+		t.Error("Import(net/http) has no Request type")
+	}
+	
+	// Packages are loaded once and shared
+	second, err := std.Import("net/http")
+	if err != nil || second != first {
+		t.Errorf("second Import(net/http) = %p, %v, want the cached %p", second, err, first)
+	}
+	
+	// Packages outside the standard library are never taken from the host, even when the
+	// server's own module could provide them
+	for _, path := range []string{"golang.org/x/tools/go/ssa", "github.com/redis/go-redis/v9", "example.com/missing"} {
+		if _, err := std.Import(path); err == nil {
+			t.Errorf("Import(%s) succeeded, want an error", path)
+		}
+	}
+}
+
+func TestGoStdImporterMissingSources(t *testing.T) {
+	std := newGoStdImporter(t.TempDir())
+	if err := std.available(); err == nil || !strings.Contains(err.Error(), "not installed") {
+		t.Errorf("available() = %v, want an error naming the missing sources", err)
+	}
+}
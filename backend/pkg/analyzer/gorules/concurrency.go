@@ -0,0 +1,831 @@
+package gorules
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/astutil"
+	"golang.org/x/tools/go/ast/inspector"
+)
+
+// Concurrency is the rule pack for concurrency hazards
+var Concurrency = []*analysis.Analyzer{
+	GoroutineLeak,
+	UnstoppedTicker,
+	LoopVarCapture,
+	MutexCopy,
+	SelectCtxDone,
+}
+
+// GoroutineLeak reports goroutines that can never exit
+var GoroutineLeak = &analysis.Analyzer{
+	Name:     "goroutineleak",
+	Doc:      "report goroutines that loop forever or block forever on an unbuffered channel send",
+	Requires: []*analysis.Analyzer{inspect.Analyzer},
+	Run:      runGoroutineLeak,
+}
+
+// UnstoppedTicker reports tickers that are never stopped
+var UnstoppedTicker = &analysis.Analyzer{
+	Name:     "unstoppedticker",
+	Doc:      "report time.NewTicker tickers that are never stopped and uses of time.Tick",
+	Requires: []*analysis.Analyzer{inspect.Analyzer},
+	Run:      runUnstoppedTicker,
+}
+
+// LoopVarCapture reports loop variables captured by goroutines and deferred closures in code
+// built as a Go version before 1.22
+var LoopVarCapture = &analysis.Analyzer{
+	Name:     "loopvarcapture",
+	Doc:      "report loop variables captured by goroutines and deferred closures started in the loop",
+	Requires: []*analysis.Analyzer{inspect.Analyzer},
+	Run:      runLoopVarCapture,
+}
+
+// MutexCopy reports values containing locks that are copied
+var MutexCopy = &analysis.Analyzer{
+	Name:     "mutexcopy",
+	Doc:      "report value receivers, parameters, assignments and range variables that copy a sync lock",
+	Requires: []*analysis.Analyzer{inspect.Analyzer},
+	Run:      runMutexCopy,
+}
+
+// SelectCtxDone reports blocking selects that ignore the cancellation of an available context
+var SelectCtxDone = &analysis.Analyzer{
+	Name:     "selectctxdone",
+	Doc:      "report blocking select statements without a ctx.Done() case in functions that have a context",
+	Requires: []*analysis.Analyzer{inspect.Analyzer},
+	Run:      runSelectCtxDone,
+}
+
+// funcDecls maps the functions and methods of the package to their declarations
+func funcDecls(pass *analysis.Pass) map[types.Object]*ast.FuncDecl {
+	decls := make(map[types.Object]*ast.FuncDecl)
+	for _, file := range pass.Files {
+		for _, decl := range file.Decls {
+			if fn, ok := decl.(*ast.FuncDecl); ok && fn.Body != nil {
+				decls[pass.TypesInfo.Defs[fn.Name]] = fn
+			}
+		}
+	}
+	return decls
+}
+
+func runGoroutineLeak(pass *analysis.Pass) (interface{}, error) {
+	inspect := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+	decls := funcDecls(pass)
+	
+	// Goroutines running an endless loop
+	inspect.Preorder([]ast.Node{(*ast.GoStmt)(nil)}, func(node ast.Node) {
+		stmt := node.(*ast.GoStmt)
+		
+		var body *ast.BlockStmt
+		switch fn := astutil.Unparen(stmt.Call.Fun).(type) {
+		case *ast.FuncLit:
+			body = fn.Body
+		default:
+			if decl := decls[calleeFunc(pass.TypesInfo, stmt.Call)]; decl != nil {
+				body = decl.Body
+			}
+		}
+		if body == nil {
+			return
+		}
+		
+		if loop := endlessLoop(pass, body); loop != nil {
+			pass.Report(analysis.Diagnostic{
+				Pos:      stmt.Pos(),
+				End:      stmt.Call.Pos(),
+				Category: "goroutine-leak",
+				Message: fmt.Sprintf("Goroutine runs an endless loop (line %d) with no way to stop it, so it leaks; "+
+					"pass it a context or a stop channel and return when it is done", pass.Fset.Position(loop.Pos()).Line),
+			})
+		}
+	})
+	
+	// Goroutines sending on an unbuffered channel that the receiver may stop waiting for
+	inspect.Preorder([]ast.Node{(*ast.FuncDecl)(nil), (*ast.FuncLit)(nil)}, func(node ast.Node) {
+		var body *ast.BlockStmt
+		switch fn := node.(type) {
+		case *ast.FuncDecl:
+			body = fn.Body
+		case *ast.FuncLit:
+			body = fn.Body
+		}
+		if body != nil {
+			checkAbandonedSends(pass, body)
+		}
+	})
+	
+	return nil, nil
+}
+
+// endlessLoop returns the first loop in body, outside nested functions, that can never end
+func endlessLoop(pass *analysis.Pass, body *ast.BlockStmt) ast.Stmt {
+	var found ast.Stmt
+	labels := make(map[ast.Stmt]string)
+	ast.Inspect(body, func(node ast.Node) bool {
+		if found != nil {
+			return false
+		}
+		switch loop := node.(type) {
+		case *ast.FuncLit:
+			return false
+		case *ast.LabeledStmt:
+			labels[loop.Stmt] = loop.Label.Name
+		case *ast.ForStmt:
+			if loop.Cond == nil && !loopCanExit(pass, loop, labels[loop]) {
+				found = loop
+			}
+		case *ast.RangeStmt:
+			// A ticker's channel is never closed
+			if isTickerChannel(pass, loop.X) && !loopCanExit(pass, loop, labels[loop]) {
+				found = loop
+			}
+		}
+		return true
+	})
+	return found
+}
+
+// isTickerChannel reports whether expr is the C field of a time.Ticker or a time.Tick channel
+func isTickerChannel(pass *analysis.Pass, expr ast.Expr) bool {
+	switch x := astutil.Unparen(expr).(type) {
+	case *ast.SelectorExpr:
+		return x.Sel.Name == "C" && isNamedType(pass.TypesInfo.TypeOf(x.X), "time", "Ticker")
+	case *ast.CallExpr:
+		return isFunc(calleeFunc(pass.TypesInfo, x), "time", "Tick")
+	}
+	return false
+}
+
+// loopCanExit reports whether a loop contains a return, a break out of the loop, a goto or a
+// call that ends the goroutine
+func loopCanExit(pass *analysis.Pass, loop ast.Stmt, label string) bool {
+	exits := false
+	var stack []ast.Node
+	ast.Inspect(loop, func(node ast.Node) bool {
+		if exits {
+			return false
+		}
+		if node == nil {
+			stack = stack[:len(stack)-1]
+			return false
+		}
+		
+		switch stmt := node.(type) {
+		case *ast.FuncLit:
+			return false
+		case *ast.ReturnStmt:
+			exits = true
+		case *ast.BranchStmt:
+			switch {
+			case stmt.Tok == token.GOTO:
+				exits = true
+			case stmt.Tok == token.BREAK && stmt.Label != nil:
+				exits = stmt.Label.Name == label
+			case stmt.Tok == token.BREAK:
+				exits = innermostBreakable(stack) == loop
+			}
+		case *ast.CallExpr:
+			exits = endsGoroutine(pass, stmt)
+		}
+		
+		stack = append(stack, node)
+		return true
+	})
+	return exits
+}
+
+// innermostBreakable returns the innermost statement in stack that an unlabeled break leaves
+func innermostBreakable(stack []ast.Node) ast.Node {
+	for i := len(stack) - 1; i >= 0; i-- {
+		switch stack[i].(type) {
+		case *ast.ForStmt, *ast.RangeStmt, *ast.SwitchStmt, *ast.TypeSwitchStmt, *ast.SelectStmt:
+			return stack[i]
+		}
+	}
+	return nil
+}
+
+// endsGoroutine reports whether a call never returns to its caller
+func endsGoroutine(pass *analysis.Pass, call *ast.CallExpr) bool {
+	if id, ok := astutil.Unparen(call.Fun).(*ast.Ident); ok && pass.TypesInfo.Uses[id] == types.Universe.Lookup("panic") {
+		return true
+	}
+	fn := calleeFunc(pass.TypesInfo, call)
+	for _, name := range []string{"Fatal", "Fatalf", "Fatalln", "Panic", "Panicf", "Panicln"} {
+		if isFunc(fn, "log", name) {
+			return true
+		}
+	}
+	return isFunc(fn, "os", "Exit") || isFunc(fn, "runtime", "Goexit")
+}
+
+// checkAbandonedSends reports goroutines sending on a local unbuffered channel when the function
+// receives from it in a select that can take another case, leaving the sender blocked forever
+func checkAbandonedSends(pass *analysis.Pass, body *ast.BlockStmt) {
+	// Unbuffered channels made in this function
+	channels := make(map[types.Object]*ast.CallExpr)
+	ast.Inspect(body, func(node ast.Node) bool {
+		if _, ok := node.(*ast.FuncLit); ok {
+			return false
+		}
+		assign, ok := node.(*ast.AssignStmt)
+		if !ok || len(assign.Lhs) != len(assign.Rhs) {
+			return true
+		}
+		for i, rhs := range assign.Rhs {
+			call, ok := astutil.Unparen(rhs).(*ast.CallExpr)
+			if !ok || len(call.Args) != 1 {
+				continue
+			}
+			if id, ok := call.Fun.(*ast.Ident); ok && id.Name == "make" {
+				if t := pass.TypesInfo.TypeOf(call); t != nil {
+					if _, ok := t.Underlying().(*types.Chan); ok {
+						if obj := identObject(pass.TypesInfo, assign.Lhs[i]); obj != nil {
+							channels[obj] = call
+						}
+					}
+				}
+			}
+		}
+		return true
+	})
+	if len(channels) == 0 {
+		return
+	}
+	
+	// Selects in this function that may stop waiting for one of the channels
+	abandoned := make(map[types.Object]*ast.SelectStmt)
+	ast.Inspect(body, func(node ast.Node) bool {
+		if _, ok := node.(*ast.FuncLit); ok {
+			return false
+		}
+		sel, ok := node.(*ast.SelectStmt)
+		if !ok || len(sel.Body.List) < 2 {
+			return true
+		}
+		for _, clause := range sel.Body.List {
+			if obj := receivedChannel(pass, clause.(*ast.CommClause).Comm); obj != nil && channels[obj] != nil {
+				abandoned[obj] = sel
+			}
+		}
+		return true
+	})
+	
+	// Sends in goroutines started by this function
+	reported := make(map[types.Object]bool)
+	ast.Inspect(body, func(node ast.Node) bool {
+		stmt, ok := node.(*ast.GoStmt)
+		if !ok {
+			return true
+		}
+		lit, ok := astutil.Unparen(stmt.Call.Fun).(*ast.FuncLit)
+		if !ok {
+			return true
+		}
+		ast.Inspect(lit.Body, func(node ast.Node) bool {
+			send, ok := node.(*ast.SendStmt)
+			if !ok {
+				return true
+			}
+			obj := identObject(pass.TypesInfo, send.Chan)
+			sel := abandoned[obj]
+			if sel == nil || reported[obj] {
+				return true
+			}
+			reported[obj] = true
+			
+			makeCall := channels[obj]
+			pass.Report(analysis.Diagnostic{
+				Pos:      send.Pos(),
+				End:      send.End(),
+				Category: "goroutine-leak",
+				Message: fmt.Sprintf("Goroutine blocks forever sending on unbuffered channel %s when the select on line %d takes another case; "+
+					"give the channel a buffer of 1 so that the send always completes", obj.Name(), pass.Fset.Position(sel.Pos()).Line),
+				SuggestedFixes: []analysis.SuggestedFix{{
+					Message: fmt.Sprintf("Make %s buffered", obj.Name()),
+					TextEdits: []analysis.TextEdit{{
+						Pos:     makeCall.Rparen,
+						End:     makeCall.Rparen,
+						NewText: []byte(", 1"),
+					}},
+				}},
+			})
+			return true
+		})
+		return false
+	})
+}
+
+// receivedChannel returns the variable a select case receives from, if it is a plain identifier
+func receivedChannel(pass *analysis.Pass, comm ast.Stmt) types.Object {
+	var expr ast.Expr
+	switch stmt := comm.(type) {
+	case *ast.ExprStmt:
+		expr = stmt.X
+	case *ast.AssignStmt:
+		if len(stmt.Rhs) == 1 {
+			expr = stmt.Rhs[0]
+		}
+	}
+	recv, ok := astutil.Unparen(expr).(*ast.UnaryExpr)
+	if !ok || recv.Op != token.ARROW {
+		return nil
+	}
+	return identObject(pass.TypesInfo, recv.X)
+}
+
+func runUnstoppedTicker(pass *analysis.Pass) (interface{}, error) {
+	inspect := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+	
+	inspect.Preorder([]ast.Node{(*ast.FuncDecl)(nil)}, func(node ast.Node) {
+		decl := node.(*ast.FuncDecl)
+		if decl.Body == nil {
+			return
+		}
+		
+		ast.Inspect(decl.Body, func(node ast.Node) bool {
+			call, ok := node.(*ast.CallExpr)
+			if !ok {
+				return true
+			}
+			fn := calleeFunc(pass.TypesInfo, call)
+			
+			// time.Tick cannot be stopped; it is fine in main, which never returns
+			if isFunc(fn, "time", "Tick") && !(decl.Name.Name == "main" && decl.Recv == nil && pass.Pkg.Name() == "main") {
+				pass.Report(analysis.Diagnostic{
+					Pos:      call.Pos(),
+					End:      call.End(),
+					Category: "unstopped-ticker",
+					Message:  "time.Tick creates a ticker that can never be stopped; use time.NewTicker and call Stop when done",
+				})
+			}
+			return true
+		})
+		
+		checkTickers(pass, decl)
+	})
+	
+	return nil, nil
+}
+
+// checkTickers reports tickers created in a function that are neither stopped nor handed to other code
+func checkTickers(pass *analysis.Pass, decl *ast.FuncDecl) {
+	type ticker struct {
+		assign *ast.AssignStmt
+		call   *ast.CallExpr
+	}
+	tickers := make(map[types.Object]*ticker)
+	order := make([]types.Object, 0)
+	
+	ast.Inspect(decl.Body, func(node ast.Node) bool {
+		assign, ok := node.(*ast.AssignStmt)
+		if !ok || len(assign.Lhs) != len(assign.Rhs) {
+			return true
+		}
+		for i, rhs := range assign.Rhs {
+			call, ok := astutil.Unparen(rhs).(*ast.CallExpr)
+			if !ok || !isFunc(calleeFunc(pass.TypesInfo, call), "time", "NewTicker") {
+				continue
+			}
+			// Tickers stored in fields or elsewhere are managed by other code
+			if obj := identObject(pass.TypesInfo, assign.Lhs[i]); obj != nil && obj.Parent() != pass.Pkg.Scope() {
+				if _, seen := tickers[obj]; !seen {
+					order = append(order, obj)
+				}
+				tickers[obj] = &ticker{assign: assign, call: call}
+			}
+		}
+		return true
+	})
+	if len(tickers) == 0 {
+		return
+	}
+	
+	// A ticker is handled when it is stopped, or escapes by any use other than reading C or calling Reset
+	handled := make(map[types.Object]bool)
+	goroutines := make(map[types.Object]*ast.FuncLit)
+	var stack []ast.Node
+	ast.Inspect(decl.Body, func(node ast.Node) bool {
+		if node == nil {
+			stack = stack[:len(stack)-1]
+			return false
+		}
+		stack = append(stack, node)
+		
+		id, ok := node.(*ast.Ident)
+		if !ok {
+			return true
+		}
+		obj := pass.TypesInfo.Uses[id]
+		if tickers[obj] == nil {
+			return true
+		}
+		
+		// Reading C or resetting the ticker does not hand it to other code
+		parent := stack[len(stack)-2]
+		if selector, ok := parent.(*ast.SelectorExpr); !ok || selector.X != id || (selector.Sel.Name != "C" && selector.Sel.Name != "Reset") {
+			handled[obj] = true
+		}
+		
+		// Remember the goroutine the ticker is used in
+		for i := len(stack) - 1; i > 0; i-- {
+			if lit, ok := stack[i].(*ast.FuncLit); ok {
+				if goStmt, ok := stack[i-1].(*ast.CallExpr); ok && goStmt.Fun == lit && i > 1 {
+					if _, ok := stack[i-2].(*ast.GoStmt); ok {
+						goroutines[obj] = lit
+					}
+				}
+				break
+			}
+		}
+		return true
+	})
+	
+	for _, obj := range order {
+		if handled[obj] {
+			continue
+		}
+		t := tickers[obj]
+		
+		// A ticker used by a goroutine is stopped when the goroutine exits; otherwise when the function returns
+		var fix analysis.SuggestedFix
+		if lit := goroutines[obj]; lit != nil && len(lit.Body.List) > 0 {
+			fix = insertBefore(pass, "Stop the ticker when the goroutine exits", lit.Body.List[0].Pos(), "defer "+obj.Name()+".Stop()")
+		} else if inBlock(decl.Body, t.assign) {
+			fix = analysis.SuggestedFix{
+				Message: "Stop the ticker when the function returns",
+				TextEdits: []analysis.TextEdit{{
+					Pos:     t.assign.End(),
+					End:     t.assign.End(),
+					NewText: []byte("\n" + lineIndent(pass, t.assign.Pos()) + "defer " + obj.Name() + ".Stop()"),
+				}},
+			}
+		}
+		
+		diagnostic := analysis.Diagnostic{
+			Pos:      t.call.Pos(),
+			End:      t.call.End(),
+			Category: "unstopped-ticker",
+			Message: fmt.Sprintf("Ticker %s is never stopped, so it keeps firing and is never garbage collected; "+
+				"call %s.Stop() when it is no longer needed", obj.Name(), obj.Name()),
+		}
+		if fix.Message != "" {
+			diagnostic.SuggestedFixes = []analysis.SuggestedFix{fix}
+		}
+		pass.Report(diagnostic)
+	}
+}
+
+// inBlock reports whether stmt is directly in a block, where a statement can be added after it
+func inBlock(body *ast.BlockStmt, stmt ast.Stmt) bool {
+	found := false
+	ast.Inspect(body, func(node ast.Node) bool {
+		if block, ok := node.(*ast.BlockStmt); ok {
+			for _, s := range block.List {
+				if s == stmt {
+					found = true
+				}
+			}
+		}
+		return !found
+	})
+	return found
+}
+
+func runLoopVarCapture(pass *analysis.Pass) (interface{}, error) {
+	inspect := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+	
+	// Since Go 1.22 closures capture the variables of their own iteration
+	modern := make(map[*token.File]bool)
+	for _, file := range pass.Files {
+		modern[pass.Fset.File(file.Pos())] = perIterationLoopVars(pass, file)
+	}
+	
+	inspect.Preorder([]ast.Node{(*ast.RangeStmt)(nil), (*ast.ForStmt)(nil)}, func(node ast.Node) {
+		if modern[pass.Fset.File(node.Pos())] {
+			return
+		}
+		
+		// The variables declared by the loop
+		var vars []ast.Expr
+		var body *ast.BlockStmt
+		switch loop := node.(type) {
+		case *ast.RangeStmt:
+			if loop.Tok != token.DEFINE {
+				return
+			}
+			vars = []ast.Expr{loop.Key, loop.Value}
+			body = loop.Body
+		case *ast.ForStmt:
+			init, ok := loop.Init.(*ast.AssignStmt)
+			if !ok || init.Tok != token.DEFINE {
+				return
+			}
+			vars = init.Lhs
+			body = loop.Body
+		}
+		
+		loopVars := make(map[types.Object]bool)
+		for _, v := range vars {
+			if obj := identObject(pass.TypesInfo, v); obj != nil && obj.Name() != "_" {
+				loopVars[obj] = true
+			}
+		}
+		if len(loopVars) == 0 || len(body.List) == 0 {
+			return
+		}
+		
+		reported := make(map[types.Object]bool)
+		ast.Inspect(body, func(node ast.Node) bool {
+			var lit *ast.FuncLit
+			kind := ""
+			switch stmt := node.(type) {
+			case *ast.GoStmt:
+				lit, _ = astutil.Unparen(stmt.Call.Fun).(*ast.FuncLit)
+				kind = "a goroutine"
+			case *ast.DeferStmt:
+				lit, _ = astutil.Unparen(stmt.Call.Fun).(*ast.FuncLit)
+				kind = "a deferred function"
+			case *ast.CallExpr:
+				// errgroup.Group.Go and similar run the function concurrently
+				if selector, ok := stmt.Fun.(*ast.SelectorExpr); ok && selector.Sel.Name == "Go" && len(stmt.Args) == 1 {
+					lit, _ = astutil.Unparen(stmt.Args[0]).(*ast.FuncLit)
+					kind = "a goroutine"
+				}
+			}
+			if lit == nil {
+				return true
+			}
+			
+			ast.Inspect(lit.Body, func(node ast.Node) bool {
+				id, ok := node.(*ast.Ident)
+				if !ok {
+					return true
+				}
+				obj := pass.TypesInfo.Uses[id]
+				if !loopVars[obj] || reported[obj] {
+					return true
+				}
+				reported[obj] = true
+				
+				pass.Report(analysis.Diagnostic{
+					Pos:      id.Pos(),
+					End:      id.End(),
+					Category: "loop-var-capture",
+					Message: fmt.Sprintf("Loop variable %s is captured by %s started in the loop; before Go 1.22 every iteration shares the variable, "+
+						"so it may see a later value. Pass %s as an argument or copy it with %s := %s", obj.Name(), kind, obj.Name(), obj.Name(), obj.Name()),
+					SuggestedFixes: []analysis.SuggestedFix{
+						insertBefore(pass, fmt.Sprintf("Copy %s for each iteration", obj.Name()), body.List[0].Pos(), obj.Name()+" := "+obj.Name()),
+					},
+				})
+				return true
+			})
+			return false
+		})
+	})
+	
+	return nil, nil
+}
+
+// syncLockTypes are the sync types that must not be copied after first use
+var syncLockTypes = map[string]bool{
+	"Mutex": true, "RWMutex": true, "WaitGroup": true, "Once": true, "Cond": true, "Map": true, "Pool": true,
+}
+
+// containedLock returns the name of a sync lock that values of t contain, or "" if there is none
+func containedLock(t types.Type, seen map[types.Type]bool) string {
+	if t == nil || seen[t] {
+		return ""
+	}
+	seen[t] = true
+	
+	if named, ok := t.(*types.Named); ok && named.Obj().Pkg() != nil &&
+		named.Obj().Pkg().Path() == "sync" && syncLockTypes[named.Obj().Name()] {
+		return "sync." + named.Obj().Name()
+	}
+	
+	switch underlying := t.Underlying().(type) {
+	case *types.Struct:
+		for i := 0; i < underlying.NumFields(); i++ {
+			if lock := containedLock(underlying.Field(i).Type(), seen); lock != "" {
+				return lock
+			}
+		}
+	case *types.Array:
+		return containedLock(underlying.Elem(), seen)
+	}
+	return ""
+}
+
+// lockIn returns the sync lock that values of t contain, or ""
+func lockIn(t types.Type) string {
+	return containedLock(t, make(map[types.Type]bool))
+}
+
+// isExistingValue reports whether expr refers to a value that already exists, as opposed to a
+// new value such as a composite literal or a call result
+func isExistingValue(expr ast.Expr) bool {
+	switch x := astutil.Unparen(expr).(type) {
+	case *ast.Ident:
+		return x.Name != "nil"
+	case *ast.SelectorExpr, *ast.StarExpr, *ast.IndexExpr:
+		return true
+	}
+	return false
+}
+
+func runMutexCopy(pass *analysis.Pass) (interface{}, error) {
+	inspect := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+	
+	report := func(node ast.Node, message string, fixes ...analysis.SuggestedFix) {
+		pass.Report(analysis.Diagnostic{
+			Pos:            node.Pos(),
+			End:            node.End(),
+			Category:       "mutex-copy",
+			Message:        message,
+			SuggestedFixes: fixes,
+		})
+	}
+	
+	checkParams := func(fn *ast.FuncType) {
+		if fn.Params == nil {
+			return
+		}
+		for _, field := range fn.Params.List {
+			if lock := lockIn(pass.TypesInfo.TypeOf(field.Type)); lock != "" {
+				report(field.Type, fmt.Sprintf("Parameter passes a copy of a value containing a %s, so the copy is locked and unlocked independently; pass a pointer instead", lock))
+			}
+		}
+	}
+	
+	nodes := []ast.Node{
+		(*ast.FuncDecl)(nil), (*ast.FuncLit)(nil), (*ast.AssignStmt)(nil), (*ast.ValueSpec)(nil),
+		(*ast.RangeStmt)(nil), (*ast.ReturnStmt)(nil),
+	}
+	inspect.Preorder(nodes, func(node ast.Node) {
+		switch stmt := node.(type) {
+		case *ast.FuncDecl:
+			if stmt.Recv != nil && len(stmt.Recv.List) == 1 {
+				field := stmt.Recv.List[0]
+				if _, isPointer := field.Type.(*ast.StarExpr); !isPointer {
+					if lock := lockIn(pass.TypesInfo.TypeOf(field.Type)); lock != "" {
+						report(field.Type, fmt.Sprintf("Method %s has a value receiver, so every call works on a copy of the %s; use a pointer receiver", stmt.Name.Name, lock),
+							analysis.SuggestedFix{
+								Message:   "Use a pointer receiver",
+								TextEdits: []analysis.TextEdit{{Pos: field.Type.Pos(), End: field.Type.Pos(), NewText: []byte("*")}},
+							})
+					}
+				}
+			}
+			checkParams(stmt.Type)
+			
+		case *ast.FuncLit:
+			checkParams(stmt.Type)
+			
+		case *ast.AssignStmt:
+			if len(stmt.Lhs) != len(stmt.Rhs) {
+				return
+			}
+			for i, rhs := range stmt.Rhs {
+				// Assigning to the blank identifier does not make a copy that can be used
+				if id, ok := stmt.Lhs[i].(*ast.Ident); ok && id.Name == "_" {
+					continue
+				}
+				if lock := lockIn(pass.TypesInfo.TypeOf(rhs)); lock != "" && isExistingValue(rhs) {
+					report(rhs, fmt.Sprintf("Assignment copies a value containing a %s; copy a pointer to it instead", lock))
+				}
+			}
+			
+		case *ast.ValueSpec:
+			for _, value := range stmt.Values {
+				if lock := lockIn(pass.TypesInfo.TypeOf(value)); lock != "" && isExistingValue(value) {
+					report(value, fmt.Sprintf("Variable declaration copies a value containing a %s; copy a pointer to it instead", lock))
+				}
+			}
+			
+		case *ast.RangeStmt:
+			if stmt.Value == nil {
+				return
+			}
+			if lock := lockIn(pass.TypesInfo.TypeOf(stmt.Value)); lock != "" {
+				report(stmt.Value, fmt.Sprintf("Range variable copies each element, which contains a %s; range over the indexes or store pointers", lock))
+			}
+			
+		case *ast.ReturnStmt:
+			for _, result := range stmt.Results {
+				if lock := lockIn(pass.TypesInfo.TypeOf(result)); lock != "" && isExistingValue(result) {
+					report(result, fmt.Sprintf("Return copies a value containing a %s; return a pointer instead", lock))
+				}
+			}
+		}
+	})
+	
+	return nil, nil
+}
+
+func runSelectCtxDone(pass *analysis.Pass) (interface{}, error) {
+	inspect := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+	
+	inspect.WithStack([]ast.Node{(*ast.SelectStmt)(nil)}, func(node ast.Node, push bool, stack []ast.Node) bool {
+		if !push {
+			return true
+		}
+		sel := node.(*ast.SelectStmt)
+		
+		// The contexts available to the select: parameters of the enclosing functions
+		ctxName := ""
+		for i := len(stack) - 1; i >= 0 && ctxName == ""; i-- {
+			var fn *ast.FuncType
+			switch f := stack[i].(type) {
+			case *ast.FuncDecl:
+				fn = f.Type
+			case *ast.FuncLit:
+				fn = f.Type
+			}
+			if fn == nil || fn.Params == nil {
+				continue
+			}
+			for _, field := range fn.Params.List {
+				if !isContext(pass.TypesInfo.TypeOf(field.Type)) {
+					continue
+				}
+				for _, name := range field.Names {
+					if name.Name != "_" {
+						ctxName = name.Name
+						break
+					}
+				}
+			}
+		}
+		if ctxName == "" {
+			return true
+		}
+		
+		// Selects with a default case do not block; selects with a timeout or a Done case end on their own
+		for _, clause := range sel.Body.List {
+			comm := clause.(*ast.CommClause)
+			if comm.Comm == nil || receivesFromDoneOrTimer(pass, comm.Comm) {
+				return true
+			}
+		}
+		
+		diagnostic := analysis.Diagnostic{
+			Pos:      sel.Pos(),
+			End:      sel.Body.Lbrace,
+			Category: "select-missing-ctx-done",
+			Message: fmt.Sprintf("select has no case for %s.Done(), so it keeps blocking after the context is cancelled; "+
+				"add a case <-%s.Done() that returns", ctxName, ctxName),
+		}
+		if ret := returnStatement(pass, enclosingFunc(stack), ctxName+".Err()"); ret != "" {
+			indent := lineIndent(pass, sel.Pos())
+			diagnostic.SuggestedFixes = []analysis.SuggestedFix{{
+				Message: fmt.Sprintf("Return when %s is cancelled", ctxName),
+				TextEdits: []analysis.TextEdit{{
+					Pos:     sel.Body.Rbrace,
+					End:     sel.Body.Rbrace,
+					NewText: []byte("case <-" + ctxName + ".Done():\n" + indent + "\t" + ret + "\n" + indent),
+				}},
+			}}
+		}
+		pass.Report(diagnostic)
+		return true
+	})
+	
+	return nil, nil
+}
+
+// receivesFromDoneOrTimer reports whether a select case receives from a Done() channel or a timer
+func receivesFromDoneOrTimer(pass *analysis.Pass, comm ast.Stmt) bool {
+	var expr ast.Expr
+	switch stmt := comm.(type) {
+	case *ast.ExprStmt:
+		expr = stmt.X
+	case *ast.AssignStmt:
+		if len(stmt.Rhs) == 1 {
+			expr = stmt.Rhs[0]
+		}
+	}
+	recv, ok := astutil.Unparen(expr).(*ast.UnaryExpr)
+	if !ok || recv.Op != token.ARROW {
+		return false
+	}
+	
+	switch x := astutil.Unparen(recv.X).(type) {
+	case *ast.CallExpr:
+		if selector, ok := x.Fun.(*ast.SelectorExpr); ok && selector.Sel.Name == "Done" {
+			return true
+		}
+		fn := calleeFunc(pass.TypesInfo, x)
+		return isFunc(fn, "time", "After")
+	case *ast.SelectorExpr:
+		return x.Sel.Name == "C" && isNamedType(pass.TypesInfo.TypeOf(x.X), "time", "Timer")
+	}
+	return false
+}
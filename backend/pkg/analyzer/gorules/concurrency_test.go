@@ -0,0 +1,27 @@
+package gorules
+
+import (
+	"testing"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/analysistest"
+)
+
+func TestConcurrency(t *testing.T) {
+	tests := []struct {
+		analyzer *analysis.Analyzer
+		pkg      string
+	}{
+		{GoroutineLeak, "goroutineleak"},
+		{UnstoppedTicker, "unstoppedticker"},
+		{LoopVarCapture, "loopvarcapture"},
+		{MutexCopy, "mutexcopy"},
+		{SelectCtxDone, "selectctxdone"},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.analyzer.Name, func(t *testing.T) {
+			analysistest.RunWithSuggestedFixes(t, analysistest.TestData(), tt.analyzer, tt.pkg)
+		})
+	}
+}
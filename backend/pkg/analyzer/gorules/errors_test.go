@@ -0,0 +1,54 @@
+package gorules
+
+import (
+	"testing"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/analysistest"
+)
+
+func TestErrorHandling(t *testing.T) {
+	tests := []struct {
+		analyzer *analysis.Analyzer
+		pkg      string
+	}{
+		{ErrorStringCompare, "errorstringcompare"},
+		{ErrorEqualityCompare, "errorequalitycompare"},
+		{ErrorfWrapVerb, "errorfwrapverb"},
+		{ErrorLoggedAndDropped, "errorloggedanddropped"},
+		{NilErrorNilValue, "nilerrornilvalue"},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.analyzer.Name, func(t *testing.T) {
+			analysistest.RunWithSuggestedFixes(t, analysistest.TestData(), tt.analyzer, tt.pkg)
+		})
+	}
+}
+
+func TestFormatVerbs(t *testing.T) {
+	tests := []struct {
+		literal string
+		want    []formatVerb
+		wantOK  bool
+	}{
+		{`"plain"`, []formatVerb{}, true},
+		{`"%s: %v"`, []formatVerb{{offset: 2, verb: 's', arg: 0}, {offset: 6, verb: 'v', arg: 1}}, true},
+		{`"100%% %-5.2f"`, []formatVerb{{offset: 12, verb: 'f', arg: 0}}, true},
+		{`"%*d %w"`, []formatVerb{{offset: 3, verb: 'd', arg: 1}, {offset: 6, verb: 'w', arg: 2}}, true},
+		{`"%[2]s %[1]s"`, nil, false},
+	}
+	
+	for _, tt := range tests {
+		got, ok := formatVerbs(tt.literal)
+		if ok != tt.wantOK || len(got) != len(tt.want) {
+			t.Errorf("formatVerbs(%s) = %v, %v, want %v, %v", tt.literal, got, ok, tt.want, tt.wantOK)
+			continue
+		}
+		for i := range got {
+			if got[i] != tt.want[i] {
+				t.Errorf("formatVerbs(%s)[%d] = %+v, want %+v", tt.literal, i, got[i], tt.want[i])
+			}
+		}
+	}
+}
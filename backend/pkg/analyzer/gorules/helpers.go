@@ -0,0 +1,195 @@
+// Package gorules contains CodeHawk's own go/analysis passes for Go code. Each diagnostic
+// carries its rule ID as the category and explains how to fix the problem; mechanical fixes
+// are attached as suggested fixes.
+package gorules
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+	"go/version"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/ast/astutil"
+	"golang.org/x/tools/go/types/typeutil"
+)
+
+// isNamedType reports whether t, or the type t points to, is the named type pkgPath.name
+func isNamedType(t types.Type, pkgPath, name string) bool {
+	if pointer, ok := t.(*types.Pointer); ok {
+		t = pointer.Elem()
+	}
+	named, ok := t.(*types.Named)
+	if !ok || named.Obj().Pkg() == nil {
+		return false
+	}
+	return named.Obj().Pkg().Path() == pkgPath && named.Obj().Name() == name
+}
+
+// isContext reports whether t is context.Context
+func isContext(t types.Type) bool {
+	return isNamedType(t, "context", "Context")
+}
+
+// isError reports whether t is the error interface
+func isError(t types.Type) bool {
+	return types.Identical(t, types.Universe.Lookup("error").Type())
+}
+
+// calleeFunc returns the function or method a call invokes, or nil for dynamic calls and conversions
+func calleeFunc(info *types.Info, call *ast.CallExpr) *types.Func {
+	fn, _ := typeutil.Callee(info, call).(*types.Func)
+	return fn
+}
+
+// isFunc reports whether fn is the package-level function pkgPath.name
+func isFunc(fn *types.Func, pkgPath, name string) bool {
+	return fn != nil && fn.Pkg() != nil && fn.Pkg().Path() == pkgPath && fn.Name() == name &&
+		fn.Type().(*types.Signature).Recv() == nil
+}
+
+// isMethod reports whether fn is a method named name of pkgPath.typeName
+func isMethod(fn *types.Func, pkgPath, typeName, name string) bool {
+	if fn == nil || fn.Name() != name {
+		return false
+	}
+	recv := fn.Type().(*types.Signature).Recv()
+	return recv != nil && isNamedType(recv.Type(), pkgPath, typeName)
+}
+
+// identObject returns the object an identifier expression refers to, or nil
+func identObject(info *types.Info, expr ast.Expr) types.Object {
+	ident, ok := astutil.Unparen(expr).(*ast.Ident)
+	if !ok {
+		return nil
+	}
+	return info.ObjectOf(ident)
+}
+
+// lineIndent returns the indentation of the line containing pos
+func lineIndent(pass *analysis.Pass, pos token.Pos) string {
+	file := pass.Fset.File(pos)
+	if file == nil || pass.ReadFile == nil {
+		return ""
+	}
+	src, err := pass.ReadFile(file.Name())
+	if err != nil {
+		return ""
+	}
+	
+	start := file.Offset(file.LineStart(file.Line(pos)))
+	end := start
+	for end < len(src) && (src[end] == ' ' || src[end] == '\t') {
+		end++
+	}
+	return string(src[start:end])
+}
+
+// insertBefore returns a fix that inserts a statement on its own line before the statement at pos
+func insertBefore(pass *analysis.Pass, message string, pos token.Pos, statement string) analysis.SuggestedFix {
+	return analysis.SuggestedFix{
+		Message: message,
+		TextEdits: []analysis.TextEdit{{
+			Pos:     pos,
+			End:     pos,
+			NewText: []byte(statement + "\n" + lineIndent(pass, pos)),
+		}},
+	}
+}
+
+// zeroValue returns the expression for the zero value of t, or "" when there is none to write
+func zeroValue(pass *analysis.Pass, t types.Type) string {
+	switch underlying := t.Underlying().(type) {
+	case *types.Basic:
+		switch {
+		case underlying.Info()&types.IsBoolean != 0:
+			return "false"
+		case underlying.Info()&types.IsNumeric != 0:
+			return "0"
+		case underlying.Info()&types.IsString != 0:
+			return `""`
+		case underlying.Kind() == types.UnsafePointer || underlying.Kind() == types.UntypedNil:
+			return "nil"
+		}
+		return ""
+	case *types.Pointer, *types.Slice, *types.Map, *types.Chan, *types.Signature, *types.Interface:
+		return "nil"
+	case *types.Struct, *types.Array:
+		name := types.TypeString(t, types.RelativeTo(pass.Pkg))
+		if strings.Contains(name, "\n") {
+			return ""
+		}
+		return name + "{}"
+	}
+	return ""
+}
+
+// enclosingFunc returns the type of the innermost function in a stack of nodes
+func enclosingFunc(stack []ast.Node) *ast.FuncType {
+	for i := len(stack) - 1; i >= 0; i-- {
+		switch fn := stack[i].(type) {
+		case *ast.FuncDecl:
+			return fn.Type
+		case *ast.FuncLit:
+			return fn.Type
+		}
+	}
+	return nil
+}
+
+// resultTypes returns the result types of a function of the given type, one per result, or nil
+// when one of them is not known
+func resultTypes(pass *analysis.Pass, fn *ast.FuncType) []types.Type {
+	results := make([]types.Type, 0)
+	if fn.Results == nil {
+		return results
+	}
+	for _, field := range fn.Results.List {
+		t := pass.TypesInfo.TypeOf(field.Type)
+		if t == nil || t == types.Typ[types.Invalid] {
+			return nil
+		}
+		results = append(results, t)
+		for i := 1; i < len(field.Names); i++ {
+			results = append(results, t)
+		}
+	}
+	return results
+}
+
+// returnStatement builds a return statement for a function of the given type, returning zero
+// values and errValue for a trailing error result. It returns "" when a zero value cannot be written.
+func returnStatement(pass *analysis.Pass, fn *ast.FuncType, errValue string) string {
+	if fn.Results == nil || len(fn.Results.List) == 0 {
+		return "return"
+	}
+	
+	results := resultTypes(pass, fn)
+	if results == nil {
+		return ""
+	}
+	
+	values := make([]string, len(results))
+	for i, t := range results {
+		values[i] = zeroValue(pass, t)
+		if i == len(results)-1 && isError(t) && errValue != "" {
+			values[i] = errValue
+		}
+		if values[i] == "" {
+			return ""
+		}
+	}
+	return "return " + strings.Join(values, ", ")
+}
+
+// perIterationLoopVars reports whether file is built as Go 1.22 or later, where every loop
+// iteration has its own variables. A //go:build version in the file takes precedence over the
+// package's; code without a known version is treated as older.
+func perIterationLoopVars(pass *analysis.Pass, file *ast.File) bool {
+	v := file.GoVersion
+	if v == "" {
+		v = pass.Pkg.GoVersion()
+	}
+	return version.IsValid(v) && version.Compare(v, "go1.22") >= 0
+}
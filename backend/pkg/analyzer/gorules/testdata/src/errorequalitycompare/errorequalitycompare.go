@@ -0,0 +1,21 @@
+package errorequalitycompare
+
+import (
+	"io"
+	"os"
+)
+
+func read(f *os.File) error {
+	_, err := f.Read(nil)
+	if err == io.EOF { // want "Errors compared with == do not match once the error is wrapped"
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	switch err { // want "switch compares err with =="
+	case io.ErrUnexpectedEOF:
+		return err
+	}
+	return nil
+}
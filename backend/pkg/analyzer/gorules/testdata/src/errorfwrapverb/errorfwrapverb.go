@@ -0,0 +1,17 @@
+package errorfwrapverb
+
+import "fmt"
+
+func load(name string) error {
+	err := fmt.Errorf("missing")
+	if name == "" {
+		return fmt.Errorf("load %s: %v", name, err) // want "fmt.Errorf formats err with %v"
+	}
+	if name == "x" {
+		return fmt.Errorf("load %q: %w", name, err)
+	}
+	if name == "y" {
+		return fmt.Errorf("load %v", name)
+	}
+	return fmt.Errorf("load %*d: %s", 3, 4, err) // want "fmt.Errorf formats err with %s"
+}
@@ -0,0 +1,44 @@
+package errorloggedanddropped
+
+import "log"
+
+func save() error {
+	return nil
+}
+
+func run() error {
+	if err := save(); err != nil { // want "err is logged and then dropped"
+		log.Printf("save failed: %v", err)
+	}
+	return nil
+}
+
+func count() (int, error) {
+	err := save()
+	if err != nil { // want "err is logged and then dropped"
+		log.Println("count")
+		log.Println(err)
+	}
+	return 1, nil
+}
+
+func background() {
+	if err := save(); err != nil {
+		log.Printf("save failed: %v", err)
+	}
+}
+
+func retried() error {
+	if err := save(); err != nil {
+		log.Printf("retrying: %v", err)
+		return save()
+	}
+	return nil
+}
+
+func unrelated() error {
+	if err := save(); err != nil {
+		log.Println("save failed")
+	}
+	return nil
+}
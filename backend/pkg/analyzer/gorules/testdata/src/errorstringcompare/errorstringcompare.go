@@ -0,0 +1,26 @@
+package errorstringcompare
+
+import (
+	"errors"
+	"strings"
+)
+
+var ErrNotFound = errors.New("not found")
+
+func find() error {
+	return ErrNotFound
+}
+
+func check() bool {
+	err := find()
+	if err.Error() == "not found" { // want "Error is identified by comparing its message"
+		return true
+	}
+	if "timeout" != err.Error() { // want "Error is identified by comparing its message"
+		return false
+	}
+	if errors.Is(err, ErrNotFound) {
+		return true
+	}
+	return strings.Contains(err.Error(), "found") // want "Error is identified by searching its message with strings.Contains"
+}
@@ -0,0 +1,65 @@
+package goroutineleak
+
+import (
+	"context"
+	"time"
+)
+
+func poll() {
+	for {
+		time.Sleep(time.Second)
+	}
+}
+
+func start(ctx context.Context) {
+	go poll() // want "Goroutine runs an endless loop"
+
+	go func() { // want "Goroutine runs an endless loop"
+		for {
+			time.Sleep(time.Second)
+		}
+	}()
+
+	go func() {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			default:
+			}
+		}
+	}()
+
+	go func() {
+	loop:
+		for {
+			select {
+			case <-ctx.Done():
+				break loop
+			case <-time.After(time.Second):
+			}
+		}
+	}()
+}
+
+func fetch(ctx context.Context) (string, error) {
+	result := make(chan string)
+	go func() {
+		result <- "done" // want "Goroutine blocks forever sending on unbuffered channel result"
+	}()
+
+	select {
+	case value := <-result:
+		return value, nil
+	case <-ctx.Done():
+		return "", ctx.Err()
+	}
+}
+
+func wait() string {
+	result := make(chan string)
+	go func() {
+		result <- "done"
+	}()
+	return <-result
+}
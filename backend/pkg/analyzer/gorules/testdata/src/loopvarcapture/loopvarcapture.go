@@ -0,0 +1,39 @@
+package loopvarcapture
+
+import "sync"
+
+type group struct{}
+
+func (group) Go(f func() error) {}
+
+func process(items []string, handle func(string)) {
+	var wg sync.WaitGroup
+	for _, item := range items {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			handle(item) // want "Loop variable item is captured by a goroutine"
+		}()
+	}
+	wg.Wait()
+
+	for i := 0; i < 3; i++ {
+		defer func() {
+			println(i) // want "Loop variable i is captured by a deferred function"
+		}()
+	}
+
+	var g group
+	for _, item := range items {
+		g.Go(func() error {
+			handle(item) // want "Loop variable item is captured by a goroutine"
+			return nil
+		})
+	}
+
+	for _, item := range items {
+		go func(item string) {
+			handle(item)
+		}(item)
+	}
+}
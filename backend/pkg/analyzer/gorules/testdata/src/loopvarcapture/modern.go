@@ -0,0 +1,11 @@
+//go:build go1.22
+
+package loopvarcapture
+
+func processModern(items []string, handle func(string)) {
+	for _, item := range items {
+		go func() {
+			handle(item)
+		}()
+	}
+}
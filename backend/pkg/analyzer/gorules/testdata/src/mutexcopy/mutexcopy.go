@@ -0,0 +1,35 @@
+package mutexcopy
+
+import "sync"
+
+type counter struct {
+	mu sync.Mutex
+	n  int
+}
+
+func (c counter) Value() int { // want "Method Value has a value receiver"
+	return c.n
+}
+
+func (c *counter) Inc() {
+	c.mu.Lock()
+	c.n++
+	c.mu.Unlock()
+}
+
+func report(c counter) {} // want "Parameter passes a copy of a value containing a sync.Mutex"
+
+func copies(c *counter, all []counter) counter {
+	snapshot := *c       // want "Assignment copies a value containing a sync.Mutex"
+	var other = snapshot // want "Variable declaration copies a value containing a sync.Mutex"
+	_ = other
+	for _, item := range all { // want "Range variable copies each element"
+		_ = item
+	}
+	for i := range all {
+		all[i].Inc()
+	}
+	fresh := counter{}
+	fresh.Inc()
+	return *c // want "Return copies a value containing a sync.Mutex"
+}
@@ -0,0 +1,26 @@
+package nilerrornilvalue
+
+import "errors"
+
+var ErrNotFound = errors.New("not found")
+
+type user struct{}
+
+func find(id int) (*user, error) {
+	if id == 0 {
+		return nil, nil // want "Returning a nil value with a nil error"
+	}
+	return &user{}, nil
+}
+
+func list() ([]user, error) {
+	return nil, nil
+}
+
+func lookup() (map[string]int, error) {
+	return nil, nil // want "Returning a nil value with a nil error"
+}
+
+func failing() (*user, error) {
+	return nil, ErrNotFound
+}
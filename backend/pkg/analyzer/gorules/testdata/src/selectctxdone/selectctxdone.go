@@ -0,0 +1,56 @@
+package selectctxdone
+
+import (
+	"context"
+	"time"
+)
+
+func wait(ctx context.Context, results <-chan string) (string, error) {
+	select { // want "select has no case for ctx.Done"
+	case r := <-results:
+		return r, nil
+	}
+}
+
+func forward(ctx context.Context, in <-chan int, out chan<- int) {
+	for {
+		select { // want "select has no case for ctx.Done"
+		case v := <-in:
+			out <- v
+		}
+	}
+}
+
+func cancellable(ctx context.Context, results <-chan string) (string, error) {
+	select {
+	case r := <-results:
+		return r, nil
+	case <-ctx.Done():
+		return "", ctx.Err()
+	}
+}
+
+func timed(ctx context.Context, results <-chan string) string {
+	select {
+	case r := <-results:
+		return r
+	case <-time.After(time.Second):
+		return ""
+	}
+}
+
+func poll(ctx context.Context, results <-chan string) string {
+	select {
+	case r := <-results:
+		return r
+	default:
+		return ""
+	}
+}
+
+func noContext(results <-chan string) string {
+	select {
+	case r := <-results:
+		return r
+	}
+}
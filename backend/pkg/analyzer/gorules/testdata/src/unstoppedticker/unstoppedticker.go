@@ -0,0 +1,41 @@
+package unstoppedticker
+
+import "time"
+
+func tick(work func()) {
+	for range time.Tick(time.Second) { // want "time.Tick creates a ticker that can never be stopped"
+		work()
+	}
+}
+
+func poll(work func()) {
+	ticker := time.NewTicker(time.Second) // want "Ticker ticker is never stopped"
+	for range ticker.C {
+		work()
+	}
+}
+
+func background(work func()) {
+	ticker := time.NewTicker(time.Second) // want "Ticker ticker is never stopped"
+	go func() {
+		for range ticker.C {
+			work()
+		}
+	}()
+}
+
+func stopped(work func()) {
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+	<-ticker.C
+	work()
+}
+
+type poller struct {
+	ticker *time.Ticker
+}
+
+func (p *poller) start() {
+	ticker := time.NewTicker(time.Second)
+	p.ticker = ticker
+}
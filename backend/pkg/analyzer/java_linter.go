@@ -0,0 +1,373 @@
+package analyzer
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+var (
+	// javaPackagePattern matches the package declaration of a compilation unit
+	javaPackagePattern = regexp.MustCompile(`(?m)^\s*package\s+([\w.]+)\s*;`)
+	
+	// javaPublicTypePattern matches the public top-level type, which names the source file
+	javaPublicTypePattern = regexp.MustCompile(`(?m)^public\s+(?:(?:abstract|final|sealed|non-sealed|static|strictfp)\s+)*(?:class|interface|enum|record|@interface)\s+(\w+)`)
+	
+	// javaTypePattern matches any top-level type declaration
+	javaTypePattern = regexp.MustCompile(`(?m)^(?:(?:abstract|final|sealed|non-sealed|strictfp)\s+)*(?:class|interface|enum|record|@interface)\s+(\w+)`)
+)
+
+// JavaLinter implements the Linter interface for Java
+type JavaLinter struct {
+	*BaseAnalyzer
+	pmdPath        string
+	checkstylePath string
+}
+
+// NewJavaLinter creates a new Java linter
+func NewJavaLinter(config map[string]string) *JavaLinter {
+	// Default PMD path
+	pmdPath := "pmd"
+	if path, ok := config["pmdPath"]; ok && path != "" {
+		pmdPath = path
+	}
+	
+	// Default Checkstyle path
+	checkstylePath := "checkstyle"
+	if path, ok := config["checkstylePath"]; ok && path != "" {
+		checkstylePath = path
+	}
+	
+	return &JavaLinter{
+		BaseAnalyzer:   NewBaseAnalyzer(config),
+		pmdPath:        pmdPath,
+		checkstylePath: checkstylePath,
+	}
+}
+
+// Language returns the identifier for the supported language
+func (l *JavaLinter) Language() string {
+	return "java"
+}
+
+// Analyze analyzes the provided code and returns issues found
+func (l *JavaLinter) Analyze(ctx context.Context, code string, options map[string]interface{}) (*AnalysisResult, error) {
+	return l.BaseAnalyzer.CommonAnalyzeWrapper(
+		ctx,
+		code,
+		options,
+		l.findIssues,
+		l.SuggestFixes,
+	)
+}
+
+// findIssues analyzes the code and returns issues
+func (l *JavaLinter) findIssues(ctx context.Context, code string, options map[string]interface{}) ([]Issue, error) {
+	// Create a temporary directory holding the sources and rule configuration
+	tmpDir, err := ioutil.TempDir("", "codehawk-java")
+	if err != nil {
+		return nil, l.WrapError(err, "failed to create temporary directory")
+	}
+	defer os.RemoveAll(tmpDir)
+	
+	// Other files of a multi-file submission are analyzed alongside the code so that
+	// cross-file rules see the whole package, but only issues in the code are reported
+	sourceDir := filepath.Join(tmpDir, "src")
+	sourcePath := l.sourcePath(code, options)
+	
+	files := l.GetFilesOption(options)
+	files[sourcePath] = code
+	
+	if err := l.WriteFiles(sourceDir, files); err != nil {
+		return nil, l.WrapError(err, "failed to write source files")
+	}
+	
+	sourceFile := filepath.Join(sourceDir, filepath.FromSlash(sourcePath))
+	
+	pmdIssues, err := l.runPMD(ctx, tmpDir, sourceDir, sourceFile, options)
+	if err != nil {
+		// Log the error but continue with Checkstyle
+		fmt.Printf("Warning: PMD failed: %v\n", err)
+	}
+	
+	checkstyleIssues, checkstyleErr := l.runCheckstyle(ctx, tmpDir, sourceDir, sourceFile, options)
+	if checkstyleErr != nil {
+		// Log the error but continue
+		fmt.Printf("Warning: Checkstyle failed: %v\n", checkstyleErr)
+	}
+	
+	if err != nil && checkstyleErr != nil {
+		return nil, err
+	}
+	
+	return append(pmdIssues, checkstyleIssues...), nil
+}
+
+// sourcePath returns the path of the analyzed code relative to the source root. Without a
+// "path" option it is derived from the package declaration and the public type, which
+// Checkstyle and the compiler expect the file layout to match.
+func (l *JavaLinter) sourcePath(code string, options map[string]interface{}) string {
+	if path := l.GetStringOption(options, "path", ""); path != "" {
+		return filepath.ToSlash(filepath.Clean(path))
+	}
+	
+	typeName := "Main"
+	if matches := javaPublicTypePattern.FindStringSubmatch(code); matches != nil {
+		typeName = matches[1]
+	} else if matches := javaTypePattern.FindStringSubmatch(code); matches != nil {
+		typeName = matches[1]
+	}
+	
+	packageDir := ""
+	if matches := javaPackagePattern.FindStringSubmatch(code); matches != nil {
+		packageDir = strings.ReplaceAll(matches[1], ".", "/") + "/"
+	}
+	
+	return packageDir + typeName + ".java"
+}
+
+// ruleConfig returns the ruleset or configuration named by key, which only the server may set,
+// for every organization or for the one the analysis of ctx runs for: a reference the tool
+// resolves itself (a path or built-in ruleset) or the XML document, which is written to dir.
+// Requests cannot give one, since Checkstyle modules and PMD rule references read any file or URL
+// they name.
+func (l *JavaLinter) ruleConfig(ctx context.Context, options map[string]interface{}, key, defaultValue, dir string) (string, error) {
+	if _, ok := options[key]; ok {
+		return "", fmt.Errorf("%s can only be set in the linter configuration", key)
+	}
+	value := l.GetOrganizationOption(ctx, key, defaultValue)
+	if !strings.HasPrefix(strings.TrimSpace(value), "<") {
+		return value, nil
+	}
+	
+	path := filepath.Join(dir, key+".xml")
+	if err := ioutil.WriteFile(path, []byte(value), 0644); err != nil {
+		return "", fmt.Errorf("failed to write %s: %w", key, err)
+	}
+	return path, nil
+}
+
+// pmdReport is PMD's XML report
+type pmdReport struct {
+	Files []struct {
+		Name       string `xml:"name,attr"`
+		Violations []struct {
+			BeginLine       int    `xml:"beginline,attr"`
+			BeginColumn     int    `xml:"begincolumn,attr"`
+			Rule            string `xml:"rule,attr"`
+			RuleSet         string `xml:"ruleset,attr"`
+			ExternalInfoURL string `xml:"externalInfoUrl,attr"`
+			Priority        int    `xml:"priority,attr"`
+			Message         string `xml:",chardata"`
+		} `xml:"violation"`
+	} `xml:"file"`
+	Errors []struct {
+		Filename string `xml:"filename,attr"`
+		Msg      string `xml:"msg,attr"`
+	} `xml:"error"`
+}
+
+// runPMD runs PMD against the source tree and parses its XML report
+func (l *JavaLinter) runPMD(ctx context.Context, tmpDir, sourceDir, sourceFile string, options map[string]interface{}) ([]Issue, error) {
+	ruleset, err := l.ruleConfig(ctx, options, "pmdRuleset", "rulesets/java/quickstart.xml", tmpDir)
+	if err != nil {
+		return nil, l.WrapError(err, "failed to prepare PMD ruleset")
+	}
+	
+	cmd := exec.CommandContext(
+		ctx,
+		l.pmdPath,
+		"check",
+		"--dir", sourceDir,
+		"--rulesets", ruleset,
+		"--format", "xml",
+		"--no-cache",
+		"--no-progress",
+	)
+	
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	
+	// PMD exits with status 4 when it finds violations, so only treat the
+	// run as failed when it produced no report
+	err = cmd.Run()
+	if err != nil && stdout.Len() == 0 {
+		return nil, l.WrapError(fmt.Errorf("failed to run PMD: %w, stderr: %s", err, stderr.String()), "PMD execution error")
+	}
+	
+	var report pmdReport
+	if err := xml.Unmarshal(stdout.Bytes(), &report); err != nil {
+		return nil, l.WrapError(err, "failed to parse PMD output")
+	}
+	
+	issues := make([]Issue, 0)
+	for _, file := range report.Files {
+		if !sameFile(file.Name, sourceFile) {
+			continue
+		}
+		
+		for _, violation := range file.Violations {
+			column := violation.BeginColumn
+			issues = append(issues, Issue{
+				Line:     violation.BeginLine,
+				Column:   &column,
+				Message:  strings.TrimSpace(violation.Message),
+				Severity: pmdSeverity(violation.Priority),
+				RuleID:   violation.Rule,
+				Context:  violation.ExternalInfoURL,
+			})
+		}
+	}
+	
+	// Files PMD could not parse are reported as processing errors
+	for _, processingError := range report.Errors {
+		if !sameFile(processingError.Filename, sourceFile) {
+			continue
+		}
+		
+		issues = append(issues, Issue{
+			Line:     1,
+			Message:  processingError.Msg,
+			Severity: "error",
+			RuleID:   "parse-error",
+		})
+	}
+	
+	return issues, nil
+}
+
+// pmdSeverity maps a PMD priority (1 is highest, 5 lowest) to a CodeHawk severity
+func pmdSeverity(priority int) string {
+	switch priority {
+	case 1, 2:
+		return "error"
+	case 3:
+		return "warning"
+	case 4:
+		return "suggestion"
+	default:
+		return "info"
+	}
+}
+
+// checkstyleReport is Checkstyle's XML report
+type checkstyleReport struct {
+	Files []struct {
+		Name   string `xml:"name,attr"`
+		Errors []struct {
+			Line     int    `xml:"line,attr"`
+			Column   int    `xml:"column,attr"`
+			Severity string `xml:"severity,attr"`
+			Message  string `xml:"message,attr"`
+			Source   string `xml:"source,attr"`
+		} `xml:"error"`
+	} `xml:"file"`
+}
+
+// runCheckstyle runs Checkstyle against the source tree and parses its XML report
+func (l *JavaLinter) runCheckstyle(ctx context.Context, tmpDir, sourceDir, sourceFile string, options map[string]interface{}) ([]Issue, error) {
+	config, err := l.ruleConfig(ctx, options, "checkstyleConfig", "/google_checks.xml", tmpDir)
+	if err != nil {
+		return nil, l.WrapError(err, "failed to prepare Checkstyle configuration")
+	}
+	
+	cmd := exec.CommandContext(
+		ctx,
+		l.checkstylePath,
+		"-c", config,
+		"-f", "xml",
+		sourceDir,
+	)
+	
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	
+	// Checkstyle exits with the number of errors it found
+	err = cmd.Run()
+	
+	// Skip any audit messages printed before the report
+	output := stdout.Bytes()
+	start := bytes.Index(output, []byte("<?xml"))
+	if start < 0 {
+		start = bytes.Index(output, []byte("<checkstyle"))
+	}
+	if start < 0 {
+		return nil, l.WrapError(fmt.Errorf("failed to run Checkstyle: %v, stderr: %s", err, stderr.String()), "Checkstyle execution error")
+	}
+	
+	var report checkstyleReport
+	if err := xml.Unmarshal(output[start:], &report); err != nil {
+		return nil, l.WrapError(err, "failed to parse Checkstyle output")
+	}
+	
+	issues := make([]Issue, 0)
+	for _, file := range report.Files {
+		if !sameFile(file.Name, sourceFile) {
+			continue
+		}
+		
+		for _, checkstyleError := range file.Errors {
+			if checkstyleError.Severity == "ignore" {
+				continue
+			}
+			
+			issue := Issue{
+				Line:     checkstyleError.Line,
+				Message:  checkstyleError.Message,
+				Severity: l.MapSeverity(checkstyleError.Severity),
+				RuleID:   checkstyleRuleID(checkstyleError.Source),
+				Context:  checkstyleError.Source,
+			}
+			
+			// Checkstyle omits the column for line-level checks
+			if checkstyleError.Column > 0 {
+				column := checkstyleError.Column
+				issue.Column = &column
+			}
+			
+			issues = append(issues, issue)
+		}
+	}
+	
+	return issues, nil
+}
+
+// checkstyleRuleID shortens a check's class name to its module name, e.g.
+// "com.puppycrawl.tools.checkstyle.checks.naming.MemberNameCheck" becomes "MemberName"
+func checkstyleRuleID(source string) string {
+	name := source[strings.LastIndex(source, ".")+1:]
+	return strings.TrimSuffix(name, "Check")
+}
+
+// sameFile reports whether a path reported by a tool refers to the given file
+func sameFile(reported, file string) bool {
+	if filepath.Clean(reported) == filepath.Clean(file) {
+		return true
+	}
+	
+	// The temporary directory may be reported through a symlink, e.g. /private/var on macOS
+	resolvedReported, err := filepath.EvalSymlinks(reported)
+	if err != nil {
+		return false
+	}
+	resolvedFile, err := filepath.EvalSymlinks(file)
+	if err != nil {
+		return false
+	}
+	return resolvedReported == resolvedFile
+}
+
+// SuggestFixes attempts to generate fixes for the identified issues
+func (l *JavaLinter) SuggestFixes(ctx context.Context, code string, issues []Issue) ([]Issue, error) {
+	// Neither PMD nor Checkstyle produce fixes
+	return []Issue{}, nil
+}
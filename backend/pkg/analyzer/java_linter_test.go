@@ -0,0 +1,233 @@
+package analyzer
+
+import (
+	"context"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+)
+
+func TestJavaSourcePath(t *testing.T) {
+	l := NewJavaLinter(map[string]string{})
+	tests := []struct {
+		name    string
+		code    string
+		options map[string]interface{}
+		want    string
+	}{
+		{"package and public class", "package com.example.app;\n\npublic class UserService {}\n", nil, "com/example/app/UserService.java"},
+		{"public type after others", "package a;\n\nclass Helper {}\n\npublic interface Api {}\n", nil, "a/Api.java"},
+		{"default package", "class Helper {}\n", nil, "Helper.java"},
+		{"no type", "// empty\n", nil, "Main.java"},
+		{"explicit path", "public class A {}", map[string]interface{}{"path": "src/main/java/A.java"}, "src/main/java/A.java"},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := l.sourcePath(tt.code, tt.options); got != tt.want {
+				t.Errorf("sourcePath() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestJavaRuleConfig(t *testing.T) {
+	ruleset := `<?xml version="1.0"?><ruleset name="org"><rule ref="category/java/bestpractices.xml/UnusedLocalVariable"/></ruleset>`
+	tests := []struct {
+		name         string
+		config       map[string]string
+		organization string
+		settings     map[string]string
+		options      map[string]interface{}
+		want         string
+		wantFile     bool
+		wantError    bool
+	}{
+		{
+			name: "built-in default",
+			want: "rulesets/java/quickstart.xml",
+		},
+		{
+			name:         "organization set in the configuration",
+			config:       map[string]string{"pmdRuleset": "/etc/codehawk/pmd.xml", OrganizationKey("pmdRuleset", "org-1"): "/etc/codehawk/org-1.xml"},
+			organization: "org-1",
+			want:         "/etc/codehawk/org-1.xml",
+		},
+		{
+			name:         "other organization",
+			config:       map[string]string{"pmdRuleset": "/etc/codehawk/pmd.xml", OrganizationKey("pmdRuleset", "org-1"): "/etc/codehawk/org-1.xml"},
+			organization: "org-2",
+			want:         "/etc/codehawk/pmd.xml",
+		},
+		{
+			name:         "organization setting",
+			config:       map[string]string{"pmdRuleset": "/etc/codehawk/pmd.xml"},
+			organization: "org-1",
+			settings:     map[string]string{"pmdRuleset": ruleset},
+			wantFile:     true,
+		},
+		{
+			name:         "requested over an organization setting",
+			organization: "org-1",
+			settings:     map[string]string{"pmdRuleset": ruleset},
+			options:      map[string]interface{}{"pmdRuleset": "rulesets/java/quickstart.xml"},
+			wantError:    true,
+		},
+		{
+			name:   "configured path",
+			config: map[string]string{"pmdRuleset": "/etc/codehawk/pmd.xml"},
+			want:   "/etc/codehawk/pmd.xml",
+		},
+		{
+			name:     "configured XML",
+			config:   map[string]string{"pmdRuleset": ruleset},
+			wantFile: true,
+		},
+		{
+			name:      "requested XML",
+			config:    map[string]string{"pmdRuleset": "/etc/codehawk/pmd.xml"},
+			options:   map[string]interface{}{"pmdRuleset": ruleset},
+			wantError: true,
+		},
+		{
+			name:      "requested external rule reference",
+			options:   map[string]interface{}{"pmdRuleset": `<ruleset name="x"><rule ref="/etc/codehawk/secret.xml"/></ruleset>`},
+			wantError: true,
+		},
+		{
+			name:      "requested path",
+			options:   map[string]interface{}{"pmdRuleset": "/etc/passwd"},
+			wantError: true,
+		},
+		{
+			name:      "requested XML with a public doctype",
+			options:   map[string]interface{}{"pmdRuleset": `<!DOCTYPE r PUBLIC "id" "http://169.254.169.254/latest"><ruleset/>`},
+			wantError: true,
+		},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			l := NewJavaLinter(tt.config)
+			ctx := WithOrganization(context.Background(), tt.organization, tt.settings)
+			got, err := l.ruleConfig(ctx, tt.options, "pmdRuleset", "rulesets/java/quickstart.xml", dir)
+			if (err != nil) != tt.wantError {
+				t.Fatalf("ruleConfig() error = %v, wantError %v", err, tt.wantError)
+			}
+			if tt.wantError {
+				return
+			}
+			
+			if !tt.wantFile {
+				if got != tt.want {
+					t.Errorf("ruleConfig() = %q, want %q", got, tt.want)
+				}
+				return
+			}
+			if filepath.Dir(got) != dir {
+				t.Fatalf("ruleConfig() = %q, want a file in %s", got, dir)
+			}
+			written, err := ioutil.ReadFile(got)
+			if err != nil || string(written) != ruleset {
+				t.Errorf("written ruleset = %q (%v), want %q", written, err, ruleset)
+			}
+		})
+	}
+}
+
+func TestJavaRunPMD(t *testing.T) {
+	dir := t.TempDir()
+	sourceDir := filepath.Join(dir, "src")
+	sourceFile := filepath.Join(sourceDir, "com", "example", "App.java")
+	otherFile := filepath.Join(sourceDir, "com", "example", "Other.java")
+	
+	report := `<?xml version="1.0" encoding="UTF-8"?>
+<pmd version="7.6.0">
+<file name="` + sourceFile + `">
+<violation beginline="5" begincolumn="9" rule="UnusedLocalVariable" ruleset="Best Practices" priority="3" externalInfoUrl="https://docs.pmd-code.org/UnusedLocalVariable">
+Avoid unused local variables such as 'x'.
+</violation>
+<violation beginline="2" begincolumn="1" rule="AvoidCatchingNPE" ruleset="Error Prone" priority="2" externalInfoUrl="">
+Avoid catching NullPointerException
+</violation>
+</file>
+<file name="` + otherFile + `">
+<violation beginline="1" begincolumn="1" rule="UnusedPrivateField" ruleset="Best Practices" priority="3" externalInfoUrl="">Unused</violation>
+</file>
+<error filename="` + sourceFile + `" msg="ParseException: Encountered &quot;}&quot;"/>
+</pmd>`
+	
+	l := NewJavaLinter(map[string]string{"pmdPath": fakeTool(t, report)})
+	issues, err := l.runPMD(context.Background(), dir, sourceDir, sourceFile, nil)
+	if err != nil {
+		t.Fatalf("runPMD() error = %v", err)
+	}
+	
+	want := []Issue{
+		{Line: 5, Message: "Avoid unused local variables such as 'x'.", Severity: "warning", RuleID: "UnusedLocalVariable", Context: "https://docs.pmd-code.org/UnusedLocalVariable"},
+		{Line: 2, Message: "Avoid catching NullPointerException", Severity: "error", RuleID: "AvoidCatchingNPE"},
+		{Line: 1, Message: `ParseException: Encountered "}"`, Severity: "error", RuleID: "parse-error"},
+	}
+	if len(issues) != len(want) {
+		t.Fatalf("got %d issues, want %d: %+v", len(issues), len(want), issues)
+	}
+	for i, issue := range issues {
+		if issue.Line != want[i].Line || issue.Message != want[i].Message || issue.Severity != want[i].Severity || issue.RuleID != want[i].RuleID || issue.Context != want[i].Context {
+			t.Errorf("issue %d = %+v, want %+v", i, issue, want[i])
+		}
+	}
+}
+
+func TestJavaRunCheckstyle(t *testing.T) {
+	dir := t.TempDir()
+	sourceDir := filepath.Join(dir, "src")
+	sourceFile := filepath.Join(sourceDir, "App.java")
+	
+	output := `Starting audit...
+<?xml version="1.0" encoding="UTF-8"?>
+<checkstyle version="10.18.2">
+<file name="` + sourceFile + `">
+<error line="3" column="5" severity="warning" message="Member name 'Foo' must match pattern." source="com.puppycrawl.tools.checkstyle.checks.naming.MemberNameCheck"/>
+<error line="7" severity="error" message="Line is longer than 100 characters." source="com.puppycrawl.tools.checkstyle.checks.sizes.LineLengthCheck"/>
+<error line="9" column="1" severity="ignore" message="Ignored." source="com.puppycrawl.tools.checkstyle.checks.TodoCommentCheck"/>
+</file>
+</checkstyle>
+Audit done.`
+	
+	l := NewJavaLinter(map[string]string{"checkstylePath": fakeTool(t, output)})
+	issues, err := l.runCheckstyle(context.Background(), dir, sourceDir, sourceFile, nil)
+	if err != nil {
+		t.Fatalf("runCheckstyle() error = %v", err)
+	}
+	
+	want := []struct {
+		line     int
+		column   int
+		severity string
+		ruleID   string
+	}{
+		{3, 5, "warning", "MemberName"},
+		{7, 0, "error", "LineLength"},
+	}
+	if len(issues) != len(want) {
+		t.Fatalf("got %d issues, want %d: %+v", len(issues), len(want), issues)
+	}
+	for i, issue := range issues {
+		if issue.Line != want[i].line || issue.Severity != want[i].severity || issue.RuleID != want[i].ruleID {
+			t.Errorf("issue %d = %+v, want %+v", i, issue, want[i])
+		}
+		if (want[i].column == 0) != (issue.Column == nil) || (issue.Column != nil && *issue.Column != want[i].column) {
+			t.Errorf("issue %d column = %v, want %d", i, issue.Column, want[i].column)
+		}
+	}
+}
+
+func TestPMDSeverity(t *testing.T) {
+	want := map[int]string{1: "error", 2: "error", 3: "warning", 4: "suggestion", 5: "info"}
+	for priority, severity := range want {
+		if got := pmdSeverity(priority); got != severity {
+			t.Errorf("pmdSeverity(%d) = %q, want %q", priority, got, severity)
+		}
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"strconv"
 	"strings"
 )
 
@@ -16,6 +17,7 @@ import (
 type JavaScriptLinter struct {
 	*BaseAnalyzer
 	eslintPath     string
+	tscPath        string
 	configPath     string
 	typescriptMode bool
 }
@@ -39,6 +41,13 @@ type ESLintResult struct {
 	WarningCount int `json:"warningCount"`
 }
 
+// javascriptExtensions and typescriptExtensions are the file extensions each mode lints code
+// as, when the "path" option names such a file
+var (
+	javascriptExtensions = map[string]bool{".js": true, ".mjs": true, ".cjs": true, ".jsx": true, ".vue": true, ".svelte": true}
+	typescriptExtensions = map[string]bool{".ts": true, ".mts": true, ".cts": true, ".tsx": true, ".vue": true, ".svelte": true}
+)
+
 // NewJavaScriptLinter creates a new JavaScript linter
 func NewJavaScriptLinter(config map[string]string) *JavaScriptLinter {
 	// Default ESLint path
@@ -47,6 +56,12 @@ func NewJavaScriptLinter(config map[string]string) *JavaScriptLinter {
 		eslintPath = path
 	}
 	
+	// Default TypeScript compiler path
+	tscPath := "tsc"
+	if path, ok := config["tscPath"]; ok && path != "" {
+		tscPath = path
+	}
+	
 	// Default config path
 	configPath := ""
 	if path, ok := config["configPath"]; ok && path != "" {
@@ -56,6 +71,7 @@ func NewJavaScriptLinter(config map[string]string) *JavaScriptLinter {
 	return &JavaScriptLinter{
 		BaseAnalyzer:   NewBaseAnalyzer(config),
 		eslintPath:     eslintPath,
+		tscPath:        tscPath,
 		configPath:     configPath,
 		typescriptMode: false,
 	}
@@ -83,10 +99,33 @@ func (l *JavaScriptLinter) Analyze(ctx context.Context, code string, options map
 		code,
 		options,
 		l.findIssues,
-		l.SuggestFixes,
+		func(ctx context.Context, code string, issues []Issue) ([]Issue, error) {
+			return l.suggestFixes(ctx, code, issues, options)
+		},
 	)
 }
 
+// sourceFileName returns the name of the file the code is linted as. JSX, TSX, Vue and Svelte
+// components need their own parsers, so the extension comes from the file name given by the
+// "path" option when the linter's mode accepts it, and is .js or .ts otherwise.
+func (l *JavaScriptLinter) sourceFileName(options map[string]interface{}) string {
+	return javascriptSourceFileName(l.typescriptMode, l.GetStringOption(options, "path", ""))
+}
+
+// javascriptSourceFileName returns the name JavaScript or TypeScript code submitted as path is
+// analyzed as, shared by the linters and the security scanner
+func javascriptSourceFileName(typescript bool, path string) string {
+	accepted, ext := javascriptExtensions, ".js"
+	if typescript {
+		accepted, ext = typescriptExtensions, ".ts"
+	}
+	
+	if pathExt := strings.ToLower(filepath.Ext(path)); accepted[pathExt] {
+		ext = pathExt
+	}
+	return "code" + ext
+}
+
 // findIssues analyzes the code and returns issues
 func (l *JavaScriptLinter) findIssues(ctx context.Context, code string, options map[string]interface{}) ([]Issue, error) {
 	// Create a temporary directory
@@ -97,12 +136,7 @@ func (l *JavaScriptLinter) findIssues(ctx context.Context, code string, options
 	defer os.RemoveAll(tmpDir)
 	
 	// Create a temporary file for the code
-	ext := ".js"
-	if l.typescriptMode {
-		ext = ".ts"
-	}
-	
-	tmpFile := filepath.Join(tmpDir, "code"+ext)
+	tmpFile := filepath.Join(tmpDir, l.sourceFileName(options))
 	if err := ioutil.WriteFile(tmpFile, []byte(code), 0644); err != nil {
 		return nil, l.WrapError(err, "failed to write to temporary file")
 	}
@@ -118,7 +152,7 @@ func (l *JavaScriptLinter) findIssues(ctx context.Context, code string, options
 	} else {
 		// Use a default configuration if none is provided
 		configFile := filepath.Join(tmpDir, ".eslintrc.json")
-		defaultConfig := l.getDefaultConfig()
+		defaultConfig := l.getDefaultConfig(filepath.Base(tmpFile))
 		if err := ioutil.WriteFile(configFile, []byte(defaultConfig), 0644); err != nil {
 			return nil, l.WrapError(err, "failed to write default ESLint config")
 		}
@@ -152,10 +186,6 @@ func (l *JavaScriptLinter) findIssues(ctx context.Context, code string, options
 		return nil, l.WrapError(err, "failed to parse ESLint output")
 	}
 	
-	if len(eslintResults) == 0 {
-		return []Issue{}, nil
-	}
-	
 	// Convert ESLint results to CodeHawk issues
 	issues := make([]Issue, 0)
 	for _, result := range eslintResults {
@@ -165,11 +195,32 @@ func (l *JavaScriptLinter) findIssues(ctx context.Context, code string, options
 		}
 	}
 	
+	// Merge in compiler diagnostics for TypeScript; tsc cannot read Vue and Svelte components
+	ext := filepath.Ext(tmpFile)
+	if l.typescriptMode && ext != ".vue" && ext != ".svelte" && l.GetBoolOption(options, "typeCheck", true) {
+		typeIssues, err := l.runTypeCheck(ctx, tmpDir, filepath.Base(tmpFile), options)
+		if err != nil {
+			// Log the error but keep the ESLint results
+			fmt.Printf("Warning: tsc type-check failed: %v\n", err)
+		} else {
+			issues = append(issues, typeIssues...)
+		}
+	}
+	
 	return issues, nil
 }
 
-// SuggestFixes attempts to generate fixes for the identified issues
+// SuggestFixes attempts to generate fixes for the identified issues. Without request options
+// the code is fixed as a plain .js or .ts file; Analyze passes the "path" option through.
 func (l *JavaScriptLinter) SuggestFixes(ctx context.Context, code string, issues []Issue) ([]Issue, error) {
+	return l.suggestFixes(ctx, code, issues, nil)
+}
+
+// suggestFixes generates fixes for the issues by running ESLint's fixer on the code, linted as
+// the file named by the request options
+func (l *JavaScriptLinter) suggestFixes(ctx context.Context, code string, issues []Issue, options map[string]interface{}) ([]Issue, error) {
+	fileName := l.sourceFileName(options)
+	
 	// Create a temporary directory
 	tmpDir, err := ioutil.TempDir("", "codehawk-eslint-fix")
 	if err != nil {
@@ -178,12 +229,7 @@ func (l *JavaScriptLinter) SuggestFixes(ctx context.Context, code string, issues
 	defer os.RemoveAll(tmpDir)
 	
 	// Create a temporary file for the code
-	ext := ".js"
-	if l.typescriptMode {
-		ext = ".ts"
-	}
-	
-	tmpFile := filepath.Join(tmpDir, "code"+ext)
+	tmpFile := filepath.Join(tmpDir, fileName)
 	if err := ioutil.WriteFile(tmpFile, []byte(code), 0644); err != nil {
 		return nil, l.WrapError(err, "failed to write to temporary file")
 	}
@@ -200,7 +246,7 @@ func (l *JavaScriptLinter) SuggestFixes(ctx context.Context, code string, issues
 	} else {
 		// Use a default configuration if none is provided
 		configFile := filepath.Join(tmpDir, ".eslintrc.json")
-		defaultConfig := l.getDefaultConfig()
+		defaultConfig := l.getDefaultConfig(filepath.Base(tmpFile))
 		if err := ioutil.WriteFile(configFile, []byte(defaultConfig), 0644); err != nil {
 			return nil, l.WrapError(err, "failed to write default ESLint config")
 		}
@@ -267,6 +313,40 @@ func (l *JavaScriptLinter) SuggestFixes(ctx context.Context, code string, issues
 	return suggestions, nil
 }
 
+// FormatCode formats code with prettier
+func (l *JavaScriptLinter) FormatCode(ctx context.Context, code string, options map[string]interface{}) (string, error) {
+	ctx, cancel := l.CreateTimeoutContext(ctx)
+	defer cancel()
+	
+	// prettier infers the parser from the file name
+	args := []string{
+		"--stdin-filepath", l.sourceFileName(options),
+		"--print-width", strconv.Itoa(l.GetIntOption(options, "lineLength", 80)),
+		"--tab-width", strconv.Itoa(l.GetIntOption(options, "indentWidth", 2)),
+	}
+	
+	if l.GetStringOption(options, "quoteStyle", "single") == "single" {
+		args = append(args, "--single-quote")
+	}
+	
+	cmd := exec.CommandContext(
+		ctx,
+		l.GetStringOption(nil, "prettierPath", "prettier"),
+		args...,
+	)
+	
+	var stdout, stderr bytes.Buffer
+	cmd.Stdin = strings.NewReader(code)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	
+	if err := cmd.Run(); err != nil {
+		return "", l.WrapError(formatterRunError(ctx, "prettier", err, stderr.String()), "prettier execution error")
+	}
+	
+	return stdout.String(), nil
+}
+
 // convertESLintMessage converts an ESLint message to a CodeHawk issue
 func (l *JavaScriptLinter) convertESLintMessage(msg struct {
 	RuleID    string `json:"ruleId"`
@@ -316,44 +396,75 @@ func (l *JavaScriptLinter) convertESLintMessage(msg struct {
 	return issue
 }
 
-// getDefaultConfig returns a default ESLint configuration
-func (l *JavaScriptLinter) getDefaultConfig() string {
-	if l.typescriptMode {
-		return `{
-			"parser": "@typescript-eslint/parser",
-			"plugins": ["@typescript-eslint"],
-			"extends": [
-				"eslint:recommended",
-				"plugin:@typescript-eslint/recommended"
-			],
-			"rules": {
-				"semi": ["error", "always"],
-				"quotes": ["warn", "single"],
-				"indent": ["warn", 2],
-				"no-unused-vars": "warn",
-				"@typescript-eslint/explicit-function-return-type": "warn",
-				"@typescript-eslint/no-explicit-any": "warn"
-			}
-		}`
+// getDefaultConfig returns a default ESLint configuration for the file the code is linted as.
+// Vue and Svelte components are parsed by their own parsers, with the TypeScript parser for
+// their scripts in TypeScript mode; other files get the React hooks and accessibility rules,
+// and JSX and TSX files the React rules as well.
+func (l *JavaScriptLinter) getDefaultConfig(fileName string) string {
+	extends := []string{"eslint:recommended"}
+	plugins := make([]string, 0)
+	parserOptions := map[string]interface{}{
+		"ecmaVersion": 2018,
+		"sourceType":  "module",
 	}
+	rules := map[string]interface{}{
+		"semi":           []interface{}{"error", "always"},
+		"quotes":         []interface{}{"warn", "single"},
+		"indent":         []interface{}{"warn", 2},
+		"no-unused-vars": "warn",
+	}
+	config := map[string]interface{}{}
 	
-	return `{
-		"env": {
+	if l.typescriptMode {
+		config["parser"] = "@typescript-eslint/parser"
+		plugins = append(plugins, "@typescript-eslint")
+		extends = append(extends, "plugin:@typescript-eslint/recommended")
+		rules["@typescript-eslint/explicit-function-return-type"] = "warn"
+		rules["@typescript-eslint/no-explicit-any"] = "warn"
+	} else {
+		config["env"] = map[string]interface{}{
 			"browser": true,
-			"node": true,
-			"es6": true
-		},
-		"extends": "eslint:recommended",
-		"parserOptions": {
-			"ecmaVersion": 2018,
-			"sourceType": "module"
-		},
-		"rules": {
-			"semi": ["error", "always"],
-			"quotes": ["warn", "single"],
-			"indent": ["warn", 2],
-			"no-unused-vars": "warn",
-			"no-console": "warn"
+			"node":    true,
+			"es6":     true,
+		}
+		rules["no-console"] = "warn"
+	}
+	
+	switch ext := filepath.Ext(fileName); ext {
+	case ".vue", ".svelte":
+		if l.typescriptMode {
+			parserOptions["parser"] = "@typescript-eslint/parser"
+		}
+		if ext == ".vue" {
+			config["parser"] = "vue-eslint-parser"
+			extends = append(extends, "plugin:vue/vue3-recommended")
+		} else {
+			// The Svelte config parses .svelte files with svelte-eslint-parser
+			extends = append(extends, "plugin:svelte/recommended")
+		}
+	default:
+		if !l.typescriptMode {
+			parserOptions["ecmaFeatures"] = map[string]interface{}{"jsx": true}
+		}
+		plugins = append(plugins, "react-hooks", "jsx-a11y")
+		extends = append(extends, "plugin:jsx-a11y/recommended")
+		rules["react-hooks/rules-of-hooks"] = "error"
+		rules["react-hooks/exhaustive-deps"] = "warn"
+		
+		if ext == ".jsx" || ext == ".tsx" {
+			extends = append(extends, "plugin:react/recommended", "plugin:react/jsx-runtime")
+			config["settings"] = map[string]interface{}{
+				"react": map[string]interface{}{"version": "18.2"},
+			}
 		}
-	}`
-}
\ No newline at end of file
+	}
+	
+	config["extends"] = extends
+	config["plugins"] = plugins
+	config["parserOptions"] = parserOptions
+	config["rules"] = rules
+	
+	// The configuration only holds plain values, which always encode
+	encoded, _ := json.MarshalIndent(config, "", "  ")
+	return string(encoded)
+}
@@ -0,0 +1,288 @@
+package analyzer
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// fakeESLint writes an eslint script that logs the name of the file it lints and copies its
+// configuration into logDir. With --fix it swaps double quotes for single quotes in the file,
+// otherwise it reports the quotes rule on line 2.
+func fakeESLint(t *testing.T, logDir string) string {
+	t.Helper()
+	script := `for last; do :; done
+basename "$last" >> "` + logDir + `/files"
+prev=""
+for arg; do
+	if [ "$prev" = "--config" ]; then cp "$arg" "` + logDir + `/config.json"; fi
+	prev=$arg
+done
+case " $* " in *" --fix "*) sed -i "s/\"/'/g" "$last"; exit 0;; esac
+cat <<'CODEHAWK_EOF'
+[{"filePath":"code","messages":[{"ruleId":"quotes","severity":1,"message":"Strings must use singlequote.","line":2,"column":19,"nodeType":"Literal","fix":{"range":[45,48],"text":"'a'"}}],"errorCount":0,"warningCount":1}]
+CODEHAWK_EOF
+exit 1`
+	return fakeScript(t, script)
+}
+
+func TestJavaScriptSourceFileName(t *testing.T) {
+	tests := []struct {
+		path           string
+		wantJavaScript string
+		wantTypeScript string
+	}{
+		{"", "code.js", "code.ts"},
+		{"src/App.JSX", "code.jsx", "code.ts"},
+		{"src/App.tsx", "code.js", "code.tsx"},
+		{"App.vue", "code.vue", "code.vue"},
+		{"Button.svelte", "code.svelte", "code.svelte"},
+		{"server.mjs", "code.mjs", "code.ts"},
+		{"server.cts", "code.js", "code.cts"},
+		{"script.py", "code.js", "code.ts"},
+	}
+	
+	javascript, typescript := NewJavaScriptLinter(map[string]string{}), NewTypeScriptLinter(map[string]string{})
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			options := map[string]interface{}{"path": tt.path}
+			if got := javascript.sourceFileName(options); got != tt.wantJavaScript {
+				t.Errorf("javascript: got %s, want %s", got, tt.wantJavaScript)
+			}
+			if got := typescript.sourceFileName(options); got != tt.wantTypeScript {
+				t.Errorf("typescript: got %s, want %s", got, tt.wantTypeScript)
+			}
+		})
+	}
+}
+
+func TestJavaScriptDefaultConfig(t *testing.T) {
+	tests := []struct {
+		name              string
+		typescript        bool
+		fileName          string
+		wantParser        interface{}
+		wantScriptParser  interface{}
+		wantExtends       []string
+		wantPlugins       []string
+		wantJSX           bool
+		wantReactSettings bool
+	}{
+		{
+			name:        "javascript",
+			fileName:    "code.js",
+			wantExtends: []string{"eslint:recommended", "plugin:jsx-a11y/recommended"},
+			wantPlugins: []string{"react-hooks", "jsx-a11y"},
+			wantJSX:     true,
+		},
+		{
+			name:              "jsx",
+			fileName:          "code.jsx",
+			wantExtends:       []string{"eslint:recommended", "plugin:jsx-a11y/recommended", "plugin:react/recommended", "plugin:react/jsx-runtime"},
+			wantPlugins:       []string{"react-hooks", "jsx-a11y"},
+			wantJSX:           true,
+			wantReactSettings: true,
+		},
+		{
+			name:              "tsx",
+			typescript:        true,
+			fileName:          "code.tsx",
+			wantParser:        "@typescript-eslint/parser",
+			wantExtends:       []string{"eslint:recommended", "plugin:@typescript-eslint/recommended", "plugin:jsx-a11y/recommended", "plugin:react/recommended", "plugin:react/jsx-runtime"},
+			wantPlugins:       []string{"@typescript-eslint", "react-hooks", "jsx-a11y"},
+			wantReactSettings: true,
+		},
+		{
+			name:        "vue",
+			fileName:    "code.vue",
+			wantParser:  "vue-eslint-parser",
+			wantExtends: []string{"eslint:recommended", "plugin:vue/vue3-recommended"},
+			wantPlugins: []string{},
+		},
+		{
+			name:             "vue with typescript",
+			typescript:       true,
+			fileName:         "code.vue",
+			wantParser:       "vue-eslint-parser",
+			wantScriptParser: "@typescript-eslint/parser",
+			wantExtends:      []string{"eslint:recommended", "plugin:@typescript-eslint/recommended", "plugin:vue/vue3-recommended"},
+			wantPlugins:      []string{"@typescript-eslint"},
+		},
+		{
+			name:             "svelte with typescript",
+			typescript:       true,
+			fileName:         "code.svelte",
+			wantParser:       "@typescript-eslint/parser",
+			wantScriptParser: "@typescript-eslint/parser",
+			wantExtends:      []string{"eslint:recommended", "plugin:@typescript-eslint/recommended", "plugin:svelte/recommended"},
+			wantPlugins:      []string{"@typescript-eslint"},
+		},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			linter := NewJavaScriptLinter(map[string]string{})
+			if tt.typescript {
+				linter = NewTypeScriptLinter(map[string]string{})
+			}
+			
+			var config struct {
+				Parser        interface{}            `json:"parser"`
+				Extends       []string               `json:"extends"`
+				Plugins       []string               `json:"plugins"`
+				ParserOptions map[string]interface{} `json:"parserOptions"`
+				Settings      map[string]interface{} `json:"settings"`
+				Rules         map[string]interface{} `json:"rules"`
+			}
+			if err := json.Unmarshal([]byte(linter.getDefaultConfig(tt.fileName)), &config); err != nil {
+				t.Fatal(err)
+			}
+			
+			if config.Parser != tt.wantParser {
+				t.Errorf("got parser %v, want %v", config.Parser, tt.wantParser)
+			}
+			if config.ParserOptions["parser"] != tt.wantScriptParser {
+				t.Errorf("got script parser %v, want %v", config.ParserOptions["parser"], tt.wantScriptParser)
+			}
+			if !reflect.DeepEqual(config.Extends, tt.wantExtends) {
+				t.Errorf("got extends %v, want %v", config.Extends, tt.wantExtends)
+			}
+			if !reflect.DeepEqual(config.Plugins, tt.wantPlugins) {
+				t.Errorf("got plugins %v, want %v", config.Plugins, tt.wantPlugins)
+			}
+			if _, ok := config.ParserOptions["ecmaFeatures"]; ok != tt.wantJSX {
+				t.Errorf("got ecmaFeatures %v, want jsx: %v", config.ParserOptions["ecmaFeatures"], tt.wantJSX)
+			}
+			if (config.Settings != nil) != tt.wantReactSettings {
+				t.Errorf("got settings %v, want react settings: %v", config.Settings, tt.wantReactSettings)
+			}
+			if _, ok := config.Rules["react-hooks/rules-of-hooks"]; ok != strings.Contains(strings.Join(tt.wantPlugins, ","), "react-hooks") {
+				t.Errorf("got rules %v, want the hooks rules only with the hooks plugin", config.Rules)
+			}
+		})
+	}
+}
+
+func TestJavaScriptAnalyze(t *testing.T) {
+	code := "<template><p/></template>\n<script>const a = \"a\"</script>\n"
+	
+	tests := []struct {
+		name       string
+		typescript bool
+		options    map[string]interface{}
+		wantFile   string
+		wantParser string
+		wantTSC    bool
+	}{
+		{
+			name:       "vue component",
+			options:    map[string]interface{}{"path": "src/App.vue"},
+			wantFile:   "code.vue",
+			wantParser: "vue-eslint-parser",
+		},
+		{
+			name:       "vue component in typescript mode is not type-checked",
+			typescript: true,
+			options:    map[string]interface{}{"path": "src/App.vue"},
+			wantFile:   "code.vue",
+			wantParser: "vue-eslint-parser",
+		},
+		{
+			name:       "tsx is type-checked",
+			typescript: true,
+			options:    map[string]interface{}{"path": "src/App.tsx"},
+			wantFile:   "code.tsx",
+			wantParser: "@typescript-eslint/parser",
+			wantTSC:    true,
+		},
+		{
+			name:       "type check turned off",
+			typescript: true,
+			options:    map[string]interface{}{"path": "src/App.tsx", "typeCheck": false},
+			wantFile:   "code.tsx",
+			wantParser: "@typescript-eslint/parser",
+		},
+		{
+			name:     "extension of the other mode",
+			options:  map[string]interface{}{"path": "src/App.ts"},
+			wantFile: "code.js",
+		},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logDir := t.TempDir()
+			tscMarker := filepath.Join(logDir, "tsc-ran")
+			config := map[string]string{"eslintPath": fakeESLint(t, logDir), "tscPath": fakeScript(t, "touch "+tscMarker)}
+			
+			linter := NewJavaScriptLinter(config)
+			if tt.typescript {
+				linter = NewTypeScriptLinter(config)
+			}
+			result, err := linter.Analyze(context.Background(), code, tt.options)
+			if err != nil {
+				t.Fatal(err)
+			}
+			
+			files, err := ioutil.ReadFile(filepath.Join(logDir, "files"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if want := tt.wantFile + "\n" + tt.wantFile + "\n"; string(files) != want {
+				t.Errorf("eslint linted and fixed %q, want %q", files, want)
+			}
+			
+			var eslintConfig map[string]interface{}
+			data, err := ioutil.ReadFile(filepath.Join(logDir, "config.json"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := json.Unmarshal(data, &eslintConfig); err != nil {
+				t.Fatal(err)
+			}
+			if parser, _ := eslintConfig["parser"].(string); parser != tt.wantParser {
+				t.Errorf("got parser %q, want %q", parser, tt.wantParser)
+			}
+			
+			if _, err := os.Stat(tscMarker); (err == nil) != tt.wantTSC {
+				t.Errorf("got tsc run: %v, want %v", err == nil, tt.wantTSC)
+			}
+			
+			if len(result.Issues) != 1 {
+				t.Fatalf("got %d issues, want 1: %+v", len(result.Issues), result.Issues)
+			}
+			issue := result.Issues[0]
+			if issue.RuleID != "quotes" || issue.Severity != "warning" || issue.Line != 2 || *issue.Column != 19 || issue.Context != "Literal" {
+				t.Errorf("unexpected issue %+v", issue)
+			}
+			if issue.Fix == nil || issue.Fix.Replacement != "'a'" {
+				t.Errorf("got fix %+v, want the eslint fix text", issue.Fix)
+			}
+			
+			if len(result.Suggestions) != 1 {
+				t.Fatalf("got %d suggestions, want 1: %+v", len(result.Suggestions), result.Suggestions)
+			}
+			fixed, err := ApplyFix(code, result.Suggestions[0].Line, *result.Suggestions[0].Fix)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if want := "<template><p/></template>\n<script>const a = 'a'</script>\n"; fixed != want {
+				t.Errorf("got fixed code %q, want %q", fixed, want)
+			}
+		})
+	}
+}
+
+func TestJavaScriptAnalyzeESLintFailure(t *testing.T) {
+	eslintPath := fakeScript(t, "echo 'Oops! Something went wrong!' >&2\nexit 2")
+	
+	_, err := NewJavaScriptLinter(map[string]string{"eslintPath": eslintPath}).Analyze(context.Background(), "let a\n", nil)
+	if err == nil || !strings.Contains(err.Error(), "Something went wrong") {
+		t.Errorf("got error %v, want eslint's stderr", err)
+	}
+}
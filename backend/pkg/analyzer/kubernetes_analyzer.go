@@ -0,0 +1,285 @@
+package analyzer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// yamlErrorLinePattern extracts the line from yaml errors such as "yaml: line 4: mapping values are not allowed"
+var yamlErrorLinePattern = regexp.MustCompile(`line (\d+)`)
+
+// KubernetesAnalyzer implements the Linter interface for Kubernetes manifests. Manifests are
+// checked natively, so no external tool is needed.
+type KubernetesAnalyzer struct {
+	*BaseAnalyzer
+}
+
+// NewKubernetesAnalyzer creates a new Kubernetes manifest analyzer
+func NewKubernetesAnalyzer(config map[string]string) *KubernetesAnalyzer {
+	return &KubernetesAnalyzer{
+		BaseAnalyzer: NewBaseAnalyzer(config),
+	}
+}
+
+// Language returns the identifier for the supported language
+func (a *KubernetesAnalyzer) Language() string {
+	return "kubernetes"
+}
+
+// Analyze analyzes the provided manifests and returns issues found
+func (a *KubernetesAnalyzer) Analyze(ctx context.Context, code string, options map[string]interface{}) (*AnalysisResult, error) {
+	return a.BaseAnalyzer.CommonAnalyzeWrapper(
+		ctx,
+		code,
+		options,
+		a.findIssues,
+		a.SuggestFixes,
+	)
+}
+
+// k8sCheck collects the issues found in one manifest stream
+type k8sCheck struct {
+	issues   []Issue
+	disabled map[string]bool
+}
+
+// report records an issue unless its rule has been disabled
+func (c *k8sCheck) report(node *yaml.Node, ruleID, severity, message string) {
+	if c.disabled[ruleID] {
+		return
+	}
+	
+	column := node.Column
+	c.issues = append(c.issues, Issue{
+		Line:     node.Line,
+		Column:   &column,
+		Message:  message,
+		Severity: severity,
+		RuleID:   ruleID,
+	})
+}
+
+// findIssues parses every document in the manifest and checks the pod specs it contains
+func (a *KubernetesAnalyzer) findIssues(ctx context.Context, code string, options map[string]interface{}) ([]Issue, error) {
+	check := &k8sCheck{
+		issues:   make([]Issue, 0),
+		disabled: make(map[string]bool),
+	}
+	for _, rule := range a.GetListOption(options, "disabledRules", nil) {
+		check.disabled[rule] = true
+	}
+	
+	decoder := yaml.NewDecoder(strings.NewReader(code))
+	for {
+		var document yaml.Node
+		err := decoder.Decode(&document)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			// The rest of the stream cannot be read after a syntax error
+			line := 1
+			if matches := yamlErrorLinePattern.FindStringSubmatch(err.Error()); matches != nil {
+				line, _ = strconv.Atoi(matches[1])
+			}
+			check.issues = append(check.issues, Issue{
+				Line:     line,
+				Message:  err.Error(),
+				Severity: "error",
+				RuleID:   "yaml-syntax-error",
+			})
+			break
+		}
+		
+		if len(document.Content) == 0 {
+			continue
+		}
+		a.checkObject(check, document.Content[0])
+	}
+	
+	return check.issues, nil
+}
+
+// checkObject checks a single Kubernetes object, including the items of a List
+func (a *KubernetesAnalyzer) checkObject(check *k8sCheck, object *yaml.Node) {
+	if object.Kind != yaml.MappingNode {
+		return
+	}
+	
+	kind := yamlScalar(yamlLookup(object, "kind"))
+	if kind == "List" {
+		if items := yamlLookup(object, "items"); items != nil {
+			for _, item := range items.Content {
+				a.checkObject(check, item)
+			}
+		}
+		return
+	}
+	
+	// Find the pod template of each workload kind
+	var podSpec *yaml.Node
+	switch kind {
+	case "Pod":
+		podSpec = yamlLookup(object, "spec")
+	case "Deployment", "StatefulSet", "DaemonSet", "ReplicaSet", "ReplicationController", "Job":
+		podSpec = yamlLookup(object, "spec", "template", "spec")
+	case "CronJob":
+		podSpec = yamlLookup(object, "spec", "jobTemplate", "spec", "template", "spec")
+	}
+	if podSpec == nil {
+		return
+	}
+	
+	// Batch workloads run to completion and have no use for probes
+	needsProbes := kind != "Job" && kind != "CronJob" && kind != "Pod"
+	
+	podSecurity := yamlLookup(podSpec, "securityContext")
+	
+	if containers := yamlLookup(podSpec, "containers"); containers != nil {
+		for _, container := range containers.Content {
+			a.checkContainer(check, container, podSecurity, needsProbes)
+		}
+	}
+	if containers := yamlLookup(podSpec, "initContainers"); containers != nil {
+		for _, container := range containers.Content {
+			a.checkContainer(check, container, podSecurity, false)
+		}
+	}
+}
+
+// checkContainer checks a single container of a pod spec
+func (a *KubernetesAnalyzer) checkContainer(check *k8sCheck, container, podSecurity *yaml.Node, needsProbes bool) {
+	if container.Kind != yaml.MappingNode {
+		return
+	}
+	
+	name := yamlScalar(yamlLookup(container, "name"))
+	anchor := container
+	if nameNode := yamlLookup(container, "name"); nameNode != nil {
+		anchor = nameNode
+	}
+	
+	// Image tags
+	if image := yamlLookup(container, "image"); image != nil {
+		if tag := imageTag(image.Value); tag == "" || tag == "latest" {
+			check.report(image, "k8s-latest-tag", "warning",
+				fmt.Sprintf("Container %q uses image %q without a pinned tag; use a specific version or digest so deployments are reproducible", name, image.Value))
+		}
+	}
+	
+	// Resource limits
+	limits := yamlLookup(container, "resources", "limits")
+	if limits == nil {
+		check.report(anchor, "k8s-missing-resource-limits", "warning",
+			fmt.Sprintf("Container %q has no resource limits; set resources.limits.cpu and resources.limits.memory", name))
+	} else {
+		for _, resource := range []string{"cpu", "memory"} {
+			if yamlLookup(limits, resource) == nil {
+				check.report(limits, "k8s-missing-resource-limits", "warning",
+					fmt.Sprintf("Container %q has no %s limit", name, resource))
+			}
+		}
+	}
+	
+	// Privileged containers
+	security := yamlLookup(container, "securityContext")
+	if privileged := yamlLookup(security, "privileged"); yamlScalar(privileged) == "true" {
+		check.report(privileged, "k8s-privileged-container", "error",
+			fmt.Sprintf("Container %q runs privileged, which gives it full access to the host", name))
+	}
+	
+	// Probes
+	if needsProbes {
+		for _, probe := range []string{"livenessProbe", "readinessProbe"} {
+			if yamlLookup(container, probe) == nil {
+				check.report(anchor, "k8s-missing-probe", "warning",
+					fmt.Sprintf("Container %q has no %s", name, probe))
+			}
+		}
+	}
+	
+	// Running as root; container settings override the pod's
+	runAsNonRoot := yamlScalar(yamlLookup(podSecurity, "runAsNonRoot"))
+	if value := yamlLookup(security, "runAsNonRoot"); value != nil {
+		runAsNonRoot = yamlScalar(value)
+	}
+	
+	runAsUser := yamlLookup(podSecurity, "runAsUser")
+	if value := yamlLookup(security, "runAsUser"); value != nil {
+		runAsUser = value
+	}
+	
+	if runAsUser != nil && yamlScalar(runAsUser) == "0" {
+		check.report(runAsUser, "k8s-run-as-root", "error",
+			fmt.Sprintf("Container %q explicitly runs as root (runAsUser: 0)", name))
+	} else if runAsNonRoot != "true" && runAsUser == nil {
+		check.report(anchor, "k8s-run-as-root", "warning",
+			fmt.Sprintf("Container %q may run as root; set securityContext.runAsNonRoot: true or a non-zero runAsUser", name))
+	}
+}
+
+// imageTag returns the tag of an image reference, or "@" for references pinned by digest
+func imageTag(image string) string {
+	if strings.Contains(image, "@") {
+		return "@"
+	}
+	
+	// A colon before the last slash belongs to the registry host, e.g. "registry:5000/app"
+	lastPart := image[strings.LastIndex(image, "/")+1:]
+	if colon := strings.LastIndex(lastPart, ":"); colon >= 0 {
+		return lastPart[colon+1:]
+	}
+	return ""
+}
+
+// yamlLookup follows a path of mapping keys from node, returning nil if any is missing
+func yamlLookup(node *yaml.Node, path ...string) *yaml.Node {
+	for _, key := range path {
+		if node == nil {
+			return nil
+		}
+		
+		// Resolve aliases to the anchored node
+		if node.Kind == yaml.AliasNode {
+			node = node.Alias
+		}
+		if node.Kind != yaml.MappingNode {
+			return nil
+		}
+		
+		var next *yaml.Node
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			if node.Content[i].Value == key {
+				next = node.Content[i+1]
+				break
+			}
+		}
+		node = next
+	}
+	
+	if node != nil && node.Kind == yaml.AliasNode {
+		node = node.Alias
+	}
+	return node
+}
+
+// yamlScalar returns the value of a scalar node, or an empty string
+func yamlScalar(node *yaml.Node) string {
+	if node == nil || node.Kind != yaml.ScalarNode {
+		return ""
+	}
+	return node.Value
+}
+
+// SuggestFixes attempts to generate fixes for the identified issues
+func (a *KubernetesAnalyzer) SuggestFixes(ctx context.Context, code string, issues []Issue) ([]Issue, error) {
+	// Manifest fixes depend on the workload, so none are generated automatically
+	return []Issue{}, nil
+}
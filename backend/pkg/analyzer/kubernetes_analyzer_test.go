@@ -0,0 +1,146 @@
+package analyzer
+
+import (
+	"context"
+	"testing"
+)
+
+func TestImageTag(t *testing.T) {
+	tests := []struct {
+		image string
+		want  string
+	}{
+		{"nginx", ""},
+		{"nginx:1.25", "1.25"},
+		{"nginx:latest", "latest"},
+		{"registry:5000/team/app", ""},
+		{"registry:5000/team/app:v2", "v2"},
+		{"nginx@sha256:0123abcd", "@"},
+	}
+	
+	for _, tt := range tests {
+		if got := imageTag(tt.image); got != tt.want {
+			t.Errorf("imageTag(%q) = %q, want %q", tt.image, got, tt.want)
+		}
+	}
+}
+
+func TestKubernetesFindIssues(t *testing.T) {
+	tests := []struct {
+		name    string
+		code    string
+		options map[string]interface{}
+		want    map[string]int
+	}{
+		{
+			name: "hardened deployment",
+			code: `apiVersion: apps/v1
+kind: Deployment
+spec:
+  template:
+    spec:
+      securityContext:
+        runAsNonRoot: true
+      containers:
+        - name: web
+          image: nginx:1.25
+          resources:
+            limits: {cpu: 500m, memory: 128Mi}
+          livenessProbe: {httpGet: {path: /, port: 80}}
+          readinessProbe: {httpGet: {path: /, port: 80}}
+`,
+			want: map[string]int{},
+		},
+		{
+			name: "bare pod",
+			code: `apiVersion: v1
+kind: Pod
+spec:
+  containers:
+    - name: app
+      image: app
+      securityContext:
+        privileged: true
+        runAsUser: 0
+`,
+			want: map[string]int{"k8s-latest-tag": 1, "k8s-missing-resource-limits": 1, "k8s-privileged-container": 1, "k8s-run-as-root": 1},
+		},
+		{
+			name: "list items and partial limits",
+			code: `apiVersion: v1
+kind: List
+items:
+  - kind: CronJob
+    spec:
+      jobTemplate:
+        spec:
+          template:
+            spec:
+              securityContext:
+                runAsUser: 1000
+              containers:
+                - name: job
+                  image: job:latest
+                  resources:
+                    limits: {cpu: 100m}
+`,
+			want: map[string]int{"k8s-latest-tag": 1, "k8s-missing-resource-limits": 1},
+		},
+		{
+			name: "anchors are resolved",
+			code: `kind: Deployment
+spec:
+  template:
+    spec:
+      containers:
+        - name: web
+          image: web:1.0
+          resources:
+            limits: &limits {cpu: 1, memory: 1Gi}
+          livenessProbe: {exec: {command: [true]}}
+          readinessProbe: {exec: {command: [true]}}
+          securityContext: {runAsNonRoot: true}
+        - name: sidecar
+          image: sidecar:1.0
+          resources:
+            limits: *limits
+          securityContext: {runAsNonRoot: true}
+`,
+			want: map[string]int{"k8s-missing-probe": 2},
+		},
+		{
+			name:    "disabled rules",
+			code:    "kind: Pod\nspec:\n  containers:\n    - name: app\n      image: app\n",
+			options: map[string]interface{}{"disabledRules": []interface{}{"k8s-latest-tag", "k8s-missing-resource-limits", "k8s-run-as-root"}},
+			want:    map[string]int{},
+		},
+		{
+			name: "syntax error",
+			code: "kind: Pod\nspec:\n  containers: [\n",
+			want: map[string]int{"yaml-syntax-error": 1},
+		},
+	}
+	
+	a := NewKubernetesAnalyzer(map[string]string{})
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			issues, err := a.findIssues(context.Background(), tt.code, tt.options)
+			if err != nil {
+				t.Fatalf("findIssues() error = %v", err)
+			}
+			
+			got := make(map[string]int)
+			for _, issue := range issues {
+				got[issue.RuleID]++
+			}
+			if len(got) != len(tt.want) {
+				t.Errorf("issues = %+v, want %v", issues, tt.want)
+			}
+			for ruleID, count := range tt.want {
+				if got[ruleID] != count {
+					t.Errorf("%s reported %d times, want %d", ruleID, got[ruleID], count)
+				}
+			}
+		})
+	}
+}
@@ -16,12 +16,41 @@ type Issue struct {
 	Fix         *IssueFix   `json:"fix,omitempty"`
 	Suggestions []IssueFix  `json:"suggestions,omitempty"`
 	Metadata    interface{} `json:"metadata,omitempty"`
+	Category    string      `json:"category,omitempty"`
+	Tags        []string    `json:"tags,omitempty"`
+	CWE         string      `json:"cwe,omitempty"`
+	OWASP       string      `json:"owasp,omitempty"`
+	DocURL      string      `json:"docUrl,omitempty"`
+	Confidence  string      `json:"confidence,omitempty"`
+	// Cell is the index of the notebook cell an issue of a Jupyter notebook is in; Line then
+	// counts the lines of the cell
+	Cell *int `json:"cell,omitempty"`
+	// RelatedLocations are other places involved in the issue, such as the path of a data flow
+	RelatedLocations []RelatedLocation `json:"relatedLocations,omitempty"`
 }
 
-// IssueFix represents a suggested fix for an issue
+// RelatedLocation is a secondary location of an issue
+type RelatedLocation struct {
+	Line    int    `json:"line"`
+	Column  int    `json:"column,omitempty"`
+	Message string `json:"message"`
+}
+
+// IssueFix represents a suggested fix for an issue. Without a Range the
+// replacement substitutes the whole line the issue was reported on.
 type IssueFix struct {
-	Description string `json:"description"`
-	Replacement string `json:"replacement"`
+	Description string    `json:"description"`
+	Replacement string    `json:"replacement"`
+	Range       *FixRange `json:"range,omitempty"`
+}
+
+// FixRange identifies the span of source replaced by a fix. Lines and
+// columns are 1-based, columns count characters, and the end is exclusive.
+type FixRange struct {
+	StartLine   int `json:"startLine"`
+	StartColumn int `json:"startColumn"`
+	EndLine     int `json:"endLine"`
+	EndColumn   int `json:"endColumn"`
 }
 
 // AnalysisResult represents the result of code analysis
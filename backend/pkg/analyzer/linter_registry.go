@@ -7,8 +7,9 @@ import (
 
 // LinterRegistry manages available linters
 type LinterRegistry struct {
-	linters map[string]Linter
-	mu      sync.RWMutex
+	linters  map[string]Linter
+	fallback Linter
+	mu       sync.RWMutex
 }
 
 // NewLinterRegistry creates a new linter registry
@@ -39,6 +40,33 @@ func (r *LinterRegistry) GetLinter(language string) (Linter, bool) {
 	return linter, ok
 }
 
+// SetFallback sets the linter used for languages without a registered linter
+func (r *LinterRegistry) SetFallback(linter Linter) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	
+	r.fallback = linter
+}
+
+// GetFallback retrieves the linter used for languages without a registered linter
+func (r *LinterRegistry) GetFallback() (Linter, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	
+	return r.fallback, r.fallback != nil
+}
+
+// GetFormatter retrieves the formatter for a specific language, if its linter supports formatting
+func (r *LinterRegistry) GetFormatter(language string) (Formatter, bool) {
+	linter, ok := r.GetLinter(language)
+	if !ok {
+		return nil, false
+	}
+	
+	formatter, ok := linter.(Formatter)
+	return formatter, ok
+}
+
 // GetSupportedLanguages returns a list of supported languages
 func (r *LinterRegistry) GetSupportedLanguages() []string {
 	r.mu.RLock()
@@ -57,21 +85,29 @@ func (r *LinterRegistry) RegisterDefaultLinters() {
 	// Python linter
 	pythonLinter := NewPythonLinter(map[string]string{
 		"pylintPath": "pylint",
+		"ruffPath":   "ruff",
+		"mypyPath":   "mypy",
+		"blackPath":  "black",
+		"backends":   "pylint",
+		"formatter":  "black",
 		"timeout":    "15s",
 	})
 	r.Register(pythonLinter)
 	
 	// JavaScript linter
 	jsLinter := NewJavaScriptLinter(map[string]string{
-		"eslintPath": "eslint",
-		"timeout":    "15s",
+		"eslintPath":   "eslint",
+		"prettierPath": "prettier",
+		"timeout":      "15s",
 	})
 	r.Register(jsLinter)
 	
 	// TypeScript linter
 	tsLinter := NewTypeScriptLinter(map[string]string{
-		"eslintPath": "eslint",
-		"timeout":    "15s",
+		"eslintPath":   "eslint",
+		"tscPath":      "tsc",
+		"prettierPath": "prettier",
+		"timeout":      "30s",
 	})
 	r.Register(tsLinter)
 	
@@ -79,7 +115,100 @@ func (r *LinterRegistry) RegisterDefaultLinters() {
 	goLinter := NewGoLinter(map[string]string{
 		"golangciLintPath": "golangci-lint",
 		"staticcheckPath":  "staticcheck",
+		"goPath":           "go",
+		"gofmtPath":        "gofmt",
+		"goimportsPath":    "goimports",
 		"timeout":          "15s",
+		"buildTimeout":     "60s",
 	})
 	r.Register(goLinter)
+	
+	// Rust linter
+	rustLinter := NewRustLinter(map[string]string{
+		"cargoPath":   "cargo",
+		"rustfmtPath": "rustfmt",
+		"timeout":     "60s",
+	})
+	r.Register(rustLinter)
+	
+	// Java linter
+	javaLinter := NewJavaLinter(map[string]string{
+		"pmdPath":          "pmd",
+		"checkstylePath":   "checkstyle",
+		"pmdRuleset":       "rulesets/java/quickstart.xml",
+		"checkstyleConfig": "/google_checks.xml",
+		"timeout":          "60s",
+	})
+	r.Register(javaLinter)
+	
+	// Shell script linter
+	shellLinter := NewShellLinter(map[string]string{
+		"shellcheckPath": "shellcheck",
+		"timeout":        "15s",
+	})
+	r.Register(shellLinter)
+	
+	// Dockerfile linter
+	dockerfileLinter := NewDockerfileLinter(map[string]string{
+		"hadolintPath": "hadolint",
+		"timeout":      "15s",
+	})
+	r.Register(dockerfileLinter)
+	
+	// Kubernetes manifest analyzer
+	kubernetesAnalyzer := NewKubernetesAnalyzer(map[string]string{
+		"timeout": "10s",
+	})
+	r.Register(kubernetesAnalyzer)
+	
+	// SQL linter
+	sqlLinter := NewSQLLinter(map[string]string{
+		"sqlfluffPath": "sqlfluff",
+		"dialect":      "postgres",
+		"timeout":      "15s",
+	})
+	r.Register(sqlLinter)
+	
+	// Terraform analyzer
+	terraformAnalyzer := NewTerraformAnalyzer(map[string]string{
+		"tflintPath": "tflint",
+		"timeout":    "30s",
+	})
+	r.Register(terraformAnalyzer)
+	
+	// C and C++ linters
+	cLinter := NewCLinter(map[string]string{
+		"clangTidyPath": "clang-tidy",
+		"checks":        "clang-analyzer-*,bugprone-*,cert-*,performance-*,readability-*,-readability-magic-numbers,-readability-identifier-length",
+		"timeout":       "60s",
+	})
+	r.Register(cLinter)
+	
+	cppLinter := NewCppLinter(map[string]string{
+		"clangTidyPath": "clang-tidy",
+		"checks":        "clang-analyzer-*,bugprone-*,cppcoreguidelines-*,modernize-*,performance-*,readability-*,-readability-magic-numbers,-readability-identifier-length,-cppcoreguidelines-avoid-magic-numbers,-modernize-use-trailing-return-type",
+		"timeout":       "60s",
+	})
+	r.Register(cppLinter)
+	
+	// Code embedded in Markdown and HTML documents, analyzed by the linters above
+	markdownAnalyzer := NewMarkdownAnalyzer(map[string]string{
+		"timeout": "120s",
+	}, r)
+	r.Register(markdownAnalyzer)
+	
+	htmlAnalyzer := NewHTMLAnalyzer(map[string]string{
+		"timeout": "60s",
+	}, r)
+	r.Register(htmlAnalyzer)
+	
+	// Language-agnostic checks for everything else
+	r.SetFallback(NewGenericAnalyzer(map[string]string{
+		"maxLineLength":   "120",
+		"maxFileLines":    "1000",
+		"maxNestingDepth": "5",
+		"tabWidth":        "4",
+		"todoMarkers":     "TODO,FIXME,XXX,HACK",
+		"timeout":         "5s",
+	}))
 }
\ No newline at end of file
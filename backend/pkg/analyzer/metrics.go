@@ -0,0 +1,875 @@
+package analyzer
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// FunctionMetrics are the size and complexity metrics of a single function
+type FunctionMetrics struct {
+	Name                 string  `json:"name"`
+	Line                 int     `json:"line"`
+	EndLine              int     `json:"endLine"`
+	LinesOfCode          int     `json:"linesOfCode"`
+	Parameters           int     `json:"parameters"`
+	CyclomaticComplexity int     `json:"cyclomaticComplexity"`
+	CognitiveComplexity  int     `json:"cognitiveComplexity"`
+	NestingDepth         int     `json:"nestingDepth"`
+	MaintainabilityIndex float64 `json:"maintainabilityIndex"`
+	// Cell is the index of the notebook cell a function of a Jupyter notebook is in; Line and
+	// EndLine then count the lines of the cell
+	Cell *int `json:"cell,omitempty"`
+}
+
+// FileMetrics are the metrics of the whole file. Complexities are the sums over its functions.
+type FileMetrics struct {
+	Lines                int `json:"lines"`
+	LinesOfCode          int `json:"linesOfCode"`
+	CommentLines         int `json:"commentLines"`
+	BlankLines           int `json:"blankLines"`
+	Functions            int `json:"functions"`
+	CyclomaticComplexity int `json:"cyclomaticComplexity"`
+	CognitiveComplexity  int `json:"cognitiveComplexity"`
+	MaxNestingDepth      int `json:"maxNestingDepth"`
+	// MaintainabilityIndex is only computed for languages whose functions can be parsed
+	MaintainabilityIndex float64 `json:"maintainabilityIndex,omitempty"`
+}
+
+// CodeMetrics are the metrics computed for an analysis
+type CodeMetrics struct {
+	File      FileMetrics       `json:"file"`
+	Functions []FunctionMetrics `json:"functions"`
+}
+
+// lineKind classifies a source line for the line counts
+type lineKind int
+
+const (
+	blankLine lineKind = iota
+	codeLine
+	commentLine
+)
+
+// metricsSyntax describes a language well enough for the lightweight parsers: how to skip
+// comments and strings, where functions are, and which tokens add to the complexity
+type metricsSyntax struct {
+	lineComments []string
+	blockComment [2]string
+	quotes       string
+	// charLiterals is set for languages where ' delimits single characters, so that lifetimes
+	// and similar uses of ' are not taken for strings
+	charLiterals bool
+	tripleQuotes bool
+	
+	// functions match function headers up to the opening brace of the body; the first group
+	// is the name and the second the parameter list
+	functions []*regexp.Regexp
+	// indented is set for languages whose blocks are delimited by indentation
+	indented bool
+	// braceless is set for languages where a control structure can have a single statement
+	// without braces as its body
+	braceless bool
+	
+	// structures are the control structures, which add one plus their nesting level to the
+	// cognitive complexity and nest what they contain
+	structures map[string]bool
+	// continuations are the else-like branches, which add one to the cognitive complexity
+	continuations map[string]bool
+	// decisions are the tokens that add a path to the cyclomatic complexity
+	decisions map[string]bool
+	logical   map[string]bool
+	ternary   bool
+	keywords  map[string]bool
+}
+
+// wordSet builds a set from space-separated words
+func wordSet(words string) map[string]bool {
+	set := make(map[string]bool)
+	for _, word := range strings.Fields(words) {
+		set[word] = true
+	}
+	return set
+}
+
+var (
+	jsFunctionPatterns = []*regexp.Regexp{
+		regexp.MustCompile(`function\s*\*?\s*([A-Za-z_$][\w$]*)?\s*(?:<[^>(]*>)?\s*\(([^)]*)\)\s*(?::\s*[^{;]+)?\{`),
+		regexp.MustCompile(`(?:const|let|var)\s+([A-Za-z_$][\w$]*)\s*(?::[^=]+)?=\s*(?:async\s+)?\(([^)]*)\)\s*(?::\s*[^=;{]+)?=>\s*\{`),
+		regexp.MustCompile(`(?m)^[ \t]*(?:(?:async|static|public|private|protected|readonly|override|get|set)\s+)*\*?([A-Za-z_$][\w$]*)\s*(?:<[^>(]*>)?\s*\(([^)]*)\)\s*(?::\s*[^{;]+)?\{`),
+	}
+	
+	cFunctionPatterns = []*regexp.Regexp{
+		regexp.MustCompile(`(?m)^[ \t]*(?:[\w:<>,*&\[\]]+[ \t*&]+)*?([A-Za-z_~][\w:~]*)\s*\(([^;{}()]*)\)\s*(?:const\s*)?(?:noexcept\s*)?(?:override\s*)?(?:throws\s+[\w.,\s]+)?\{`),
+	}
+	
+	rustFunctionPatterns = []*regexp.Regexp{
+		regexp.MustCompile(`\bfn\s+([A-Za-z_]\w*)\s*(?:<[^{>]*>)?\s*\(([^)]*)\)[^{;]*\{`),
+	}
+	
+	goFunctionPatterns = []*regexp.Regexp{
+		regexp.MustCompile(`(?m)^func\s+(?:\([^)]*\)\s*)?([A-Za-z_]\w*)\s*(?:\[[^\]]*\])?\s*\(([^)]*)\)[^{\n]*\{`),
+	}
+	
+	pythonFunctionPattern = regexp.MustCompile(`(?m)^([ \t]*)(?:async[ \t]+)?def[ \t]+(\w+)[ \t]*\(`)
+	
+	// metricsTokenPattern splits masked code into tokens; masked strings remain as a pair of quotes
+	metricsTokenPattern = regexp.MustCompile("\"[ \\t\\r\\n]*\"|'[ \\t\\r\\n]*'|`[ \\t\\r\\n]*`|[A-Za-z_$][\\w$]*|\\d[\\w.]*|&&|\\|\\||\\?\\?|\\?\\.|=>|->|::|<<=?|>>=?|\\+\\+|--|[-+*/%&|^<>=!:]=|[^\\s\\w]")
+	
+	// charLiteralPattern matches a character literal at the start of the text
+	charLiteralPattern = regexp.MustCompile(`^'(?:\\.[^'\n]*|[^\\'\n])'`)
+	
+	// metricsNotFunctions are words the header patterns can take for function names
+	metricsNotFunctions = wordSet("if for while switch catch return new else do sizeof synchronized with typeof")
+)
+
+// metricsSyntaxes are the languages with function metrics, plus comment syntax for line counts
+// of other languages
+var metricsSyntaxes = map[string]*metricsSyntax{
+	"go": {
+		lineComments:  []string{"//"},
+		blockComment:  [2]string{"/*", "*/"},
+		quotes:        "\"`",
+		charLiterals:  true,
+		functions:     goFunctionPatterns,
+		structures:    wordSet("if for switch select"),
+		continuations: wordSet("else"),
+		decisions:     wordSet("if for case"),
+		logical:       wordSet("&& ||"),
+		keywords:      wordSet("break case chan const continue default defer else fallthrough for func go goto if import interface map package range return select struct switch type var"),
+	},
+	"javascript": {
+		lineComments:  []string{"//"},
+		blockComment:  [2]string{"/*", "*/"},
+		quotes:        "\"'`",
+		functions:     jsFunctionPatterns,
+		braceless:     true,
+		structures:    wordSet("if for while switch catch do"),
+		continuations: wordSet("else"),
+		decisions:     wordSet("if for while case catch"),
+		logical:       wordSet("&& || ??"),
+		ternary:       true,
+		keywords:      wordSet("async await break case catch class const continue default delete do else export extends finally for function if import in instanceof let new of return static super switch this throw try typeof var void while yield"),
+	},
+	"java": {
+		lineComments:  []string{"//"},
+		blockComment:  [2]string{"/*", "*/"},
+		quotes:        "\"",
+		charLiterals:  true,
+		functions:     cFunctionPatterns,
+		braceless:     true,
+		structures:    wordSet("if for while switch catch do"),
+		continuations: wordSet("else"),
+		decisions:     wordSet("if for while case catch"),
+		logical:       wordSet("&& ||"),
+		ternary:       true,
+		keywords:      wordSet("abstract break case catch class continue default do else extends final finally for if implements import instanceof interface new package private protected public return static super switch synchronized this throw throws try void while"),
+	},
+	"c": {
+		lineComments:  []string{"//"},
+		blockComment:  [2]string{"/*", "*/"},
+		quotes:        "\"",
+		charLiterals:  true,
+		functions:     cFunctionPatterns,
+		braceless:     true,
+		structures:    wordSet("if for while switch do"),
+		continuations: wordSet("else"),
+		decisions:     wordSet("if for while case"),
+		logical:       wordSet("&& ||"),
+		ternary:       true,
+		keywords:      wordSet("break case const continue default do else enum extern for goto if return sizeof static struct switch typedef union void volatile while"),
+	},
+	"rust": {
+		lineComments:  []string{"//"},
+		blockComment:  [2]string{"/*", "*/"},
+		quotes:        "\"",
+		charLiterals:  true,
+		functions:     rustFunctionPatterns,
+		structures:    wordSet("if for while loop match"),
+		continuations: wordSet("else"),
+		decisions:     wordSet("if for while =>"),
+		logical:       wordSet("&& ||"),
+		keywords:      wordSet("as break const continue crate else enum fn for if impl in let loop match mod move mut pub ref return self static struct trait type unsafe use where while"),
+	},
+	"python": {
+		lineComments:  []string{"#"},
+		quotes:        "\"'",
+		tripleQuotes:  true,
+		indented:      true,
+		structures:    wordSet("if for while except match"),
+		continuations: wordSet("elif else"),
+		decisions:     wordSet("if elif for while except case"),
+		logical:       wordSet("and or"),
+		keywords:      wordSet("and as assert async await break class continue def del elif else except finally for from global if import in is lambda nonlocal not or pass raise return try while with yield"),
+	},
+	"shell":      {lineComments: []string{"#"}, quotes: "\"'"},
+	"dockerfile": {lineComments: []string{"#"}, quotes: "\"'"},
+	"kubernetes": {lineComments: []string{"#"}, quotes: "\"'"},
+	"terraform":  {lineComments: []string{"#", "//"}, blockComment: [2]string{"/*", "*/"}, quotes: "\""},
+	"sql":        {lineComments: []string{"--"}, blockComment: [2]string{"/*", "*/"}, quotes: "'\""},
+}
+
+func init() {
+	// The C family and TypeScript share the syntax of their relatives
+	metricsSyntaxes["typescript"] = metricsSyntaxes["javascript"]
+	
+	cpp := *metricsSyntaxes["c"]
+	cpp.structures = wordSet("if for while switch catch do")
+	cpp.decisions = wordSet("if for while case catch")
+	metricsSyntaxes["cpp"] = &cpp
+}
+
+// metricThresholds are the per-function limits reported as issues, each configurable with its
+// option; a limit of 0 turns the check off
+var metricThresholds = []struct {
+	ruleID       string
+	option       string
+	defaultLimit int
+	// minimum is set when values below the limit are reported rather than values above it
+	minimum bool
+	value   func(FunctionMetrics) float64
+	message string
+}{
+	{
+		ruleID:       "cyclomatic-complexity",
+		option:       "maxCyclomaticComplexity",
+		defaultLimit: 10,
+		value:        func(f FunctionMetrics) float64 { return float64(f.CyclomaticComplexity) },
+		message:      "Function %s has a cyclomatic complexity of %s (limit %d); split it into smaller functions or simplify its conditions",
+	},
+	{
+		ruleID:       "cognitive-complexity",
+		option:       "maxCognitiveComplexity",
+		defaultLimit: 15,
+		value:        func(f FunctionMetrics) float64 { return float64(f.CognitiveComplexity) },
+		message:      "Function %s has a cognitive complexity of %s (limit %d); flatten it with early returns or extract the nested logic",
+	},
+	{
+		ruleID:       "function-length",
+		option:       "maxFunctionLines",
+		defaultLimit: 60,
+		value:        func(f FunctionMetrics) float64 { return float64(f.LinesOfCode) },
+		message:      "Function %s has %s lines of code (limit %d); extract parts of it into separate functions",
+	},
+	{
+		ruleID:       "too-many-parameters",
+		option:       "maxParameters",
+		defaultLimit: 5,
+		value:        func(f FunctionMetrics) float64 { return float64(f.Parameters) },
+		message:      "Function %s takes %s parameters (limit %d); group related parameters into a struct or options object",
+	},
+	{
+		ruleID:       "deep-nesting",
+		option:       "maxNestingDepth",
+		defaultLimit: 4,
+		value:        func(f FunctionMetrics) float64 { return float64(f.NestingDepth) },
+		message:      "Function %s nests control structures %s levels deep (limit %d); use early returns or extract the inner blocks",
+	},
+	{
+		ruleID:       "maintainability-index",
+		option:       "minMaintainabilityIndex",
+		defaultLimit: 20,
+		minimum:      true,
+		value:        func(f FunctionMetrics) float64 { return f.MaintainabilityIndex },
+		message:      "Function %s has a maintainability index of %s (minimum %d), so it is too long, complex or dense to change safely; split it up",
+	},
+}
+
+// MetricsCalculator computes size and complexity metrics. It runs for every language: Go is
+// parsed with go/ast, other languages with lightweight parsers, and languages without a
+// parser only get line counts.
+type MetricsCalculator struct {
+	*BaseAnalyzer
+}
+
+// NewMetricsCalculator creates a new metrics calculator
+func NewMetricsCalculator(config map[string]string) *MetricsCalculator {
+	return &MetricsCalculator{
+		BaseAnalyzer: NewBaseAnalyzer(config),
+	}
+}
+
+// Measure computes the metrics of the code and returns an issue for every threshold a function
+// exceeds. The metrics are nil when the metrics option is turned off.
+func (m *MetricsCalculator) Measure(language, code string, options map[string]interface{}) (*CodeMetrics, []Issue) {
+	issues := make([]Issue, 0)
+	if !m.GetBoolOption(options, "metrics", true) {
+		return nil, issues
+	}
+	
+	syntax, ok := metricsSyntaxes[language]
+	if !ok {
+		syntax = &metricsSyntax{}
+	}
+	masked, lines := maskSource(code, syntax)
+	
+	metrics := &CodeMetrics{Functions: make([]FunctionMetrics, 0)}
+	metrics.File.Lines = len(lines)
+	for _, kind := range lines {
+		switch kind {
+		case codeLine:
+			metrics.File.LinesOfCode++
+		case commentLine:
+			metrics.File.CommentLines++
+		default:
+			metrics.File.BlankLines++
+		}
+	}
+	
+	// Go is parsed properly when it can be; snippets that do not parse get the lightweight parser
+	parsed := false
+	var volume float64
+	if language == "go" {
+		metrics.Functions, volume, parsed = goFunctionMetrics(code, lines)
+	}
+	if !parsed && (len(syntax.functions) > 0 || syntax.indented) {
+		metrics.Functions = parseFunctionMetrics(code, masked, lines, syntax)
+		volume = tokenVolume(metricsTokenPattern.FindAllString(masked, -1), syntax)
+		parsed = true
+	}
+	if !parsed {
+		return metrics, issues
+	}
+	
+	for _, function := range metrics.Functions {
+		metrics.File.CyclomaticComplexity += function.CyclomaticComplexity
+		metrics.File.CognitiveComplexity += function.CognitiveComplexity
+		if function.NestingDepth > metrics.File.MaxNestingDepth {
+			metrics.File.MaxNestingDepth = function.NestingDepth
+		}
+	}
+	metrics.File.Functions = len(metrics.Functions)
+	metrics.File.MaintainabilityIndex = maintainabilityIndex(volume, metrics.File.CyclomaticComplexity, metrics.File.LinesOfCode)
+	
+	return metrics, m.thresholdIssues(metrics.Functions, options)
+}
+
+// thresholdIssues reports the functions whose metrics are over their limits
+func (m *MetricsCalculator) thresholdIssues(functions []FunctionMetrics, options map[string]interface{}) []Issue {
+	issues := make([]Issue, 0)
+	disabled := make(map[string]bool)
+	for _, rule := range m.GetListOption(options, "disabledRules", nil) {
+		disabled[rule] = true
+	}
+	
+	for _, threshold := range metricThresholds {
+		limit := m.GetIntOption(options, threshold.option, threshold.defaultLimit)
+		if limit <= 0 || disabled[threshold.ruleID] {
+			continue
+		}
+		for _, function := range functions {
+			value := threshold.value(function)
+			if (threshold.minimum && value >= float64(limit)) || (!threshold.minimum && value <= float64(limit)) {
+				continue
+			}
+			issues = append(issues, Issue{
+				Line:     function.Line,
+				Message:  fmt.Sprintf(threshold.message, function.Name, strconv.FormatFloat(value, 'f', -1, 64), limit),
+				Severity: "warning",
+				RuleID:   threshold.ruleID,
+				Category: CategoryMaintainability,
+			})
+		}
+	}
+	return issues
+}
+
+// maintainabilityIndex computes the maintainability index on the 0-100 scale used by Visual
+// Studio, from the Halstead volume, the cyclomatic complexity and the lines of code
+func maintainabilityIndex(volume float64, cyclomatic, linesOfCode int) float64 {
+	index := 171 - 5.2*math.Log(math.Max(volume, 1)) - 0.23*float64(cyclomatic) - 16.2*math.Log(math.Max(float64(linesOfCode), 1))
+	index = math.Max(0, math.Min(100, index*100/171))
+	return math.Round(index*10) / 10
+}
+
+// halstead counts the distinct and total operators and operands of a piece of code
+type halstead struct {
+	operators map[string]int
+	operands  map[string]int
+}
+
+// newHalstead creates empty Halstead counts
+func newHalstead() *halstead {
+	return &halstead{operators: make(map[string]int), operands: make(map[string]int)}
+}
+
+// volume returns the Halstead volume, the program length times the log of the vocabulary
+func (h *halstead) volume() float64 {
+	vocabulary := len(h.operators) + len(h.operands)
+	if vocabulary < 2 {
+		return 0
+	}
+	length := 0
+	for _, count := range h.operators {
+		length += count
+	}
+	for _, count := range h.operands {
+		length += count
+	}
+	return float64(length) * math.Log2(float64(vocabulary))
+}
+
+// tokenVolume returns the Halstead volume of tokens from metricsTokenPattern. Names that are not
+// keywords, numbers and strings are operands and everything else is an operator.
+func tokenVolume(tokens []string, syntax *metricsSyntax) float64 {
+	h := newHalstead()
+	for _, token := range tokens {
+		c := token[0]
+		switch {
+		case c >= '0' && c <= '9', strings.IndexByte("\"'`", c) >= 0 && len(token) > 1:
+			h.operands[token]++
+		case (c == '_' || c == '$' || c >= 'A' && c <= 'Z' || c >= 'a' && c <= 'z') && !syntax.keywords[token]:
+			h.operands[token]++
+		default:
+			h.operators[token]++
+		}
+	}
+	return h.volume()
+}
+
+// maskSource blanks out comments and the contents of strings, keeping newlines, so that the
+// parsers only see code. It also classifies each line as code, comment or blank; lines that only
+// hold part of a multi-line string, such as a docstring, count as comments.
+func maskSource(code string, syntax *metricsSyntax) (string, []lineKind) {
+	masked := []byte(code)
+	blank := func(from, to int) {
+		for k := from; k < to && k < len(masked); k++ {
+			if masked[k] != '\n' {
+				masked[k] = ' '
+			}
+		}
+	}
+	
+	for i := 0; i < len(code); {
+		rest := code[i:]
+		
+		comment := false
+		for _, prefix := range syntax.lineComments {
+			if strings.HasPrefix(rest, prefix) {
+				comment = true
+				break
+			}
+		}
+		if comment {
+			end := strings.IndexByte(rest, '\n')
+			if end < 0 {
+				end = len(rest)
+			}
+			blank(i, i+end)
+			i += end
+			continue
+		}
+		
+		if open, close := syntax.blockComment[0], syntax.blockComment[1]; open != "" && strings.HasPrefix(rest, open) {
+			end := len(rest)
+			if k := strings.Index(rest[len(open):], close); k >= 0 {
+				end = len(open) + k + len(close)
+			}
+			blank(i, i+end)
+			i += end
+			continue
+		}
+		
+		if syntax.tripleQuotes && (strings.HasPrefix(rest, `"""`) || strings.HasPrefix(rest, `'''`)) {
+			end := len(rest)
+			if k := strings.Index(rest[3:], rest[:3]); k >= 0 {
+				end = k + 6
+			}
+			blank(i+1, i+end-1)
+			i += end
+			continue
+		}
+		
+		c := code[i]
+		if c == '\'' && syntax.charLiterals {
+			if literal := charLiteralPattern.FindString(rest); literal != "" {
+				blank(i+1, i+len(literal)-1)
+				i += len(literal)
+				continue
+			}
+		} else if strings.IndexByte(syntax.quotes, c) >= 0 {
+			end, closed := stringEnd(rest, c)
+			if closed {
+				blank(i+1, i+end-1)
+			} else {
+				blank(i+1, i+end)
+			}
+			i += end
+			continue
+		}
+		i++
+	}
+	
+	codeLines := strings.Split(code, "\n")
+	maskedLines := strings.Split(string(masked), "\n")
+	kinds := make([]lineKind, len(codeLines))
+	for i := range codeLines {
+		switch {
+		case strings.TrimSpace(maskedLines[i]) != "":
+			kinds[i] = codeLine
+		case strings.TrimSpace(codeLines[i]) != "":
+			kinds[i] = commentLine
+		default:
+			kinds[i] = blankLine
+		}
+	}
+	// A trailing newline does not start another line
+	if len(kinds) > 1 && codeLines[len(codeLines)-1] == "" {
+		kinds = kinds[:len(kinds)-1]
+	}
+	
+	return string(masked), kinds
+}
+
+// stringEnd returns the offset just past the string literal at the start of text, and whether it
+// was closed. Only backquoted strings span lines.
+func stringEnd(text string, quote byte) (int, bool) {
+	for k := 1; k < len(text); k++ {
+		switch {
+		case text[k] == '\\' && quote != '`':
+			k++
+		case text[k] == quote:
+			return k + 1, true
+		case text[k] == '\n' && quote != '`':
+			return k, false
+		}
+	}
+	return len(text), false
+}
+
+// countLinesOfCode counts the code lines in the 1-based range [from, to]
+func countLinesOfCode(lines []lineKind, from, to int) int {
+	count := 0
+	for line := from; line <= to && line <= len(lines); line++ {
+		if line >= 1 && lines[line-1] == codeLine {
+			count++
+		}
+	}
+	return count
+}
+
+// countParameters counts the comma-separated parameters of a parameter list, ignoring the
+// receiver-like parameters that callers do not pass
+func countParameters(params string) int {
+	count := 0
+	depth := 0
+	start := 0
+	add := func(param string) {
+		param = strings.TrimSpace(param)
+		switch param {
+		case "", "void", "self", "&self", "&mut self", "mut self", "cls", "*", "/":
+			return
+		}
+		count++
+	}
+	for k := 0; k < len(params); k++ {
+		switch params[k] {
+		case '(', '[', '{', '<':
+			depth++
+		case ')', ']', '}', '>':
+			depth--
+		case ',':
+			if depth == 0 {
+				add(params[start:k])
+				start = k + 1
+			}
+		}
+	}
+	add(params[start:])
+	return count
+}
+
+// parseFunctionMetrics finds the functions with the lightweight parser for the syntax
+func parseFunctionMetrics(code, masked string, lines []lineKind, syntax *metricsSyntax) []FunctionMetrics {
+	if syntax.indented {
+		return indentedFunctionMetrics(code, masked, lines, syntax)
+	}
+	
+	functions := make([]FunctionMetrics, 0)
+	seen := make(map[int]bool)
+	for _, pattern := range syntax.functions {
+		for _, match := range pattern.FindAllStringSubmatchIndex(masked, -1) {
+			name := "<anonymous>"
+			if match[2] >= 0 {
+				name = masked[match[2]:match[3]]
+			}
+			bodyStart := match[1] - 1
+			if seen[bodyStart] || metricsNotFunctions[name] || strings.HasSuffix(strings.TrimSpace(masked[:match[0]]), "new") {
+				continue
+			}
+			bodyEnd := matchingBrace(masked, bodyStart)
+			if bodyEnd < 0 {
+				continue
+			}
+			seen[bodyStart] = true
+			
+			line, _ := offsetToPosition(code, match[0])
+			endLine, _ := offsetToPosition(code, bodyEnd)
+			
+			tokens := metricsTokenPattern.FindAllString(masked[bodyStart:bodyEnd+1], -1)
+			cyclomatic, cognitive, nesting := braceComplexity(tokens, syntax)
+			loc := countLinesOfCode(lines, line, endLine)
+			volume := tokenVolume(metricsTokenPattern.FindAllString(masked[match[0]:bodyEnd+1], -1), syntax)
+			functions = append(functions, FunctionMetrics{
+				Name:                 name,
+				Line:                 line,
+				EndLine:              endLine,
+				LinesOfCode:          loc,
+				Parameters:           countParameters(masked[match[4]:match[5]]),
+				CyclomaticComplexity: cyclomatic,
+				CognitiveComplexity:  cognitive,
+				NestingDepth:         nesting,
+				MaintainabilityIndex: maintainabilityIndex(volume, cyclomatic, loc),
+			})
+		}
+	}
+	
+	sort.SliceStable(functions, func(i, j int) bool {
+		return functions[i].Line < functions[j].Line
+	})
+	return functions
+}
+
+// matchingBrace returns the offset of the brace closing the one at open, or -1
+func matchingBrace(masked string, open int) int {
+	depth := 0
+	for k := open; k < len(masked); k++ {
+		switch masked[k] {
+		case '{':
+			depth++
+		case '}':
+			depth--
+			if depth == 0 {
+				return k
+			}
+		}
+	}
+	return -1
+}
+
+// braceComplexity computes the cyclomatic and cognitive complexity and the nesting depth of a
+// function body in a language with braces
+func braceComplexity(tokens []string, syntax *metricsSyntax) (int, int, int) {
+	const (
+		plainBrace = iota
+		controlBrace
+		doBrace
+	)
+	
+	cyclomatic, cognitive, nesting := 1, 0, 0
+	braces := make([]int, 0)
+	depth := 0
+	pending := plainBrace
+	parens := 0
+	lastLogical := ""
+	closedDo := false
+	
+	for i, token := range tokens {
+		prev, next := "", ""
+		if i > 0 {
+			prev = tokens[i-1]
+		}
+		if i+1 < len(tokens) {
+			next = tokens[i+1]
+		}
+		if syntax.decisions[token] {
+			cyclomatic++
+		}
+		
+		switch {
+		case token == "(" || token == "[":
+			parens++
+			lastLogical = ""
+		case token == ")" || token == "]":
+			parens--
+			lastLogical = ""
+		case token == "{":
+			// Braces inside a condition belong to literals, not to the body
+			kind := plainBrace
+			if parens <= 0 {
+				kind, pending = pending, plainBrace
+			}
+			braces = append(braces, kind)
+			if kind != plainBrace {
+				depth++
+				if depth > nesting {
+					nesting = depth
+				}
+			}
+			lastLogical = ""
+		case token == "}":
+			closedDo = false
+			if len(braces) > 0 {
+				kind := braces[len(braces)-1]
+				braces = braces[:len(braces)-1]
+				if kind != plainBrace {
+					depth--
+				}
+				closedDo = kind == doBrace
+			}
+			lastLogical = ""
+		case token == ";" || token == ",":
+			if token == ";" && parens <= 0 && syntax.braceless {
+				pending = plainBrace
+			}
+			lastLogical = ""
+		case syntax.continuations[token]:
+			if next != "if" {
+				cognitive++
+				pending = controlBrace
+			}
+		case syntax.structures[token]:
+			switch {
+			case token == "while" && prev == "}" && closedDo:
+				// The condition of a do-while loop, already counted by the do
+			case token == "if" && syntax.continuations[prev]:
+				cognitive++
+				pending = controlBrace
+			case token == "do":
+				cognitive += 1 + depth
+				pending = doBrace
+			default:
+				cognitive += 1 + depth
+				pending = controlBrace
+			}
+		case syntax.logical[token]:
+			cyclomatic++
+			// A sequence of the same operator only counts once
+			if token != lastLogical {
+				cognitive++
+			}
+			lastLogical = token
+		case token == "?" && syntax.ternary:
+			cyclomatic++
+			cognitive += 1 + depth
+		}
+	}
+	
+	return cyclomatic, cognitive, nesting
+}
+
+// indentedFunctionMetrics finds the functions of a language whose blocks are delimited by
+// indentation. A function's body is every following line indented deeper than its header.
+func indentedFunctionMetrics(code, masked string, lines []lineKind, syntax *metricsSyntax) []FunctionMetrics {
+	functions := make([]FunctionMetrics, 0)
+	maskedLines := strings.Split(masked, "\n")
+	
+	for _, match := range pythonFunctionPattern.FindAllStringSubmatchIndex(masked, -1) {
+		indent := match[3] - match[2]
+		name := masked[match[4]:match[5]]
+		open := match[1] - 1
+		
+		// The parameter list may span lines
+		close := -1
+		depth := 0
+		for k := open; k < len(masked) && close < 0; k++ {
+			switch masked[k] {
+			case '(', '[', '{':
+				depth++
+			case ')', ']', '}':
+				depth--
+				if depth == 0 {
+					close = k
+				}
+			}
+		}
+		if close < 0 {
+			continue
+		}
+		
+		line, _ := offsetToPosition(code, match[2])
+		headerEnd, _ := offsetToPosition(code, close)
+		endLine := headerEnd
+		for k := headerEnd; k < len(maskedLines); k++ {
+			text := maskedLines[k]
+			if strings.TrimSpace(text) == "" {
+				continue
+			}
+			if len(text)-len(strings.TrimLeft(text, " \t")) <= indent {
+				break
+			}
+			endLine = k + 1
+		}
+		
+		body := maskedLines[headerEnd:endLine]
+		cyclomatic, cognitive, nesting := indentedComplexity(body, syntax)
+		loc := countLinesOfCode(lines, line, endLine)
+		volume := tokenVolume(metricsTokenPattern.FindAllString(strings.Join(maskedLines[line-1:endLine], "\n"), -1), syntax)
+		functions = append(functions, FunctionMetrics{
+			Name:                 name,
+			Line:                 line,
+			EndLine:              endLine,
+			LinesOfCode:          loc,
+			Parameters:           countParameters(masked[open+1 : close]),
+			CyclomaticComplexity: cyclomatic,
+			CognitiveComplexity:  cognitive,
+			NestingDepth:         nesting,
+			MaintainabilityIndex: maintainabilityIndex(volume, cyclomatic, loc),
+		})
+	}
+	
+	return functions
+}
+
+// indentedComplexity computes the cyclomatic and cognitive complexity and the nesting depth of
+// the masked lines of a function body in a language delimited by indentation
+func indentedComplexity(body []string, syntax *metricsSyntax) (int, int, int) {
+	cyclomatic, cognitive, nesting := 1, 0, 0
+	// blocks holds the indentation of the enclosing nesting structures
+	blocks := make([]int, 0)
+	
+	for _, text := range body {
+		tokens := metricsTokenPattern.FindAllString(text, -1)
+		if len(tokens) == 0 {
+			continue
+		}
+		indent := len(text) - len(strings.TrimLeft(text, " \t"))
+		for len(blocks) > 0 && blocks[len(blocks)-1] >= indent {
+			blocks = blocks[:len(blocks)-1]
+		}
+		
+		first := 0
+		if tokens[0] == "async" && len(tokens) > 1 {
+			first = 1
+		}
+		keyword := tokens[first]
+		switch {
+		case syntax.structures[keyword]:
+			cognitive += 1 + len(blocks)
+			blocks = append(blocks, indent)
+		case syntax.continuations[keyword]:
+			cognitive++
+			blocks = append(blocks, indent)
+		case keyword == "def" || keyword == "class":
+			// Nested functions nest their bodies without adding to the complexity
+			blocks = append(blocks, indent)
+		}
+		if len(blocks) > nesting {
+			nesting = len(blocks)
+		}
+		
+		lastLogical := ""
+		for i, token := range tokens {
+			if syntax.decisions[token] {
+				cyclomatic++
+			}
+			switch {
+			case syntax.logical[token]:
+				if token != lastLogical {
+					cognitive++
+				}
+				lastLogical = token
+			case token == "if" && i != first:
+				// Conditional expressions and comprehension filters
+				cognitive++
+			case token == "(" || token == ")" || token == "," || token == ":" || token == "[" || token == "]":
+				lastLogical = ""
+			}
+		}
+	}
+	
+	return cyclomatic, cognitive, nesting
+}
@@ -0,0 +1,355 @@
+package analyzer
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestMetricsMeasureFunctions(t *testing.T) {
+	tests := []struct {
+		name     string
+		language string
+		code     string
+		want     []FunctionMetrics
+	}{
+		{
+			name:     "go function",
+			language: "go",
+			code:     "package main\n\n// add returns the sum\nfunc add(a, b int) int {\n\treturn a + b\n}\n",
+			want: []FunctionMetrics{
+				{Name: "add", Line: 4, EndLine: 6, LinesOfCode: 3, Parameters: 2, CyclomaticComplexity: 1},
+			},
+		},
+		{
+			name:     "go branches and loops",
+			language: "go",
+			code: `package main
+
+func classify(n int, ok bool) string {
+	if n > 0 && ok {
+		for i := 0; i < n; i++ {
+			if i%2 == 0 {
+				continue
+			}
+		}
+		return "positive"
+	} else if n < 0 {
+		return "negative"
+	} else {
+		return "zero"
+	}
+}
+`,
+			want: []FunctionMetrics{
+				{Name: "classify", Line: 3, EndLine: 16, LinesOfCode: 14, Parameters: 2, CyclomaticComplexity: 6, CognitiveComplexity: 9, NestingDepth: 3},
+			},
+		},
+		{
+			name:     "go methods are named after their receiver",
+			language: "go",
+			code:     "package main\n\ntype Server[T any] struct{}\n\nfunc (s *Server[T]) Run(T) {}\n",
+			want: []FunctionMetrics{
+				{Name: "Server.Run", Line: 5, EndLine: 5, LinesOfCode: 1, Parameters: 1, CyclomaticComplexity: 1},
+			},
+		},
+		{
+			name:     "go that does not parse falls back to the lightweight parser",
+			language: "go",
+			code:     "func f(a int) {\n\tif a > 0 {\n\t\treturn\n\t}\n}\n",
+			want: []FunctionMetrics{
+				{Name: "f", Line: 1, EndLine: 5, LinesOfCode: 5, Parameters: 1, CyclomaticComplexity: 2, CognitiveComplexity: 1, NestingDepth: 1},
+			},
+		},
+		{
+			name:     "javascript else if, logical operators and ternaries",
+			language: "javascript",
+			code: `function check(a, b) {
+  if (a && b) {
+    return 1;
+  } else if (a) {
+    return 2;
+  }
+  return a ? 3 : 4;
+}
+`,
+			want: []FunctionMetrics{
+				{Name: "check", Line: 1, EndLine: 8, LinesOfCode: 8, Parameters: 2, CyclomaticComplexity: 5, CognitiveComplexity: 4, NestingDepth: 1},
+			},
+		},
+		{
+			name:     "typescript arrow function",
+			language: "typescript",
+			code:     "const sum = (xs: number[]): number => {\n  let s = 0;\n  for (const x of xs) s += x;\n  return s;\n};\n",
+			want: []FunctionMetrics{
+				{Name: "sum", Line: 1, EndLine: 5, LinesOfCode: 5, Parameters: 1, CyclomaticComplexity: 2, CognitiveComplexity: 1},
+			},
+		},
+		{
+			name:     "rust character literals do not unbalance braces",
+			language: "rust",
+			code:     "fn is_open<'a>(s: &'a str, c: char) -> bool {\n    c == '{'\n}\n\nfn other() {}\n",
+			want: []FunctionMetrics{
+				{Name: "is_open", Line: 1, EndLine: 3, LinesOfCode: 3, Parameters: 2, CyclomaticComplexity: 1},
+				{Name: "other", Line: 5, EndLine: 5, LinesOfCode: 1, CyclomaticComplexity: 1},
+			},
+		},
+		{
+			name:     "python indentation",
+			language: "python",
+			code: `def process(self, items, limit=10):
+    """Sum the items.
+
+    Items over the limit are skipped.
+    """
+    total = 0
+    for item in items:
+        if item > limit and item % 2:
+            total += item
+        elif item < 0:
+            pass
+    return total
+
+x = 1
+`,
+			want: []FunctionMetrics{
+				{Name: "process", Line: 1, EndLine: 12, LinesOfCode: 10, Parameters: 2, CyclomaticComplexity: 4, CognitiveComplexity: 5, NestingDepth: 2},
+			},
+		},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			metrics, _ := NewMetricsCalculator(nil).Measure(tt.language, tt.code, nil)
+			if metrics == nil {
+				t.Fatal("got no metrics")
+			}
+			if len(metrics.Functions) != len(tt.want) {
+				t.Fatalf("got %d functions, want %d: %+v", len(metrics.Functions), len(tt.want), metrics.Functions)
+			}
+			for i, got := range metrics.Functions {
+				if got.MaintainabilityIndex <= 0 || got.MaintainabilityIndex > 100 {
+					t.Errorf("%s: maintainability index %v is out of range", got.Name, got.MaintainabilityIndex)
+				}
+				got.MaintainabilityIndex = 0
+				if got != tt.want[i] {
+					t.Errorf("got %+v, want %+v", got, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestMetricsMeasureFile(t *testing.T) {
+	tests := []struct {
+		name            string
+		language        string
+		code            string
+		want            FileMetrics
+		wantMaintenance bool
+	}{
+		{
+			name:            "go file sums its functions",
+			language:        "go",
+			code:            "package main\n\n/* helpers\n   below */\nfunc a(x bool) {\n\tif x {\n\t}\n}\n\nfunc b() {\n\tfor {\n\t\tif true {\n\t\t}\n\t}\n}\n",
+			want:            FileMetrics{Lines: 15, LinesOfCode: 11, CommentLines: 2, BlankLines: 2, Functions: 2, CyclomaticComplexity: 5, CognitiveComplexity: 4, MaxNestingDepth: 2},
+			wantMaintenance: true,
+		},
+		{
+			name:     "shell only gets line counts",
+			language: "shell",
+			code:     "#!/bin/sh\n# say hello\n\necho \"# not a comment\"\n",
+			want:     FileMetrics{Lines: 4, LinesOfCode: 1, CommentLines: 2, BlankLines: 1},
+		},
+		{
+			name:     "unknown language counts every non-blank line as code",
+			language: "cobol",
+			code:     "a\n\n# b\n",
+			want:     FileMetrics{Lines: 3, LinesOfCode: 2, BlankLines: 1},
+		},
+		{
+			name:     "sql comments",
+			language: "sql",
+			code:     "-- users\nSELECT '--' FROM t; /* trailing */\n/*\n*/\n",
+			want:     FileMetrics{Lines: 4, LinesOfCode: 1, CommentLines: 3},
+		},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			metrics, issues := NewMetricsCalculator(nil).Measure(tt.language, tt.code, nil)
+			if metrics == nil {
+				t.Fatal("got no metrics")
+			}
+			got := metrics.File
+			if (got.MaintainabilityIndex > 0) != tt.wantMaintenance {
+				t.Errorf("got maintainability index %v, want one: %v", got.MaintainabilityIndex, tt.wantMaintenance)
+			}
+			got.MaintainabilityIndex = 0
+			if got != tt.want {
+				t.Errorf("got %+v, want %+v", got, tt.want)
+			}
+			if len(issues) != 0 {
+				t.Errorf("got issues %+v, want none", issues)
+			}
+		})
+	}
+}
+
+func TestMetricsThresholds(t *testing.T) {
+	classify := `package main
+
+func classify(n int, ok bool) string {
+	if n > 0 && ok {
+		for i := 0; i < n; i++ {
+			if i%2 == 0 {
+				continue
+			}
+		}
+		return "positive"
+	} else if n < 0 {
+		return "negative"
+	} else {
+		return "zero"
+	}
+}
+`
+	many := "package main\n\nfunc many(a, b, c, d, e, f int) {}\n"
+	
+	tests := []struct {
+		name        string
+		code        string
+		options     map[string]interface{}
+		want        []string
+		wantMessage string
+	}{
+		{
+			name: "within the defaults",
+			code: classify,
+			want: []string{},
+		},
+		{
+			name:        "too many parameters",
+			code:        many,
+			want:        []string{"too-many-parameters"},
+			wantMessage: "Function many takes 6 parameters (limit 5)",
+		},
+		{
+			name:    "limit of zero turns the check off",
+			code:    many,
+			options: map[string]interface{}{"maxParameters": 0},
+			want:    []string{},
+		},
+		{
+			name:    "disabled rule",
+			code:    many,
+			options: map[string]interface{}{"disabledRules": "too-many-parameters"},
+			want:    []string{},
+		},
+		{
+			name:        "lowered limits from json",
+			code:        classify,
+			options:     map[string]interface{}{"maxCyclomaticComplexity": float64(5), "maxCognitiveComplexity": "8", "maxNestingDepth": 2},
+			want:        []string{"cyclomatic-complexity", "cognitive-complexity", "deep-nesting"},
+			wantMessage: "Function classify has a cyclomatic complexity of 6 (limit 5)",
+		},
+		{
+			name:        "long function",
+			code:        classify,
+			options:     map[string]interface{}{"maxFunctionLines": 13},
+			want:        []string{"function-length"},
+			wantMessage: "Function classify has 14 lines of code (limit 13)",
+		},
+		{
+			name:        "maintainability minimum",
+			code:        classify,
+			options:     map[string]interface{}{"minMaintainabilityIndex": 100},
+			want:        []string{"maintainability-index"},
+			wantMessage: "(minimum 100)",
+		},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, issues := NewMetricsCalculator(nil).Measure("go", tt.code, tt.options)
+			got := make([]string, 0, len(issues))
+			for _, issue := range issues {
+				got = append(got, issue.RuleID)
+				if issue.Category != CategoryMaintainability || issue.Severity != "warning" || issue.Line != 3 {
+					t.Errorf("unexpected issue %+v", issue)
+				}
+			}
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Fatalf("got rules %v, want %v", got, tt.want)
+			}
+			if tt.wantMessage != "" && !strings.Contains(issues[0].Message, tt.wantMessage) {
+				t.Errorf("got message %q, want it to contain %q", issues[0].Message, tt.wantMessage)
+			}
+		})
+	}
+}
+
+func TestMetricsDisabled(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  map[string]string
+		options map[string]interface{}
+	}{
+		{name: "option", options: map[string]interface{}{"metrics": false}},
+		{name: "configuration", config: map[string]string{"metrics": "false"}},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			metrics, issues := NewMetricsCalculator(tt.config).Measure("go", "package main\n\nfunc f(a, b, c, d, e, f int) {}\n", tt.options)
+			if metrics != nil || len(issues) != 0 {
+				t.Errorf("got %+v and %+v, want nothing", metrics, issues)
+			}
+		})
+	}
+}
+
+func TestCountParameters(t *testing.T) {
+	tests := []struct {
+		params string
+		want   int
+	}{
+		{"", 0},
+		{"void", 0},
+		{"a, b", 2},
+		{"self, x, y=1", 2},
+		{"&mut self, v: Vec<(u8, u8)>", 1},
+		{"m map[string]int, f func(a, b int) error", 2},
+		{"a, /, b, *, c", 3},
+		{"{ a, b }: Props", 1},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.params, func(t *testing.T) {
+			if got := countParameters(tt.params); got != tt.want {
+				t.Errorf("countParameters(%q) = %d, want %d", tt.params, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMaintainabilityIndex(t *testing.T) {
+	tests := []struct {
+		name        string
+		volume      float64
+		cyclomatic  int
+		linesOfCode int
+		want        float64
+	}{
+		{name: "trivial", volume: 0, cyclomatic: 1, linesOfCode: 0, want: 99.9},
+		{name: "huge", volume: 1e6, cyclomatic: 50, linesOfCode: 1000, want: 0},
+		{name: "typical", volume: 500, cyclomatic: 5, linesOfCode: 30, want: 48.2},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := maintainabilityIndex(tt.volume, tt.cyclomatic, tt.linesOfCode); got != tt.want {
+				t.Errorf("maintainabilityIndex(%v, %d, %d) = %v, want %v", tt.volume, tt.cyclomatic, tt.linesOfCode, got, tt.want)
+			}
+		})
+	}
+}
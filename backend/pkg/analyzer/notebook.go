@@ -0,0 +1,496 @@
+package analyzer
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// pythonCellMagics are the cell magics whose body is Python, so that only their first line is
+// ignored; the cells of any other cell magic, such as %%bash, are skipped altogether
+var pythonCellMagics = map[string]bool{
+	"time":    true,
+	"timeit":  true,
+	"capture": true,
+	"prun":    true,
+	"debug":   true,
+}
+
+// ipythonLine matches the magics and shell escapes of a code cell, also when their output is
+// assigned, e.g. files = !ls. Lines continuing an expression with % or != are Python.
+var ipythonLine = regexp.MustCompile(`^\s*([A-Za-z_][A-Za-z0-9_, ]*=\s*)?(%{1,2}[A-Za-z]|!([^=]|$))`)
+
+// notebookCell is a cell of a Jupyter notebook. The offsets locate its source value in the
+// notebook's JSON text, so that fixes can replace it.
+type notebookCell struct {
+	cellType string
+	source   string
+	lines    bool // the source is a list of lines rather than a single string
+	start    int
+	end      int
+}
+
+// notebook is a parsed Jupyter notebook
+type notebook struct {
+	text  string
+	cells []notebookCell
+}
+
+// notebookLine is the origin of a line of the Python source extracted from a notebook
+type notebookLine struct {
+	cell  int // index of the cell, or -1 for the blank lines between cells
+	line  int // 1-based line within the cell
+	start int // byte offset of the line in the extracted source
+	// original is the text of a magic or shell escape, which the extracted source replaces
+	// with a placeholder
+	original *string
+}
+
+// notebookSource is the Python source of a notebook's code cells, one after the other
+type notebookSource struct {
+	code    string
+	origins []notebookLine
+}
+
+// isNotebook reports whether code is a Jupyter notebook, either by the file name given by the
+// "path" option or, without one, by its content
+func isNotebook(code string, path string) bool {
+	if path != "" {
+		return strings.HasSuffix(strings.ToLower(path), ".ipynb")
+	}
+	
+	trimmed := strings.TrimSpace(code)
+	if !strings.HasPrefix(trimmed, "{") {
+		return false
+	}
+	var probe struct {
+		Cells    json.RawMessage `json:"cells"`
+		NBFormat *int            `json:"nbformat"`
+	}
+	return json.Unmarshal([]byte(trimmed), &probe) == nil && probe.Cells != nil && probe.NBFormat != nil
+}
+
+// parseNotebook parses the JSON of a notebook, recording where the source of each cell is
+func parseNotebook(text string) (*notebook, error) {
+	decoder := json.NewDecoder(strings.NewReader(text))
+	if err := expectDelim(decoder, '{'); err != nil {
+		return nil, err
+	}
+	
+	nb := &notebook{text: text}
+	for decoder.More() {
+		key, err := decoder.Token()
+		if err != nil {
+			return nil, err
+		}
+		if key != "cells" {
+			var skipped json.RawMessage
+			if err := decoder.Decode(&skipped); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		
+		if err := expectDelim(decoder, '['); err != nil {
+			return nil, err
+		}
+		for decoder.More() {
+			cell, err := parseNotebookCell(decoder, text)
+			if err != nil {
+				return nil, fmt.Errorf("cell %d: %w", len(nb.cells), err)
+			}
+			nb.cells = append(nb.cells, cell)
+		}
+		if err := expectDelim(decoder, ']'); err != nil {
+			return nil, err
+		}
+	}
+	
+	return nb, nil
+}
+
+// parseNotebookCell parses the next cell object of the decoder
+func parseNotebookCell(decoder *json.Decoder, text string) (notebookCell, error) {
+	var cell notebookCell
+	if err := expectDelim(decoder, '{'); err != nil {
+		return cell, err
+	}
+	
+	for decoder.More() {
+		key, err := decoder.Token()
+		if err != nil {
+			return cell, err
+		}
+		
+		// The value starts after the colon that follows the key
+		start := int(decoder.InputOffset())
+		for start < len(text) && strings.IndexByte(" \t\r\n:", text[start]) >= 0 {
+			start++
+		}
+		var value json.RawMessage
+		if err := decoder.Decode(&value); err != nil {
+			return cell, err
+		}
+		
+		switch key {
+		case "cell_type":
+			if err := json.Unmarshal(value, &cell.cellType); err != nil {
+				return cell, fmt.Errorf("invalid cell_type: %w", err)
+			}
+		case "source":
+			cell.start = start
+			cell.end = int(decoder.InputOffset())
+			var lines []string
+			if err := json.Unmarshal(value, &lines); err == nil {
+				cell.source = strings.Join(lines, "")
+				cell.lines = true
+			} else if err := json.Unmarshal(value, &cell.source); err != nil {
+				return cell, fmt.Errorf("invalid source: %w", err)
+			}
+		}
+	}
+	
+	return cell, expectDelim(decoder, '}')
+}
+
+// expectDelim reads the next token of the decoder, which must be the given delimiter
+func expectDelim(decoder *json.Decoder, delim json.Delim) error {
+	token, err := decoder.Token()
+	if err != nil {
+		return err
+	}
+	if token != delim {
+		return fmt.Errorf("expected %s, found %v", delim, token)
+	}
+	return nil
+}
+
+// pythonSource extracts the code cells of the notebook into a single Python source. Cells are
+// separated by two blank lines, magics and shell escapes are replaced by placeholders that keep
+// the lines of the cells in place, and the cells of non-Python cell magics are left out.
+func (nb *notebook) pythonSource() *notebookSource {
+	var code strings.Builder
+	origins := make([]notebookLine, 0)
+	for index, cell := range nb.cells {
+		if cell.cellType != "code" || strings.TrimSpace(cell.source) == "" {
+			continue
+		}
+		
+		lines := strings.Split(cell.source, "\n")
+		first := strings.TrimSpace(lines[0])
+		if strings.HasPrefix(first, "%%") {
+			name := strings.TrimPrefix(first, "%%")
+			if fields := strings.Fields(name); len(fields) > 0 {
+				name = fields[0]
+			}
+			if !pythonCellMagics[name] {
+				continue
+			}
+		}
+		
+		if code.Len() > 0 {
+			for i := 0; i < 2; i++ {
+				origins = append(origins, notebookLine{cell: -1, start: code.Len()})
+				code.WriteString("\n")
+			}
+		}
+		for i, line := range lines {
+			origin := notebookLine{cell: index, line: i + 1, start: code.Len()}
+			if ipythonLine.MatchString(line) {
+				original := line
+				origin.original = &original
+				
+				// An indented placeholder must still be a statement, so that its block is not empty
+				indent := getIndentation(line)
+				line = "# " + strings.TrimSpace(line)
+				if indent != "" {
+					line = indent + "pass"
+				}
+			}
+			origins = append(origins, origin)
+			code.WriteString(line)
+			code.WriteString("\n")
+		}
+	}
+	
+	return &notebookSource{code: code.String(), origins: origins}
+}
+
+// mapIssue moves an issue found in the extracted source to its cell and line within the cell,
+// turning its fix into an edit of the notebook. Issues on the lines between cells or on
+// placeholders are dropped.
+func (nb *notebook) mapIssue(source *notebookSource, issue Issue) (Issue, bool) {
+	if issue.Line <= 0 {
+		issue.Fix = nil
+		return issue, true
+	}
+	if issue.Line > len(source.origins) {
+		return issue, false
+	}
+	origin := source.origins[issue.Line-1]
+	if origin.cell < 0 || origin.original != nil {
+		return issue, false
+	}
+	
+	if issue.Fix != nil {
+		fix, err := nb.mapFix(source, issue.Line, *issue.Fix)
+		if err != nil {
+			fmt.Printf("Warning: dropping fix for %s in cell %d: %v\n", issue.RuleID, origin.cell, err)
+		}
+		issue.Fix = fix
+	}
+	
+	cell := origin.cell
+	issue.Cell = &cell
+	issue.Line = origin.line
+	return issue, true
+}
+
+// mapFix turns a fix of the extracted source into a fix of the notebook JSON that replaces the
+// source of the cell it changes. Fixes spanning several cells or touching magics and shell
+// escapes cannot be mapped.
+func (nb *notebook) mapFix(source *notebookSource, line int, fix IssueFix) (*IssueFix, error) {
+	code := source.code
+	
+	// The byte range of the extracted source the fix replaces
+	var start, end int
+	if fix.Range == nil {
+		start = positionToOffset(code, line, 1)
+		end = start + strings.IndexByte(code[start:], '\n')
+	} else {
+		start = positionToOffset(code, fix.Range.StartLine, fix.Range.StartColumn)
+		end = positionToOffset(code, fix.Range.EndLine, fix.Range.EndColumn)
+	}
+	replacement := fix.Replacement
+	
+	startLine, _ := offsetToPosition(code, start)
+	if startLine > len(source.origins) {
+		return nil, fmt.Errorf("fix starts past the end of the source")
+	}
+	cell := source.origins[startLine-1].cell
+	if cell < 0 {
+		return nil, fmt.Errorf("fix starts between cells")
+	}
+	
+	// The lines of the cell in the extracted source
+	first, last := startLine-1, startLine-1
+	for first > 0 && source.origins[first-1].cell == cell {
+		first--
+	}
+	for last+1 < len(source.origins) && source.origins[last+1].cell == cell {
+		last++
+	}
+	cellStart := source.origins[first].start
+	cellEnd := source.origins[last].start + strings.IndexByte(code[source.origins[last].start:], '\n')
+	
+	// A fix may also replace the line break ending the cell, as long as it puts one back. One
+	// deleting the last lines of the cell instead takes the line break before them.
+	joined := false
+	if end == cellEnd+1 && strings.HasSuffix(replacement, "\n") {
+		end = cellEnd
+		replacement = strings.TrimSuffix(replacement, "\n")
+	} else if end == cellEnd+1 && replacement == "" {
+		end = cellEnd
+		if start > cellStart && code[start-1] == '\n' {
+			start--
+			joined = true
+		}
+	}
+	if end > cellEnd {
+		return nil, fmt.Errorf("fix spans several cells")
+	}
+	
+	text := code[cellStart:start] + replacement + code[end:cellEnd]
+	delta := len(replacement) - (end - start)
+	
+	// Put the magics and shell escapes back in place of their placeholders, from the last
+	for i := last; i >= first; i-- {
+		origin := source.origins[i]
+		if origin.original == nil {
+			continue
+		}
+		lineStart := origin.start
+		lineEnd := lineStart + strings.IndexByte(code[lineStart:], '\n')
+		offset := lineStart - cellStart
+		switch {
+		case lineStart >= end:
+			offset += delta
+		case start > lineEnd, joined && start == lineEnd:
+		default:
+			return nil, fmt.Errorf("fix changes a magic or shell escape")
+		}
+		text = text[:offset] + *origin.original + text[offset+lineEnd-lineStart:]
+	}
+	
+	replaced := nb.cells[cell]
+	startLine, startColumn := offsetToPosition(nb.text, replaced.start)
+	endLine, endColumn := offsetToPosition(nb.text, replaced.end)
+	return &IssueFix{
+		Description: fix.Description,
+		Replacement: nb.encodeSource(replaced, text),
+		Range: &FixRange{
+			StartLine:   startLine,
+			StartColumn: startColumn,
+			EndLine:     endLine,
+			EndColumn:   endColumn,
+		},
+	}, nil
+}
+
+// encodeSource encodes the new source of a cell as JSON in the layout of the cell's current
+// source, which Jupyter writes as a list of lines indented on their own lines
+func (nb *notebook) encodeSource(cell notebookCell, source string) string {
+	if !cell.lines {
+		return encodeJSONString(source)
+	}
+	
+	lines := strings.SplitAfter(source, "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	if len(lines) == 0 {
+		return "[]"
+	}
+	
+	current := nb.text[cell.start:cell.end]
+	if !strings.Contains(current, "\n") {
+		encoded := make([]string, len(lines))
+		for i, line := range lines {
+			encoded[i] = encodeJSONString(line)
+		}
+		return "[" + strings.Join(encoded, ", ") + "]"
+	}
+	
+	// Keep the indentation of the items and of the closing bracket
+	itemIndent := "  "
+	if open := strings.IndexByte(current, '\n'); open >= 0 {
+		rest := current[open+1:]
+		itemIndent = rest[:len(rest)-len(strings.TrimLeft(rest, " \t"))]
+	}
+	closeIndent := current[strings.LastIndexByte(current, '\n')+1 : len(current)-1]
+	
+	var encoded strings.Builder
+	encoded.WriteString("[\n")
+	for i, line := range lines {
+		encoded.WriteString(itemIndent)
+		encoded.WriteString(encodeJSONString(line))
+		if i < len(lines)-1 {
+			encoded.WriteString(",")
+		}
+		encoded.WriteString("\n")
+	}
+	encoded.WriteString(closeIndent)
+	encoded.WriteString("]")
+	return encoded.String()
+}
+
+// encodeJSONString encodes a string as JSON the way Jupyter does, without escaping HTML
+func encodeJSONString(s string) string {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(s); err != nil {
+		return fmt.Sprintf("%q", s)
+	}
+	return strings.TrimSuffix(buf.String(), "\n")
+}
+
+// Notebook is a Jupyter notebook as seen by the stages that run after the linter: the security
+// scanners and the metrics work on the Python source of its code cells, and their issues are
+// moved back to the cells with MapIssues, as the linter's are
+type Notebook struct {
+	nb     *notebook
+	source *notebookSource
+}
+
+// ParseNotebook returns the notebook in code when it is one. Only Python notebooks are parsed,
+// since the Python linter is the one that analyzes notebooks.
+func ParseNotebook(language, code string, options map[string]interface{}) (*Notebook, bool) {
+	path, _ := options["path"].(string)
+	if language != "python" || !isNotebook(code, path) {
+		return nil, false
+	}
+	
+	nb, err := parseNotebook(code)
+	if err != nil {
+		return nil, false
+	}
+	return &Notebook{nb: nb, source: nb.pythonSource()}, true
+}
+
+// Code returns the Python source of the notebook's code cells
+func (n *Notebook) Code() string {
+	return n.source.code
+}
+
+// MapIssues moves issues found in Code to their cells, dropping those between cells or on the
+// placeholders of magics
+func (n *Notebook) MapIssues(issues []Issue) []Issue {
+	mapped := make([]Issue, 0, len(issues))
+	for _, issue := range issues {
+		if issue, ok := n.nb.mapIssue(n.source, issue); ok {
+			mapped = append(mapped, issue)
+		}
+	}
+	return mapped
+}
+
+// MapMetrics moves the functions measured in Code to their cells and leaves the blank lines
+// between cells out of the file's line counts
+func (n *Notebook) MapMetrics(metrics *CodeMetrics) {
+	if metrics == nil {
+		return
+	}
+	
+	for i := range metrics.Functions {
+		function := &metrics.Functions[i]
+		if function.Line < 1 || function.Line > len(n.source.origins) {
+			continue
+		}
+		origin := n.source.origins[function.Line-1]
+		if origin.cell < 0 {
+			continue
+		}
+		
+		// The function ends in its own cell, before any blank lines that separate the next
+		end := function.EndLine
+		if end > len(n.source.origins) {
+			end = len(n.source.origins)
+		}
+		for end > function.Line && n.source.origins[end-1].cell != origin.cell {
+			end--
+		}
+		
+		cell := origin.cell
+		function.Cell = &cell
+		function.Line = origin.line
+		function.EndLine = n.source.origins[end-1].line
+	}
+	
+	separators := 0
+	for _, origin := range n.source.origins {
+		if origin.cell < 0 {
+			separators++
+		}
+	}
+	metrics.File.Lines -= separators
+	metrics.File.BlankLines -= separators
+}
+
+// cellSources returns the source of every cell, and the notebook text with the cell sources
+// blanked out, keeping its lines, so that what else it holds can be scanned on its own
+func (n *Notebook) cellSources() ([]string, string) {
+	sources := make([]string, len(n.nb.cells))
+	rest := []byte(n.nb.text)
+	for i, cell := range n.nb.cells {
+		sources[i] = cell.source
+		for offset := cell.start; offset < cell.end; offset++ {
+			if rest[offset] != '\n' {
+				rest[offset] = ' '
+			}
+		}
+	}
+	return sources, string(rest)
+}
@@ -0,0 +1,438 @@
+package analyzer
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+const testNotebook = `{
+ "cells": [
+  {
+   "cell_type": "markdown",
+   "metadata": {},
+   "source": [
+    "# Title"
+   ]
+  },
+  {
+   "cell_type": "code",
+   "execution_count": null,
+   "metadata": {},
+   "outputs": [],
+   "source": [
+    "%matplotlib inline\n",
+    "import os\n",
+    "files = !ls\n",
+    "x = (\"a %s\"\n",
+    "     % os.sep)"
+   ]
+  },
+  {
+   "cell_type": "code",
+   "execution_count": null,
+   "metadata": {},
+   "outputs": [],
+   "source": [
+    "%%bash\n",
+    "echo hi"
+   ]
+  },
+  {
+   "cell_type": "code",
+   "execution_count": null,
+   "metadata": {},
+   "outputs": [],
+   "source": [
+    "def f(a):\n",
+    "    if a:\n",
+    "        %time g()\n",
+    "    return a"
+   ]
+  },
+  {
+   "cell_type": "code",
+   "metadata": {},
+   "source": "def h():\n    pass"
+  }
+ ],
+ "metadata": {"kernelspec": {"language": "python"}},
+ "nbformat": 4,
+ "nbformat_minor": 5
+}
+`
+
+func TestIsNotebook(t *testing.T) {
+	tests := []struct {
+		name string
+		code string
+		path string
+		want bool
+	}{
+		{name: "notebook content", code: testNotebook, want: true},
+		{name: "notebook path", code: "not even json", path: "analysis/Report.IPYNB", want: true},
+		{name: "other path", code: testNotebook, path: "script.py", want: false},
+		{name: "json without nbformat", code: `{"cells": []}`, want: false},
+		{name: "json without cells", code: `{"nbformat": 4}`, want: false},
+		{name: "python", code: "print({})\n", want: false},
+		{name: "invalid json", code: `{"cells": [`, want: false},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isNotebook(tt.code, tt.path); got != tt.want {
+				t.Errorf("isNotebook() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseNotebookErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		text    string
+		wantErr string
+	}{
+		{name: "not an object", text: `[]`, wantErr: "expected {"},
+		{name: "cells not a list", text: `{"cells": {}}`, wantErr: "expected ["},
+		{name: "invalid source", text: `{"cells": [{"cell_type": "code", "source": 5}]}`, wantErr: "cell 0: invalid source"},
+		{name: "invalid cell type", text: `{"cells": [{}, {"cell_type": 1}]}`, wantErr: "cell 1: invalid cell_type"},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := parseNotebook(tt.text)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("got error %v, want one containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestNotebookPythonSource(t *testing.T) {
+	nb, err := parseNotebook(testNotebook)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(nb.cells) != 5 {
+		t.Fatalf("got %d cells, want 5", len(nb.cells))
+	}
+	
+	source := nb.pythonSource()
+	want := `# %matplotlib inline
+import os
+# files = !ls
+x = ("a %s"
+     % os.sep)
+
+
+def f(a):
+    if a:
+        pass
+    return a
+
+
+def h():
+    pass
+`
+	if source.code != want {
+		t.Errorf("got source\n%s\nwant\n%s", source.code, want)
+	}
+	
+	empty := &notebook{cells: []notebookCell{{cellType: "code", source: "  \n"}, {cellType: "raw", source: "x = 1"}}}
+	if got := empty.pythonSource().code; got != "" {
+		t.Errorf("got source %q for a notebook without code, want none", got)
+	}
+}
+
+func TestNotebookMapIssue(t *testing.T) {
+	nb, err := parseNotebook(testNotebook)
+	if err != nil {
+		t.Fatal(err)
+	}
+	source := nb.pythonSource()
+	
+	tests := []struct {
+		name     string
+		issue    Issue
+		wantOK   bool
+		wantCell int
+		wantLine int
+		// wantSource is the source of the cell once the mapped fix is applied to the notebook,
+		// or empty when the fix is dropped
+		wantSource string
+	}{
+		{
+			name:     "issue in the first code cell",
+			issue:    Issue{Line: 2, RuleID: "F401"},
+			wantOK:   true,
+			wantCell: 1,
+			wantLine: 2,
+		},
+		{
+			name:     "issue in a cell after a skipped cell magic",
+			issue:    Issue{Line: 15, RuleID: "E303"},
+			wantOK:   true,
+			wantCell: 4,
+			wantLine: 2,
+		},
+		{
+			name:  "issue between cells",
+			issue: Issue{Line: 6, RuleID: "E303"},
+		},
+		{
+			name:  "issue on a magic placeholder",
+			issue: Issue{Line: 3, RuleID: "E265"},
+		},
+		{
+			name:  "issue past the source",
+			issue: Issue{Line: 99, RuleID: "W391"},
+		},
+		{
+			name:       "removing a line keeps the magics around it",
+			issue:      Issue{Line: 2, RuleID: "F401", Fix: &IssueFix{Range: &FixRange{StartLine: 2, StartColumn: 1, EndLine: 3, EndColumn: 1}}},
+			wantOK:     true,
+			wantCell:   1,
+			wantLine:   2,
+			wantSource: "%matplotlib inline\nfiles = !ls\nx = (\"a %s\"\n     % os.sep)",
+		},
+		{
+			name:       "line fix after an indented magic",
+			issue:      Issue{Line: 11, RuleID: "custom", Fix: &IssueFix{Replacement: "    return not a"}},
+			wantOK:     true,
+			wantCell:   3,
+			wantLine:   4,
+			wantSource: "def f(a):\n    if a:\n        %time g()\n    return not a",
+		},
+		{
+			name:       "fix in a cell whose source is a string",
+			issue:      Issue{Line: 15, RuleID: "custom", Fix: &IssueFix{Replacement: "return 1", Range: &FixRange{StartLine: 15, StartColumn: 5, EndLine: 15, EndColumn: 9}}},
+			wantOK:     true,
+			wantCell:   4,
+			wantLine:   2,
+			wantSource: "def h():\n    return 1",
+		},
+		{
+			name:       "fix replacing the line break that ends the cell",
+			issue:      Issue{Line: 14, RuleID: "custom", Fix: &IssueFix{Replacement: "def h():\n    return None\n", Range: &FixRange{StartLine: 14, StartColumn: 1, EndLine: 16, EndColumn: 1}}},
+			wantOK:     true,
+			wantCell:   4,
+			wantLine:   1,
+			wantSource: "def h():\n    return None",
+		},
+		{
+			name:       "deleting the last line of a cell after a magic",
+			issue:      Issue{Line: 11, RuleID: "custom", Fix: &IssueFix{Range: &FixRange{StartLine: 11, StartColumn: 1, EndLine: 12, EndColumn: 1}}},
+			wantOK:     true,
+			wantCell:   3,
+			wantLine:   4,
+			wantSource: "def f(a):\n    if a:\n        %time g()",
+		},
+		{
+			name:       "deleting the last line of the last cell",
+			issue:      Issue{Line: 15, RuleID: "custom", Fix: &IssueFix{Range: &FixRange{StartLine: 15, StartColumn: 1, EndLine: 16, EndColumn: 1}}},
+			wantOK:     true,
+			wantCell:   4,
+			wantLine:   2,
+			wantSource: "def h():",
+		},
+		{
+			name:     "fix inserting at the end of the source is dropped",
+			issue:    Issue{Line: 15, RuleID: "custom", Fix: &IssueFix{Replacement: "h()\n", Range: &FixRange{StartLine: 16, StartColumn: 1, EndLine: 16, EndColumn: 1}}},
+			wantOK:   true,
+			wantCell: 4,
+			wantLine: 2,
+		},
+		{
+			name:     "fix spanning cells is dropped",
+			issue:    Issue{Line: 5, RuleID: "custom", Fix: &IssueFix{Range: &FixRange{StartLine: 5, StartColumn: 1, EndLine: 8, EndColumn: 1}}},
+			wantOK:   true,
+			wantCell: 1,
+			wantLine: 5,
+		},
+		{
+			name:     "fix changing a magic is dropped",
+			issue:    Issue{Line: 2, RuleID: "custom", Fix: &IssueFix{Range: &FixRange{StartLine: 2, StartColumn: 1, EndLine: 3, EndColumn: 5}}},
+			wantOK:   true,
+			wantCell: 1,
+			wantLine: 2,
+		},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := nb.mapIssue(source, tt.issue)
+			if ok != tt.wantOK {
+				t.Fatalf("got ok %v, want %v", ok, tt.wantOK)
+			}
+			if !ok {
+				return
+			}
+			if got.Cell == nil || *got.Cell != tt.wantCell || got.Line != tt.wantLine {
+				t.Fatalf("got cell %v line %d, want cell %d line %d", got.Cell, got.Line, tt.wantCell, tt.wantLine)
+			}
+			
+			if tt.wantSource == "" {
+				if got.Fix != nil {
+					t.Errorf("got fix %+v, want none", got.Fix)
+				}
+				return
+			}
+			if got.Fix == nil {
+				t.Fatal("got no fix")
+			}
+			fixed, err := ApplyFix(testNotebook, got.Line, *got.Fix)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !json.Valid([]byte(fixed)) {
+				t.Fatalf("fixed notebook is not valid JSON:\n%s", fixed)
+			}
+			reparsed, err := parseNotebook(fixed)
+			if err != nil {
+				t.Fatal(err)
+			}
+			cell := reparsed.cells[tt.wantCell]
+			if cell.source != tt.wantSource {
+				t.Errorf("got cell source %q, want %q", cell.source, tt.wantSource)
+			}
+			if cell.lines != nb.cells[tt.wantCell].lines {
+				t.Errorf("fix changed the layout of the cell source")
+			}
+		})
+	}
+	
+	unplaced, ok := nb.mapIssue(source, Issue{RuleID: "E902", Fix: &IssueFix{Replacement: "x"}})
+	if !ok || unplaced.Fix != nil || unplaced.Cell != nil {
+		t.Errorf("got %+v, want the issue kept without a cell or fix", unplaced)
+	}
+}
+
+func TestNotebookMapIssueUnusedImportEndingCell(t *testing.T) {
+	code := `{"cells": [
+  {"cell_type": "code", "metadata": {}, "outputs": [], "source": ["import os\n", "import sys"]},
+  {"cell_type": "code", "metadata": {}, "outputs": [], "source": ["print(os)"]}
+ ], "metadata": {}, "nbformat": 4, "nbformat_minor": 5}`
+	nb, err := parseNotebook(code)
+	if err != nil {
+		t.Fatal(err)
+	}
+	source := nb.pythonSource()
+	
+	// ruff deletes the unused import with its line break
+	issue := Issue{Line: 2, RuleID: "F401", Fix: &IssueFix{Range: &FixRange{StartLine: 2, StartColumn: 1, EndLine: 3, EndColumn: 1}}}
+	got, ok := nb.mapIssue(source, issue)
+	if !ok || got.Fix == nil {
+		t.Fatalf("got %+v, want the issue with its fix", got)
+	}
+	fixed, err := ApplyFix(code, got.Line, *got.Fix)
+	if err != nil {
+		t.Fatal(err)
+	}
+	reparsed, err := parseNotebook(fixed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if reparsed.cells[0].source != "import os" || reparsed.cells[1].source != "print(os)" {
+		t.Errorf("got cell sources %q and %q, want \"import os\" and \"print(os)\"", reparsed.cells[0].source, reparsed.cells[1].source)
+	}
+}
+
+func TestNotebookEncodeSource(t *testing.T) {
+	tests := []struct {
+		name    string
+		current string
+		source  string
+		want    string
+	}{
+		{
+			name:    "string",
+			current: `"x = 1"`,
+			source:  "a = '<b>'\nb = 2",
+			want:    `"a = '<b>'\nb = 2"`,
+		},
+		{
+			name:    "list on one line",
+			current: `["x = 1"]`,
+			source:  "a = 1\nb = 2\n",
+			want:    `["a = 1\n", "b = 2\n"]`,
+		},
+		{
+			name:    "indented list",
+			current: "[\n      \"x = 1\"\n     ]",
+			source:  "a = 1\nb = 2",
+			want:    "[\n      \"a = 1\\n\",\n      \"b = 2\"\n     ]",
+		},
+		{
+			name:    "empty source",
+			current: "[\n    \"x = 1\"\n   ]",
+			source:  "",
+			want:    "[]",
+		},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			text := `{"source": ` + tt.current + `}`
+			cell := notebookCell{lines: strings.HasPrefix(tt.current, "["), start: len(`{"source": `), end: len(text) - 1}
+			nb := &notebook{text: text}
+			if got := nb.encodeSource(cell, tt.source); got != tt.want {
+				t.Errorf("got %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseNotebookForStages(t *testing.T) {
+	if _, ok := ParseNotebook("python", "import os\n", nil); ok {
+		t.Error("ParseNotebook() accepted a Python file")
+	}
+	if _, ok := ParseNotebook("json", testNotebook, nil); ok {
+		t.Error("ParseNotebook() accepted a notebook outside a Python analysis")
+	}
+	
+	nb, ok := ParseNotebook("python", testNotebook, map[string]interface{}{"path": "report.ipynb"})
+	if !ok {
+		t.Fatal("ParseNotebook() did not recognise the notebook")
+	}
+	if !strings.HasPrefix(nb.Code(), "# %matplotlib inline\nimport os\n") {
+		t.Errorf("Code() = %q, want the source of the code cells", nb.Code())
+	}
+	
+	// Issues of the later stages are moved to their cells like the linter's
+	issues := nb.MapIssues([]Issue{{Line: 9, RuleID: "B101"}, {Line: 6, RuleID: "between-cells"}})
+	if len(issues) != 1 || issues[0].Cell == nil || *issues[0].Cell != 3 || issues[0].Line != 2 {
+		t.Errorf("MapIssues() = %+v, want the issue on line 2 of cell 3", issues)
+	}
+}
+
+func TestNotebookMapMetrics(t *testing.T) {
+	nb, ok := ParseNotebook("python", testNotebook, nil)
+	if !ok {
+		t.Fatal("ParseNotebook() did not recognise the notebook")
+	}
+	
+	metrics, _ := NewMetricsCalculator(map[string]string{}).Measure("python", nb.Code(), nil)
+	lines, blankLines := metrics.File.Lines, metrics.File.BlankLines
+	nb.MapMetrics(metrics)
+	
+	// The four blank lines separating the three code cells are not part of the notebook
+	if metrics.File.Lines != lines-4 || metrics.File.BlankLines != blankLines-4 {
+		t.Errorf("got %d lines and %d blank lines, want %d and %d", metrics.File.Lines, metrics.File.BlankLines, lines-4, blankLines-4)
+	}
+	
+	want := map[string][3]int{"f": {3, 1, 4}, "h": {4, 1, 2}}
+	if len(metrics.Functions) != len(want) {
+		t.Fatalf("got %d functions, want %d", len(metrics.Functions), len(want))
+	}
+	for _, function := range metrics.Functions {
+		position, ok := want[function.Name]
+		if !ok || function.Cell == nil {
+			t.Errorf("function %s has no cell", function.Name)
+			continue
+		}
+		if got := [3]int{*function.Cell, function.Line, function.EndLine}; got != position {
+			t.Errorf("function %s at cell, line, end line %v, want %v", function.Name, got, position)
+		}
+	}
+}
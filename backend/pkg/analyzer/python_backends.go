@@ -0,0 +1,329 @@
+package analyzer
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// PythonBackend runs an external Python analysis tool against a file
+type PythonBackend interface {
+	// Name returns the identifier used to select the backend
+	Name() string
+	
+	// Run analyzes the file at path, which contains code, and returns the issues found
+	Run(ctx context.Context, path string, code string, options map[string]interface{}) ([]Issue, error)
+}
+
+// pylintBackend reports issues using pylint's JSON output
+type pylintBackend struct {
+	*BaseAnalyzer
+	path string
+}
+
+// Name returns the identifier used to select the backend
+func (b *pylintBackend) Name() string {
+	return "pylint"
+}
+
+// Run analyzes the file with pylint
+func (b *pylintBackend) Run(ctx context.Context, path string, code string, options map[string]interface{}) ([]Issue, error) {
+	cmd := exec.CommandContext(
+		ctx,
+		b.path,
+		"--output-format=json",
+		path,
+	)
+	
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	
+	// pylint encodes the categories it found in its exit status, so only
+	// treat the run as failed when it produced no output at all
+	err := cmd.Run()
+	if err != nil && stdout.Len() == 0 {
+		return nil, b.WrapError(fmt.Errorf("failed to run pylint: %w, stderr: %s", err, stderr.String()), "pylint execution error")
+	}
+	
+	var pylintResults []map[string]interface{}
+	if err := json.Unmarshal(stdout.Bytes(), &pylintResults); err != nil {
+		return nil, b.WrapError(err, "failed to parse pylint output")
+	}
+	
+	issues := make([]Issue, 0, len(pylintResults))
+	for _, result := range pylintResults {
+		issues = append(issues, convertPylintResult(result))
+	}
+	
+	return issues, nil
+}
+
+// convertPylintResult converts a pylint result to a CodeHawk issue
+func convertPylintResult(result map[string]interface{}) Issue {
+	// Extract basic information
+	line, _ := result["line"].(float64)
+	column, _ := result["column"].(float64)
+	messageID, _ := result["message-id"].(string)
+	message, _ := result["message"].(string)
+	symbol, _ := result["symbol"].(string)
+	
+	// Map pylint category to CodeHawk severity
+	var severity string
+	switch result["type"] {
+	case "error", "fatal":
+		severity = "error"
+	case "warning":
+		severity = "warning"
+	case "convention", "refactor":
+		severity = "suggestion"
+	default:
+		severity = "info"
+	}
+	
+	col := int(column)
+	
+	return Issue{
+		Line:     int(line),
+		Column:   &col,
+		Message:  message,
+		Severity: severity,
+		RuleID:   messageID,
+		Context:  symbol,
+	}
+}
+
+// ruffLocation is a position in ruff's JSON output
+type ruffLocation struct {
+	Row    int `json:"row"`
+	Column int `json:"column"`
+}
+
+// ruffDiagnostic is a single entry of ruff's JSON output
+type ruffDiagnostic struct {
+	Code        string       `json:"code"`
+	Message     string       `json:"message"`
+	Location    ruffLocation `json:"location"`
+	EndLocation ruffLocation `json:"end_location"`
+	URL         string       `json:"url"`
+	Fix         *struct {
+		Applicability string `json:"applicability"`
+		Message       string `json:"message"`
+		Edits         []struct {
+			Content     string       `json:"content"`
+			Location    ruffLocation `json:"location"`
+			EndLocation ruffLocation `json:"end_location"`
+		} `json:"edits"`
+	} `json:"fix"`
+}
+
+// ruffBackend reports issues using ruff, including its native fixes
+type ruffBackend struct {
+	*BaseAnalyzer
+	path string
+}
+
+// Name returns the identifier used to select the backend
+func (b *ruffBackend) Name() string {
+	return "ruff"
+}
+
+// Run analyzes the file with ruff
+func (b *ruffBackend) Run(ctx context.Context, path string, code string, options map[string]interface{}) ([]Issue, error) {
+	args := []string{
+		"check",
+		"--output-format=json",
+		"--no-cache",
+		"--exit-zero",
+	}
+	
+	// Rule selection can be narrowed per request, e.g. "E,F,I"
+	if selectRules := b.GetStringOption(options, "ruffSelect", ""); selectRules != "" {
+		args = append(args, "--select", selectRules)
+	}
+	
+	args = append(args, path)
+	
+	cmd := exec.CommandContext(ctx, b.path, args...)
+	
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	
+	if err := cmd.Run(); err != nil {
+		return nil, b.WrapError(fmt.Errorf("failed to run ruff: %w, stderr: %s", err, stderr.String()), "ruff execution error")
+	}
+	
+	var diagnostics []ruffDiagnostic
+	if err := json.Unmarshal(stdout.Bytes(), &diagnostics); err != nil {
+		return nil, b.WrapError(err, "failed to parse ruff output")
+	}
+	
+	issues := make([]Issue, 0, len(diagnostics))
+	for _, diagnostic := range diagnostics {
+		issues = append(issues, b.convertDiagnostic(diagnostic, code))
+	}
+	
+	return issues, nil
+}
+
+// convertDiagnostic converts a ruff diagnostic to a CodeHawk issue
+func (b *ruffBackend) convertDiagnostic(diagnostic ruffDiagnostic, code string) Issue {
+	column := diagnostic.Location.Column
+	
+	// Syntax errors have no code; everything else is a lint finding
+	severity := "warning"
+	ruleID := diagnostic.Code
+	if ruleID == "" || strings.HasPrefix(ruleID, "E9") {
+		severity = "error"
+		if ruleID == "" {
+			ruleID = "syntax-error"
+		}
+	}
+	
+	issue := Issue{
+		Line:     diagnostic.Location.Row,
+		Column:   &column,
+		Message:  diagnostic.Message,
+		Severity: severity,
+		RuleID:   ruleID,
+		Context:  diagnostic.URL,
+	}
+	
+	if diagnostic.Fix == nil || len(diagnostic.Fix.Edits) == 0 {
+		return issue
+	}
+	
+	edits := make([]textEdit, 0, len(diagnostic.Fix.Edits))
+	for _, edit := range diagnostic.Fix.Edits {
+		edits = append(edits, textEdit{
+			start: positionToOffset(code, edit.Location.Row, edit.Location.Column),
+			end:   positionToOffset(code, edit.EndLocation.Row, edit.EndLocation.Column),
+			text:  edit.Content,
+		})
+	}
+	
+	description := diagnostic.Fix.Message
+	if description == "" {
+		description = "Auto-fix with ruff"
+	}
+	
+	fix, err := buildRangeFix(code, description, edits)
+	if err != nil {
+		return issue
+	}
+	
+	// Only fixes ruff considers safe are offered as the fix; the rest become suggestions
+	switch strings.ToLower(diagnostic.Fix.Applicability) {
+	case "safe", "automatic", "":
+		issue.Fix = fix
+	default:
+		issue.Suggestions = append(issue.Suggestions, *fix)
+	}
+	
+	return issue
+}
+
+// mypyDiagnostic is a single line of mypy's JSON output
+type mypyDiagnostic struct {
+	File     string  `json:"file"`
+	Line     int     `json:"line"`
+	Column   int     `json:"column"`
+	Message  string  `json:"message"`
+	Hint     *string `json:"hint"`
+	Code     *string `json:"code"`
+	Severity string  `json:"severity"`
+}
+
+// mypyBackend reports type errors using mypy's JSON output
+type mypyBackend struct {
+	*BaseAnalyzer
+	path string
+}
+
+// Name returns the identifier used to select the backend
+func (b *mypyBackend) Name() string {
+	return "mypy"
+}
+
+// Run analyzes the file with mypy
+func (b *mypyBackend) Run(ctx context.Context, path string, code string, options map[string]interface{}) ([]Issue, error) {
+	args := []string{
+		"--output", "json",
+		"--ignore-missing-imports",
+		"--no-incremental",
+		"--cache-dir", "/dev/null",
+		"--no-error-summary",
+	}
+	
+	if b.GetBoolOption(options, "mypyStrict", false) {
+		args = append(args, "--strict")
+	}
+	
+	args = append(args, path)
+	
+	cmd := exec.CommandContext(ctx, b.path, args...)
+	
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	
+	// mypy exits with status 1 when it reports errors
+	err := cmd.Run()
+	if err != nil && !strings.Contains(err.Error(), "exit status 1") {
+		return nil, b.WrapError(fmt.Errorf("failed to run mypy: %w, stderr: %s", err, stderr.String()), "mypy execution error")
+	}
+	
+	issues := make([]Issue, 0)
+	scanner := bufio.NewScanner(&stdout)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		
+		var diagnostic mypyDiagnostic
+		if err := json.Unmarshal([]byte(line), &diagnostic); err != nil {
+			return nil, b.WrapError(err, "failed to parse mypy output")
+		}
+		
+		message := diagnostic.Message
+		if diagnostic.Hint != nil && *diagnostic.Hint != "" {
+			message = message + "\n" + *diagnostic.Hint
+		}
+		
+		// Notes elaborate on the preceding error
+		if diagnostic.Severity == "note" && len(issues) > 0 && issues[len(issues)-1].Line == diagnostic.Line {
+			last := &issues[len(issues)-1]
+			last.Message = last.Message + "\n" + message
+			continue
+		}
+		
+		ruleID := "mypy"
+		if diagnostic.Code != nil && *diagnostic.Code != "" {
+			ruleID = *diagnostic.Code
+		}
+		
+		issue := Issue{
+			Line:     diagnostic.Line,
+			Message:  message,
+			Severity: b.MapSeverity(diagnostic.Severity),
+			RuleID:   ruleID,
+			Context:  "mypy",
+		}
+		
+		// mypy columns are 0-based and -1 when unknown
+		if diagnostic.Column >= 0 {
+			column := diagnostic.Column + 1
+			issue.Column = &column
+		}
+		
+		issues = append(issues, issue)
+	}
+	
+	return issues, nil
+}
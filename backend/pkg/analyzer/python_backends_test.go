@@ -0,0 +1,217 @@
+package analyzer
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// fakeTool writes a shell script standing in for an external tool, printing output
+func fakeTool(t *testing.T, output string) string {
+	t.Helper()
+	return fakeScript(t, "cat <<'CODEHAWK_EOF'\n"+output+"\nCODEHAWK_EOF")
+}
+
+// fakeScript writes a shell script standing in for an external tool, running the commands of body
+func fakeScript(t *testing.T, body string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "tool")
+	if err := ioutil.WriteFile(path, []byte("#!/bin/sh\n"+body+"\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestConvertPylintResult(t *testing.T) {
+	tests := []struct {
+		name         string
+		result       map[string]interface{}
+		wantSeverity string
+	}{
+		{"error", map[string]interface{}{"type": "error", "line": 3.0, "column": 4.0, "message-id": "E0602", "symbol": "undefined-variable", "message": "Undefined variable 'x'"}, "error"},
+		{"fatal", map[string]interface{}{"type": "fatal", "line": 1.0, "column": 0.0, "message-id": "F0001", "symbol": "fatal", "message": "Fatal"}, "error"},
+		{"warning", map[string]interface{}{"type": "warning", "line": 2.0, "column": 0.0, "message-id": "W0611", "symbol": "unused-import", "message": "Unused import os"}, "warning"},
+		{"convention", map[string]interface{}{"type": "convention", "line": 1.0, "column": 0.0, "message-id": "C0114", "symbol": "missing-module-docstring", "message": "Missing module docstring"}, "suggestion"},
+		{"information", map[string]interface{}{"type": "info", "line": 1.0, "column": 0.0, "message-id": "I0011", "symbol": "locally-disabled", "message": "Locally disabling"}, "info"},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			issue := convertPylintResult(tt.result)
+			if issue.Severity != tt.wantSeverity {
+				t.Errorf("severity = %q, want %q", issue.Severity, tt.wantSeverity)
+			}
+			if issue.Line != int(tt.result["line"].(float64)) || issue.Column == nil || *issue.Column != int(tt.result["column"].(float64)) {
+				t.Errorf("position = %d:%v, want %v:%v", issue.Line, issue.Column, tt.result["line"], tt.result["column"])
+			}
+			if issue.RuleID != tt.result["message-id"] || issue.Context != tt.result["symbol"] || issue.Message != tt.result["message"] {
+				t.Errorf("issue = %+v, want the rule, symbol and message of %v", issue, tt.result)
+			}
+		})
+	}
+}
+
+func TestRuffConvertDiagnostic(t *testing.T) {
+	code := "import os\nimport sys\n\nprint(sys.argv)\n"
+	fix := func(applicability string) string {
+		return `, "fix": {"applicability": "` + applicability + `", "message": "Remove unused import: os",
+			"edits": [{"content": "", "location": {"row": 1, "column": 1}, "end_location": {"row": 2, "column": 1}}]}`
+	}
+	
+	tests := []struct {
+		name            string
+		diagnostic      string
+		wantSeverity    string
+		wantRuleID      string
+		wantFix         bool
+		wantSuggestions int
+	}{
+		{
+			name:         "safe fix",
+			diagnostic:   `{"code": "F401", "message": "os imported but unused", "location": {"row": 1, "column": 8}` + fix("safe") + `}`,
+			wantSeverity: "warning",
+			wantRuleID:   "F401",
+			wantFix:      true,
+		},
+		{
+			name:            "unsafe fix becomes a suggestion",
+			diagnostic:      `{"code": "F401", "message": "os imported but unused", "location": {"row": 1, "column": 8}` + fix("unsafe") + `}`,
+			wantSeverity:    "warning",
+			wantRuleID:      "F401",
+			wantSuggestions: 1,
+		},
+		{
+			name:         "syntax error",
+			diagnostic:   `{"code": null, "message": "SyntaxError: Expected an expression", "location": {"row": 4, "column": 7}}`,
+			wantSeverity: "error",
+			wantRuleID:   "syntax-error",
+		},
+		{
+			name:         "E9 rules are errors",
+			diagnostic:   `{"code": "E902", "message": "No such file or directory", "location": {"row": 1, "column": 1}}`,
+			wantSeverity: "error",
+			wantRuleID:   "E902",
+		},
+	}
+	
+	backend := &ruffBackend{BaseAnalyzer: NewBaseAnalyzer(nil)}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var diagnostic ruffDiagnostic
+			if err := json.Unmarshal([]byte(tt.diagnostic), &diagnostic); err != nil {
+				t.Fatal(err)
+			}
+			
+			issue := backend.convertDiagnostic(diagnostic, code)
+			if issue.Severity != tt.wantSeverity || issue.RuleID != tt.wantRuleID {
+				t.Errorf("issue = %s %s, want %s %s", issue.Severity, issue.RuleID, tt.wantSeverity, tt.wantRuleID)
+			}
+			if (issue.Fix != nil) != tt.wantFix || len(issue.Suggestions) != tt.wantSuggestions {
+				t.Fatalf("fix = %v with %d suggestions, want fix %v with %d", issue.Fix, len(issue.Suggestions), tt.wantFix, tt.wantSuggestions)
+			}
+			
+			fix := issue.Fix
+			if fix == nil && len(issue.Suggestions) > 0 {
+				fix = &issue.Suggestions[0]
+			}
+			if fix != nil {
+				fixed, err := ApplyFix(code, issue.Line, *fix)
+				if err != nil {
+					t.Fatalf("ApplyFix() error = %v", err)
+				}
+				if want := "import sys\n\nprint(sys.argv)\n"; fixed != want {
+					t.Errorf("fixed code = %q, want %q", fixed, want)
+				}
+			}
+		})
+	}
+}
+
+func TestMypyBackendRun(t *testing.T) {
+	output := `{"file": "code.py", "line": 4, "column": 10, "message": "Incompatible return value type (got \"str\", expected \"int\")", "hint": null, "code": "return-value", "severity": "error"}
+{"file": "code.py", "line": 4, "column": 10, "message": "Did you mean \"len\"?", "hint": null, "code": null, "severity": "note"}
+{"file": "code.py", "line": 7, "column": -1, "message": "Name \"y\" is not defined", "hint": "Define it first", "code": "name-defined", "severity": "error"}`
+	
+	backend := &mypyBackend{BaseAnalyzer: NewBaseAnalyzer(nil), path: fakeTool(t, output)}
+	issues, err := backend.Run(context.Background(), "code.py", "", nil)
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	
+	want := []struct {
+		line    int
+		column  int
+		ruleID  string
+		message string
+	}{
+		{4, 11, "return-value", "Incompatible return value type (got \"str\", expected \"int\")\nDid you mean \"len\"?"},
+		{7, 0, "name-defined", "Name \"y\" is not defined\nDefine it first"},
+	}
+	if len(issues) != len(want) {
+		t.Fatalf("got %d issues, want %d: %+v", len(issues), len(want), issues)
+	}
+	for i, issue := range issues {
+		if issue.Line != want[i].line || issue.RuleID != want[i].ruleID || issue.Message != want[i].message || issue.Severity != "error" {
+			t.Errorf("issue %d = %+v, want %+v", i, issue, want[i])
+		}
+		if want[i].column == 0 && issue.Column != nil {
+			t.Errorf("issue %d column = %d, want none", i, *issue.Column)
+		}
+		if want[i].column != 0 && (issue.Column == nil || *issue.Column != want[i].column) {
+			t.Errorf("issue %d column = %v, want %d", i, issue.Column, want[i].column)
+		}
+	}
+}
+
+func TestSelectBackends(t *testing.T) {
+	linter := NewPythonLinter(map[string]string{
+		"backends":                           "pylint,ruff",
+		OrganizationKey("backends", "org-2"): "mypy",
+	})
+	
+	tests := []struct {
+		name         string
+		organization string
+		settings     map[string]string
+		options      map[string]interface{}
+		want         []string
+	}{
+		{"linter configuration", "", nil, nil, []string{"pylint", "ruff"}},
+		{"organization without a choice", "org-1", nil, nil, []string{"pylint", "ruff"}},
+		{"organization set by the server", "org-2", nil, nil, []string{"mypy"}},
+		{"organization setting", "org-1", map[string]string{"backends": "ruff, mypy"}, nil, []string{"ruff", "mypy"}},
+		{"request over organization", "org-1", map[string]string{"backends": "ruff"}, map[string]interface{}{"backends": []interface{}{"mypy"}}, []string{"mypy"}},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := WithOrganization(context.Background(), tt.organization, tt.settings)
+			backends, err := linter.selectBackends(ctx, tt.options)
+			if err != nil {
+				t.Fatalf("selectBackends() error = %v", err)
+			}
+			
+			var got []string
+			for _, backend := range backends {
+				got = append(got, backend.Name())
+			}
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("selectBackends() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateBackends(t *testing.T) {
+	linter := NewPythonLinter(nil)
+	
+	if err := linter.ValidateBackends("ruff, MyPy"); err != nil {
+		t.Errorf("ValidateBackends() error = %v", err)
+	}
+	if err := linter.ValidateBackends("ruff,flake8"); err == nil || !strings.Contains(err.Error(), "flake8") {
+		t.Errorf("ValidateBackends() error = %v, want unknown flake8", err)
+	}
+}
@@ -3,20 +3,20 @@ package analyzer
 import (
 	"bytes"
 	"context"
-	"encoding/json"
 	"fmt"
 	"io/ioutil"
 	"os"
 	"os/exec"
-	"path/filepath"
 	"strconv"
 	"strings"
+	"sync"
 )
 
 // PythonLinter implements the Linter interface for Python
 type PythonLinter struct {
 	*BaseAnalyzer
-	pylintPath string
+	backends map[string]PythonBackend
+	mu       sync.RWMutex
 }
 
 // NewPythonLinter creates a new Python linter
@@ -27,10 +27,73 @@ func NewPythonLinter(config map[string]string) *PythonLinter {
 		pylintPath = path
 	}
 	
-	return &PythonLinter{
+	// Default ruff path
+	ruffPath := "ruff"
+	if path, ok := config["ruffPath"]; ok && path != "" {
+		ruffPath = path
+	}
+	
+	// Default mypy path
+	mypyPath := "mypy"
+	if path, ok := config["mypyPath"]; ok && path != "" {
+		mypyPath = path
+	}
+	
+	linter := &PythonLinter{
 		BaseAnalyzer: NewBaseAnalyzer(config),
-		pylintPath:   pylintPath,
+		backends:     make(map[string]PythonBackend),
+	}
+	
+	linter.RegisterBackend(&pylintBackend{BaseAnalyzer: linter.BaseAnalyzer, path: pylintPath})
+	linter.RegisterBackend(&ruffBackend{BaseAnalyzer: linter.BaseAnalyzer, path: ruffPath})
+	linter.RegisterBackend(&mypyBackend{BaseAnalyzer: linter.BaseAnalyzer, path: mypyPath})
+	
+	return linter
+}
+
+// RegisterBackend adds a backend that can be selected through the "backends" option
+func (l *PythonLinter) RegisterBackend(backend PythonBackend) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.backends[backend.Name()] = backend
+}
+
+// selectBackends returns the backends requested in the options, defaulting to pylint.
+// Without a request, the "backends" setting of the organization the analysis runs for
+// applies, and then the "backends" key of the linter configuration.
+func (l *PythonLinter) selectBackends(ctx context.Context, options map[string]interface{}) ([]PythonBackend, error) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	
+	names := l.GetListOption(map[string]interface{}{"backends": l.GetOrganizationOption(ctx, "backends", "")}, "backends", []string{"pylint"})
+	if _, requested := options["backends"]; requested {
+		names = l.GetListOption(options, "backends", names)
+	}
+	
+	return l.lookupBackends(names)
+}
+
+// lookupBackends returns the registered backends of the given names
+func (l *PythonLinter) lookupBackends(names []string) ([]PythonBackend, error) {
+	selected := make([]PythonBackend, 0, len(names))
+	for _, name := range names {
+		backend, ok := l.backends[strings.ToLower(name)]
+		if !ok {
+			return nil, fmt.Errorf("unknown Python backend: %s", name)
+		}
+		selected = append(selected, backend)
 	}
+	
+	return selected, nil
+}
+
+// ValidateBackends checks a comma-separated list of backends before an organization saves it
+func (l *PythonLinter) ValidateBackends(list string) error {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	
+	_, err := l.lookupBackends(l.GetListOption(map[string]interface{}{"backends": list}, "backends", nil))
+	return err
 }
 
 // Language returns the identifier for the supported language
@@ -38,72 +101,72 @@ func (l *PythonLinter) Language() string {
 	return "python"
 }
 
-// Analyze analyzes the provided code and returns issues found
+// Analyze analyzes the provided code and returns issues found. Jupyter notebooks, named by the
+// "path" option or recognized by their content, are analyzed through their code cells.
 func (l *PythonLinter) Analyze(ctx context.Context, code string, options map[string]interface{}) (*AnalysisResult, error) {
+	if isNotebook(code, l.GetStringOption(options, "path", "")) {
+		return l.analyzeNotebook(ctx, code, options)
+	}
+	
 	return l.BaseAnalyzer.CommonAnalyzeWrapper(
 		ctx,
 		code,
 		options,
 		l.findIssues,
-		l.SuggestFixes,
+		func(ctx context.Context, code string, issues []Issue) ([]Issue, error) {
+			return l.suggestFixes(ctx, code, issues, options)
+		},
 	)
 }
 
-// findIssues analyzes the code and returns issues
-func (l *PythonLinter) findIssues(ctx context.Context, code string, options map[string]interface{}) ([]Issue, error) {
-	// Create a temporary file for the code
-	tmpFile, err := ioutil.TempFile("", "codehawk-*.py")
+// analyzeNotebook lints the code cells of a notebook as one source, then maps the issues back
+// to their cells and their fixes to edits of the notebook JSON
+func (l *PythonLinter) analyzeNotebook(ctx context.Context, code string, options map[string]interface{}) (*AnalysisResult, error) {
+	nb, err := parseNotebook(code)
 	if err != nil {
-		return nil, l.WrapError(err, "failed to create temporary file")
+		return nil, l.WrapError(err, "failed to parse notebook")
 	}
-	defer os.Remove(tmpFile.Name())
 	
-	// Write the code to the temporary file
-	if _, err := tmpFile.Write([]byte(code)); err != nil {
-		return nil, l.WrapError(err, "failed to write to temporary file")
-	}
-	
-	if err := tmpFile.Close(); err != nil {
-		return nil, l.WrapError(err, "failed to close temporary file")
+	source := nb.pythonSource()
+	if source.code == "" {
+		return &AnalysisResult{Issues: []Issue{}}, nil
 	}
 	
-	// Run pylint on the temporary file
-	cmd := exec.CommandContext(
+	result, err := l.BaseAnalyzer.CommonAnalyzeWrapper(
 		ctx,
-		l.pylintPath,
-		"--output-format=json",
-		tmpFile.Name(),
+		source.code,
+		options,
+		l.findIssues,
+		func(ctx context.Context, code string, issues []Issue) ([]Issue, error) {
+			return l.suggestFixes(ctx, code, issues, options)
+		},
 	)
-	
-	var stdout, stderr bytes.Buffer
-	cmd.Stdout = &stdout
-	cmd.Stderr = &stderr
-	
-	// Execute pylint
-	err = cmd.Run()
-	if err != nil && err.Error() != "exit status 1" {
-		// exit status 1 is normal for pylint when it finds issues
-		return nil, l.WrapError(fmt.Errorf("failed to run pylint: %w, stderr: %s", err, stderr.String()), "pylint execution error")
+	if err != nil {
+		return nil, err
 	}
 	
-	// Parse the JSON output
-	var pylintResults []map[string]interface{}
-	if err := json.Unmarshal(stdout.Bytes(), &pylintResults); err != nil {
-		return nil, l.WrapError(err, "failed to parse pylint output")
+	issues := make([]Issue, 0, len(result.Issues))
+	for _, issue := range result.Issues {
+		if mapped, ok := nb.mapIssue(source, issue); ok {
+			issues = append(issues, mapped)
+		}
 	}
 	
-	// Convert pylint results to CodeHawk issues
-	issues := make([]Issue, 0, len(pylintResults))
-	for _, result := range pylintResults {
-		issue := l.convertPylintResult(result)
-		issues = append(issues, issue)
+	// Suggestions are only worth keeping when their fix applies to the notebook
+	suggestions := make([]Issue, 0, len(result.Suggestions))
+	for _, suggestion := range result.Suggestions {
+		if mapped, ok := nb.mapIssue(source, suggestion); ok && mapped.Fix != nil {
+			suggestions = append(suggestions, mapped)
+		}
 	}
 	
-	return issues, nil
+	result.Issues = issues
+	result.Suggestions = suggestions
+	return result, nil
 }
 
-// SuggestFixes attempts to generate fixes for the identified issues
-func (l *PythonLinter) SuggestFixes(ctx context.Context, code string, issues []Issue) ([]Issue, error) {
+// findIssues analyzes the code and returns issues
+func (l *PythonLinter) findIssues(ctx context.Context, code string, options map[string]interface{}) ([]Issue, error) {
 	// Create a temporary file for the code
 	tmpFile, err := ioutil.TempFile("", "codehawk-*.py")
 	if err != nil {
@@ -120,80 +183,87 @@ func (l *PythonLinter) SuggestFixes(ctx context.Context, code string, issues []I
 		return nil, l.WrapError(err, "failed to close temporary file")
 	}
 	
-	// Run pycodestyle for suggestions
-	cmd := exec.CommandContext(
-		ctx,
-		"pycodestyle",
-		"--format=%(path)s:%(row)d:%(col)d: %(code)s %(text)s",
-		tmpFile.Name(),
-	)
+	backends, err := l.selectBackends(ctx, options)
+	if err != nil {
+		return nil, err
+	}
 	
-	var stdout, stderr bytes.Buffer
-	cmd.Stdout = &stdout
-	cmd.Stderr = &stderr
+	// Run every selected backend and combine the results
+	issues := make([]Issue, 0)
+	var lastErr error
+	failed := 0
+	for _, backend := range backends {
+		backendIssues, err := backend.Run(ctx, tmpFile.Name(), code, options)
+		if err != nil {
+			// Log the error but continue with the other backends
+			fmt.Printf("Warning: %s failed: %v\n", backend.Name(), err)
+			lastErr = err
+			failed++
+			continue
+		}
+		issues = append(issues, backendIssues...)
+	}
 	
-	// Execute pycodestyle
-	err = cmd.Run()
-	if err != nil && err.Error() != "exit status 1" {
-		// exit status 1 is normal when it finds issues
-		return nil, l.WrapError(fmt.Errorf("failed to run pycodestyle: %w, stderr: %s", err, stderr.String()), "pycodestyle execution error")
+	if failed == len(backends) && lastErr != nil {
+		return nil, lastErr
 	}
 	
-	// Parse the output and generate suggestions
+	return issues, nil
+}
+
+// SuggestFixes attempts to generate fixes for the identified issues, formatting the code as
+// configured for the linter
+func (l *PythonLinter) SuggestFixes(ctx context.Context, code string, issues []Issue) ([]Issue, error) {
+	return l.suggestFixes(ctx, code, issues, nil)
+}
+
+// suggestFixes generates fixes for the issues, formatting the code with the formatter and line
+// length of the request options
+func (l *PythonLinter) suggestFixes(ctx context.Context, code string, issues []Issue, options map[string]interface{}) ([]Issue, error) {
 	suggestions := make([]Issue, 0)
-	lines := strings.Split(stdout.String(), "\n")
 	
-	for _, line := range lines {
-		if line == "" {
-			continue
-		}
-		
-		parts := strings.Split(line, ": ")
-		if len(parts) < 2 {
-			continue
-		}
-		
-		// Parse location
-		locParts := strings.Split(parts[0], ":")
-		if len(locParts) < 3 {
-			continue
-		}
-		
-		lineNum, err := strconv.Atoi(locParts[1])
-		if err != nil {
-			continue
-		}
-		
-		colNum, err := strconv.Atoi(locParts[2])
-		if err != nil {
-			continue
-		}
-		
-		// Parse code and message
-		codeMsgParts := strings.SplitN(parts[1], " ", 2)
-		if len(codeMsgParts) < 2 {
-			continue
+	// Turn each hunk of the formatter's output into a precise fix
+	formatter := l.GetStringOption(options, "formatter", "black")
+	formatted, err := l.FormatCode(ctx, code, options)
+	if err != nil {
+		// Log the error but still offer fixes for individual issues
+		fmt.Printf("Warning: %s failed: %v\n", formatter, err)
+	} else {
+		hunkLines, fixes := hunkFixes(code, formatted, fmt.Sprintf("Format code with %s", formatter))
+		for i, fix := range fixes {
+			suggestions = append(suggestions, Issue{
+				Line:     hunkLines[i],
+				Message:  "Code formatting issue",
+				Severity: "suggestion",
+				RuleID:   formatter,
+				Fix:      fix,
+			})
 		}
 		
-		ruleID := codeMsgParts[0]
-		message := codeMsgParts[1]
-		
-		// Generate a suggestion
-		suggestion := Issue{
-			Line:     lineNum,
-			Column:   &colNum,
-			Message:  fmt.Sprintf("%s: %s", ruleID, message),
-			Severity: "suggestion",
-			RuleID:   ruleID,
-			Fix:      l.generateFix(code, lineNum, colNum, ruleID),
+		// Formatting issues reported by the linters are fixed by the hunk covering their line
+		for _, issue := range issues {
+			if issue.Fix != nil || !isPythonFormattingRule(issue.RuleID) {
+				continue
+			}
+			for _, fix := range fixes {
+				if fixCoversLine(fix, issue.Line) {
+					suggestions = append(suggestions, Issue{
+						Line:     issue.Line,
+						Column:   issue.Column,
+						Message:  fmt.Sprintf("Suggested fix for: %s", issue.Message),
+						Severity: "suggestion",
+						RuleID:   issue.RuleID,
+						Fix:      fix,
+					})
+					break
+				}
+			}
 		}
-		
-		suggestions = append(suggestions, suggestion)
 	}
 	
 	// Also generate suggestions for existing issues
 	for _, issue := range issues {
-		fix := l.generateFix(code, issue.Line, issue.Column != nil ? *issue.Column : 0, issue.RuleID)
+		fix := l.generateFix(code, issue.Line, issue.RuleID)
 		if fix != nil {
 			suggestion := Issue{
 				Line:     issue.Line,
@@ -210,43 +280,73 @@ func (l *PythonLinter) SuggestFixes(ctx context.Context, code string, issues []I
 	return suggestions, nil
 }
 
-// convertPylintResult converts a pylint result to a CodeHawk issue
-func (l *PythonLinter) convertPylintResult(result map[string]interface{}) Issue {
-	// Extract basic information
-	line := int(result["line"].(float64))
-	column := int(result["column"].(float64))
-	messageID := result["message-id"].(string)
-	message := result["message"].(string)
-	symbol := result["symbol"].(string)
-	
-	// Map pylint category to CodeHawk severity using BaseAnalyzer method
-	var severity string
-	switch result["type"] {
-	case "error", "fatal":
-		severity = "error"
-	case "warning":
-		severity = "warning"
-	case "convention", "refactor":
-		severity = "suggestion"
+// FormatCode formats code with the configured formatter, either black or ruff
+func (l *PythonLinter) FormatCode(ctx context.Context, code string, options map[string]interface{}) (string, error) {
+	ctx, cancel := l.CreateTimeoutContext(ctx)
+	defer cancel()
+	
+	formatter := l.GetStringOption(options, "formatter", "black")
+	lineLength := l.GetIntOption(options, "lineLength", 88)
+	quoteStyle := l.GetStringOption(options, "quoteStyle", "double")
+	
+	var cmd *exec.Cmd
+	switch formatter {
+	case "black":
+		args := []string{"--quiet", "--line-length", strconv.Itoa(lineLength)}
+		// black only emits double quotes; single quotes are kept by skipping normalization
+		if quoteStyle == "single" {
+			args = append(args, "--skip-string-normalization")
+		}
+		args = append(args, "-")
+		cmd = exec.CommandContext(ctx, l.GetStringOption(nil, "blackPath", "black"), args...)
+	case "ruff":
+		cmd = exec.CommandContext(
+			ctx,
+			l.GetStringOption(nil, "ruffPath", "ruff"),
+			"format",
+			"--no-cache",
+			"--line-length", strconv.Itoa(lineLength),
+			"--config", fmt.Sprintf("format.quote-style = %q", quoteStyle),
+			"--stdin-filename", "code.py",
+		)
 	default:
-		severity = "info"
+		return "", fmt.Errorf("unknown Python formatter: %s", formatter)
 	}
 	
-	// Create the issue
-	issue := Issue{
-		Line:     line,
-		Column:   &column,
-		Message:  message,
-		Severity: severity,
-		RuleID:   messageID,
-		Context:  symbol,
+	var stdout, stderr bytes.Buffer
+	cmd.Stdin = strings.NewReader(code)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	
+	// Both formatters exit non-zero when the code cannot be parsed
+	if err := cmd.Run(); err != nil {
+		return "", l.WrapError(formatterRunError(ctx, formatter, err, stderr.String()), "formatter execution error")
 	}
 	
-	return issue
+	return stdout.String(), nil
+}
+
+// isPythonFormattingRule reports whether a pylint, pycodestyle or ruff rule concerns formatting only
+func isPythonFormattingRule(ruleID string) bool {
+	switch ruleID {
+	case "C0301", "C0303", "C0304", "C0305", "C0321", "W0311", "W0301",
+		"line-too-long", "trailing-whitespace", "missing-final-newline", "trailing-newlines",
+		"multiple-statements", "bad-indentation", "unnecessary-semicolon":
+		return true
+	}
+	
+	// pycodestyle codes as reported by ruff: indentation, whitespace, blank lines and line length
+	for _, prefix := range []string{"E1", "E2", "E3", "E5", "E70", "W1", "W2", "W3", "W5", "COM"} {
+		if strings.HasPrefix(ruleID, prefix) {
+			return true
+		}
+	}
+	
+	return false
 }
 
 // generateFix attempts to generate a fix for a specific issue
-func (l *PythonLinter) generateFix(code string, line, column int, ruleID string) *IssueFix {
+func (l *PythonLinter) generateFix(code string, line int, ruleID string) *IssueFix {
 	// Split the code into lines
 	lines := strings.Split(code, "\n")
 	if line <= 0 || line > len(lines) {
@@ -259,44 +359,6 @@ func (l *PythonLinter) generateFix(code string, line, column int, ruleID string)
 	
 	// Generate fixes based on rule ID
 	switch ruleID {
-	case "E201": // Whitespace after '('
-		if column <= 0 || column >= len(lineText) {
-			return nil
-		}
-		replacement := lineText[:column] + lineText[column+1:]
-		return &IssueFix{
-			Description: "Remove whitespace after '('",
-			Replacement: replacement,
-		}
-		
-	case "E202": // Whitespace before ')'
-		if column <= 1 || column >= len(lineText) {
-			return nil
-		}
-		replacement := lineText[:column-1] + lineText[column:]
-		return &IssueFix{
-			Description: "Remove whitespace before ')'",
-			Replacement: replacement,
-		}
-		
-	case "E225": // Missing whitespace around operator
-		if column <= 0 || column >= len(lineText)-1 {
-			return nil
-		}
-		// Try to identify the operator
-		op := lineText[column : column+1]
-		replacement := lineText[:column] + " " + op + " " + lineText[column+1:]
-		return &IssueFix{
-			Description: "Add whitespace around operator",
-			Replacement: replacement,
-		}
-		
-	case "E302": // Expected 2 blank lines
-		return &IssueFix{
-			Description: "Add 2 blank lines",
-			Replacement: lineText + "\n\n",
-		}
-		
 	case "C0111", "missing-docstring":
 		indent := getIndentation(lineText)
 		
@@ -0,0 +1,253 @@
+package analyzer
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// defaultScoreWeights are the points an issue costs by severity, the multipliers applied by
+// category, the weight of poorly maintainable functions and the size the costs are scaled to
+var defaultScoreWeights = map[string]float64{
+	"error":      10,
+	"warning":    3,
+	"suggestion": 1,
+	"info":       0.5,
+	
+	"security":        2,
+	"correctness":     1.5,
+	"performance":     1,
+	"maintainability": 1,
+	"compatibility":   1,
+	"style":           0.5,
+	
+	"metrics":      5,
+	"sizeBaseline": 100,
+}
+
+// gradeThresholds map the minimum score of each grade
+var gradeThresholds = []struct {
+	grade string
+	score float64
+}{
+	{"A", 90},
+	{"B", 80},
+	{"C", 70},
+	{"D", 60},
+	{"F", 0},
+}
+
+// maintainableIndex is the maintainability index from which a function costs no points
+const maintainableIndex = 65
+
+// QualityScore is the weighted quality score of an analysis, from 0 to 100, with its grade and
+// the deductions that make up the difference from 100
+type QualityScore struct {
+	Score     float64          `json:"score"`
+	Grade     string           `json:"grade"`
+	Breakdown []ScoreDeduction `json:"breakdown"`
+}
+
+// ScoreDeduction is the points a rule, or the code metrics, cost the score
+type ScoreDeduction struct {
+	RuleID   string  `json:"ruleId"`
+	Category string  `json:"category,omitempty"`
+	Severity string  `json:"severity,omitempty"`
+	Count    int     `json:"count"`
+	Points   float64 `json:"points"`
+	Lines    []int   `json:"lines,omitempty"`
+	Reason   string  `json:"reason"`
+}
+
+// QualityScorer grades analyses. The scoreWeights entry of the configuration overrides default
+// weights for every analysis, and the organization's scoreWeights setting those for the analyses
+// of one organization, both as a list of key=value entries such as "error=12,security=3".
+// Requests cannot change the weights, so that grades compare across an organization.
+type QualityScorer struct {
+	*BaseAnalyzer
+}
+
+// NewQualityScorer creates a new quality scorer
+func NewQualityScorer(config map[string]string) *QualityScorer {
+	return &QualityScorer{
+		BaseAnalyzer: NewBaseAnalyzer(config),
+	}
+}
+
+// Score computes the quality score of code from its issues and metrics. Every issue costs the
+// weight of its severity times the multiplier of its category, functions with a low
+// maintainability index cost up to the metrics weight each, and the total is scaled down for
+// code longer than the size baseline so that scores of small and large files compare. The
+// score is nil when the qualityScore option is turned off. The weights are those of the
+// organization the analysis of ctx runs for, if any.
+func (q *QualityScorer) Score(ctx context.Context, code string, issues []Issue, metrics *CodeMetrics, options map[string]interface{}) *QualityScore {
+	if !q.GetBoolOption(options, "qualityScore", true) {
+		return nil
+	}
+	weights := q.weights(ctx)
+	
+	linesOfCode := 0
+	if metrics != nil {
+		linesOfCode = metrics.File.LinesOfCode
+	} else {
+		for _, line := range strings.Split(code, "\n") {
+			if strings.TrimSpace(line) != "" {
+				linesOfCode++
+			}
+		}
+	}
+	scale := 1.0
+	if baseline := weights["sizeBaseline"]; baseline > 0 && float64(linesOfCode) > baseline {
+		scale = baseline / float64(linesOfCode)
+	}
+	
+	// Issues are grouped by rule, so the breakdown shows which rules cost the most
+	deductions := make(map[string]*ScoreDeduction)
+	order := make([]string, 0)
+	for _, issue := range issues {
+		severity := q.MapSeverity(issue.Severity)
+		points := weights[severity] * scale
+		if multiplier, ok := weights[strings.ToLower(issue.Category)]; ok {
+			points *= multiplier
+		}
+		if points <= 0 {
+			continue
+		}
+		
+		ruleID := issue.RuleID
+		if ruleID == "" {
+			ruleID = "unknown"
+		}
+		key := ruleID + "\x00" + severity
+		deduction, ok := deductions[key]
+		if !ok {
+			deduction = &ScoreDeduction{
+				RuleID:   ruleID,
+				Category: issue.Category,
+				Severity: severity,
+			}
+			deductions[key] = deduction
+			order = append(order, key)
+		}
+		deduction.Count++
+		deduction.Points += points
+		if issue.Line > 0 {
+			deduction.Lines = append(deduction.Lines, issue.Line)
+		}
+	}
+	
+	breakdown := make([]ScoreDeduction, 0, len(order)+1)
+	for _, key := range order {
+		deduction := deductions[key]
+		noun := "issues"
+		if deduction.Count == 1 {
+			noun = "issue"
+		}
+		deduction.Reason = fmt.Sprintf("%d %s %s", deduction.Count, deduction.Severity, noun)
+		if deduction.Category != "" {
+			deduction.Reason = fmt.Sprintf("%d %s %s %s", deduction.Count, deduction.Category, deduction.Severity, noun)
+		}
+		breakdown = append(breakdown, *deduction)
+	}
+	
+	if metrics != nil && weights["metrics"] > 0 {
+		deduction := ScoreDeduction{RuleID: "metrics", Category: CategoryMaintainability}
+		for _, function := range metrics.Functions {
+			if function.MaintainabilityIndex >= maintainableIndex {
+				continue
+			}
+			deduction.Count++
+			deduction.Points += weights["metrics"] * (maintainableIndex - function.MaintainabilityIndex) / maintainableIndex * scale
+			deduction.Lines = append(deduction.Lines, function.Line)
+		}
+		if deduction.Count > 0 {
+			deduction.Reason = fmt.Sprintf("%d functions with a maintainability index below %d", deduction.Count, maintainableIndex)
+			if deduction.Count == 1 {
+				deduction.Reason = fmt.Sprintf("1 function with a maintainability index below %d", maintainableIndex)
+			}
+			breakdown = append(breakdown, deduction)
+		}
+	}
+	
+	total := 0.0
+	for i := range breakdown {
+		total += breakdown[i].Points
+		breakdown[i].Points = roundScore(breakdown[i].Points)
+	}
+	sort.SliceStable(breakdown, func(i, j int) bool {
+		return breakdown[i].Points > breakdown[j].Points
+	})
+	
+	score := roundScore(math.Max(0, 100-total))
+	return &QualityScore{
+		Score:     score,
+		Grade:     scoreGrade(score),
+		Breakdown: breakdown,
+	}
+}
+
+// weights returns the default weights overridden by the configured weights, and those by the
+// weights of the organization the analysis of ctx runs for
+func (q *QualityScorer) weights(ctx context.Context) map[string]float64 {
+	weights := make(map[string]float64, len(defaultScoreWeights))
+	for key, value := range defaultScoreWeights {
+		weights[key] = value
+	}
+	
+	lists := []string{q.GetStringOption(nil, "scoreWeights", "")}
+	if organizationWeights, ok := q.GetOrganizationSetting(ctx, "scoreWeights"); ok {
+		lists = append(lists, organizationWeights)
+	}
+	for _, list := range lists {
+		for _, entry := range strings.Split(list, ",") {
+			parts := strings.SplitN(entry, "=", 2)
+			if len(parts) != 2 {
+				continue
+			}
+			if value, err := strconv.ParseFloat(strings.TrimSpace(parts[1]), 64); err == nil {
+				weights[strings.TrimSpace(parts[0])] = value
+			}
+		}
+	}
+	return weights
+}
+
+// ValidateScoreWeights checks a list of score weights before an organization saves it: every
+// entry must set a known weight to a number that is not negative
+func ValidateScoreWeights(list string) error {
+	for _, entry := range strings.Split(list, ",") {
+		if strings.TrimSpace(entry) == "" {
+			continue
+		}
+		parts := strings.SplitN(entry, "=", 2)
+		if len(parts) != 2 {
+			return fmt.Errorf("invalid score weight %q, want key=value", strings.TrimSpace(entry))
+		}
+		key := strings.TrimSpace(parts[0])
+		if _, ok := defaultScoreWeights[key]; !ok {
+			return fmt.Errorf("unknown score weight %q", key)
+		}
+		if value, err := strconv.ParseFloat(strings.TrimSpace(parts[1]), 64); err != nil || value < 0 || math.IsInf(value, 0) || math.IsNaN(value) {
+			return fmt.Errorf("score weight %s must be a number of at least 0", key)
+		}
+	}
+	return nil
+}
+
+// scoreGrade maps a score to its letter grade
+func scoreGrade(score float64) string {
+	for _, threshold := range gradeThresholds {
+		if score >= threshold.score {
+			return threshold.grade
+		}
+	}
+	return "F"
+}
+
+// roundScore rounds points to one decimal
+func roundScore(points float64) float64 {
+	return math.Round(points*10) / 10
+}
@@ -0,0 +1,255 @@
+package analyzer
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestQualityScorerScore(t *testing.T) {
+	tests := []struct {
+		name          string
+		code          string
+		issues        []Issue
+		metrics       *CodeMetrics
+		config        map[string]string
+		organization  string
+		settings      map[string]string
+		options       map[string]interface{}
+		wantScore     float64
+		wantGrade     string
+		wantBreakdown []ScoreDeduction
+	}{
+		{
+			name:          "no issues",
+			code:          "x := 1\n",
+			wantScore:     100,
+			wantGrade:     "A",
+			wantBreakdown: []ScoreDeduction{},
+		},
+		{
+			name:      "security multiplier",
+			code:      "x := 1\n",
+			issues:    []Issue{{Line: 3, Severity: "error", RuleID: "G101", Category: CategorySecurity}},
+			wantScore: 80,
+			wantGrade: "B",
+			wantBreakdown: []ScoreDeduction{
+				{RuleID: "G101", Category: CategorySecurity, Severity: "error", Count: 1, Points: 20, Lines: []int{3}, Reason: "1 security error issue"},
+			},
+		},
+		{
+			name: "issues are grouped by rule and ordered by points",
+			code: "x := 1\n",
+			issues: []Issue{
+				{Severity: "information", Message: "no rule"},
+				{Line: 1, Severity: "warning", RuleID: "indent", Category: CategoryStyle},
+				{Line: 4, Severity: "warn", RuleID: "indent", Category: CategoryStyle},
+			},
+			wantScore: 96.5,
+			wantGrade: "A",
+			wantBreakdown: []ScoreDeduction{
+				{RuleID: "indent", Category: CategoryStyle, Severity: "warning", Count: 2, Points: 3, Lines: []int{1, 4}, Reason: "2 style warning issues"},
+				{RuleID: "unknown", Severity: "info", Count: 1, Points: 0.5, Reason: "1 info issue"},
+			},
+		},
+		{
+			name:      "long code is scaled to the baseline",
+			code:      strings.Repeat("x := 1\n\n", 200),
+			issues:    []Issue{{Line: 9, Severity: "critical", RuleID: "nil-deref", Category: CategoryCorrectness}},
+			wantScore: 92.5,
+			wantGrade: "A",
+			wantBreakdown: []ScoreDeduction{
+				{RuleID: "nil-deref", Category: CategoryCorrectness, Severity: "error", Count: 1, Points: 7.5, Lines: []int{9}, Reason: "1 correctness error issue"},
+			},
+		},
+		{
+			name: "poorly maintainable functions",
+			code: "ignored when there are metrics\n",
+			metrics: &CodeMetrics{
+				File: FileMetrics{LinesOfCode: 50},
+				Functions: []FunctionMetrics{
+					{Name: "tangled", Line: 2, MaintainabilityIndex: 32.5},
+					{Name: "clean", Line: 20, MaintainabilityIndex: 80},
+				},
+			},
+			wantScore: 97.5,
+			wantGrade: "A",
+			wantBreakdown: []ScoreDeduction{
+				{RuleID: "metrics", Category: CategoryMaintainability, Count: 1, Points: 2.5, Lines: []int{2}, Reason: "1 function with a maintainability index below 65"},
+			},
+		},
+		{
+			name:      "configured weights",
+			code:      "x := 1\n",
+			issues:    []Issue{{Line: 1, Severity: "error", RuleID: "E1", Category: CategoryCorrectness}},
+			config:    map[string]string{"scoreWeights": "error=20, correctness=1"},
+			wantScore: 80,
+			wantGrade: "B",
+			wantBreakdown: []ScoreDeduction{
+				{RuleID: "E1", Category: CategoryCorrectness, Severity: "error", Count: 1, Points: 20, Lines: []int{1}, Reason: "1 correctness error issue"},
+			},
+		},
+		{
+			name: "organization weights, where a zero weight skips the issue",
+			code: "x := 1\n",
+			issues: []Issue{
+				{Line: 1, Severity: "warning", RuleID: "W1"},
+				{Line: 2, Severity: "error", RuleID: "E1"},
+			},
+			config: map[string]string{
+				"scoreWeights":               "error=20",
+				OrganizationKey("scoreWeights", "org-1"):     "warning=0, error = 35, bogus",
+				OrganizationKey("scoreWeights", "org-other"): "error=1",
+			},
+			organization: "org-1",
+			wantScore:    65,
+			wantGrade:    "D",
+			wantBreakdown: []ScoreDeduction{
+				{RuleID: "E1", Severity: "error", Count: 1, Points: 35, Lines: []int{2}, Reason: "1 error issue"},
+			},
+		},
+		{
+			name:         "weights the organization set itself",
+			code:         "x := 1\n",
+			issues:       []Issue{{Line: 2, Severity: "error", RuleID: "E1"}},
+			config:       map[string]string{OrganizationKey("scoreWeights", "org-1"): "error=30"},
+			organization: "org-1",
+			settings:     map[string]string{"scoreWeights": "error=15"},
+			wantScore:    85,
+			wantGrade:    "B",
+			wantBreakdown: []ScoreDeduction{
+				{RuleID: "E1", Severity: "error", Count: 1, Points: 15, Lines: []int{2}, Reason: "1 error issue"},
+			},
+		},
+		{
+			name:         "organization without weights",
+			code:         "x := 1\n",
+			issues:       []Issue{{Line: 2, Severity: "error", RuleID: "E1"}},
+			config:       map[string]string{OrganizationKey("scoreWeights", "org-other"): "error=1"},
+			organization: "org-1",
+			wantScore:    90,
+			wantGrade:    "A",
+			wantBreakdown: []ScoreDeduction{
+				{RuleID: "E1", Severity: "error", Count: 1, Points: 10, Lines: []int{2}, Reason: "1 error issue"},
+			},
+		},
+		{
+			name:      "requests cannot change the weights",
+			code:      "x := 1\n",
+			issues:    []Issue{{Line: 2, Severity: "error", RuleID: "E1"}},
+			config:    map[string]string{OrganizationKey("scoreWeights", "org-1"): "error=30"},
+			options:   map[string]interface{}{"scoreWeights": "error=0", OrganizationKey("scoreWeights", "org-1"): "error=0"},
+			wantScore: 90,
+			wantGrade: "A",
+			wantBreakdown: []ScoreDeduction{
+				{RuleID: "E1", Severity: "error", Count: 1, Points: 10, Lines: []int{2}, Reason: "1 error issue"},
+			},
+		},
+		{
+			name: "score does not go below zero",
+			code: "x := 1\n",
+			issues: []Issue{
+				{Severity: "error", RuleID: "G1", Category: CategorySecurity},
+				{Severity: "error", RuleID: "G1", Category: CategorySecurity},
+				{Severity: "error", RuleID: "G1", Category: CategorySecurity},
+				{Severity: "error", RuleID: "G1", Category: CategorySecurity},
+				{Severity: "error", RuleID: "G1", Category: CategorySecurity},
+				{Severity: "error", RuleID: "G1", Category: CategorySecurity},
+			},
+			wantScore: 0,
+			wantGrade: "F",
+			wantBreakdown: []ScoreDeduction{
+				{RuleID: "G1", Category: CategorySecurity, Severity: "error", Count: 6, Points: 120, Reason: "6 security error issues"},
+			},
+		},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := WithOrganization(context.Background(), tt.organization, tt.settings)
+			got := NewQualityScorer(tt.config).Score(ctx, tt.code, tt.issues, tt.metrics, tt.options)
+			if got == nil {
+				t.Fatal("got no score")
+			}
+			if got.Score != tt.wantScore || got.Grade != tt.wantGrade {
+				t.Errorf("got %v (%s), want %v (%s)", got.Score, got.Grade, tt.wantScore, tt.wantGrade)
+			}
+			if !reflect.DeepEqual(got.Breakdown, tt.wantBreakdown) {
+				t.Errorf("got breakdown %+v, want %+v", got.Breakdown, tt.wantBreakdown)
+			}
+		})
+	}
+}
+
+func TestQualityScorerDisabled(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  map[string]string
+		options map[string]interface{}
+	}{
+		{name: "option", options: map[string]interface{}{"qualityScore": false}},
+		{name: "configuration", config: map[string]string{"qualityScore": "false"}},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NewQualityScorer(tt.config).Score(context.Background(), "x\n", nil, nil, tt.options); got != nil {
+				t.Errorf("got %+v, want no score", got)
+			}
+		})
+	}
+}
+
+func TestValidateScoreWeights(t *testing.T) {
+	tests := []struct {
+		list    string
+		wantErr string
+	}{
+		{list: ""},
+		{list: "error=12, security=3,"},
+		{list: "warning=0"},
+		{list: "bogus", wantErr: "want key=value"},
+		{list: "errors=1", wantErr: "unknown score weight"},
+		{list: "error=-1", wantErr: "at least 0"},
+		{list: "error=NaN", wantErr: "at least 0"},
+		{list: "error=many", wantErr: "at least 0"},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.list, func(t *testing.T) {
+			err := ValidateScoreWeights(tt.list)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("ValidateScoreWeights() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("ValidateScoreWeights() error = %v, want one mentioning %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestScoreGrade(t *testing.T) {
+	tests := []struct {
+		score float64
+		want  string
+	}{
+		{100, "A"},
+		{90, "A"},
+		{89.9, "B"},
+		{80, "B"},
+		{70, "C"},
+		{60, "D"},
+		{59.9, "F"},
+		{0, "F"},
+	}
+	
+	for _, tt := range tests {
+		if got := scoreGrade(tt.score); got != tt.want {
+			t.Errorf("scoreGrade(%v) = %s, want %s", tt.score, got, tt.want)
+		}
+	}
+}
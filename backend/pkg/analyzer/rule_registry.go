@@ -0,0 +1,406 @@
+package analyzer
+
+import (
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// Rule categories, as described in docs/concepts.md
+const (
+	CategoryCorrectness     = "correctness"
+	CategoryStyle           = "style"
+	CategoryMaintainability = "maintainability"
+	CategoryPerformance     = "performance"
+	CategorySecurity        = "security"
+	CategoryCompatibility   = "compatibility"
+)
+
+// RuleMetadata describes a rule for reports and quality gates. DocURL may contain {id}, which
+// is replaced by the rule ID, or {name}, replaced by the part of the ID after the last :: or /.
+type RuleMetadata struct {
+	Category string   `json:"category,omitempty"`
+	Tags     []string `json:"tags,omitempty"`
+	CWE      string   `json:"cwe,omitempty"`
+	OWASP    string   `json:"owasp,omitempty"`
+	DocURL   string   `json:"docUrl,omitempty"`
+}
+
+// ruleFamily gives metadata to every rule of a tool whose ID matches a pattern, for tools with
+// too many rules to list one by one
+type ruleFamily struct {
+	languages map[string]bool
+	pattern   *regexp.Regexp
+	metadata  RuleMetadata
+}
+
+// RuleRegistry maps linter rules to their metadata. Rules are looked up by ID, first for the
+// issue's language and then for any language; rules that are not registered by ID get the
+// metadata of the families they match. Each match only fills the fields the earlier ones left
+// empty.
+type RuleRegistry struct {
+	rules    map[string]RuleMetadata
+	families []ruleFamily
+	mu       sync.RWMutex
+}
+
+// NewRuleRegistry creates a new, empty rule registry
+func NewRuleRegistry() *RuleRegistry {
+	return &RuleRegistry{
+		rules: make(map[string]RuleMetadata),
+	}
+}
+
+// Register adds the metadata of a rule. An empty language registers it for every language.
+func (r *RuleRegistry) Register(language, ruleID string, metadata RuleMetadata) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.rules[language+"/"+ruleID] = metadata
+}
+
+// RegisterFamily adds metadata for every rule whose ID matches pattern in the given languages,
+// or in every language when none are given
+func (r *RuleRegistry) RegisterFamily(pattern string, metadata RuleMetadata, languages ...string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	family := ruleFamily{
+		pattern:  regexp.MustCompile(pattern),
+		metadata: metadata,
+	}
+	if len(languages) > 0 {
+		family.languages = make(map[string]bool)
+		for _, language := range languages {
+			family.languages[language] = true
+		}
+	}
+	r.families = append(r.families, family)
+}
+
+// Lookup returns the metadata of a rule of the language
+func (r *RuleRegistry) Lookup(language, ruleID string) (RuleMetadata, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	
+	var metadata RuleMetadata
+	found := false
+	for _, key := range []string{language + "/" + ruleID, "/" + ruleID} {
+		if registered, ok := r.rules[key]; ok {
+			mergeRuleMetadata(&metadata, registered)
+			found = true
+		}
+	}
+	// Families only describe the rules that are not registered by ID
+	if !found {
+		for _, family := range r.families {
+			if (family.languages == nil || family.languages[language]) && family.pattern.MatchString(ruleID) {
+				mergeRuleMetadata(&metadata, family.metadata)
+				found = true
+			}
+		}
+	}
+	
+	if metadata.DocURL != "" {
+		name := ruleID
+		if i := strings.LastIndexAny(ruleID, ":/"); i >= 0 {
+			name = ruleID[i+1:]
+		}
+		metadata.DocURL = strings.NewReplacer("{id}", ruleID, "{name}", name).Replace(metadata.DocURL)
+	}
+	return metadata, found
+}
+
+// Annotate fills in the category, tags, CWE, OWASP and documentation URL of issues from their
+// rules' metadata. Fields a linter already set are kept, a CWE without an OWASP category gets
+// the one it falls under, and a URL in the context serves as the documentation URL.
+func (r *RuleRegistry) Annotate(language string, issues []Issue) {
+	for i := range issues {
+		issue := &issues[i]
+		if metadata, ok := r.Lookup(language, issue.RuleID); ok {
+			if issue.Category == "" {
+				issue.Category = metadata.Category
+			}
+			if len(issue.Tags) == 0 {
+				issue.Tags = metadata.Tags
+			}
+			if issue.CWE == "" {
+				issue.CWE = metadata.CWE
+			}
+			if issue.OWASP == "" {
+				issue.OWASP = metadata.OWASP
+			}
+			if issue.DocURL == "" {
+				issue.DocURL = metadata.DocURL
+			}
+		}
+		
+		if issue.OWASP == "" && issue.CWE != "" {
+			issue.OWASP = cweOWASP[issue.CWE]
+		}
+		if issue.DocURL == "" && (strings.HasPrefix(issue.Context, "https://") || strings.HasPrefix(issue.Context, "http://")) {
+			issue.DocURL = issue.Context
+		}
+	}
+}
+
+// mergeRuleMetadata fills the empty fields of metadata from other
+func mergeRuleMetadata(metadata *RuleMetadata, other RuleMetadata) {
+	if metadata.Category == "" {
+		metadata.Category = other.Category
+	}
+	if len(metadata.Tags) == 0 {
+		metadata.Tags = other.Tags
+	}
+	if metadata.CWE == "" {
+		metadata.CWE = other.CWE
+	}
+	if metadata.OWASP == "" {
+		metadata.OWASP = other.OWASP
+	}
+	if metadata.DocURL == "" {
+		metadata.DocURL = other.DocURL
+	}
+}
+
+// OWASP Top 10 (2021) categories
+const (
+	owaspBrokenAccessControl   = "A01:2021-Broken Access Control"
+	owaspCryptographicFailures = "A02:2021-Cryptographic Failures"
+	owaspInjection             = "A03:2021-Injection"
+	owaspInsecureDesign        = "A04:2021-Insecure Design"
+	owaspMisconfiguration      = "A05:2021-Security Misconfiguration"
+	owaspOutdatedComponents    = "A06:2021-Vulnerable and Outdated Components"
+	owaspAuthFailures          = "A07:2021-Identification and Authentication Failures"
+	owaspIntegrityFailures     = "A08:2021-Software and Data Integrity Failures"
+	owaspSSRF                  = "A10:2021-Server-Side Request Forgery"
+)
+
+// cweOWASP maps the CWEs reported by the security tools to the OWASP Top 10 category they fall under
+var cweOWASP = map[string]string{
+	"CWE-22":   owaspBrokenAccessControl,
+	"CWE-200":  owaspBrokenAccessControl,
+	"CWE-276":  owaspBrokenAccessControl,
+	"CWE-284":  owaspBrokenAccessControl,
+	"CWE-352":  owaspBrokenAccessControl,
+	"CWE-732":  owaspBrokenAccessControl,
+	"CWE-261":  owaspCryptographicFailures,
+	"CWE-310":  owaspCryptographicFailures,
+	"CWE-319":  owaspCryptographicFailures,
+	"CWE-321":  owaspCryptographicFailures,
+	"CWE-326":  owaspCryptographicFailures,
+	"CWE-327":  owaspCryptographicFailures,
+	"CWE-328":  owaspCryptographicFailures,
+	"CWE-330":  owaspCryptographicFailures,
+	"CWE-338":  owaspCryptographicFailures,
+	"CWE-20":   owaspInjection,
+	"CWE-74":   owaspInjection,
+	"CWE-77":   owaspInjection,
+	"CWE-78":   owaspInjection,
+	"CWE-79":   owaspInjection,
+	"CWE-89":   owaspInjection,
+	"CWE-94":   owaspInjection,
+	"CWE-95":   owaspInjection,
+	"CWE-1336": owaspInjection,
+	"CWE-209":  owaspInsecureDesign,
+	"CWE-377":  owaspInsecureDesign,
+	"CWE-703":  owaspInsecureDesign,
+	"CWE-16":   owaspMisconfiguration,
+	"CWE-250":  owaspMisconfiguration,
+	"CWE-611":  owaspMisconfiguration,
+	"CWE-1104": owaspOutdatedComponents,
+	"CWE-259":  owaspAuthFailures,
+	"CWE-295":  owaspAuthFailures,
+	"CWE-798":  owaspAuthFailures,
+	"CWE-494":  owaspIntegrityFailures,
+	"CWE-502":  owaspIntegrityFailures,
+	"CWE-918":  owaspSSRF,
+}
+
+// RegisterDefaultRules registers the metadata of CodeHawk's own rules and of the rules of the
+// tools behind the default linters
+func (r *RuleRegistry) RegisterDefaultRules() {
+	rule := func(category string, tags ...string) RuleMetadata {
+		return RuleMetadata{Category: category, Tags: tags}
+	}
+	security := func(cwe string, tags ...string) RuleMetadata {
+		return RuleMetadata{Category: CategorySecurity, Tags: tags, CWE: cwe, OWASP: cweOWASP[cwe]}
+	}
+	
+	// Language-agnostic checks
+	r.Register("", "line-too-long", rule(CategoryStyle, "formatting"))
+	r.Register("", "trailing-whitespace", rule(CategoryStyle, "formatting"))
+	r.Register("", "mixed-indentation", rule(CategoryStyle, "formatting"))
+	r.Register("", "mixed-line-endings", rule(CategoryStyle, "formatting"))
+	r.Register("", "missing-final-newline", rule(CategoryStyle, "formatting"))
+	r.Register("", "byte-order-mark", rule(CategoryCompatibility, "encoding"))
+	r.Register("", "invalid-encoding", rule(CategoryCompatibility, "encoding"))
+	r.Register("", "file-too-long", rule(CategoryMaintainability, "size"))
+	r.Register("", "todo-comment", rule(CategoryMaintainability, "todo"))
+	r.Register("", "syntax-error", rule(CategoryCorrectness, "syntax"))
+	r.Register("", "parse-error", rule(CategoryCorrectness, "syntax"))
+	r.Register("", "yaml-syntax-error", rule(CategoryCorrectness, "syntax"))
+	r.Register("", "hcl-syntax-error", rule(CategoryCorrectness, "syntax"))
+	r.Register("", "compile-error", rule(CategoryCorrectness))
+	r.Register("", "missing-dependency", rule(CategoryCorrectness, "dependencies"))
+	
+	// Code metrics
+	r.Register("", "cyclomatic-complexity", rule(CategoryMaintainability, "complexity"))
+	r.Register("", "cognitive-complexity", rule(CategoryMaintainability, "complexity"))
+	r.Register("", "deep-nesting", rule(CategoryMaintainability, "complexity"))
+	r.Register("", "function-length", rule(CategoryMaintainability, "size"))
+	r.Register("", "too-many-parameters", rule(CategoryMaintainability, "size"))
+	r.Register("", "maintainability-index", rule(CategoryMaintainability, "complexity"))
+	
+	// Secrets and taint analysis
+	r.RegisterFamily(`^secret-`, security("CWE-798", "secrets"))
+	r.Register("", "secret-private-key", security("CWE-321", "secrets"))
+	r.RegisterFamily(`^taint-`, RuleMetadata{Category: CategorySecurity, Tags: []string{"taint", "injection"}})
+	
+	// Go rule packs
+	for _, id := range []string{"goroutine-leak", "unstopped-ticker", "loop-var-capture", "mutex-copy", "select-missing-ctx-done"} {
+		r.Register("go", id, rule(CategoryCorrectness, "concurrency"))
+	}
+	for _, id := range []string{"error-string-compare", "error-equality-compare", "errorf-wrap-verb", "error-logged-and-dropped", "nil-error-nil-value"} {
+		r.Register("go", id, rule(CategoryCorrectness, "error-handling"))
+	}
+	
+	// Go tools: golangci-lint reports the linter's name, staticcheck its check code
+	r.Register("go", "gofmt", rule(CategoryStyle, "formatting"))
+	r.Register("go", "goimports", rule(CategoryStyle, "formatting"))
+	r.Register("go", "errcheck", rule(CategoryCorrectness, "error-handling"))
+	r.Register("go", "govet", rule(CategoryCorrectness))
+	r.Register("go", "staticcheck", rule(CategoryCorrectness))
+	r.Register("go", "typecheck", rule(CategoryCorrectness))
+	r.Register("go", "ineffassign", rule(CategoryCorrectness))
+	r.Register("go", "bodyclose", rule(CategoryCorrectness, "resources"))
+	r.Register("go", "gosimple", rule(CategoryMaintainability))
+	r.Register("go", "unused", rule(CategoryMaintainability, "unused"))
+	r.Register("go", "gocritic", rule(CategoryMaintainability))
+	r.Register("go", "revive", rule(CategoryStyle))
+	r.Register("go", "stylecheck", rule(CategoryStyle))
+	r.Register("go", "misspell", rule(CategoryStyle))
+	r.Register("go", "prealloc", rule(CategoryPerformance))
+	r.Register("go", "gosec", rule(CategorySecurity))
+	r.RegisterFamily(`^SA\d+$`, RuleMetadata{Category: CategoryCorrectness, DocURL: "https://staticcheck.dev/docs/checks/#{id}"}, "go")
+	r.RegisterFamily(`^S1\d+$`, RuleMetadata{Category: CategoryMaintainability, Tags: []string{"simplification"}, DocURL: "https://staticcheck.dev/docs/checks/#{id}"}, "go")
+	r.RegisterFamily(`^ST\d+$`, RuleMetadata{Category: CategoryStyle, DocURL: "https://staticcheck.dev/docs/checks/#{id}"}, "go")
+	r.RegisterFamily(`^(QF\d+|U1000)$`, RuleMetadata{Category: CategoryMaintainability, DocURL: "https://staticcheck.dev/docs/checks/#{id}"}, "go")
+	r.RegisterFamily(`^G\d{3}$`, RuleMetadata{Category: CategorySecurity, DocURL: "https://github.com/securego/gosec#available-rules"}, "go")
+	
+	// Python: ruff and pylint codes, bandit tests and mypy error codes
+	r.Register("python", "black", rule(CategoryStyle, "formatting"))
+	r.RegisterFamily(`^[EW]\d{3}$`, rule(CategoryStyle, "pycodestyle"), "python")
+	r.RegisterFamily(`^F\d{3}$`, rule(CategoryCorrectness, "pyflakes"), "python")
+	r.RegisterFamily(`^(N|D|I)\d{3}$`, rule(CategoryStyle), "python")
+	r.RegisterFamily(`^(C90|SIM)\d+$`, rule(CategoryMaintainability), "python")
+	r.RegisterFamily(`^PERF\d+$`, rule(CategoryPerformance), "python")
+	r.RegisterFamily(`^UP\d{3}$`, rule(CategoryCompatibility, "pyupgrade"), "python")
+	r.RegisterFamily(`^S\d{3}$`, rule(CategorySecurity), "python")
+	r.RegisterFamily(`^B[1-7]\d{2}$`, RuleMetadata{Category: CategorySecurity, Tags: []string{"bandit"}, DocURL: "https://bandit.readthedocs.io/en/latest/plugins/index.html"}, "python")
+	r.RegisterFamily(`^B[09]\d{2}$`, rule(CategoryCorrectness, "bugbear"), "python")
+	r.RegisterFamily(`^[EFW]\d{4}$`, rule(CategoryCorrectness, "pylint"), "python")
+	r.RegisterFamily(`^C\d{4}$`, rule(CategoryStyle, "pylint"), "python")
+	r.RegisterFamily(`^R\d{4}$`, rule(CategoryMaintainability, "pylint", "refactoring"), "python")
+	r.RegisterFamily(`^[a-z]+(-[a-z]+)*$`, RuleMetadata{Category: CategoryCorrectness, Tags: []string{"typing"}, DocURL: "https://mypy.readthedocs.io/en/stable/error_code_list.html"}, "python")
+	
+	// JavaScript and TypeScript: ESLint core and plugin rules and tsc diagnostics
+	eslint := func(metadata RuleMetadata) RuleMetadata {
+		metadata.DocURL = "https://eslint.org/docs/latest/rules/{id}"
+		return metadata
+	}
+	for _, language := range []string{"javascript", "typescript"} {
+		r.Register(language, "semi", eslint(rule(CategoryStyle, "formatting")))
+		r.Register(language, "quotes", eslint(rule(CategoryStyle, "formatting")))
+		r.Register(language, "indent", eslint(rule(CategoryStyle, "formatting")))
+		r.Register(language, "prettier", rule(CategoryStyle, "formatting"))
+		r.Register(language, "no-unused-vars", eslint(rule(CategoryMaintainability, "unused")))
+		r.Register(language, "no-console", eslint(rule(CategoryMaintainability)))
+		r.Register(language, "prefer-const", eslint(rule(CategoryMaintainability)))
+		r.Register(language, "eqeqeq", eslint(rule(CategoryCorrectness)))
+		r.Register(language, "no-undef", eslint(rule(CategoryCorrectness)))
+		r.Register(language, "no-unreachable", eslint(rule(CategoryCorrectness)))
+		r.Register(language, "no-dupe-keys", eslint(rule(CategoryCorrectness)))
+		r.Register(language, "no-empty", eslint(rule(CategoryCorrectness, "error-handling")))
+		r.Register(language, "no-eval", eslint(security("CWE-95")))
+		r.Register(language, "no-implied-eval", eslint(security("CWE-95")))
+	}
+	r.RegisterFamily(`^[a-z]+(-[a-z]+)*$`, RuleMetadata{DocURL: "https://eslint.org/docs/latest/rules/{id}"}, "javascript", "typescript")
+	r.RegisterFamily(`^security/`, RuleMetadata{Category: CategorySecurity, DocURL: "https://github.com/eslint-community/eslint-plugin-security/blob/main/docs/rules/{name}.md"}, "javascript", "typescript")
+	r.RegisterFamily(`^@typescript-eslint/`, RuleMetadata{Category: CategoryCorrectness, DocURL: "https://typescript-eslint.io/rules/{name}"}, "typescript")
+	r.RegisterFamily(`^react-hooks/`, RuleMetadata{Category: CategoryCorrectness, Tags: []string{"react", "hooks"}, DocURL: "https://react.dev/reference/rules/rules-of-hooks"}, "javascript", "typescript")
+	r.RegisterFamily(`^jsx-a11y/`, RuleMetadata{Category: CategoryCorrectness, Tags: []string{"react", "accessibility"}, DocURL: "https://github.com/jsx-eslint/eslint-plugin-jsx-a11y/blob/main/docs/rules/{name}.md"}, "javascript", "typescript")
+	r.RegisterFamily(`^react/`, RuleMetadata{Category: CategoryCorrectness, Tags: []string{"react"}, DocURL: "https://github.com/jsx-eslint/eslint-plugin-react/blob/master/docs/rules/{name}.md"}, "javascript", "typescript")
+	r.RegisterFamily(`^vue/`, RuleMetadata{Tags: []string{"vue"}, DocURL: "https://eslint.vuejs.org/rules/{name}.html"}, "javascript", "typescript")
+	r.RegisterFamily(`^svelte/`, RuleMetadata{Tags: []string{"svelte"}, DocURL: "https://sveltejs.github.io/eslint-plugin-svelte/rules/{name}/"}, "javascript", "typescript")
+	r.RegisterFamily(`^TS\d+$`, rule(CategoryCorrectness, "typing"), "typescript")
+	
+	// Rust: rustc error codes and lints, and clippy lints
+	r.RegisterFamily(`^E\d{4}$`, RuleMetadata{Category: CategoryCorrectness, DocURL: "https://doc.rust-lang.org/error_codes/{id}.html"}, "rust")
+	r.RegisterFamily(`^(unused_|dead_code)`, rule(CategoryMaintainability, "unused"), "rust")
+	r.RegisterFamily(`^clippy::`, RuleMetadata{DocURL: "https://rust-lang.github.io/rust-clippy/master/index.html#{name}"}, "rust")
+	r.Register("rust", "rustfmt", rule(CategoryStyle, "formatting"))
+	
+	// Java: PMD and Checkstyle rule names
+	r.Register("java", "UnusedLocalVariable", rule(CategoryMaintainability, "unused"))
+	r.Register("java", "UnusedPrivateField", rule(CategoryMaintainability, "unused"))
+	r.Register("java", "UnusedPrivateMethod", rule(CategoryMaintainability, "unused"))
+	r.Register("java", "EmptyCatchBlock", rule(CategoryCorrectness, "error-handling"))
+	r.Register("java", "CompareObjectsWithEquals", rule(CategoryCorrectness))
+	r.Register("java", "AvoidPrintStackTrace", rule(CategoryMaintainability, "error-handling"))
+	r.RegisterFamily(`^(LineLength|Indentation|WhitespaceAround|WhitespaceAfter|NeedBraces|LeftCurly|RightCurly|FileTabCharacter)$`, rule(CategoryStyle, "formatting"), "java")
+	r.RegisterFamily(`^(Javadoc|MissingJavadoc)`, rule(CategoryStyle, "documentation"), "java")
+	r.RegisterFamily(`Name$`, rule(CategoryStyle, "naming"), "java")
+	
+	// C and C++: clang-tidy check groups
+	clangTidy := func(category string, tags ...string) RuleMetadata {
+		return RuleMetadata{Category: category, Tags: tags, DocURL: "https://clang.llvm.org/extra/clang-tidy/checks/list.html"}
+	}
+	for _, language := range []string{"c", "cpp"} {
+		r.RegisterFamily(`^clang-analyzer-security`, clangTidy(CategorySecurity), language)
+		r.RegisterFamily(`^cert-`, clangTidy(CategorySecurity, "cert"), language)
+		r.RegisterFamily(`^(bugprone-|clang-analyzer-|clang-diagnostic-)`, clangTidy(CategoryCorrectness), language)
+		r.RegisterFamily(`^performance-`, clangTidy(CategoryPerformance), language)
+		r.RegisterFamily(`^portability-`, clangTidy(CategoryCompatibility, "portability"), language)
+		r.RegisterFamily(`^modernize-`, clangTidy(CategoryMaintainability, "modernize"), language)
+		r.RegisterFamily(`^(readability-|cppcoreguidelines-|misc-|google-|llvm-)`, clangTidy(CategoryMaintainability), language)
+	}
+	
+	// Shell scripts and Dockerfiles: ShellCheck and hadolint codes
+	r.RegisterFamily(`^SC3\d{3}$`, RuleMetadata{Category: CategoryCompatibility, Tags: []string{"portability"}, DocURL: "https://www.shellcheck.net/wiki/{id}"}, "shell", "dockerfile")
+	r.RegisterFamily(`^SC\d{4}$`, RuleMetadata{Category: CategoryCorrectness, DocURL: "https://www.shellcheck.net/wiki/{id}"}, "shell", "dockerfile")
+	r.Register("dockerfile", "DL3002", security("CWE-250", "containers"))
+	r.Register("dockerfile", "DL3004", security("CWE-250", "containers"))
+	r.RegisterFamily(`^DL\d{4}$`, RuleMetadata{Category: CategoryMaintainability, Tags: []string{"containers"}, DocURL: "https://github.com/hadolint/hadolint/wiki/{id}"}, "dockerfile")
+	
+	// SQL: sqlfluff rule codes
+	sqlfluff := func(category string) RuleMetadata {
+		return RuleMetadata{Category: category, DocURL: "https://docs.sqlfluff.com/en/stable/reference/rules.html"}
+	}
+	r.RegisterFamily(`^(LT|CP|AL|JJ)\d{2}$`, sqlfluff(CategoryStyle), "sql")
+	r.RegisterFamily(`^(AM|RF)\d{2}$`, sqlfluff(CategoryCorrectness), "sql")
+	r.RegisterFamily(`^(ST|CV)\d{2}$`, sqlfluff(CategoryMaintainability), "sql")
+	
+	// Kubernetes manifests
+	r.Register("kubernetes", "k8s-privileged-container", security("CWE-250", "kubernetes"))
+	r.Register("kubernetes", "k8s-run-as-root", security("CWE-250", "kubernetes"))
+	r.Register("kubernetes", "k8s-latest-tag", rule(CategoryCorrectness, "kubernetes", "reproducibility"))
+	r.Register("kubernetes", "k8s-missing-probe", rule(CategoryCorrectness, "kubernetes", "reliability"))
+	r.Register("kubernetes", "k8s-missing-resource-limits", rule(CategoryPerformance, "kubernetes", "resources"))
+	
+	// Terraform
+	r.Register("terraform", "tf-public-s3-bucket", security("CWE-732", "terraform", "aws"))
+	r.Register("terraform", "tf-open-security-group", security("CWE-284", "terraform", "aws"))
+	r.Register("terraform", "tf-unpinned-provider", rule(CategoryCompatibility, "terraform", "reproducibility"))
+	r.Register("terraform", "tf-unpinned-module", rule(CategoryCompatibility, "terraform", "reproducibility"))
+	r.Register("terraform", "tf-missing-tags", rule(CategoryMaintainability, "terraform"))
+	
+	r.RegisterFamily(`^terraform_`, RuleMetadata{Category: CategoryMaintainability, Tags: []string{"terraform"}, DocURL: "https://github.com/terraform-linters/tflint-ruleset-terraform/blob/main/docs/rules/{id}.md"}, "terraform")
+	r.RegisterFamily(`^aws_`, rule(CategoryCorrectness, "terraform", "aws"), "terraform")
+	
+	// SQL migrations
+	r.Register("sql", "sql-index-not-concurrent", rule(CategoryPerformance, "migrations", "locking"))
+	r.Register("sql", "sql-drop-table", rule(CategoryCorrectness, "migrations", "data-loss"))
+	r.Register("sql", "sql-drop-column", rule(CategoryCompatibility, "migrations", "data-loss"))
+	r.Register("sql", "sql-column-type-change", rule(CategoryCompatibility, "migrations"))
+	r.Register("sql", "sql-not-null-without-default", rule(CategoryCorrectness, "migrations"))
+	r.Register("sql", "sql-missing-down-migration", rule(CategoryMaintainability, "migrations"))
+}
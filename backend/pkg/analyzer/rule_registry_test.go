@@ -0,0 +1,266 @@
+package analyzer
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRuleRegistryLookup(t *testing.T) {
+	registry := NewRuleRegistry()
+	registry.RegisterDefaultRules()
+	
+	tests := []struct {
+		name      string
+		language  string
+		ruleID    string
+		want      RuleMetadata
+		wantFound bool
+	}{
+		{
+			name:      "language-agnostic rule",
+			language:  "go",
+			ruleID:    "trailing-whitespace",
+			want:      RuleMetadata{Category: CategoryStyle, Tags: []string{"formatting"}},
+			wantFound: true,
+		},
+		{
+			name:      "family with an id in the documentation url",
+			language:  "go",
+			ruleID:    "SA1019",
+			want:      RuleMetadata{Category: CategoryCorrectness, DocURL: "https://staticcheck.dev/docs/checks/#SA1019"},
+			wantFound: true,
+		},
+		{
+			name:     "family of another language",
+			language: "python",
+			ruleID:   "SA1019",
+		},
+		{
+			name:      "bandit test",
+			language:  "python",
+			ruleID:    "B105",
+			want:      RuleMetadata{Category: CategorySecurity, Tags: []string{"bandit"}, DocURL: "https://bandit.readthedocs.io/en/latest/plugins/index.html"},
+			wantFound: true,
+		},
+		{
+			name:      "bugbear check",
+			language:  "python",
+			ruleID:    "B006",
+			want:      RuleMetadata{Category: CategoryCorrectness, Tags: []string{"bugbear"}},
+			wantFound: true,
+		},
+		{
+			name:      "mypy error code",
+			language:  "python",
+			ruleID:    "arg-type",
+			want:      RuleMetadata{Category: CategoryCorrectness, Tags: []string{"typing"}, DocURL: "https://mypy.readthedocs.io/en/stable/error_code_list.html"},
+			wantFound: true,
+		},
+		{
+			name:      "name after the last separator",
+			language:  "rust",
+			ruleID:    "clippy::needless_return",
+			want:      RuleMetadata{DocURL: "https://rust-lang.github.io/rust-clippy/master/index.html#needless_return"},
+			wantFound: true,
+		},
+		{
+			name:      "scoped plugin rule",
+			language:  "typescript",
+			ruleID:    "@typescript-eslint/no-explicit-any",
+			want:      RuleMetadata{Category: CategoryCorrectness, DocURL: "https://typescript-eslint.io/rules/no-explicit-any"},
+			wantFound: true,
+		},
+		{
+			name:      "registered rule ignores families",
+			language:  "javascript",
+			ruleID:    "no-eval",
+			want:      RuleMetadata{Category: CategorySecurity, CWE: "CWE-95", OWASP: "A03:2021-Injection", DocURL: "https://eslint.org/docs/latest/rules/no-eval"},
+			wantFound: true,
+		},
+		{
+			name:      "core rule only known by its family",
+			language:  "javascript",
+			ruleID:    "no-shadow",
+			want:      RuleMetadata{DocURL: "https://eslint.org/docs/latest/rules/no-shadow"},
+			wantFound: true,
+		},
+		{
+			name:      "plugin rule",
+			language:  "typescript",
+			ruleID:    "security/detect-object-injection",
+			want:      RuleMetadata{Category: CategorySecurity, DocURL: "https://github.com/eslint-community/eslint-plugin-security/blob/main/docs/rules/detect-object-injection.md"},
+			wantFound: true,
+		},
+		{
+			name:      "secret family",
+			language:  "go",
+			ruleID:    "secret-aws-access-key",
+			want:      RuleMetadata{Category: CategorySecurity, Tags: []string{"secrets"}, CWE: "CWE-798", OWASP: "A07:2021-Identification and Authentication Failures"},
+			wantFound: true,
+		},
+		{
+			name:      "registered secret overrides the family",
+			language:  "python",
+			ruleID:    "secret-private-key",
+			want:      RuleMetadata{Category: CategorySecurity, Tags: []string{"secrets"}, CWE: "CWE-321", OWASP: "A02:2021-Cryptographic Failures"},
+			wantFound: true,
+		},
+		{
+			name:      "earlier families win",
+			language:  "shell",
+			ruleID:    "SC3010",
+			want:      RuleMetadata{Category: CategoryCompatibility, Tags: []string{"portability"}, DocURL: "https://www.shellcheck.net/wiki/SC3010"},
+			wantFound: true,
+		},
+		{
+			name:      "clang-tidy security check",
+			language:  "cpp",
+			ruleID:    "clang-analyzer-security.insecureAPI.strcpy",
+			want:      RuleMetadata{Category: CategorySecurity, DocURL: "https://clang.llvm.org/extra/clang-tidy/checks/list.html"},
+			wantFound: true,
+		},
+		{
+			name:      "hadolint security rule",
+			language:  "dockerfile",
+			ruleID:    "DL3002",
+			want:      RuleMetadata{Category: CategorySecurity, Tags: []string{"containers"}, CWE: "CWE-250", OWASP: "A05:2021-Security Misconfiguration"},
+			wantFound: true,
+		},
+		{
+			name:      "checkstyle naming",
+			language:  "java",
+			ruleID:    "MethodName",
+			want:      RuleMetadata{Category: CategoryStyle, Tags: []string{"naming"}},
+			wantFound: true,
+		},
+		{
+			name:     "unknown rule",
+			language: "go",
+			ruleID:   "no-such-rule",
+		},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, found := registry.Lookup(tt.language, tt.ruleID)
+			if found != tt.wantFound {
+				t.Fatalf("got found %v, want %v", found, tt.wantFound)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRuleRegistryMerge(t *testing.T) {
+	registry := NewRuleRegistry()
+	registry.Register("go", "custom", RuleMetadata{Category: CategoryStyle})
+	registry.Register("", "custom", RuleMetadata{Category: CategoryCorrectness, Tags: []string{"shared"}, DocURL: "https://example.com/{id}"})
+	registry.RegisterFamily(`^custom`, RuleMetadata{CWE: "CWE-20"})
+	registry.RegisterFamily(`^acme/`, RuleMetadata{Category: CategoryPerformance}, "go")
+	registry.RegisterFamily(`/`, RuleMetadata{Category: CategorySecurity, Tags: []string{"acme"}, DocURL: "https://acme.test/{name}"})
+	
+	tests := []struct {
+		name     string
+		language string
+		ruleID   string
+		want     RuleMetadata
+	}{
+		{
+			name:     "language rule filled in by the any-language rule",
+			language: "go",
+			ruleID:   "custom",
+			want:     RuleMetadata{Category: CategoryStyle, Tags: []string{"shared"}, DocURL: "https://example.com/custom"},
+		},
+		{
+			name:     "any-language rule",
+			language: "python",
+			ruleID:   "custom",
+			want:     RuleMetadata{Category: CategoryCorrectness, Tags: []string{"shared"}, DocURL: "https://example.com/custom"},
+		},
+		{
+			name:     "families merged in registration order",
+			language: "go",
+			ruleID:   "acme/fast-path",
+			want:     RuleMetadata{Category: CategoryPerformance, Tags: []string{"acme"}, DocURL: "https://acme.test/fast-path"},
+		},
+		{
+			name:     "family limited to another language",
+			language: "rust",
+			ruleID:   "acme/fast-path",
+			want:     RuleMetadata{Category: CategorySecurity, Tags: []string{"acme"}, DocURL: "https://acme.test/fast-path"},
+		},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, found := registry.Lookup(tt.language, tt.ruleID)
+			if !found {
+				t.Fatal("rule not found")
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRuleRegistryAnnotate(t *testing.T) {
+	registry := NewRuleRegistry()
+	registry.RegisterDefaultRules()
+	
+	tests := []struct {
+		name     string
+		language string
+		issue    Issue
+		want     Issue
+	}{
+		{
+			name:     "metadata from the rule",
+			language: "go",
+			issue:    Issue{RuleID: "G104"},
+			want:     Issue{RuleID: "G104", Category: CategorySecurity, DocURL: "https://github.com/securego/gosec#available-rules"},
+		},
+		{
+			name:     "fields set by the linter are kept",
+			language: "javascript",
+			issue:    Issue{RuleID: "no-eval", Category: CategoryCorrectness, Tags: []string{"legacy"}, DocURL: "https://example.com/eval"},
+			want:     Issue{RuleID: "no-eval", Category: CategoryCorrectness, Tags: []string{"legacy"}, CWE: "CWE-95", OWASP: "A03:2021-Injection", DocURL: "https://example.com/eval"},
+		},
+		{
+			name:     "owasp category from the cwe of an unknown rule",
+			language: "go",
+			issue:    Issue{RuleID: "taint", CWE: "CWE-89"},
+			want:     Issue{RuleID: "taint", CWE: "CWE-89", OWASP: "A03:2021-Injection"},
+		},
+		{
+			name:     "cwe without an owasp category",
+			language: "go",
+			issue:    Issue{RuleID: "custom", CWE: "CWE-1"},
+			want:     Issue{RuleID: "custom", CWE: "CWE-1"},
+		},
+		{
+			name:     "documentation url from the context",
+			language: "go",
+			issue:    Issue{RuleID: "custom", Context: "https://example.com/custom"},
+			want:     Issue{RuleID: "custom", Context: "https://example.com/custom", DocURL: "https://example.com/custom"},
+		},
+		{
+			name:     "context that is not a url",
+			language: "go",
+			issue:    Issue{RuleID: "custom", Context: "see the docs"},
+			want:     Issue{RuleID: "custom", Context: "see the docs"},
+		},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			issues := []Issue{tt.issue}
+			registry.Annotate(tt.language, issues)
+			if !reflect.DeepEqual(issues[0], tt.want) {
+				t.Errorf("got %+v, want %+v", issues[0], tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,516 @@
+package analyzer
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// rustMainPattern detects code that defines a binary entry point
+var rustMainPattern = regexp.MustCompile(`(?m)^\s*(?:pub\s+)?(?:async\s+)?fn\s+main\s*\(`)
+
+// rustMacroPattern finds invocations of the macros that read the environment or other files at
+// compile time, and imports that rename them
+var rustMacroPattern = regexp.MustCompile(`\b(include|include_str|include_bytes|env|option_env)\s*(!|as\b)`)
+
+// rustMacroArgumentPattern matches the opening of a macro call whose first argument is a plain
+// string literal
+var rustMacroArgumentPattern = regexp.MustCompile(`^\s*[(\[{]\s*"([^"\\]*)"`)
+
+// rustAttributePattern finds the opening of an outer or inner attribute
+var rustAttributePattern = regexp.MustCompile(`#!?\s*\[`)
+
+// rustPathAttributePattern finds a path attribute within an attribute, including one applied
+// through cfg_attr
+var rustPathAttributePattern = regexp.MustCompile(`\bpath\s*=`)
+
+// rustAttributeValuePattern matches an attribute value that is a plain string literal
+var rustAttributeValuePattern = regexp.MustCompile(`^\s*"([^"\\]*)"`)
+
+// rustEnvironment lists the variables of the server environment that cargo and rustc need
+var rustEnvironment = []string{"PATH", "HOME", "CARGO_HOME", "RUSTUP_HOME"}
+
+// rustEditions are the editions a request may select for the temporary crate
+var rustEditions = map[string]bool{
+	"2015": true,
+	"2018": true,
+	"2021": true,
+	"2024": true,
+}
+
+// RustLinter implements the Linter interface for Rust
+type RustLinter struct {
+	*BaseAnalyzer
+	cargoPath   string
+	rustfmtPath string
+}
+
+// NewRustLinter creates a new Rust linter
+func NewRustLinter(config map[string]string) *RustLinter {
+	// Default cargo path
+	cargoPath := "cargo"
+	if path, ok := config["cargoPath"]; ok && path != "" {
+		cargoPath = path
+	}
+	
+	// Default rustfmt path
+	rustfmtPath := "rustfmt"
+	if path, ok := config["rustfmtPath"]; ok && path != "" {
+		rustfmtPath = path
+	}
+	
+	return &RustLinter{
+		BaseAnalyzer: NewBaseAnalyzer(config),
+		cargoPath:    cargoPath,
+		rustfmtPath:  rustfmtPath,
+	}
+}
+
+// Language returns the identifier for the supported language
+func (l *RustLinter) Language() string {
+	return "rust"
+}
+
+// Analyze analyzes the provided code and returns issues found
+func (l *RustLinter) Analyze(ctx context.Context, code string, options map[string]interface{}) (*AnalysisResult, error) {
+	return l.BaseAnalyzer.CommonAnalyzeWrapper(
+		ctx,
+		code,
+		options,
+		l.findIssues,
+		l.SuggestFixes,
+	)
+}
+
+// findIssues analyzes the code and returns issues
+func (l *RustLinter) findIssues(ctx context.Context, code string, options map[string]interface{}) ([]Issue, error) {
+	// Create a temporary directory for the cargo project
+	tmpDir, err := ioutil.TempDir("", "codehawk-rust")
+	if err != nil {
+		return nil, l.WrapError(err, "failed to create temporary directory")
+	}
+	defer os.RemoveAll(tmpDir)
+	
+	// Submitted files become modules of a minimal crate, whose manifest is always ours
+	files := l.GetFilesOption(options)
+	sourcePath := l.sourcePath(code, options)
+	files[sourcePath] = code
+	if err := checkRustFiles(files); err != nil {
+		return nil, l.WrapError(err, "unsupported cargo project")
+	}
+	if err := checkRustMacros(files); err != nil {
+		return nil, l.WrapError(err, "unsupported cargo project")
+	}
+	
+	manifest, err := l.cargoManifest(options)
+	if err != nil {
+		return nil, l.WrapError(err, "unsupported cargo project")
+	}
+	files["Cargo.toml"] = manifest
+	
+	if err := l.WriteFiles(tmpDir, files); err != nil {
+		return nil, l.WrapError(err, "failed to set up cargo project")
+	}
+	
+	return l.runClippy(ctx, tmpDir, sourcePath, code, options)
+}
+
+// checkRustFiles rejects the files that would make cargo run submitted code at build time or
+// change how it builds: manifests, which could declare build scripts, proc-macro crates and
+// dependencies, build scripts themselves, cargo configuration and toolchain overrides
+func checkRustFiles(files map[string]string) error {
+	for path := range files {
+		clean := filepath.ToSlash(filepath.Clean(path))
+		switch base := filepath.Base(clean); {
+		case base == "Cargo.toml", base == "Cargo.lock":
+			return fmt.Errorf("%s: submitted cargo manifests are not supported", path)
+		case base == "build.rs":
+			return fmt.Errorf("%s: build scripts are not supported", path)
+		case base == "rust-toolchain", base == "rust-toolchain.toml":
+			return fmt.Errorf("%s: toolchain overrides are not supported", path)
+		case clean == ".cargo" || strings.HasPrefix(clean, ".cargo/") || strings.Contains(clean, "/.cargo/"):
+			return fmt.Errorf("%s: cargo configuration is not supported", path)
+		}
+	}
+	return nil
+}
+
+// checkRustMacros rejects the code that would read the server at compile time: env! and
+// option_env! of anything but the CARGO_ variables cargo sets, include!, include_str! and
+// include_bytes! of files outside the crate, #[path] attributes naming module files outside the
+// crate, and any of these whose argument is not a plain string literal, since macros like
+// concat! could build either. Comments are not skipped, so nothing can be hidden from the check
+// behind a string the scan would misread.
+func checkRustMacros(files map[string]string) error {
+	for path, content := range files {
+		for _, match := range rustMacroPattern.FindAllStringSubmatchIndex(content, -1) {
+			name := content[match[2]:match[3]]
+			line := strings.Count(content[:match[0]], "\n") + 1
+			if content[match[4]:match[5]] != "!" {
+				return fmt.Errorf("%s:%d: %s cannot be renamed", path, line, name)
+			}
+			
+			argument := rustMacroArgumentPattern.FindStringSubmatch(content[match[1]:])
+			if argument == nil {
+				return fmt.Errorf("%s:%d: %s! only accepts a string literal", path, line, name)
+			}
+			
+			switch value := argument[1]; name {
+			case "env", "option_env":
+				if !strings.HasPrefix(value, "CARGO_") {
+					return fmt.Errorf("%s:%d: %s! can only read the CARGO_ variables", path, line, name)
+				}
+			default:
+				if !rustInsideCrate(path, value) {
+					return fmt.Errorf("%s:%d: %s! can only read files inside the crate", path, line, name)
+				}
+			}
+		}
+		
+		for _, attribute := range rustAttributePattern.FindAllStringIndex(content, -1) {
+			end := rustAttributeEnd(content, attribute[1])
+			for _, match := range rustPathAttributePattern.FindAllStringIndex(content[attribute[1]:end], -1) {
+				offset := attribute[1] + match[1]
+				line := strings.Count(content[:offset], "\n") + 1
+				
+				value := rustAttributeValuePattern.FindStringSubmatch(content[offset:end])
+				if value == nil {
+					return fmt.Errorf("%s:%d: #[path] only accepts a string literal", path, line)
+				}
+				// Paths are relative to the directory of the file, or a directory below it for
+				// modules declared in inline modules, so checking against the file's own
+				// directory never lets a path through that leaves the crate
+				if !rustInsideCrate(path, value[1]) {
+					return fmt.Errorf("%s:%d: #[path] can only name files inside the crate", path, line)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// rustInsideCrate reports whether value, a path relative to the directory of the file at path,
+// stays inside the crate
+func rustInsideCrate(path, value string) bool {
+	target := filepath.ToSlash(filepath.Clean(filepath.Join(filepath.Dir(path), value)))
+	return !filepath.IsAbs(value) && target != ".." && !strings.HasPrefix(target, "../") && !filepath.IsAbs(target)
+}
+
+// rustAttributeEnd returns the offset of the bracket closing the attribute whose contents start
+// at start, or the end of content when it is not closed
+func rustAttributeEnd(content string, start int) int {
+	depth := 1
+	for i := start; i < len(content); i++ {
+		switch content[i] {
+		case '[':
+			depth++
+		case ']':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return len(content)
+}
+
+// cargoManifest returns the Cargo.toml of the temporary crate, which has no build script and
+// no dependencies
+func (l *RustLinter) cargoManifest(options map[string]interface{}) (string, error) {
+	edition := l.GetStringOption(options, "edition", "2021")
+	if !rustEditions[edition] {
+		return "", fmt.Errorf("unknown edition %q", edition)
+	}
+	
+	return fmt.Sprintf(`[package]
+name = "codehawk_temp"
+version = "0.1.0"
+edition = "%s"
+build = false
+
+[dependencies]
+`, edition), nil
+}
+
+// sourcePath returns the project-relative path of the analyzed code. Submitted workspaces
+// name it with the "path" option; otherwise it becomes the crate's binary or library root.
+func (l *RustLinter) sourcePath(code string, options map[string]interface{}) string {
+	if path := l.GetStringOption(options, "path", ""); path != "" {
+		return filepath.ToSlash(filepath.Clean(path))
+	}
+	if rustMainPattern.MatchString(code) {
+		return "src/main.rs"
+	}
+	return "src/lib.rs"
+}
+
+// rustSpan is a source location in a rustc diagnostic
+type rustSpan struct {
+	FileName                string  `json:"file_name"`
+	ByteStart               int     `json:"byte_start"`
+	ByteEnd                 int     `json:"byte_end"`
+	LineStart               int     `json:"line_start"`
+	ColumnStart             int     `json:"column_start"`
+	IsPrimary               bool    `json:"is_primary"`
+	SuggestedReplacement    *string `json:"suggested_replacement"`
+	SuggestionApplicability *string `json:"suggestion_applicability"`
+}
+
+// rustDiagnostic is a rustc or clippy diagnostic as emitted with --message-format=json
+type rustDiagnostic struct {
+	Message string `json:"message"`
+	Code    *struct {
+		Code string `json:"code"`
+	} `json:"code"`
+	Level    string           `json:"level"`
+	Spans    []rustSpan       `json:"spans"`
+	Children []rustDiagnostic `json:"children"`
+	Rendered *string          `json:"rendered"`
+}
+
+// cargoMessage is a single line of cargo's JSON output
+type cargoMessage struct {
+	Reason  string          `json:"reason"`
+	Message *rustDiagnostic `json:"message"`
+}
+
+// runClippy runs cargo clippy offline and parses its output
+func (l *RustLinter) runClippy(ctx context.Context, dir, sourcePath, code string, options map[string]interface{}) ([]Issue, error) {
+	args := []string{
+		"clippy",
+		"--message-format=json",
+		"--offline",
+		"--quiet",
+		"--all-targets",
+		"--",
+	}
+	
+	// Extra lint groups can be enabled per request, e.g. "clippy::pedantic"
+	for _, lint := range l.GetListOption(options, "clippyLints", []string{"clippy::all"}) {
+		args = append(args, "-W", lint)
+	}
+	
+	cmd := exec.CommandContext(ctx, l.cargoPath, args...)
+	cmd.Dir = dir
+	// Submitted code must never trigger network access, and every run builds into its own
+	// target directory so that one request cannot leave artifacts for another. The server
+	// environment holds secrets that env! would compile into the diagnostics, so only what the
+	// toolchain needs is passed on.
+	cmd.Env = []string{
+		"CARGO_NET_OFFLINE=true",
+		"CARGO_TERM_COLOR=never",
+		"CARGO_TARGET_DIR=" + filepath.Join(dir, "target"),
+	}
+	for _, name := range rustEnvironment {
+		if value, ok := os.LookupEnv(name); ok {
+			cmd.Env = append(cmd.Env, name+"="+value)
+		}
+	}
+	
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	
+	// cargo exits with status 101 when the crate does not compile, which is
+	// reported through the JSON messages like any other diagnostic
+	runErr := cmd.Run()
+	
+	issues := make([]Issue, 0)
+	seen := make(map[string]bool)
+	compiled := false
+	scanner := bufio.NewScanner(&stdout)
+	scanner.Buffer(make([]byte, 0, 64*1024), 10*1024*1024)
+	for scanner.Scan() {
+		var message cargoMessage
+		if err := json.Unmarshal(scanner.Bytes(), &message); err != nil {
+			return nil, l.WrapError(err, "failed to parse cargo output")
+		}
+		
+		switch message.Reason {
+		case "compiler-message":
+			compiled = true
+			if message.Message == nil {
+				continue
+			}
+			
+			issue, ok := l.convertDiagnostic(*message.Message, sourcePath, code)
+			if !ok {
+				continue
+			}
+			
+			// --all-targets reports diagnostics once per target that includes the file
+			key := fmt.Sprintf("%d:%d:%s:%s", issue.Line, *issue.Column, issue.RuleID, issue.Message)
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			issues = append(issues, issue)
+		case "compiler-artifact", "build-finished":
+			compiled = true
+		}
+	}
+	
+	if runErr != nil && !compiled {
+		return nil, l.WrapError(fmt.Errorf("failed to run cargo clippy: %w, stderr: %s", runErr, stderr.String()), "cargo clippy execution error")
+	}
+	
+	return issues, nil
+}
+
+// convertDiagnostic converts a rustc or clippy diagnostic reported against the analyzed file
+// to a CodeHawk issue
+func (l *RustLinter) convertDiagnostic(diagnostic rustDiagnostic, sourcePath, code string) (Issue, bool) {
+	// Summaries such as "aborting due to 2 previous errors" have no location
+	var primary *rustSpan
+	for i := range diagnostic.Spans {
+		if diagnostic.Spans[i].IsPrimary {
+			primary = &diagnostic.Spans[i]
+			break
+		}
+	}
+	if primary == nil || filepath.ToSlash(filepath.Clean(primary.FileName)) != sourcePath {
+		return Issue{}, false
+	}
+	
+	ruleID := "rustc"
+	if diagnostic.Code != nil && diagnostic.Code.Code != "" {
+		ruleID = diagnostic.Code.Code
+	}
+	
+	var severity string
+	switch diagnostic.Level {
+	case "error", "error: internal compiler error":
+		severity = "error"
+	case "warning":
+		severity = "warning"
+	default:
+		severity = "info"
+	}
+	
+	column := primary.ColumnStart
+	issue := Issue{
+		Line:     primary.LineStart,
+		Column:   &column,
+		Message:  diagnostic.Message,
+		Severity: severity,
+		RuleID:   ruleID,
+	}
+	
+	if diagnostic.Rendered != nil {
+		issue.Context = *diagnostic.Rendered
+	}
+	
+	// Suggestions are attached to the help children of the diagnostic
+	for _, child := range diagnostic.Children {
+		for _, suggestion := range rustSuggestionFixes(child, sourcePath, code) {
+			if suggestion.machineApplicable && issue.Fix == nil {
+				fix := suggestion.fix
+				issue.Fix = &fix
+				continue
+			}
+			issue.Suggestions = append(issue.Suggestions, suggestion.fix)
+		}
+	}
+	
+	return issue, true
+}
+
+// rustSuggestion is a fix derived from a rustc suggestion
+type rustSuggestion struct {
+	fix               IssueFix
+	machineApplicable bool
+}
+
+// rustSuggestionFixes converts the suggested replacements of a help message into fixes. The
+// spans of one message normally form a single multi-part suggestion; when they overlap they
+// are alternatives and each becomes its own fix.
+func rustSuggestionFixes(child rustDiagnostic, sourcePath, code string) []rustSuggestion {
+	edits := make([]textEdit, 0)
+	machineApplicable := true
+	for _, span := range child.Spans {
+		if span.SuggestedReplacement == nil || filepath.ToSlash(filepath.Clean(span.FileName)) != sourcePath {
+			continue
+		}
+		edits = append(edits, textEdit{start: span.ByteStart, end: span.ByteEnd, text: *span.SuggestedReplacement})
+		if span.SuggestionApplicability == nil || *span.SuggestionApplicability != "MachineApplicable" {
+			machineApplicable = false
+		}
+	}
+	if len(edits) == 0 {
+		return nil
+	}
+	
+	if fix, err := buildRangeFix(code, child.Message, edits); err == nil {
+		return []rustSuggestion{{fix: *fix, machineApplicable: machineApplicable}}
+	}
+	
+	// Alternatives are never applied automatically
+	suggestions := make([]rustSuggestion, 0, len(edits))
+	for _, edit := range edits {
+		if fix, err := buildRangeFix(code, child.Message, []textEdit{edit}); err == nil {
+			suggestions = append(suggestions, rustSuggestion{fix: *fix})
+		}
+	}
+	return suggestions
+}
+
+// SuggestFixes attempts to generate fixes for the identified issues
+func (l *RustLinter) SuggestFixes(ctx context.Context, code string, issues []Issue) ([]Issue, error) {
+	// Run rustfmt to get properly formatted code
+	formattedCode, err := l.FormatCode(ctx, code, nil)
+	if err != nil {
+		return nil, err
+	}
+	
+	// Add suggestions based on formatting differences
+	suggestions := make([]Issue, 0)
+	hunkLines, fixes := hunkFixes(code, formattedCode, "Format code according to rustfmt")
+	for i, fix := range fixes {
+		suggestions = append(suggestions, Issue{
+			Line:     hunkLines[i],
+			Message:  "Code formatting issue",
+			Severity: "suggestion",
+			RuleID:   "rustfmt",
+			Fix:      fix,
+		})
+	}
+	
+	return suggestions, nil
+}
+
+// FormatCode formats code with rustfmt. The "edition" option selects the Rust edition and
+// "lineLength" the maximum line width.
+func (l *RustLinter) FormatCode(ctx context.Context, code string, options map[string]interface{}) (string, error) {
+	ctx, cancel := l.CreateTimeoutContext(ctx)
+	defer cancel()
+	
+	args := []string{
+		"--edition", l.GetStringOption(options, "edition", "2021"),
+		"--emit", "stdout",
+	}
+	
+	if lineLength := l.GetIntOption(options, "lineLength", 0); lineLength > 0 {
+		args = append(args, "--config", fmt.Sprintf("max_width=%d", lineLength))
+	}
+	
+	cmd := exec.CommandContext(ctx, l.rustfmtPath, args...)
+	
+	var stdout, stderr bytes.Buffer
+	cmd.Stdin = strings.NewReader(code)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	
+	if err := cmd.Run(); err != nil {
+		return "", l.WrapError(formatterRunError(ctx, "rustfmt", err, stderr.String()), "rustfmt execution error")
+	}
+	
+	return stdout.String(), nil
+}
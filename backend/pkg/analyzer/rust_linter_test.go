@@ -0,0 +1,306 @@
+package analyzer
+
+import (
+	"context"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCheckRustFiles(t *testing.T) {
+	tests := []struct {
+		name    string
+		files   map[string]string
+		wantErr string
+	}{
+		{"modules", map[string]string{"src/main.rs": "mod util;", "src/util.rs": ""}, ""},
+		{"root manifest", map[string]string{"Cargo.toml": "[package]"}, "manifests"},
+		{"member manifest", map[string]string{"crates/a/Cargo.toml": "[package]"}, "manifests"},
+		{"lock file", map[string]string{"Cargo.lock": ""}, "manifests"},
+		{"build script", map[string]string{"build.rs": "fn main() {}"}, "build scripts"},
+		{"nested build script", map[string]string{"./crates/a/build.rs": ""}, "build scripts"},
+		{"cargo configuration", map[string]string{".cargo/config.toml": "[build]\nrustc-wrapper = \"sh\""}, "cargo configuration"},
+		{"toolchain override", map[string]string{"rust-toolchain.toml": "[toolchain]"}, "toolchain"},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkRustFiles(tt.files)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("checkRustFiles() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("checkRustFiles() error = %v, want one mentioning %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCheckRustMacros(t *testing.T) {
+	tests := []struct {
+		name    string
+		files   map[string]string
+		wantErr string
+	}{
+		{"cargo variable", map[string]string{"src/lib.rs": `const V: &str = env!("CARGO_PKG_VERSION");`}, ""},
+		{"file in the crate", map[string]string{"src/lib.rs": `#![doc = include_str!("../README.md")]`, "README.md": ""}, ""},
+		{"nested module reading a sibling", map[string]string{"src/a/b.rs": `const D: &[u8] = include_bytes!("../data.bin");`}, ""},
+		{"module named like a macro", map[string]string{"src/main.rs": "use std::env;\nfn main() { let _ = env::args(); }"}, ""},
+		{"server variable", map[string]string{"src/main.rs": "fn main() {\n    let _ = env!(\"DB_PASSWORD\");\n}"}, "src/main.rs:2: env! can only read the CARGO_ variables"},
+		{"optional server variable", map[string]string{"src/lib.rs": `const K: Option<&str> = std::option_env!("API_KEY");`}, "option_env!"},
+		{"absolute path", map[string]string{"src/lib.rs": `const P: &str = include_str!("/etc/passwd");`}, "inside the crate"},
+		{"path leaving the crate", map[string]string{"src/lib.rs": `const P: &str = include_str!("../../secrets.env");`}, "inside the crate"},
+		{"included code", map[string]string{"src/lib.rs": `include!{"/proc/self/environ"}`}, "inside the crate"},
+		{"built argument", map[string]string{"src/lib.rs": `const P: &str = include_str!(concat!("/etc", "/passwd"));`}, "string literal"},
+		{"macro argument", map[string]string{"src/lib.rs": "macro_rules! read { ($p:expr) => { include_str!($p) }; }"}, "string literal"},
+		{"escaped literal", map[string]string{"src/lib.rs": `const P: &str = include_str!("\x2fetc/passwd");`}, "string literal"},
+		{"renamed macro", map[string]string{"src/lib.rs": "use std::include_str as read;"}, "cannot be renamed"},
+		{"call in a comment", map[string]string{"src/lib.rs": `// env!("HOME")`}, "CARGO_"},
+		{"module path in the crate", map[string]string{"src/main.rs": "#[path = \"util/helpers.rs\"]\nmod helpers;\nfn main() {}"}, ""},
+		{"variable named path", map[string]string{"src/main.rs": `fn main() { let path = "/etc/passwd"; }`}, ""},
+		{"absolute module path", map[string]string{"src/main.rs": "#[path = \"/etc/passwd\"]\nmod x;"}, "src/main.rs:1: #[path] can only name files inside the crate"},
+		{"module path leaving the crate", map[string]string{"src/lib.rs": `#[path = "../../secrets.rs"] mod secrets;`}, "inside the crate"},
+		{"module path behind cfg_attr", map[string]string{"src/lib.rs": `#[cfg_attr(unix, path = "/proc/self/environ")] mod x;`}, "inside the crate"},
+		{"inner module path", map[string]string{"src/lib.rs": `mod x { #![path = "/etc/hosts"] }`}, "inside the crate"},
+		{"built module path", map[string]string{"src/lib.rs": `#[path = concat!("/etc", "/passwd")] mod x;`}, "string literal"},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkRustMacros(tt.files)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("checkRustMacros() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("checkRustMacros() error = %v, want one mentioning %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCargoManifest(t *testing.T) {
+	l := NewRustLinter(map[string]string{})
+	tests := []struct {
+		name        string
+		options     map[string]interface{}
+		wantEdition string
+		wantErr     bool
+	}{
+		{"default edition", nil, `edition = "2021"`, false},
+		{"selected edition", map[string]interface{}{"edition": "2018"}, `edition = "2018"`, false},
+		{"injected manifest entries", map[string]interface{}{"edition": "2021\"\nbuild = \"src/main.rs"}, "", true},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			manifest, err := l.cargoManifest(tt.options)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("cargoManifest() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if !strings.Contains(manifest, tt.wantEdition) || !strings.Contains(manifest, "build = false") {
+				t.Errorf("manifest = %q, want %s without a build script", manifest, tt.wantEdition)
+			}
+		})
+	}
+}
+
+func TestRustSourcePath(t *testing.T) {
+	l := NewRustLinter(map[string]string{})
+	tests := []struct {
+		name    string
+		code    string
+		options map[string]interface{}
+		want    string
+	}{
+		{"binary", "fn main() {}", nil, "src/main.rs"},
+		{"async binary", "#[tokio::main]\npub async fn main() {}", nil, "src/main.rs"},
+		{"library", "pub fn add(a: i32, b: i32) -> i32 { a + b }", nil, "src/lib.rs"},
+		{"named module", "fn main() {}", map[string]interface{}{"path": "src/bin/../util.rs"}, "src/util.rs"},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := l.sourcePath(tt.code, tt.options); got != tt.want {
+				t.Errorf("sourcePath() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRustRunClippy(t *testing.T) {
+	code := "fn main() {\n    let x = 5;\n    if x == true {}\n}\n"
+	output := strings.Join([]string{
+		`{"reason":"compiler-artifact","target":{"name":"codehawk_temp"}}`,
+		`{"reason":"compiler-message","message":{"message":"unused variable: ` + "`x`" + `","code":{"code":"unused_variables"},"level":"warning","spans":[{"file_name":"src/main.rs","byte_start":20,"byte_end":21,"line_start":2,"column_start":9,"is_primary":true}],"children":[{"message":"if this is intentional, prefix it with an underscore","code":null,"level":"help","spans":[{"file_name":"src/main.rs","byte_start":20,"byte_end":21,"line_start":2,"column_start":9,"is_primary":true,"suggested_replacement":"_x","suggestion_applicability":"MachineApplicable"}],"children":[],"rendered":null}],"rendered":"warning: unused variable"}}`,
+		`{"reason":"compiler-message","message":{"message":"unused variable: ` + "`x`" + `","code":{"code":"unused_variables"},"level":"warning","spans":[{"file_name":"src/main.rs","byte_start":20,"byte_end":21,"line_start":2,"column_start":9,"is_primary":true}],"children":[],"rendered":null}}`,
+		`{"reason":"compiler-message","message":{"message":"mismatched types","code":{"code":"E0308"},"level":"error","spans":[{"file_name":"src/main.rs","byte_start":39,"byte_end":43,"line_start":3,"column_start":13,"is_primary":true}],"children":[{"message":"consider comparing","code":null,"level":"help","spans":[{"file_name":"src/main.rs","byte_start":39,"byte_end":43,"line_start":3,"column_start":13,"is_primary":true,"suggested_replacement":"1","suggestion_applicability":"MaybeIncorrect"}],"children":[],"rendered":null}],"rendered":null}}`,
+		`{"reason":"compiler-message","message":{"message":"aborting due to 1 previous error","code":null,"level":"error","spans":[],"children":[],"rendered":null}}`,
+		`{"reason":"compiler-message","message":{"message":"unused import","code":{"code":"unused_imports"},"level":"warning","spans":[{"file_name":"src/util.rs","byte_start":0,"byte_end":3,"line_start":1,"column_start":1,"is_primary":true}],"children":[],"rendered":null}}`,
+		`{"reason":"build-finished","success":false}`,
+	}, "\n")
+	
+	l := NewRustLinter(map[string]string{"cargoPath": fakeTool(t, output)})
+	issues, err := l.runClippy(context.Background(), t.TempDir(), "src/main.rs", code, nil)
+	if err != nil {
+		t.Fatalf("runClippy() error = %v", err)
+	}
+	
+	want := []struct {
+		line        int
+		ruleID      string
+		severity    string
+		fixed       string
+		suggestions int
+	}{
+		{2, "unused_variables", "warning", "fn main() {\n    let _x = 5;\n    if x == true {}\n}\n", 0},
+		{3, "E0308", "error", "", 1},
+	}
+	if len(issues) != len(want) {
+		t.Fatalf("got %d issues, want %d: %+v", len(issues), len(want), issues)
+	}
+	for i, issue := range issues {
+		if issue.Line != want[i].line || issue.RuleID != want[i].ruleID || issue.Severity != want[i].severity || len(issue.Suggestions) != want[i].suggestions {
+			t.Errorf("issue %d = %+v, want %+v", i, issue, want[i])
+		}
+		if want[i].fixed == "" {
+			if issue.Fix != nil {
+				t.Errorf("issue %d has fix %+v, want none", i, issue.Fix)
+			}
+			continue
+		}
+		if issue.Fix == nil {
+			t.Fatalf("issue %d has no fix", i)
+		}
+		fixed, err := ApplyFix(code, issue.Line, *issue.Fix)
+		if err != nil || fixed != want[i].fixed {
+			t.Errorf("issue %d fixed code = %q (%v), want %q", i, fixed, err, want[i].fixed)
+		}
+	}
+}
+
+func TestRustRunClippyEnvironment(t *testing.T) {
+	t.Setenv("DB_PASSWORD", "secret")
+	t.Setenv("CARGO_HOME", "/opt/cargo")
+	
+	dir := t.TempDir()
+	cargoPath := filepath.Join(t.TempDir(), "cargo")
+	if err := ioutil.WriteFile(cargoPath, []byte("#!/bin/sh\nenv > env.txt\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	
+	l := NewRustLinter(map[string]string{"cargoPath": cargoPath})
+	if _, err := l.runClippy(context.Background(), dir, "src/lib.rs", "", nil); err != nil {
+		t.Fatalf("runClippy() error = %v", err)
+	}
+	
+	data, err := ioutil.ReadFile(filepath.Join(dir, "env.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	env := string(data)
+	for _, want := range []string{"CARGO_HOME=/opt/cargo\n", "CARGO_NET_OFFLINE=true\n", "CARGO_TARGET_DIR=" + filepath.Join(dir, "target") + "\n"} {
+		if !strings.Contains(env, want) {
+			t.Errorf("cargo environment lacks %q:\n%s", want, env)
+		}
+	}
+	if strings.Contains(env, "DB_PASSWORD") {
+		t.Errorf("cargo environment holds the server secrets:\n%s", env)
+	}
+}
+
+func TestRustSuggestionFixes(t *testing.T) {
+	code := "let a = b + c;\n"
+	replacement := func(s string) *string { return &s }
+	machine := "MachineApplicable"
+	
+	tests := []struct {
+		name             string
+		spans            []rustSpan
+		wantFixes        []string
+		wantMachineFirst bool
+	}{
+		{
+			name: "multi-part suggestion",
+			spans: []rustSpan{
+				{FileName: "src/main.rs", ByteStart: 8, ByteEnd: 9, SuggestedReplacement: replacement("x"), SuggestionApplicability: &machine},
+				{FileName: "src/main.rs", ByteStart: 12, ByteEnd: 13, SuggestedReplacement: replacement("y"), SuggestionApplicability: &machine},
+			},
+			wantFixes:        []string{"let a = x + y;\n"},
+			wantMachineFirst: true,
+		},
+		{
+			name: "overlapping alternatives",
+			spans: []rustSpan{
+				{FileName: "src/main.rs", ByteStart: 8, ByteEnd: 13, SuggestedReplacement: replacement("b.add(c)"), SuggestionApplicability: &machine},
+				{FileName: "src/main.rs", ByteStart: 8, ByteEnd: 13, SuggestedReplacement: replacement("c + b")},
+			},
+			wantFixes: []string{"let a = b.add(c);\n", "let a = c + b;\n"},
+		},
+		{
+			name:  "spans in other files",
+			spans: []rustSpan{{FileName: "src/lib.rs", ByteStart: 0, ByteEnd: 1, SuggestedReplacement: replacement("x")}},
+		},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			suggestions := rustSuggestionFixes(rustDiagnostic{Message: "try this", Spans: tt.spans}, "src/main.rs", code)
+			if len(suggestions) != len(tt.wantFixes) {
+				t.Fatalf("got %d suggestions, want %d", len(suggestions), len(tt.wantFixes))
+			}
+			for i, suggestion := range suggestions {
+				fixed, err := ApplyFix(code, 1, suggestion.fix)
+				if err != nil || fixed != tt.wantFixes[i] {
+					t.Errorf("suggestion %d fixed code = %q (%v), want %q", i, fixed, err, tt.wantFixes[i])
+				}
+			}
+			if len(suggestions) > 0 && suggestions[0].machineApplicable != tt.wantMachineFirst {
+				t.Errorf("machineApplicable = %v, want %v", suggestions[0].machineApplicable, tt.wantMachineFirst)
+			}
+		})
+	}
+}
+
+func TestWriteFiles(t *testing.T) {
+	b := NewBaseAnalyzer(nil)
+	tests := []struct {
+		name    string
+		files   map[string]string
+		wantErr bool
+	}{
+		{"nested files", map[string]string{"src/main.rs": "fn main() {}", "src/a/b.rs": ""}, false},
+		{"parent directory", map[string]string{"../escape.rs": ""}, true},
+		{"parent directory after cleaning", map[string]string{"src/../../escape.rs": ""}, true},
+		{"absolute path", map[string]string{"/etc/escape.rs": ""}, true},
+		{"project root", map[string]string{".": ""}, true},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			err := b.WriteFiles(dir, tt.files)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("WriteFiles() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			for path, content := range tt.files {
+				written, err := ioutil.ReadFile(filepath.Join(dir, filepath.FromSlash(path)))
+				if err != nil || string(written) != content {
+					t.Errorf("%s = %q (%v), want %q", path, written, err, content)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,248 @@
+package analyzer
+
+import (
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// secretRule is a provider-specific secret pattern. The value group, when set, is the part of
+// the match holding the secret; otherwise the whole match is the secret.
+type secretRule struct {
+	id          string
+	description string
+	pattern     *regexp.Regexp
+	valueGroup  int
+	// entropy, when non-zero, is the minimum Shannon entropy of the value
+	entropy float64
+}
+
+// secretRules are checked in order, so specific providers come before the generic rules
+var secretRules = []secretRule{
+	{
+		id:          "secret-private-key",
+		description: "private key",
+		pattern:     regexp.MustCompile(`-----BEGIN (?:RSA |EC |DSA |OPENSSH |PGP |ENCRYPTED )?PRIVATE KEY(?: BLOCK)?-----`),
+	},
+	{
+		id:          "secret-aws-access-key",
+		description: "AWS access key ID",
+		pattern:     regexp.MustCompile(`\b(?:AKIA|ASIA|ABIA|ACCA)[0-9A-Z]{16}\b`),
+	},
+	{
+		id:          "secret-aws-secret-key",
+		description: "AWS secret access key",
+		pattern:     regexp.MustCompile(`(?i)aws_?secret_?(?:access_?)?key["']?\s*(?::=|=>|[:=])\s*["']?([A-Za-z0-9/+=]{40})\b`),
+		valueGroup:  1,
+	},
+	{
+		id:          "secret-github-token",
+		description: "GitHub token",
+		pattern:     regexp.MustCompile(`\b(?:gh[pousr]_[A-Za-z0-9]{36,255}|github_pat_[A-Za-z0-9_]{82})\b`),
+	},
+	{
+		id:          "secret-slack-token",
+		description: "Slack token",
+		pattern:     regexp.MustCompile(`\bxox[abeprs]-[A-Za-z0-9-]{10,}`),
+	},
+	{
+		id:          "secret-slack-webhook",
+		description: "Slack webhook URL",
+		pattern:     regexp.MustCompile(`https://hooks\.slack\.com/services/T[A-Z0-9]+/B[A-Z0-9]+/[A-Za-z0-9]+`),
+	},
+	{
+		id:          "secret-jwt",
+		description: "JSON Web Token",
+		pattern:     regexp.MustCompile(`\beyJ[A-Za-z0-9_-]{10,}\.eyJ[A-Za-z0-9_-]{10,}\.[A-Za-z0-9_-]{10,}`),
+	},
+	{
+		id:          "secret-postgres-dsn",
+		description: "PostgreSQL connection string password",
+		pattern:     regexp.MustCompile(`postgres(?:ql)?://[^:\s/@'"]+:([^@\s'"]+)@`),
+		valueGroup:  1,
+	},
+	{
+		id:          "secret-hardcoded-credential",
+		description: "hard-coded credential",
+		pattern:     regexp.MustCompile(`(?i)[\w.-]*(?:api[_-]?key|secret|token|passw(?:or)?d|pwd|credential)[\w.-]*["']?\s*(?::=|=>|[:=])\s*["'\x60]([^"'\x60\s]{8,})["'\x60]`),
+		valueGroup:  1,
+		entropy:     3.0,
+	},
+}
+
+// secretQuotedPattern matches quoted tokens that are candidates for the entropy check
+var secretQuotedPattern = regexp.MustCompile(`["'\x60]([A-Za-z0-9+/=_-]{20,})["'\x60]`)
+
+// secretPlaceholders are substrings of values that are clearly not real secrets
+var secretPlaceholders = []string{
+	"example", "placeholder", "your", "changeme", "change_me", "xxxx", "****", "dummy", "redacted", "${", "{{", "<",
+}
+
+// SecretScanner detects credentials in code. It runs for every language, independently of
+// the language's linter.
+type SecretScanner struct {
+	*BaseAnalyzer
+}
+
+// NewSecretScanner creates a new secret scanner
+func NewSecretScanner(config map[string]string) *SecretScanner {
+	return &SecretScanner{
+		BaseAnalyzer: NewBaseAnalyzer(config),
+	}
+}
+
+// Scan returns an error-severity issue for every secret found in code. Secret values are
+// masked in the messages so that the issues themselves can be stored and shared safely.
+func (s *SecretScanner) Scan(code string, options map[string]interface{}) []Issue {
+	issues := make([]Issue, 0)
+	if !s.GetBoolOption(options, "secretScanning", true) {
+		return issues
+	}
+	
+	disabled := make(map[string]bool)
+	for _, rule := range s.GetListOption(options, "disabledRules", nil) {
+		disabled[rule] = true
+	}
+	allowed := make(map[string]bool)
+	for _, value := range s.GetListOption(options, "secretsAllowlist", nil) {
+		allowed[value] = true
+	}
+	
+	// Ranges already reported, so that a value is only reported by the most specific rule
+	type span struct{ start, end int }
+	reported := make([]span, 0)
+	overlaps := func(start, end int) bool {
+		for _, r := range reported {
+			if start < r.end && r.start < end {
+				return true
+			}
+		}
+		return false
+	}
+	
+	report := func(ruleID, description string, start, end int) {
+		value := code[start:end]
+		if disabled[ruleID] || allowed[value] || overlaps(start, end) || isSecretPlaceholder(value) {
+			return
+		}
+		reported = append(reported, span{start, end})
+		
+		line, column := offsetToPosition(code, start)
+		issues = append(issues, Issue{
+			Line:     line,
+			Column:   &column,
+			Message:  "Possible " + description + " (" + MaskSecret(value) + ") found in code; remove it, rotate it and load it from the environment or a secrets manager",
+			Severity: "error",
+			RuleID:   ruleID,
+		})
+	}
+	
+	for _, rule := range secretRules {
+		for _, match := range rule.pattern.FindAllStringSubmatchIndex(code, -1) {
+			start, end := match[0], match[1]
+			if rule.valueGroup > 0 {
+				start, end = match[2*rule.valueGroup], match[2*rule.valueGroup+1]
+			}
+			if rule.entropy > 0 && shannonEntropy(code[start:end]) < rule.entropy {
+				continue
+			}
+			report(rule.id, rule.description, start, end)
+		}
+	}
+	
+	// Random-looking string literals that no provider pattern recognised
+	threshold := 4.5
+	if value, ok := options["entropyThreshold"].(float64); ok {
+		threshold = value
+	} else if value, err := strconv.ParseFloat(s.GetStringOption(options, "entropyThreshold", ""), 64); err == nil {
+		threshold = value
+	}
+	for _, match := range secretQuotedPattern.FindAllStringSubmatchIndex(code, -1) {
+		value := code[match[2]:match[3]]
+		if !hasMixedCharacterClasses(value) || shannonEntropy(value) < threshold {
+			continue
+		}
+		report("secret-high-entropy-string", "secret (high-entropy string)", match[2], match[3])
+	}
+	
+	sort.SliceStable(issues, func(i, j int) bool {
+		return issues[i].Line < issues[j].Line
+	})
+	return issues
+}
+
+// ScanNotebook scans a Jupyter notebook. Secrets in the source of a cell, of any type and
+// including its magics, are reported on their line of the cell; secrets in the outputs or the
+// metadata keep their line of the notebook file.
+func (s *SecretScanner) ScanNotebook(nb *Notebook, options map[string]interface{}) []Issue {
+	sources, rest := nb.cellSources()
+	
+	issues := make([]Issue, 0)
+	for index, source := range sources {
+		for _, issue := range s.Scan(source, options) {
+			cell := index
+			issue.Cell = &cell
+			issues = append(issues, issue)
+		}
+	}
+	return append(issues, s.Scan(rest, options)...)
+}
+
+// MaskSecret hides all but the first characters of a secret. It counts characters rather than
+// bytes, so that the masked value stays valid UTF-8.
+func MaskSecret(value string) string {
+	characters := []rune(value)
+	if len(characters) <= 8 {
+		return "****"
+	}
+	return string(characters[:4]) + "****"
+}
+
+// isSecretPlaceholder reports whether a value is documentation or a template rather than a secret
+func isSecretPlaceholder(value string) bool {
+	lower := strings.ToLower(value)
+	for _, placeholder := range secretPlaceholders {
+		if strings.Contains(lower, placeholder) {
+			return true
+		}
+	}
+	return false
+}
+
+// hasMixedCharacterClasses reports whether a value mixes letters and digits, which random
+// tokens do and identifiers or words usually do not
+func hasMixedCharacterClasses(value string) bool {
+	letters, digits := false, false
+	for _, r := range value {
+		switch {
+		case r >= '0' && r <= '9':
+			digits = true
+		case (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z'):
+			letters = true
+		}
+	}
+	return letters && digits
+}
+
+// shannonEntropy returns the Shannon entropy of value in bits per character
+func shannonEntropy(value string) float64 {
+	if value == "" {
+		return 0
+	}
+	
+	counts := make(map[rune]int)
+	total := 0
+	for _, r := range value {
+		counts[r]++
+		total++
+	}
+	
+	entropy := 0.0
+	for _, count := range counts {
+		p := float64(count) / float64(total)
+		entropy -= p * math.Log2(p)
+	}
+	return entropy
+}
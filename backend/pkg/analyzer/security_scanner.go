@@ -0,0 +1,384 @@
+package analyzer
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// semgrepCWEPattern extracts the identifier from semgrep CWE metadata such as "CWE-79: Improper Neutralization..."
+var semgrepCWEPattern = regexp.MustCompile(`CWE-\d+`)
+
+// semgrepRulesDir holds the rule packs bundled with the linters image. Scans only use packs
+// inside it, so that they never fetch rules from the registry or any other URL.
+const semgrepRulesDir = "/usr/src/linters/semgrep-rules"
+
+// semgrepDefaultRules are the bundled packs scanned for each language when the configuration
+// names none
+var semgrepDefaultRules = map[string][]string{
+	"javascript": {semgrepRulesDir + "/javascript"},
+	"typescript": {semgrepRulesDir + "/javascript", semgrepRulesDir + "/typescript"},
+}
+
+// semgrepUnsupportedExtensions are the component files the JavaScript and TypeScript linters
+// accept but semgrep has no parser for
+var semgrepUnsupportedExtensions = map[string]bool{".vue": true, ".svelte": true}
+
+// SecurityScanner runs the security tools for a language: gosec for Go, bandit for Python and
+// semgrep for JavaScript and TypeScript. Findings are reported with category "security".
+type SecurityScanner struct {
+	*BaseAnalyzer
+	gosecPath   string
+	banditPath  string
+	semgrepPath string
+}
+
+// NewSecurityScanner creates a new security scanner
+func NewSecurityScanner(config map[string]string) *SecurityScanner {
+	// Default gosec path
+	gosecPath := "gosec"
+	if path, ok := config["gosecPath"]; ok && path != "" {
+		gosecPath = path
+	}
+	
+	// Default bandit path
+	banditPath := "bandit"
+	if path, ok := config["banditPath"]; ok && path != "" {
+		banditPath = path
+	}
+	
+	// Default semgrep path
+	semgrepPath := "semgrep"
+	if path, ok := config["semgrepPath"]; ok && path != "" {
+		semgrepPath = path
+	}
+	
+	return &SecurityScanner{
+		BaseAnalyzer: NewBaseAnalyzer(config),
+		gosecPath:    gosecPath,
+		banditPath:   banditPath,
+		semgrepPath:  semgrepPath,
+	}
+}
+
+// Supports reports whether the scanner has a security tool for the language
+func (s *SecurityScanner) Supports(language string) bool {
+	switch language {
+	case "go", "python", "javascript", "typescript":
+		return true
+	}
+	return false
+}
+
+// Scan runs the security tool for the language on the code
+func (s *SecurityScanner) Scan(ctx context.Context, language, code string, options map[string]interface{}) ([]Issue, error) {
+	if !s.Supports(language) || !s.GetBoolOption(options, "securityScanning", true) {
+		return []Issue{}, nil
+	}
+	
+	ctx, cancel := s.CreateTimeoutContext(ctx)
+	defer cancel()
+	
+	tmpDir, err := ioutil.TempDir("", "codehawk-security")
+	if err != nil {
+		return nil, s.WrapError(err, "failed to create temporary directory")
+	}
+	defer os.RemoveAll(tmpDir)
+	
+	var issues []Issue
+	switch language {
+	case "go":
+		issues, err = s.runGosec(ctx, tmpDir, code, options)
+	case "python":
+		issues, err = s.runBandit(ctx, tmpDir, code, options)
+	case "javascript", "typescript":
+		issues, err = s.runSemgrep(ctx, tmpDir, language, code, options)
+	}
+	if err != nil {
+		return nil, err
+	}
+	
+	for i := range issues {
+		issues[i].Category = CategorySecurity
+	}
+	return issues, nil
+}
+
+// runGosec runs gosec on the code as a single-file module of the Go version options select
+func (s *SecurityScanner) runGosec(ctx context.Context, tmpDir, code string, options map[string]interface{}) ([]Issue, error) {
+	version, err := goVersion(s.BaseAnalyzer, options)
+	if err != nil {
+		return nil, s.WrapError(err, "unsupported Go module")
+	}
+	
+	files := map[string]string{
+		"go.mod":  "module " + goPackagePath + "\n\ngo " + version + "\n",
+		"main.go": code,
+	}
+	if err := s.WriteFiles(tmpDir, files); err != nil {
+		return nil, s.WrapError(err, "failed to write Go files")
+	}
+	
+	cmd := exec.CommandContext(ctx, s.gosecPath, "-fmt=json", "-quiet", "-no-fail", "./...")
+	cmd.Dir = tmpDir
+	
+	// Dependencies cannot be downloaded; gosec still checks the code with partial type information
+	cmd.Env = append(os.Environ(), "GOFLAGS=-mod=mod", "GOPROXY=off")
+	
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	
+	if err := cmd.Run(); err != nil && stdout.Len() == 0 {
+		return nil, s.WrapError(fmt.Errorf("failed to run gosec: %w, stderr: %s", err, stderr.String()), "gosec execution error")
+	}
+	
+	var report struct {
+		Issues []struct {
+			Severity   string `json:"severity"`
+			Confidence string `json:"confidence"`
+			CWE        struct {
+				ID  string `json:"id"`
+				URL string `json:"url"`
+			} `json:"cwe"`
+			RuleID  string `json:"rule_id"`
+			Details string `json:"details"`
+			File    string `json:"file"`
+			Line    string `json:"line"`
+			Column  string `json:"column"`
+		} `json:"Issues"`
+	}
+	if err := json.Unmarshal(stdout.Bytes(), &report); err != nil {
+		return nil, s.WrapError(err, "failed to parse gosec output")
+	}
+	
+	issues := make([]Issue, 0, len(report.Issues))
+	for _, finding := range report.Issues {
+		if filepath.Base(finding.File) != "main.go" {
+			continue
+		}
+		
+		// Multi-line findings report a range such as "12-14"
+		line, _ := strconv.Atoi(strings.SplitN(finding.Line, "-", 2)[0])
+		if line == 0 {
+			line = 1
+		}
+		
+		issue := Issue{
+			Line:       line,
+			Message:    finding.Details,
+			Severity:   securitySeverity(finding.Severity),
+			RuleID:     finding.RuleID,
+			DocURL:     finding.CWE.URL,
+			Confidence: strings.ToLower(finding.Confidence),
+		}
+		if finding.CWE.ID != "" {
+			issue.CWE = "CWE-" + finding.CWE.ID
+		}
+		if column, err := strconv.Atoi(finding.Column); err == nil && column > 0 {
+			issue.Column = &column
+		}
+		
+		issues = append(issues, issue)
+	}
+	
+	return issues, nil
+}
+
+// runBandit runs bandit on the code
+func (s *SecurityScanner) runBandit(ctx context.Context, tmpDir, code string, options map[string]interface{}) ([]Issue, error) {
+	sourceFile := filepath.Join(tmpDir, "main.py")
+	if err := ioutil.WriteFile(sourceFile, []byte(code), 0644); err != nil {
+		return nil, s.WrapError(err, "failed to write Python file")
+	}
+	
+	args := []string{"-f", "json", "-q"}
+	
+	// Tests to skip, e.g. "B101" for assert statements in test code
+	if skipped := s.GetListOption(options, "banditSkip", nil); len(skipped) > 0 {
+		args = append(args, "--skip", strings.Join(skipped, ","))
+	}
+	
+	args = append(args, sourceFile)
+	
+	cmd := exec.CommandContext(ctx, s.banditPath, args...)
+	
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	
+	// Exit code 1 is normal when bandit finds issues
+	err := cmd.Run()
+	if err != nil && !strings.Contains(err.Error(), "exit status 1") {
+		return nil, s.WrapError(fmt.Errorf("failed to run bandit: %w, stderr: %s", err, stderr.String()), "bandit execution error")
+	}
+	
+	var report struct {
+		Results []struct {
+			IssueSeverity   string `json:"issue_severity"`
+			IssueConfidence string `json:"issue_confidence"`
+			IssueCWE        struct {
+				ID   int    `json:"id"`
+				Link string `json:"link"`
+			} `json:"issue_cwe"`
+			IssueText  string `json:"issue_text"`
+			LineNumber int    `json:"line_number"`
+			ColOffset  int    `json:"col_offset"`
+			TestID     string `json:"test_id"`
+			TestName   string `json:"test_name"`
+			MoreInfo   string `json:"more_info"`
+		} `json:"results"`
+	}
+	if err := json.Unmarshal(stdout.Bytes(), &report); err != nil {
+		return nil, s.WrapError(err, "failed to parse bandit output")
+	}
+	
+	issues := make([]Issue, 0, len(report.Results))
+	for _, result := range report.Results {
+		// bandit columns are 0-based
+		column := result.ColOffset + 1
+		issue := Issue{
+			Line:       result.LineNumber,
+			Column:     &column,
+			Message:    result.IssueText,
+			Severity:   securitySeverity(result.IssueSeverity),
+			RuleID:     result.TestID,
+			DocURL:     result.MoreInfo,
+			Confidence: strings.ToLower(result.IssueConfidence),
+		}
+		if result.IssueCWE.ID != 0 {
+			issue.CWE = fmt.Sprintf("CWE-%d", result.IssueCWE.ID)
+		}
+		
+		issues = append(issues, issue)
+	}
+	
+	return issues, nil
+}
+
+// runSemgrep runs semgrep with the local rule packs of the configuration. Requests cannot choose
+// them, as semgrep fetches any URL or registry pack it is given. The code is scanned under the
+// file name the linters use, so that JSX and TSX are parsed as such; semgrep cannot parse Vue
+// and Svelte components, which are skipped.
+func (s *SecurityScanner) runSemgrep(ctx context.Context, tmpDir, language, code string, options map[string]interface{}) ([]Issue, error) {
+	fileName := javascriptSourceFileName(language == "typescript", s.GetStringOption(options, "path", ""))
+	if ext := filepath.Ext(fileName); semgrepUnsupportedExtensions[ext] {
+		return []Issue{skippedStageIssue("semgrep scan", fmt.Errorf("semgrep cannot parse %s components", ext))}, nil
+	}
+	
+	sourceFile := filepath.Join(tmpDir, fileName)
+	if err := ioutil.WriteFile(sourceFile, []byte(code), 0644); err != nil {
+		return nil, s.WrapError(err, "failed to write source file")
+	}
+	
+	rules := s.GetListOption(nil, language+"SemgrepRules", nil)
+	if len(rules) == 0 {
+		rules = s.GetListOption(nil, "semgrepRules", semgrepDefaultRules[language])
+	}
+	for _, rule := range rules {
+		if clean := filepath.Clean(rule); !strings.HasPrefix(clean, semgrepRulesDir+string(filepath.Separator)) {
+			return nil, fmt.Errorf("semgrep rule pack %q is not in %s", rule, semgrepRulesDir)
+		}
+	}
+	
+	args := []string{"scan", "--json", "--metrics=off", "--disable-version-check", "--quiet"}
+	for _, rule := range rules {
+		args = append(args, "--config", rule)
+	}
+	args = append(args, sourceFile)
+	
+	cmd := exec.CommandContext(ctx, s.semgrepPath, args...)
+	
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	
+	if err := cmd.Run(); err != nil && stdout.Len() == 0 {
+		return nil, s.WrapError(fmt.Errorf("failed to run semgrep: %w, stderr: %s", err, stderr.String()), "semgrep execution error")
+	}
+	
+	var report struct {
+		Results []struct {
+			CheckID string `json:"check_id"`
+			Start   struct {
+				Line int `json:"line"`
+				Col  int `json:"col"`
+			} `json:"start"`
+			Extra struct {
+				Message  string `json:"message"`
+				Severity string `json:"severity"`
+				Metadata struct {
+					CWE        interface{} `json:"cwe"`
+					Confidence string      `json:"confidence"`
+					References []string    `json:"references"`
+					Source     string      `json:"source"`
+				} `json:"metadata"`
+			} `json:"extra"`
+		} `json:"results"`
+	}
+	if err := json.Unmarshal(stdout.Bytes(), &report); err != nil {
+		return nil, s.WrapError(err, "failed to parse semgrep output")
+	}
+	
+	issues := make([]Issue, 0, len(report.Results))
+	for _, result := range report.Results {
+		column := result.Start.Col
+		
+		// Rule IDs are prefixed with the rule pack's directory, e.g. "rules.javascript.express.xss"
+		ruleID := result.CheckID
+		if dot := strings.LastIndex(ruleID, "."); dot >= 0 {
+			ruleID = ruleID[dot+1:]
+		}
+		
+		docURL := result.Extra.Metadata.Source
+		if docURL == "" && len(result.Extra.Metadata.References) > 0 {
+			docURL = result.Extra.Metadata.References[0]
+		}
+		
+		// CWE metadata is either a string or a list of strings
+		cwe := ""
+		switch value := result.Extra.Metadata.CWE.(type) {
+		case string:
+			cwe = semgrepCWEPattern.FindString(value)
+		case []interface{}:
+			if len(value) > 0 {
+				if first, ok := value[0].(string); ok {
+					cwe = semgrepCWEPattern.FindString(first)
+				}
+			}
+		}
+		
+		issues = append(issues, Issue{
+			Line:       result.Start.Line,
+			Column:     &column,
+			Message:    strings.TrimSpace(result.Extra.Message),
+			Severity:   securitySeverity(result.Extra.Severity),
+			RuleID:     ruleID,
+			DocURL:     docURL,
+			CWE:        cwe,
+			Confidence: strings.ToLower(result.Extra.Metadata.Confidence),
+		})
+	}
+	
+	return issues, nil
+}
+
+// securitySeverity maps the severity levels of the security tools to CodeHawk severities
+func securitySeverity(severity string) string {
+	switch strings.ToUpper(severity) {
+	case "HIGH", "ERROR", "CRITICAL":
+		return "error"
+	case "MEDIUM", "WARNING":
+		return "warning"
+	default:
+		return "info"
+	}
+}
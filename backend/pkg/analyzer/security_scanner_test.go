@@ -0,0 +1,264 @@
+package analyzer
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSecurityScannerScan(t *testing.T) {
+	gosecOutput := `{"Issues": [
+		{"severity": "HIGH", "confidence": "MEDIUM", "cwe": {"id": "89", "url": "https://cwe.mitre.org/data/definitions/89.html"},
+		 "rule_id": "G201", "details": "SQL string formatting", "file": "/tmp/x/main.go", "line": "12-14", "column": "9"},
+		{"severity": "LOW", "confidence": "HIGH", "cwe": {"id": "", "url": ""},
+		 "rule_id": "G104", "details": "Errors unhandled.", "file": "/tmp/x/vendor/lib.go", "line": "3", "column": "2"}
+	]}`
+	banditOutput := `{"results": [
+		{"issue_severity": "MEDIUM", "issue_confidence": "HIGH", "issue_cwe": {"id": 78, "link": ""},
+		 "issue_text": "subprocess call with shell=True", "line_number": 4, "col_offset": 0, "test_id": "B602",
+		 "more_info": "https://bandit.readthedocs.io/en/latest/plugins/b602.html"}
+	]}`
+	semgrepOutput := `{"results": [
+		{"check_id": "rules.javascript.browser.insecure-innerhtml", "start": {"line": 7, "col": 3},
+		 "extra": {"message": " User input reaches innerHTML ", "severity": "ERROR",
+		  "metadata": {"cwe": ["CWE-79: Improper Neutralization of Input"], "confidence": "LOW", "references": ["https://owasp.org/xss"]}}},
+		{"check_id": "eval-detected", "start": {"line": 2, "col": 1},
+		 "extra": {"message": "eval", "severity": "INFO", "metadata": {"cwe": "CWE-95", "source": "https://semgrep.dev/r/eval"}}}
+	]}`
+	
+	s := NewSecurityScanner(map[string]string{
+		"gosecPath":    fakeTool(t, gosecOutput),
+		"banditPath":   fakeTool(t, banditOutput),
+		"semgrepPath":  fakeTool(t, semgrepOutput),
+		"semgrepRules": "/usr/src/linters/semgrep-rules/javascript",
+		"timeout":      "10s",
+	})
+	
+	tests := []struct {
+		name     string
+		language string
+		options  map[string]interface{}
+		want     []Issue
+		wantCols []int
+	}{
+		{
+			name:     "gosec",
+			language: "go",
+			want: []Issue{
+				{Line: 12, Severity: "error", RuleID: "G201", CWE: "CWE-89", Confidence: "medium", DocURL: "https://cwe.mitre.org/data/definitions/89.html"},
+			},
+			wantCols: []int{9},
+		},
+		{
+			name:     "bandit",
+			language: "python",
+			want: []Issue{
+				{Line: 4, Severity: "warning", RuleID: "B602", CWE: "CWE-78", Confidence: "high", DocURL: "https://bandit.readthedocs.io/en/latest/plugins/b602.html"},
+			},
+			wantCols: []int{1},
+		},
+		{
+			name:     "semgrep",
+			language: "typescript",
+			want: []Issue{
+				{Line: 7, Message: "User input reaches innerHTML", Severity: "error", RuleID: "insecure-innerhtml", CWE: "CWE-79", Confidence: "low", DocURL: "https://owasp.org/xss"},
+				{Line: 2, Severity: "info", RuleID: "eval-detected", CWE: "CWE-95", DocURL: "https://semgrep.dev/r/eval"},
+			},
+			wantCols: []int{3, 1},
+		},
+		{
+			name:     "unsupported language",
+			language: "rust",
+			want:     []Issue{},
+		},
+		{
+			name:     "scanning disabled",
+			language: "go",
+			options:  map[string]interface{}{"securityScanning": false},
+			want:     []Issue{},
+		},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			issues, err := s.Scan(context.Background(), tt.language, "code\n", tt.options)
+			if err != nil {
+				t.Fatalf("Scan() error = %v", err)
+			}
+			if len(issues) != len(tt.want) {
+				t.Fatalf("got %d issues, want %d: %+v", len(issues), len(tt.want), issues)
+			}
+			for i, issue := range issues {
+				want := tt.want[i]
+				if issue.Line != want.Line || issue.Severity != want.Severity || issue.RuleID != want.RuleID || issue.CWE != want.CWE || issue.Confidence != want.Confidence || issue.DocURL != want.DocURL || issue.Context != "" {
+					t.Errorf("issue %d = %+v, want %+v", i, issue, want)
+				}
+				if want.Message != "" && issue.Message != want.Message {
+					t.Errorf("issue %d message = %q, want %q", i, issue.Message, want.Message)
+				}
+				if issue.Category != CategorySecurity {
+					t.Errorf("issue %d category = %q, want %q", i, issue.Category, CategorySecurity)
+				}
+				if issue.Column == nil || *issue.Column != tt.wantCols[i] {
+					t.Errorf("issue %d column = %v, want %d", i, issue.Column, tt.wantCols[i])
+				}
+			}
+		})
+	}
+}
+
+func TestSecurityScannerSemgrepRules(t *testing.T) {
+	dir := t.TempDir()
+	semgrepPath := filepath.Join(t.TempDir(), "semgrep")
+	script := "#!/bin/sh\necho \"$@\" > " + filepath.Join(dir, "args") + "\necho '{\"results\": []}'\n"
+	if err := ioutil.WriteFile(semgrepPath, []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	
+	tests := []struct {
+		name      string
+		language  string
+		config    map[string]string
+		options   map[string]interface{}
+		wantRules []string
+		wantErr   string
+	}{
+		{
+			name:      "bundled packs by default",
+			language:  "typescript",
+			wantRules: []string{"/usr/src/linters/semgrep-rules/javascript", "/usr/src/linters/semgrep-rules/typescript"},
+		},
+		{
+			name:      "packs of the language",
+			language:  "javascript",
+			config:    map[string]string{"semgrepRules": "/usr/src/linters/semgrep-rules/generic", "javascriptSemgrepRules": "/usr/src/linters/semgrep-rules/javascript/browser"},
+			wantRules: []string{"/usr/src/linters/semgrep-rules/javascript/browser"},
+		},
+		{
+			name:      "requested packs are ignored",
+			language:  "javascript",
+			options:   map[string]interface{}{"semgrepRules": "https://attacker.test/rules.yml", "javascriptSemgrepRules": []interface{}{"p/javascript"}},
+			wantRules: []string{"/usr/src/linters/semgrep-rules/javascript"},
+		},
+		{
+			name:     "registry pack",
+			language: "javascript",
+			config:   map[string]string{"semgrepRules": "p/javascript"},
+			wantErr:  "not in /usr/src/linters/semgrep-rules",
+		},
+		{
+			name:     "path leaving the rules directory",
+			language: "javascript",
+			config:   map[string]string{"semgrepRules": "/usr/src/linters/semgrep-rules/../../../etc"},
+			wantErr:  "not in /usr/src/linters/semgrep-rules",
+		},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			os.Remove(filepath.Join(dir, "args"))
+			config := map[string]string{"semgrepPath": semgrepPath}
+			for key, value := range tt.config {
+				config[key] = value
+			}
+			
+			_, err := NewSecurityScanner(config).Scan(context.Background(), tt.language, "eval(x)\n", tt.options)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Scan() error = %v, want one mentioning %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Scan() error = %v", err)
+			}
+			
+			args, err := ioutil.ReadFile(filepath.Join(dir, "args"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			var rules []string
+			fields := strings.Fields(string(args))
+			for i, field := range fields {
+				if field == "--config" && i+1 < len(fields) {
+					rules = append(rules, fields[i+1])
+				}
+			}
+			if !reflect.DeepEqual(rules, tt.wantRules) {
+				t.Errorf("semgrep ran with rule packs %v, want %v", rules, tt.wantRules)
+			}
+		})
+	}
+}
+
+func TestSecurityScannerSemgrepFileName(t *testing.T) {
+	dir := t.TempDir()
+	semgrepPath := fakeScript(t, "for last; do :; done\nbasename \"$last\" > "+filepath.Join(dir, "file")+"\necho '{\"results\": []}'")
+	s := NewSecurityScanner(map[string]string{"semgrepPath": semgrepPath})
+	
+	tests := []struct {
+		name        string
+		language    string
+		path        string
+		wantFile    string
+		wantSkipped bool
+	}{
+		{name: "JavaScript without a path", language: "javascript", wantFile: "code.js"},
+		{name: "JSX", language: "javascript", path: "src/Button.jsx", wantFile: "code.jsx"},
+		{name: "TSX", language: "typescript", path: "src/App.tsx", wantFile: "code.tsx"},
+		{name: "JavaScript path for TypeScript", language: "typescript", path: "src/App.jsx", wantFile: "code.ts"},
+		{name: "Vue component", language: "javascript", path: "src/App.vue", wantSkipped: true},
+		{name: "Svelte component", language: "typescript", path: "src/App.svelte", wantSkipped: true},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			os.Remove(filepath.Join(dir, "file"))
+			issues, err := s.Scan(context.Background(), tt.language, "eval(x)\n", map[string]interface{}{"path": tt.path})
+			if err != nil {
+				t.Fatalf("Scan() error = %v", err)
+			}
+			
+			file, err := ioutil.ReadFile(filepath.Join(dir, "file"))
+			if tt.wantSkipped {
+				if err == nil {
+					t.Errorf("semgrep ran on %s, want it skipped", file)
+				}
+				if len(issues) != 1 || issues[0].RuleID != "analysis-skipped" {
+					t.Errorf("got issues %+v, want the skipped scan noted", issues)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := strings.TrimSpace(string(file)); got != tt.wantFile {
+				t.Errorf("semgrep scanned %s, want %s", got, tt.wantFile)
+			}
+		})
+	}
+}
+
+func TestSecuritySeverity(t *testing.T) {
+	tests := []struct {
+		severity string
+		want     string
+	}{
+		{"HIGH", "error"},
+		{"error", "error"},
+		{"MEDIUM", "warning"},
+		{"WARNING", "warning"},
+		{"LOW", "info"},
+		{"", "info"},
+	}
+	
+	for _, tt := range tests {
+		if got := securitySeverity(tt.severity); got != tt.want {
+			t.Errorf("securitySeverity(%q) = %q, want %q", tt.severity, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,260 @@
+package analyzer
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// ShellLinter implements the Linter interface for shell scripts
+type ShellLinter struct {
+	*BaseAnalyzer
+	shellcheckPath string
+}
+
+// NewShellLinter creates a new shell script linter
+func NewShellLinter(config map[string]string) *ShellLinter {
+	// Default shellcheck path
+	shellcheckPath := "shellcheck"
+	if path, ok := config["shellcheckPath"]; ok && path != "" {
+		shellcheckPath = path
+	}
+	
+	return &ShellLinter{
+		BaseAnalyzer:   NewBaseAnalyzer(config),
+		shellcheckPath: shellcheckPath,
+	}
+}
+
+// Language returns the identifier for the supported language
+func (l *ShellLinter) Language() string {
+	return "shell"
+}
+
+// Analyze analyzes the provided code and returns issues found
+func (l *ShellLinter) Analyze(ctx context.Context, code string, options map[string]interface{}) (*AnalysisResult, error) {
+	return l.BaseAnalyzer.CommonAnalyzeWrapper(
+		ctx,
+		code,
+		options,
+		l.findIssues,
+		l.SuggestFixes,
+	)
+}
+
+// findIssues analyzes the code and returns issues
+func (l *ShellLinter) findIssues(ctx context.Context, code string, options map[string]interface{}) ([]Issue, error) {
+	dialect := l.GetStringOption(options, "shell", detectShellDialect(code))
+	
+	// shellcheck only understands POSIX-family shells
+	switch dialect {
+	case "sh", "bash", "dash", "ksh", "busybox":
+	default:
+		return []Issue{{
+			Line:     1,
+			Message:  fmt.Sprintf("shellcheck does not support %s scripts, so the script was not analyzed", dialect),
+			Severity: "info",
+			RuleID:   "analysis-skipped",
+		}}, nil
+	}
+	
+	return l.runShellcheck(ctx, code, dialect, options)
+}
+
+// detectShellDialect returns the shell named by the script's shebang, defaulting to bash
+func detectShellDialect(code string) string {
+	if !strings.HasPrefix(code, "#!") {
+		return "bash"
+	}
+	
+	firstLine := code
+	if end := strings.IndexByte(code, '\n'); end >= 0 {
+		firstLine = code[:end]
+	}
+	
+	// "#!/usr/bin/env bash" names the shell in its first argument, "#!/bin/sh -e" in the interpreter
+	fields := strings.Fields(strings.TrimPrefix(firstLine, "#!"))
+	if len(fields) == 0 {
+		return "bash"
+	}
+	interpreter := filepath.Base(fields[0])
+	if interpreter == "env" {
+		interpreter = ""
+		for _, field := range fields[1:] {
+			if !strings.HasPrefix(field, "-") {
+				interpreter = filepath.Base(field)
+				break
+			}
+		}
+	}
+	
+	switch interpreter {
+	case "ash":
+		return "busybox"
+	case "mksh", "pdksh":
+		return "ksh"
+	case "":
+		return "bash"
+	}
+	return interpreter
+}
+
+// shellcheckComment is a single finding in shellcheck's json1 output. Columns count
+// characters, with tabs as a single column, and the end column is exclusive.
+type shellcheckComment struct {
+	Line      int    `json:"line"`
+	EndLine   int    `json:"endLine"`
+	Column    int    `json:"column"`
+	EndColumn int    `json:"endColumn"`
+	Level     string `json:"level"`
+	Code      int    `json:"code"`
+	Message   string `json:"message"`
+	Fix       *struct {
+		Replacements []struct {
+			Line           int    `json:"line"`
+			EndLine        int    `json:"endLine"`
+			Column         int    `json:"column"`
+			EndColumn      int    `json:"endColumn"`
+			InsertionPoint string `json:"insertionPoint"`
+			Replacement    string `json:"replacement"`
+		} `json:"replacements"`
+	} `json:"fix"`
+}
+
+// runShellcheck runs shellcheck on the code and parses its output
+func (l *ShellLinter) runShellcheck(ctx context.Context, code, dialect string, options map[string]interface{}) ([]Issue, error) {
+	args := []string{
+		"--format=json1",
+		"--shell=" + dialect,
+		"--norc",
+	}
+	
+	// Minimum level to report: error, warning, info or style
+	if severity := l.GetStringOption(options, "shellcheckSeverity", ""); severity != "" {
+		args = append(args, "--severity="+severity)
+	}
+	
+	if excluded := l.GetListOption(options, "shellcheckExclude", nil); len(excluded) > 0 {
+		args = append(args, "--exclude="+strings.Join(excluded, ","))
+	}
+	
+	// Optional checks, e.g. "require-variable-braces" or "all"
+	if enabled := l.GetListOption(options, "shellcheckEnable", nil); len(enabled) > 0 {
+		args = append(args, "--enable="+strings.Join(enabled, ","))
+	}
+	
+	args = append(args, "-")
+	
+	cmd := exec.CommandContext(ctx, l.shellcheckPath, args...)
+	
+	var stdout, stderr bytes.Buffer
+	cmd.Stdin = strings.NewReader(code)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	
+	// Exit code 1 is normal when shellcheck finds issues
+	err := cmd.Run()
+	if err != nil && !strings.Contains(err.Error(), "exit status 1") {
+		return nil, l.WrapError(fmt.Errorf("failed to run shellcheck: %w, stderr: %s", err, stderr.String()), "shellcheck execution error")
+	}
+	
+	var result struct {
+		Comments []shellcheckComment `json:"comments"`
+	}
+	if err := json.Unmarshal(stdout.Bytes(), &result); err != nil {
+		return nil, l.WrapError(err, "failed to parse shellcheck output")
+	}
+	
+	issues := make([]Issue, 0, len(result.Comments))
+	for _, comment := range result.Comments {
+		issues = append(issues, l.convertComment(comment, code))
+	}
+	
+	return issues, nil
+}
+
+// convertComment converts a shellcheck comment to a CodeHawk issue
+func (l *ShellLinter) convertComment(comment shellcheckComment, code string) Issue {
+	ruleID := fmt.Sprintf("SC%d", comment.Code)
+	
+	var severity string
+	switch comment.Level {
+	case "error":
+		severity = "error"
+	case "warning":
+		severity = "warning"
+	case "style":
+		severity = "suggestion"
+	default:
+		severity = "info"
+	}
+	
+	column := comment.Column
+	issue := Issue{
+		Line:     comment.Line,
+		Column:   &column,
+		Message:  comment.Message,
+		Severity: severity,
+		RuleID:   ruleID,
+		Context:  "https://www.shellcheck.net/wiki/" + ruleID,
+	}
+	
+	if comment.Fix == nil || len(comment.Fix.Replacements) == 0 {
+		return issue
+	}
+	
+	type placedEdit struct {
+		textEdit
+		afterEnd bool
+	}
+	
+	placed := make([]placedEdit, 0, len(comment.Fix.Replacements))
+	for _, replacement := range comment.Fix.Replacements {
+		placed = append(placed, placedEdit{
+			textEdit: textEdit{
+				start: positionToOffset(code, replacement.Line, replacement.Column),
+				end:   positionToOffset(code, replacement.EndLine, replacement.EndColumn),
+				text:  replacement.Replacement,
+			},
+			afterEnd: replacement.InsertionPoint == "afterEnd",
+		})
+	}
+	
+	// Insertions at the same point go in source order: text closing the preceding token
+	// (afterEnd) before text opening the following one (beforeStart)
+	sort.SliceStable(placed, func(i, j int) bool {
+		if placed[i].start != placed[j].start {
+			return placed[i].start < placed[j].start
+		}
+		return placed[i].afterEnd && !placed[j].afterEnd
+	})
+	
+	edits := make([]textEdit, 0, len(placed))
+	for _, edit := range placed {
+		last := len(edits) - 1
+		if last >= 0 && edits[last].start == edit.start && edits[last].end == edits[last].start {
+			// Fold consecutive insertions at one point into a single edit
+			edits[last].text += edit.text
+			edits[last].end = edit.end
+			continue
+		}
+		edits = append(edits, edit.textEdit)
+	}
+	
+	if fix, err := buildRangeFix(code, fmt.Sprintf("Apply shellcheck fix for %s", ruleID), edits); err == nil {
+		issue.Fix = fix
+	}
+	
+	return issue
+}
+
+// SuggestFixes attempts to generate fixes for the identified issues
+func (l *ShellLinter) SuggestFixes(ctx context.Context, code string, issues []Issue) ([]Issue, error) {
+	// shellcheck's own fixes are attached to the issues they belong to
+	return []Issue{}, nil
+}
@@ -0,0 +1,122 @@
+package analyzer
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+)
+
+func TestDetectShellDialect(t *testing.T) {
+	tests := []struct {
+		code string
+		want string
+	}{
+		{"echo hi\n", "bash"},
+		{"#!/bin/sh -e\necho hi\n", "sh"},
+		{"#!/usr/bin/env bash\n", "bash"},
+		{"#!/usr/bin/env -S dash -x\n", "dash"},
+		{"#!/bin/ash\n", "busybox"},
+		{"#!/bin/mksh\n", "ksh"},
+		{"#!/usr/bin/env zsh\n", "zsh"},
+		{"#!\n", "bash"},
+	}
+	
+	for _, tt := range tests {
+		if got := detectShellDialect(tt.code); got != tt.want {
+			t.Errorf("detectShellDialect(%q) = %q, want %q", tt.code, got, tt.want)
+		}
+	}
+}
+
+func TestShellConvertComment(t *testing.T) {
+	code := "#!/bin/bash\nrm -rf $dir/tmp\n"
+	tests := []struct {
+		name         string
+		comment      string
+		wantSeverity string
+		wantFixed    string
+	}{
+		{
+			name: "quoting fix from two insertions",
+			comment: `{"line": 2, "endLine": 2, "column": 8, "endColumn": 12, "level": "info", "code": 2086,
+				"message": "Double quote to prevent globbing and word splitting.",
+				"fix": {"replacements": [
+					{"line": 2, "endLine": 2, "column": 12, "endColumn": 12, "insertionPoint": "afterEnd", "replacement": "\""},
+					{"line": 2, "endLine": 2, "column": 8, "endColumn": 8, "insertionPoint": "beforeStart", "replacement": "\""}
+				]}}`,
+			wantSeverity: "info",
+			wantFixed:    "#!/bin/bash\nrm -rf \"$dir\"/tmp\n",
+		},
+		{
+			name:         "style finding without a fix",
+			comment:      `{"line": 2, "endLine": 2, "column": 1, "endColumn": 3, "level": "style", "code": 2001, "message": "See if you can use ${variable//search/replace} instead."}`,
+			wantSeverity: "suggestion",
+		},
+		{
+			name:         "error",
+			comment:      `{"line": 2, "endLine": 2, "column": 1, "endColumn": 3, "level": "error", "code": 1073, "message": "Couldn't parse this."}`,
+			wantSeverity: "error",
+		},
+	}
+	
+	l := NewShellLinter(map[string]string{})
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var comment shellcheckComment
+			if err := json.Unmarshal([]byte(tt.comment), &comment); err != nil {
+				t.Fatal(err)
+			}
+			
+			issue := l.convertComment(comment, code)
+			if issue.Severity != tt.wantSeverity || issue.RuleID != fmt.Sprintf("SC%d", comment.Code) {
+				t.Errorf("issue = %s %s, want %s SC%d", issue.Severity, issue.RuleID, tt.wantSeverity, comment.Code)
+			}
+			if issue.Context != "https://www.shellcheck.net/wiki/"+issue.RuleID {
+				t.Errorf("context = %q, want the wiki page of %s", issue.Context, issue.RuleID)
+			}
+			
+			if tt.wantFixed == "" {
+				if issue.Fix != nil {
+					t.Errorf("got fix %+v, want none", issue.Fix)
+				}
+				return
+			}
+			if issue.Fix == nil {
+				t.Fatal("got no fix")
+			}
+			fixed, err := ApplyFix(code, issue.Line, *issue.Fix)
+			if err != nil || fixed != tt.wantFixed {
+				t.Errorf("fixed code = %q (%v), want %q", fixed, err, tt.wantFixed)
+			}
+		})
+	}
+}
+
+func TestShellFindIssues(t *testing.T) {
+	output := `{"comments": [{"file": "-", "line": 2, "endLine": 2, "column": 6, "endColumn": 8, "level": "warning", "code": 2034, "message": "foo appears unused."}]}`
+	l := NewShellLinter(map[string]string{"shellcheckPath": fakeTool(t, output)})
+	
+	tests := []struct {
+		name       string
+		code       string
+		options    map[string]interface{}
+		wantRuleID string
+	}{
+		{"bash script", "#!/bin/bash\nfoo=1\n", nil, "SC2034"},
+		{"dialect option", "foo=1\n", map[string]interface{}{"shell": "sh"}, "SC2034"},
+		{"unsupported shell", "#!/usr/bin/env fish\nset foo 1\n", nil, "analysis-skipped"},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			issues, err := l.findIssues(context.Background(), tt.code, tt.options)
+			if err != nil {
+				t.Fatalf("findIssues() error = %v", err)
+			}
+			if len(issues) != 1 || issues[0].RuleID != tt.wantRuleID {
+				t.Errorf("issues = %+v, want one %s", issues, tt.wantRuleID)
+			}
+		})
+	}
+}
@@ -0,0 +1,519 @@
+package analyzer
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"path"
+	"regexp"
+	"strings"
+)
+
+// sqlDialects lists the supported dialects, named as sqlfluff names them
+var sqlDialects = map[string]bool{
+	"ansi":      true,
+	"postgres":  true,
+	"mysql":     true,
+	"sqlite":    true,
+	"tsql":      true,
+	"bigquery":  true,
+	"snowflake": true,
+}
+
+var (
+	// sqlMigrationFilePattern matches golang-migrate style file names, e.g. 000001_init_schema.up.sql
+	sqlMigrationFilePattern = regexp.MustCompile(`^(.+)\.(up|down)\.sql$`)
+	
+	// sqlCreateTablePattern matches CREATE TABLE statements
+	sqlCreateTablePattern = regexp.MustCompile(`(?is)^CREATE\s+(?:OR\s+REPLACE\s+)?(?:(?:GLOBAL|LOCAL)\s+)?(?:TEMP(?:ORARY)?\s+|UNLOGGED\s+)?TABLE\s+(?:IF\s+NOT\s+EXISTS\s+)?([\w."` + "`" + `]+)`)
+	
+	// sqlDropTablePattern matches DROP TABLE statements
+	sqlDropTablePattern = regexp.MustCompile(`(?is)^DROP\s+TABLE\s+(?:IF\s+EXISTS\s+)?([\w."` + "`" + `]+)`)
+	
+	// sqlCreateIndexPattern matches CREATE INDEX statements, capturing the INDEX keyword, an
+	// optional CONCURRENTLY and the indexed table
+	sqlCreateIndexPattern = regexp.MustCompile(`(?is)^CREATE\s+(?:UNIQUE\s+)?(INDEX)(\s+CONCURRENTLY)?(?:\s+IF\s+NOT\s+EXISTS)?(?:\s+[\w."` + "`" + `]+)?\s+ON\s+(?:ONLY\s+)?([\w."` + "`" + `]+)`)
+	
+	// sqlAlterTablePattern matches ALTER TABLE statements, capturing the table and its actions
+	sqlAlterTablePattern = regexp.MustCompile(`(?is)^ALTER\s+TABLE\s+(?:IF\s+EXISTS\s+)?(?:ONLY\s+)?([\w."` + "`" + `]+)\s+(.*)$`)
+	
+	// sqlAddColumnPattern matches an ADD COLUMN action
+	sqlAddColumnPattern = regexp.MustCompile(`(?is)^ADD\s+(?:COLUMN\s+)?(?:IF\s+NOT\s+EXISTS\s+)?([\w"` + "`" + `]+)\s+(.*)$`)
+	
+	// sqlDropColumnPattern matches a DROP COLUMN action
+	sqlDropColumnPattern = regexp.MustCompile(`(?is)^DROP\s+(?:COLUMN\s+)?(?:IF\s+EXISTS\s+)?([\w"` + "`" + `]+)`)
+	
+	// sqlAlterTypePattern matches an action changing a column's type
+	sqlAlterTypePattern = regexp.MustCompile(`(?is)^(?:ALTER\s+(?:COLUMN\s+)?([\w"` + "`" + `]+)\s+(?:SET\s+DATA\s+)?TYPE\b|MODIFY\s+(?:COLUMN\s+)?([\w"` + "`" + `]+))`)
+	
+	// sqlNotNullPattern, sqlDefaultPattern and sqlGeneratedPattern inspect column definitions
+	sqlNotNullPattern   = regexp.MustCompile(`(?i)\bNOT\s+NULL\b`)
+	sqlDefaultPattern   = regexp.MustCompile(`(?i)\bDEFAULT\b`)
+	sqlGeneratedPattern = regexp.MustCompile(`(?i)\b(?:GENERATED|IDENTITY|AUTO_INCREMENT|(?:SMALL|BIG)?SERIAL)\b`)
+	
+	// sqlLockNonePattern matches MySQL's online DDL clause
+	sqlLockNonePattern = regexp.MustCompile(`(?i)\bLOCK\s*=\s*NONE\b`)
+	
+	// sqlDollarTagPattern matches the opening tag of a PostgreSQL dollar-quoted string
+	sqlDollarTagPattern = regexp.MustCompile(`^\$[A-Za-z_]*\$`)
+	
+	// sqlMigrationMarkerPattern matches goose and sql-migrate section markers
+	sqlMigrationMarkerPattern = regexp.MustCompile(`(?im)^--\s*\+(goose|migrate)\s+(Up|Down)\b`)
+)
+
+// sqlConstraintKeywords are words that follow ADD or DROP in ALTER TABLE without naming a column
+var sqlConstraintKeywords = map[string]bool{
+	"CONSTRAINT": true,
+	"PRIMARY":    true,
+	"FOREIGN":    true,
+	"UNIQUE":     true,
+	"CHECK":      true,
+	"INDEX":      true,
+	"KEY":        true,
+	"DEFAULT":    true,
+	"NOT":        true,
+	"IDENTITY":   true,
+	"EXPRESSION": true,
+	"PARTITION":  true,
+	"FULLTEXT":   true,
+	"SPATIAL":    true,
+}
+
+// SQLLinter implements the Linter interface for SQL files and migrations
+type SQLLinter struct {
+	*BaseAnalyzer
+	sqlfluffPath string
+}
+
+// NewSQLLinter creates a new SQL linter
+func NewSQLLinter(config map[string]string) *SQLLinter {
+	// Default sqlfluff path
+	sqlfluffPath := "sqlfluff"
+	if path, ok := config["sqlfluffPath"]; ok && path != "" {
+		sqlfluffPath = path
+	}
+	
+	return &SQLLinter{
+		BaseAnalyzer: NewBaseAnalyzer(config),
+		sqlfluffPath: sqlfluffPath,
+	}
+}
+
+// Language returns the identifier for the supported language
+func (l *SQLLinter) Language() string {
+	return "sql"
+}
+
+// Analyze analyzes the provided code and returns issues found
+func (l *SQLLinter) Analyze(ctx context.Context, code string, options map[string]interface{}) (*AnalysisResult, error) {
+	return l.BaseAnalyzer.CommonAnalyzeWrapper(
+		ctx,
+		code,
+		options,
+		l.findIssues,
+		l.SuggestFixes,
+	)
+}
+
+// sqlStatement is a statement of a SQL file. The masked text has comments and string
+// literals blanked out but keeps the offsets of the original.
+type sqlStatement struct {
+	start  int
+	masked string
+}
+
+// findIssues checks the migration patterns and, when enabled, runs sqlfluff
+func (l *SQLLinter) findIssues(ctx context.Context, code string, options map[string]interface{}) ([]Issue, error) {
+	dialect := strings.ToLower(l.GetStringOption(options, "dialect", "postgres"))
+	if !sqlDialects[dialect] {
+		return nil, fmt.Errorf("unsupported SQL dialect: %s", dialect)
+	}
+	
+	issues := l.checkMigration(code, dialect, options)
+	
+	if l.GetBoolOption(options, "sqlfluff", false) {
+		sqlfluffIssues, err := l.runSqlfluff(ctx, code, dialect)
+		if err != nil {
+			// Log the error but keep the migration checks
+			fmt.Printf("Warning: sqlfluff failed: %v\n", err)
+		}
+		issues = append(issues, sqlfluffIssues...)
+	}
+	
+	return issues, nil
+}
+
+// checkMigration reports risky schema changes. The file name given by the "path" option tells
+// up migrations from down migrations; destructive changes are expected in the latter.
+func (l *SQLLinter) checkMigration(code, dialect string, options map[string]interface{}) []Issue {
+	issues := make([]Issue, 0)
+	fileName := path.Base(l.GetStringOption(options, "path", ""))
+	
+	migrationName, direction := "", ""
+	if matches := sqlMigrationFilePattern.FindStringSubmatch(fileName); matches != nil {
+		migrationName, direction = matches[1], matches[2]
+	}
+	
+	statements := splitSQLStatements(maskSQL(code))
+	
+	// Indexes on tables created by the same migration cannot block anything
+	createdTables := make(map[string]bool)
+	for _, statement := range statements {
+		if matches := sqlCreateTablePattern.FindStringSubmatch(statement.masked); matches != nil {
+			createdTables[sqlIdentifier(matches[1])] = true
+		}
+	}
+	
+	report := func(offset int, ruleID, severity, message string, fix *IssueFix) {
+		line, column := offsetToPosition(code, offset)
+		issues = append(issues, Issue{
+			Line:     line,
+			Column:   &column,
+			Message:  message,
+			Severity: severity,
+			RuleID:   ruleID,
+			Fix:      fix,
+		})
+	}
+	
+	for _, statement := range statements {
+		text := statement.masked
+		
+		if matches := sqlCreateIndexPattern.FindStringSubmatchIndex(text); matches != nil {
+			table := sqlIdentifier(text[matches[6]:matches[7]])
+			concurrent := matches[4] >= 0
+			if createdTables[table] {
+				continue
+			}
+			
+			switch dialect {
+			case "postgres":
+				if !concurrent {
+					insertAt := statement.start + matches[3]
+					fix, _ := buildRangeFix(code, "Build the index concurrently (the migration must not run inside a transaction)",
+						[]textEdit{{start: insertAt, end: insertAt, text: " CONCURRENTLY"}})
+					report(statement.start, "sql-index-not-concurrent", "warning",
+						fmt.Sprintf("CREATE INDEX on %s without CONCURRENTLY blocks writes to the table while the index is built", table), fix)
+				}
+			case "mysql":
+				if !sqlLockNonePattern.MatchString(text) {
+					report(statement.start, "sql-index-not-concurrent", "warning",
+						fmt.Sprintf("CREATE INDEX on %s without LOCK=NONE may block writes to the table while the index is built", table), nil)
+				}
+			}
+			continue
+		}
+		
+		if direction != "down" {
+			if matches := sqlDropTablePattern.FindStringSubmatch(text); matches != nil {
+				report(statement.start, "sql-drop-table", "warning",
+					fmt.Sprintf("Dropping table %s permanently deletes its data; make sure no deployed code still uses it", sqlIdentifier(matches[1])), nil)
+				continue
+			}
+		}
+		
+		matches := sqlAlterTablePattern.FindStringSubmatchIndex(text)
+		if matches == nil {
+			continue
+		}
+		table := sqlIdentifier(text[matches[2]:matches[3]])
+		
+		for _, action := range splitSQLTopLevel(text, matches[4], matches[5]) {
+			actionText := text[action[0]:action[1]]
+			offset := statement.start + action[0]
+			
+			if add := sqlAddColumnPattern.FindStringSubmatch(actionText); add != nil && !sqlConstraintKeywords[strings.ToUpper(add[1])] {
+				definition := add[2]
+				if sqlNotNullPattern.MatchString(definition) && !sqlDefaultPattern.MatchString(definition) && !sqlGeneratedPattern.MatchString(definition) && !createdTables[table] {
+					report(offset, "sql-not-null-without-default", "error",
+						fmt.Sprintf("Adding NOT NULL column %s to %s without a DEFAULT fails on tables that already contain rows", sqlIdentifier(add[1]), table), nil)
+				}
+				continue
+			}
+			
+			if direction != "down" {
+				if drop := sqlDropColumnPattern.FindStringSubmatch(actionText); drop != nil && !sqlConstraintKeywords[strings.ToUpper(drop[1])] {
+					report(offset, "sql-drop-column", "warning",
+						fmt.Sprintf("Dropping column %s from %s breaks code that still reads it; deploy the code change first", sqlIdentifier(drop[1]), table), nil)
+					continue
+				}
+			}
+			
+			if alter := sqlAlterTypePattern.FindStringSubmatch(actionText); alter != nil {
+				column := alter[1]
+				if column == "" {
+					column = alter[2]
+				}
+				report(offset, "sql-column-type-change", "warning",
+					fmt.Sprintf("Changing the type of %s.%s may rewrite the table and lock it for the duration", table, sqlIdentifier(column)), nil)
+			}
+		}
+	}
+	
+	// Every up migration needs a way back
+	if migrationName != "" {
+		files := l.GetFilesOption(options)
+		if len(files) > 0 {
+			counterpart := "down"
+			if direction == "down" {
+				counterpart = "up"
+			}
+			
+			found := false
+			for filePath := range files {
+				if path.Base(filePath) == migrationName+"."+counterpart+".sql" {
+					found = true
+					break
+				}
+			}
+			if !found {
+				issues = append(issues, Issue{
+					Line:     1,
+					Message:  fmt.Sprintf("Migration %s has no %s migration; add %s.%s.sql", migrationName, counterpart, migrationName, counterpart),
+					Severity: "warning",
+					RuleID:   "sql-missing-down-migration",
+				})
+			}
+		}
+	} else if markers := sqlMigrationMarkerPattern.FindAllStringSubmatch(code, -1); len(markers) > 0 {
+		// goose and sql-migrate keep both directions in one file
+		hasDown := false
+		for _, marker := range markers {
+			if strings.EqualFold(marker[2], "Down") {
+				hasDown = true
+			}
+		}
+		if !hasDown {
+			issues = append(issues, Issue{
+				Line:     1,
+				Message:  fmt.Sprintf("Migration has no -- +%s Down section", markers[0][1]),
+				Severity: "warning",
+				RuleID:   "sql-missing-down-migration",
+			})
+		}
+	}
+	
+	return issues
+}
+
+// maskSQL blanks out comments and string literals, keeping newlines so that offsets and
+// positions in the result match the original
+func maskSQL(code string) string {
+	masked := []byte(code)
+	blank := func(start, end int) {
+		for i := start; i < end; i++ {
+			if masked[i] != '\n' {
+				masked[i] = ' '
+			}
+		}
+	}
+	
+	for i := 0; i < len(code); {
+		switch {
+		case strings.HasPrefix(code[i:], "--"):
+			end := strings.IndexByte(code[i:], '\n')
+			if end < 0 {
+				end = len(code) - i
+			}
+			blank(i, i+end)
+			i += end
+			
+		case strings.HasPrefix(code[i:], "/*"):
+			end := strings.Index(code[i+2:], "*/")
+			if end < 0 {
+				end = len(code) - i
+			} else {
+				end += 4
+			}
+			blank(i, i+end)
+			i += end
+			
+		case code[i] == '\'':
+			// Quotes are escaped by doubling them
+			end := i + 1
+			for end < len(code) {
+				if code[end] == '\'' {
+					if end+1 < len(code) && code[end+1] == '\'' {
+						end += 2
+						continue
+					}
+					break
+				}
+				end++
+			}
+			if end < len(code) {
+				end++
+			}
+			blank(i, end)
+			i = end
+			
+		case code[i] == '$':
+			// PostgreSQL dollar quoting, e.g. $$ ... $$ or $body$ ... $body$
+			tag := sqlDollarTagPattern.FindString(code[i:])
+			if tag == "" {
+				i++
+				continue
+			}
+			end := strings.Index(code[i+len(tag):], tag)
+			if end < 0 {
+				end = len(code) - i
+			} else {
+				end += 2 * len(tag)
+			}
+			blank(i, i+end)
+			i += end
+			
+		default:
+			i++
+		}
+	}
+	
+	return string(masked)
+}
+
+// splitSQLStatements splits masked SQL into statements at semicolons
+func splitSQLStatements(masked string) []sqlStatement {
+	statements := make([]sqlStatement, 0)
+	start := 0
+	for i := 0; i <= len(masked); i++ {
+		if i < len(masked) && masked[i] != ';' {
+			continue
+		}
+		
+		text := masked[start:i]
+		trimmed := strings.TrimLeft(text, " \t\r\n")
+		if strings.TrimSpace(trimmed) != "" {
+			statements = append(statements, sqlStatement{
+				start:  start + len(text) - len(trimmed),
+				masked: strings.TrimRight(trimmed, " \t\r\n"),
+			})
+		}
+		start = i + 1
+	}
+	return statements
+}
+
+// splitSQLTopLevel splits text[start:end] at commas outside parentheses, returning the trimmed
+// [start, end) offsets of each part
+func splitSQLTopLevel(text string, start, end int) [][2]int {
+	parts := make([][2]int, 0)
+	depth := 0
+	partStart := start
+	
+	addPart := func(from, to int) {
+		for from < to && strings.ContainsRune(" \t\r\n", rune(text[from])) {
+			from++
+		}
+		for to > from && strings.ContainsRune(" \t\r\n", rune(text[to-1])) {
+			to--
+		}
+		if from < to {
+			parts = append(parts, [2]int{from, to})
+		}
+	}
+	
+	for i := start; i < end; i++ {
+		switch text[i] {
+		case '(':
+			depth++
+		case ')':
+			depth--
+		case ',':
+			if depth == 0 {
+				addPart(partStart, i)
+				partStart = i + 1
+			}
+		}
+	}
+	addPart(partStart, end)
+	
+	return parts
+}
+
+// sqlIdentifier normalizes an identifier for comparison by removing quotes and case
+func sqlIdentifier(name string) string {
+	return strings.ToLower(strings.NewReplacer(`"`, "", "`", "").Replace(name))
+}
+
+// sqlfluffResult is a single file of sqlfluff's JSON output
+type sqlfluffResult struct {
+	Violations []struct {
+		Code        string `json:"code"`
+		Description string `json:"description"`
+		Name        string `json:"name"`
+		StartLineNo int    `json:"start_line_no"`
+		StartPos    int    `json:"start_line_pos"`
+		LineNo      int    `json:"line_no"`
+		LinePos     int    `json:"line_pos"`
+		Warning     bool   `json:"warning"`
+	} `json:"violations"`
+}
+
+// runSqlfluff runs sqlfluff lint on the code and parses its output
+func (l *SQLLinter) runSqlfluff(ctx context.Context, code, dialect string) ([]Issue, error) {
+	cmd := exec.CommandContext(
+		ctx,
+		l.sqlfluffPath,
+		"lint",
+		"--format", "json",
+		"--dialect", dialect,
+		"--disable-progress-bar",
+		"--nofail",
+		"-",
+	)
+	
+	var stdout, stderr bytes.Buffer
+	cmd.Stdin = strings.NewReader(code)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	
+	if err := cmd.Run(); err != nil {
+		return nil, l.WrapError(fmt.Errorf("failed to run sqlfluff: %w, stderr: %s", err, stderr.String()), "sqlfluff execution error")
+	}
+	
+	var results []sqlfluffResult
+	if err := json.Unmarshal(stdout.Bytes(), &results); err != nil {
+		return nil, l.WrapError(err, "failed to parse sqlfluff output")
+	}
+	
+	issues := make([]Issue, 0)
+	for _, result := range results {
+		for _, violation := range result.Violations {
+			// sqlfluff 2 renamed line_no/line_pos to start_line_no/start_line_pos
+			line, column := violation.StartLineNo, violation.StartPos
+			if line == 0 {
+				line, column = violation.LineNo, violation.LinePos
+			}
+			
+			severity := "suggestion"
+			if violation.Code == "PRS" || violation.Code == "TMP" {
+				severity = "error"
+			} else if violation.Warning {
+				severity = "info"
+			}
+			
+			issue := Issue{
+				Line:     line,
+				Message:  violation.Description,
+				Severity: severity,
+				RuleID:   violation.Code,
+				Context:  violation.Name,
+			}
+			if column > 0 {
+				issue.Column = &column
+			}
+			
+			issues = append(issues, issue)
+		}
+	}
+	
+	return issues, nil
+}
+
+// SuggestFixes attempts to generate fixes for the identified issues
+func (l *SQLLinter) SuggestFixes(ctx context.Context, code string, issues []Issue) ([]Issue, error) {
+	// Fixes are attached to the migration issues they belong to
+	return []Issue{}, nil
+}
@@ -0,0 +1,205 @@
+package analyzer
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestMaskSQL(t *testing.T) {
+	tests := []struct {
+		name string
+		code string
+		want string
+	}{
+		{"line comment", "SELECT 1; -- DROP TABLE users\n", "SELECT 1; " + strings.Repeat(" ", 19) + "\n"},
+		{"block comment keeps newlines", "/* a\nb */SELECT", "    \n    SELECT"},
+		{"doubled quotes", "SELECT 'it''s';", "SELECT        ;"},
+		{"dollar quoting", "DO $fn$ DROP TABLE x; $fn$;", "DO " + strings.Repeat(" ", 23) + ";"},
+		{"parameter is not a dollar quote", "SELECT $1;", "SELECT $1;"},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := maskSQL(tt.code); got != tt.want {
+				t.Errorf("maskSQL(%q) = %q, want %q", tt.code, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSplitSQLStatements(t *testing.T) {
+	masked := "CREATE TABLE a (id int);\n\n  DROP TABLE b ;\n;"
+	statements := splitSQLStatements(masked)
+	
+	want := []sqlStatement{
+		{start: 0, masked: "CREATE TABLE a (id int)"},
+		{start: 28, masked: "DROP TABLE b"},
+	}
+	if len(statements) != len(want) {
+		t.Fatalf("got %d statements, want %d: %+v", len(statements), len(want), statements)
+	}
+	for i := range want {
+		if statements[i] != want[i] {
+			t.Errorf("statement %d = %+v, want %+v", i, statements[i], want[i])
+		}
+	}
+}
+
+func TestSQLCheckMigration(t *testing.T) {
+	tests := []struct {
+		name      string
+		code      string
+		dialect   string
+		options   map[string]interface{}
+		want      []string
+		wantFixed string
+	}{
+		{
+			name:      "blocking index on postgres",
+			code:      "CREATE INDEX idx_users_email ON users (email);\n",
+			dialect:   "postgres",
+			want:      []string{"sql-index-not-concurrent"},
+			wantFixed: "CREATE INDEX CONCURRENTLY idx_users_email ON users (email);\n",
+		},
+		{
+			name:    "index on a new table",
+			code:    "CREATE TABLE users (id int);\nCREATE INDEX idx ON users (id);\n",
+			dialect: "postgres",
+			want:    []string{},
+		},
+		{
+			name:    "online index on mysql",
+			code:    "CREATE INDEX idx ON users (email) LOCK=NONE;\n",
+			dialect: "mysql",
+			want:    []string{},
+		},
+		{
+			name:    "alter table actions",
+			code:    "ALTER TABLE users ADD COLUMN age int NOT NULL, DROP COLUMN nickname, ALTER COLUMN name TYPE text, ADD CONSTRAINT u UNIQUE (email);\n",
+			dialect: "postgres",
+			want:    []string{"sql-not-null-without-default", "sql-drop-column", "sql-column-type-change"},
+		},
+		{
+			name:    "not null column with a default",
+			code:    "ALTER TABLE users ADD COLUMN active boolean NOT NULL DEFAULT true;\n",
+			dialect: "postgres",
+			want:    []string{},
+		},
+		{
+			name:    "statements in comments and strings are ignored",
+			code:    "-- DROP TABLE users;\nINSERT INTO log VALUES ('DROP TABLE users');\n",
+			dialect: "postgres",
+			want:    []string{},
+		},
+		{
+			name:    "drops are expected in down migrations",
+			code:    "DROP TABLE users;\n",
+			dialect: "postgres",
+			options: map[string]interface{}{"path": "migrations/000001_users.down.sql"},
+			want:    []string{},
+		},
+		{
+			name:    "drop in an up migration",
+			code:    "DROP TABLE users;\n",
+			dialect: "postgres",
+			options: map[string]interface{}{"path": "migrations/000002_drop.up.sql"},
+			want:    []string{"sql-drop-table"},
+		},
+		{
+			name:    "missing down file",
+			code:    "SELECT 1;\n",
+			dialect: "postgres",
+			options: map[string]interface{}{
+				"path":  "migrations/000003_noop.up.sql",
+				"files": map[string]interface{}{"migrations/000001_users.down.sql": ""},
+			},
+			want: []string{"sql-missing-down-migration"},
+		},
+		{
+			name:    "down file present",
+			code:    "SELECT 1;\n",
+			dialect: "postgres",
+			options: map[string]interface{}{
+				"path":  "migrations/000003_noop.up.sql",
+				"files": map[string]interface{}{"migrations/000003_noop.down.sql": ""},
+			},
+			want: []string{},
+		},
+		{
+			name:    "goose file without a down section",
+			code:    "-- +goose Up\nSELECT 1;\n",
+			dialect: "postgres",
+			want:    []string{"sql-missing-down-migration"},
+		},
+	}
+	
+	l := NewSQLLinter(map[string]string{})
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			issues := l.checkMigration(tt.code, tt.dialect, tt.options)
+			if len(issues) != len(tt.want) {
+				t.Fatalf("got %d issues, want %v: %+v", len(issues), tt.want, issues)
+			}
+			for i, issue := range issues {
+				if issue.RuleID != tt.want[i] {
+					t.Errorf("issue %d = %s, want %s", i, issue.RuleID, tt.want[i])
+				}
+			}
+			
+			if tt.wantFixed != "" {
+				if issues[0].Fix == nil {
+					t.Fatal("got no fix")
+				}
+				fixed, err := ApplyFix(tt.code, issues[0].Line, *issues[0].Fix)
+				if err != nil || fixed != tt.wantFixed {
+					t.Errorf("fixed code = %q (%v), want %q", fixed, err, tt.wantFixed)
+				}
+			}
+		})
+	}
+}
+
+func TestSQLRunSqlfluff(t *testing.T) {
+	output := `[{"filepath": "stdin", "violations": [
+		{"start_line_no": 1, "start_line_pos": 8, "code": "LT01", "description": "Expected only single space.", "name": "layout.spacing", "warning": false},
+		{"line_no": 2, "line_pos": 1, "code": "PRS", "description": "Line 2, Position 1: Found unparsable section", "name": "", "warning": false},
+		{"start_line_no": 3, "start_line_pos": 0, "code": "AM04", "description": "Query produces an unknown number of result columns.", "name": "ambiguous.column_count", "warning": true}
+	]}]`
+	l := NewSQLLinter(map[string]string{"sqlfluffPath": fakeTool(t, output)})
+	
+	issues, err := l.runSqlfluff(context.Background(), "SELECT  1;\n", "postgres")
+	if err != nil {
+		t.Fatalf("runSqlfluff() error = %v", err)
+	}
+	
+	tests := []struct {
+		ruleID   string
+		line     int
+		column   int
+		severity string
+	}{
+		{"LT01", 1, 8, "suggestion"},
+		{"PRS", 2, 1, "error"},
+		{"AM04", 3, 0, "info"},
+	}
+	if len(issues) != len(tests) {
+		t.Fatalf("got %d issues, want %d: %+v", len(issues), len(tests), issues)
+	}
+	for i, tt := range tests {
+		issue := issues[i]
+		if issue.RuleID != tt.ruleID || issue.Line != tt.line || issue.Severity != tt.severity {
+			t.Errorf("issue %d = %+v, want %s at line %d (%s)", i, issue, tt.ruleID, tt.line, tt.severity)
+		}
+		if (tt.column == 0) != (issue.Column == nil) || (issue.Column != nil && *issue.Column != tt.column) {
+			t.Errorf("issue %d column = %v, want %d", i, issue.Column, tt.column)
+		}
+	}
+}
+
+func TestSQLUnsupportedDialect(t *testing.T) {
+	l := NewSQLLinter(map[string]string{})
+	if _, err := l.findIssues(context.Background(), "SELECT 1;", map[string]interface{}{"dialect": "oracle"}); err == nil {
+		t.Error("findIssues() accepted an unsupported dialect")
+	}
+}
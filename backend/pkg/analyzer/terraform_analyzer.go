@@ -0,0 +1,651 @@
+package analyzer
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/zclconf/go-cty/cty"
+)
+
+var (
+	// tfPublicPrincipalPattern matches bucket policies granting access to everyone
+	tfPublicPrincipalPattern = regexp.MustCompile(`"?Principal"?\s*[:=]\s*(?:"\*"|\{\s*"?AWS"?\s*[:=]\s*"\*"\s*\})`)
+	
+	// tfRegistrySourcePattern matches registry module sources such as "terraform-aws-modules/vpc/aws"
+	tfRegistrySourcePattern = regexp.MustCompile(`^(?:[\w.-]+/)?[\w-]+/[\w-]+/[\w-]+$`)
+)
+
+// tfTaggableResources lists common resource types that accept tags
+var tfTaggableResources = map[string]bool{
+	"aws_instance":                  true,
+	"aws_s3_bucket":                 true,
+	"aws_db_instance":               true,
+	"aws_rds_cluster":               true,
+	"aws_vpc":                       true,
+	"aws_subnet":                    true,
+	"aws_security_group":            true,
+	"aws_lb":                        true,
+	"aws_alb":                       true,
+	"aws_eks_cluster":               true,
+	"aws_ecs_cluster":               true,
+	"aws_ecs_service":               true,
+	"aws_lambda_function":           true,
+	"aws_dynamodb_table":            true,
+	"aws_elasticache_cluster":       true,
+	"aws_sqs_queue":                 true,
+	"aws_sns_topic":                 true,
+	"aws_kms_key":                   true,
+	"aws_ebs_volume":                true,
+	"aws_nat_gateway":               true,
+	"aws_internet_gateway":          true,
+	"azurerm_resource_group":        true,
+	"azurerm_virtual_network":       true,
+	"azurerm_linux_virtual_machine": true,
+	"azurerm_storage_account":       true,
+	"azurerm_kubernetes_cluster":    true,
+}
+
+// tfPublicACLs are canned S3 ACLs that expose bucket contents
+var tfPublicACLs = map[string]bool{
+	"public-read":        true,
+	"public-read-write":  true,
+	"authenticated-read": true,
+}
+
+// TerraformAnalyzer implements the Linter interface for Terraform configurations. Files are
+// parsed natively as HCL; tflint findings are merged in when the binary is installed.
+type TerraformAnalyzer struct {
+	*BaseAnalyzer
+	tflintPath string
+}
+
+// NewTerraformAnalyzer creates a new Terraform analyzer
+func NewTerraformAnalyzer(config map[string]string) *TerraformAnalyzer {
+	// Default tflint path
+	tflintPath := "tflint"
+	if path, ok := config["tflintPath"]; ok && path != "" {
+		tflintPath = path
+	}
+	
+	return &TerraformAnalyzer{
+		BaseAnalyzer: NewBaseAnalyzer(config),
+		tflintPath:   tflintPath,
+	}
+}
+
+// Language returns the identifier for the supported language
+func (a *TerraformAnalyzer) Language() string {
+	return "terraform"
+}
+
+// Analyze analyzes the provided configuration and returns issues found
+func (a *TerraformAnalyzer) Analyze(ctx context.Context, code string, options map[string]interface{}) (*AnalysisResult, error) {
+	return a.BaseAnalyzer.CommonAnalyzeWrapper(
+		ctx,
+		code,
+		options,
+		a.findIssues,
+		a.SuggestFixes,
+	)
+}
+
+// tfModule is the parsed configuration the analyzed file belongs to
+type tfModule struct {
+	// requiredProviders maps local provider names to their version constraints
+	requiredProviders map[string]string
+	// defaultTags is true when an AWS provider sets default_tags
+	defaultTags bool
+}
+
+// findIssues parses the configuration, runs the built-in checks and merges tflint's findings
+func (a *TerraformAnalyzer) findIssues(ctx context.Context, code string, options map[string]interface{}) ([]Issue, error) {
+	file, diags := hclsyntax.ParseConfig([]byte(code), "main.tf", hcl.InitialPos)
+	if diags.HasErrors() {
+		return hclDiagnosticIssues(diags), nil
+	}
+	body := file.Body.(*hclsyntax.Body)
+	
+	// Provider requirements are often declared in another file of the module
+	module := &tfModule{requiredProviders: make(map[string]string)}
+	module.collect(body)
+	files := a.GetFilesOption(options)
+	for filePath, content := range files {
+		if path.Ext(filePath) != ".tf" {
+			continue
+		}
+		if other, diags := hclsyntax.ParseConfig([]byte(content), filePath, hcl.InitialPos); !diags.HasErrors() {
+			module.collect(other.Body.(*hclsyntax.Body))
+		}
+	}
+	
+	issues := a.checkBody([]byte(code), body, module, options)
+	
+	// tflint is optional and only used when it is installed
+	if a.GetBoolOption(options, "tflint", true) {
+		if _, err := exec.LookPath(a.tflintPath); err == nil {
+			tflintIssues, err := a.runTflint(ctx, code, files)
+			if err != nil {
+				// Log the error but keep the built-in checks
+				fmt.Printf("Warning: tflint failed: %v\n", err)
+			}
+			issues = append(issues, tflintIssues...)
+		}
+	}
+	
+	return issues, nil
+}
+
+// collect records the provider requirements and default tags declared in a file
+func (m *tfModule) collect(body *hclsyntax.Body) {
+	for _, block := range body.Blocks {
+		switch block.Type {
+		case "terraform":
+			for _, inner := range block.Body.Blocks {
+				if inner.Type != "required_providers" {
+					continue
+				}
+				for name, attr := range inner.Body.Attributes {
+					m.requiredProviders[name] = tfProviderConstraint(attr.Expr)
+				}
+			}
+		case "provider":
+			for _, inner := range block.Body.Blocks {
+				if inner.Type == "default_tags" {
+					m.defaultTags = true
+				}
+			}
+		}
+	}
+}
+
+// tfProviderConstraint returns the version constraint of a required_providers entry, which is
+// either a legacy version string or an object with source and version attributes
+func tfProviderConstraint(expr hclsyntax.Expression) string {
+	if version, ok := tfString(expr); ok {
+		return version
+	}
+	
+	if object, ok := expr.(*hclsyntax.ObjectConsExpr); ok {
+		for _, item := range object.Items {
+			if hcl.ExprAsKeyword(item.KeyExpr) == "version" {
+				version, _ := tfString(item.ValueExpr)
+				return version
+			}
+		}
+	}
+	return ""
+}
+
+// checkBody runs the built-in checks against the top-level blocks of a file
+func (a *TerraformAnalyzer) checkBody(src []byte, body *hclsyntax.Body, module *tfModule, options map[string]interface{}) []Issue {
+	issues := make([]Issue, 0)
+	disabled := make(map[string]bool)
+	for _, rule := range a.GetListOption(options, "disabledRules", nil) {
+		disabled[rule] = true
+	}
+	requiredTags := a.GetListOption(options, "requiredTags", nil)
+	
+	report := func(rng hcl.Range, ruleID, severity, message string) {
+		if disabled[ruleID] {
+			return
+		}
+		column := rng.Start.Column
+		issues = append(issues, Issue{
+			Line:     rng.Start.Line,
+			Column:   &column,
+			Message:  message,
+			Severity: severity,
+			RuleID:   ruleID,
+		})
+	}
+	
+	for _, block := range body.Blocks {
+		switch block.Type {
+		case "terraform":
+			for _, inner := range block.Body.Blocks {
+				if inner.Type != "required_providers" {
+					continue
+				}
+				for _, name := range tfAttributeNames(inner.Body) {
+					attr := inner.Body.Attributes[name]
+					constraint := strings.TrimSpace(tfProviderConstraint(attr.Expr))
+					if constraint == "" {
+						report(attr.SrcRange, "tf-unpinned-provider", "warning",
+							fmt.Sprintf("Provider %s has no version constraint; new major versions will be installed automatically", name))
+					} else if strings.HasPrefix(constraint, ">") && !strings.ContainsAny(constraint, "<~") {
+						report(attr.SrcRange, "tf-unpinned-provider", "info",
+							fmt.Sprintf("Provider %s version constraint %q has no upper bound; consider a pessimistic constraint such as \"~> 5.0\"", name, constraint))
+					}
+				}
+			}
+			
+		case "provider":
+			if len(block.Labels) == 0 {
+				continue
+			}
+			name := block.Labels[0]
+			if _, ok := module.requiredProviders[name]; !ok {
+				if _, legacy := block.Body.Attributes["version"]; !legacy {
+					report(block.DefRange(), "tf-unpinned-provider", "warning",
+						fmt.Sprintf("Provider %s is not listed in required_providers, so its version is not pinned", name))
+				}
+			}
+			
+		case "module":
+			source, ok := tfAttributeString(block.Body, "source")
+			if !ok {
+				continue
+			}
+			_, hasVersion := block.Body.Attributes["version"]
+			unpinnedGit := strings.HasPrefix(source, "git::") || strings.HasPrefix(source, "github.com/")
+			if (tfRegistrySourcePattern.MatchString(source) && !hasVersion) || (unpinnedGit && !strings.Contains(source, "ref=")) {
+				report(block.DefRange(), "tf-unpinned-module", "warning",
+					fmt.Sprintf("Module %s uses %q without a pinned version", tfBlockName(block), source))
+			}
+			
+		case "resource":
+			if len(block.Labels) < 2 {
+				continue
+			}
+			a.checkResource(src, block, module, requiredTags, report)
+		}
+	}
+	
+	return issues
+}
+
+// checkResource runs the resource-specific checks
+func (a *TerraformAnalyzer) checkResource(src []byte, block *hclsyntax.Block, module *tfModule, requiredTags []string, report func(hcl.Range, string, string, string)) {
+	resourceType := block.Labels[0]
+	name := tfBlockName(block)
+	attrs := block.Body.Attributes
+	
+	switch resourceType {
+	case "aws_s3_bucket", "aws_s3_bucket_acl":
+		if acl, ok := tfAttributeString(block.Body, "acl"); ok && tfPublicACLs[acl] {
+			report(attrs["acl"].SrcRange, "tf-public-s3-bucket", "error",
+				fmt.Sprintf("%s grants the %q ACL, which makes the bucket contents public", name, acl))
+		}
+		
+	case "aws_s3_bucket_public_access_block":
+		for _, setting := range []string{"block_public_acls", "block_public_policy", "ignore_public_acls", "restrict_public_buckets"} {
+			attr, ok := attrs[setting]
+			if !ok {
+				report(block.DefRange(), "tf-public-s3-bucket", "warning",
+					fmt.Sprintf("%s does not set %s, which defaults to false", name, setting))
+				continue
+			}
+			if value, ok := tfBool(attr.Expr); ok && !value {
+				report(attr.SrcRange, "tf-public-s3-bucket", "warning",
+					fmt.Sprintf("%s sets %s = false, allowing the bucket to be made public", name, setting))
+			}
+		}
+		
+	case "aws_s3_bucket_policy":
+		if attr, ok := attrs["policy"]; ok {
+			// Policies are usually jsonencode() calls or heredocs, so match the source text
+			policy := string(attr.Expr.Range().SliceBytes(src))
+			if tfPublicPrincipalPattern.MatchString(policy) {
+				report(attr.SrcRange, "tf-public-s3-bucket", "error",
+					fmt.Sprintf("%s grants access to any principal (\"*\")", name))
+			}
+		}
+		
+	case "aws_security_group":
+		for _, rule := range block.Body.Blocks {
+			if rule.Type == "ingress" {
+				a.checkIngress(name, rule.Body, rule.DefRange(), report)
+			}
+		}
+		
+	case "aws_security_group_rule":
+		if ruleType, _ := tfAttributeString(block.Body, "type"); ruleType == "ingress" {
+			a.checkIngress(name, block.Body, block.DefRange(), report)
+		}
+		
+	case "aws_vpc_security_group_ingress_rule":
+		a.checkIngress(name, block.Body, block.DefRange(), report)
+	}
+	
+	// Tags
+	if !tfTaggableResources[resourceType] {
+		return
+	}
+	tags, hasTags := attrs["tags"]
+	if !hasTags {
+		if strings.HasPrefix(resourceType, "aws_") && module.defaultTags {
+			return
+		}
+		report(block.DefRange(), "tf-missing-tags", "warning",
+			fmt.Sprintf("%s has no tags; tag resources for ownership and cost allocation", name))
+		return
+	}
+	
+	keys, ok := tfObjectKeys(tags.Expr)
+	if !ok {
+		// Tags built with merge() or variables cannot be checked statically
+		return
+	}
+	for _, required := range requiredTags {
+		if !keys[required] {
+			report(tags.SrcRange, "tf-missing-tags", "warning",
+				fmt.Sprintf("%s is missing the required tag %q", name, required))
+		}
+	}
+}
+
+// checkIngress reports ingress rules open to the whole internet. SSH, RDP and all-port rules
+// are errors; public HTTP and HTTPS are expected and not reported.
+func (a *TerraformAnalyzer) checkIngress(name string, body *hclsyntax.Body, rng hcl.Range, report func(hcl.Range, string, string, string)) {
+	open := false
+	for _, attrName := range []string{"cidr_blocks", "ipv6_cidr_blocks", "cidr_ipv4", "cidr_ipv6"} {
+		attr, ok := body.Attributes[attrName]
+		if !ok {
+			continue
+		}
+		for _, cidr := range tfStrings(attr.Expr) {
+			if cidr == "0.0.0.0/0" || cidr == "::/0" {
+				open = true
+				rng = attr.SrcRange
+			}
+		}
+	}
+	if !open {
+		return
+	}
+	
+	protocol, _ := tfAttributeString(body, "protocol")
+	if protocol == "" {
+		protocol, _ = tfAttributeString(body, "ip_protocol")
+	}
+	fromPort, hasFrom := tfAttributeNumber(body, "from_port")
+	toPort, hasTo := tfAttributeNumber(body, "to_port")
+	if !hasTo {
+		toPort = fromPort
+	}
+	
+	switch {
+	case protocol == "-1" || protocol == "all" || (hasFrom && fromPort <= 0 && toPort >= 65535):
+		report(rng, "tf-open-security-group", "error",
+			fmt.Sprintf("%s allows traffic on all ports from anywhere", name))
+	case !hasFrom:
+		report(rng, "tf-open-security-group", "warning",
+			fmt.Sprintf("%s allows ingress from anywhere", name))
+	case tfPortInRange(22, fromPort, toPort) || tfPortInRange(3389, fromPort, toPort):
+		report(rng, "tf-open-security-group", "error",
+			fmt.Sprintf("%s exposes remote administration ports (%s) to the internet", name, tfPortLabel(fromPort, toPort)))
+	case (fromPort == 80 || fromPort == 443) && fromPort == toPort:
+		// Public web traffic is intentional
+	default:
+		report(rng, "tf-open-security-group", "warning",
+			fmt.Sprintf("%s allows ingress on port %s from anywhere", name, tfPortLabel(fromPort, toPort)))
+	}
+}
+
+// tfPortInRange reports whether port lies within [from, to]
+func tfPortInRange(port, from, to int64) bool {
+	return port >= from && port <= to
+}
+
+// tfAttributeNames returns the attribute names of a body in source order
+func tfAttributeNames(body *hclsyntax.Body) []string {
+	names := make([]string, 0, len(body.Attributes))
+	for name := range body.Attributes {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		return body.Attributes[names[i]].SrcRange.Start.Byte < body.Attributes[names[j]].SrcRange.Start.Byte
+	})
+	return names
+}
+
+// tfPortLabel formats a port range for messages
+func tfPortLabel(from, to int64) string {
+	if from == to {
+		return fmt.Sprintf("%d", from)
+	}
+	return fmt.Sprintf("%d-%d", from, to)
+}
+
+// tfBlockName returns the address of a block, e.g. aws_s3_bucket.logs or module.vpc
+func tfBlockName(block *hclsyntax.Block) string {
+	if block.Type == "resource" {
+		return strings.Join(block.Labels, ".")
+	}
+	return block.Type + "." + strings.Join(block.Labels, ".")
+}
+
+// tfValue evaluates an expression that does not reference variables or other resources
+func tfValue(expr hclsyntax.Expression) (cty.Value, bool) {
+	value, diags := expr.Value(nil)
+	if diags.HasErrors() || !value.IsWhollyKnown() || value.IsNull() {
+		return cty.NilVal, false
+	}
+	return value, true
+}
+
+// tfString returns the value of a literal string expression
+func tfString(expr hclsyntax.Expression) (string, bool) {
+	value, ok := tfValue(expr)
+	if !ok || value.Type() != cty.String {
+		return "", false
+	}
+	return value.AsString(), true
+}
+
+// tfBool returns the value of a literal boolean expression
+func tfBool(expr hclsyntax.Expression) (bool, bool) {
+	value, ok := tfValue(expr)
+	if !ok || value.Type() != cty.Bool {
+		return false, false
+	}
+	return value.True(), true
+}
+
+// tfStrings returns the string elements of a literal list or tuple expression
+func tfStrings(expr hclsyntax.Expression) []string {
+	value, ok := tfValue(expr)
+	if !ok {
+		return nil
+	}
+	if value.Type() == cty.String {
+		return []string{value.AsString()}
+	}
+	if !value.CanIterateElements() {
+		return nil
+	}
+	
+	values := make([]string, 0)
+	for it := value.ElementIterator(); it.Next(); {
+		_, element := it.Element()
+		if element.IsKnown() && !element.IsNull() && element.Type() == cty.String {
+			values = append(values, element.AsString())
+		}
+	}
+	return values
+}
+
+// tfAttributeString returns the literal string value of an attribute
+func tfAttributeString(body *hclsyntax.Body, name string) (string, bool) {
+	attr, ok := body.Attributes[name]
+	if !ok {
+		return "", false
+	}
+	return tfString(attr.Expr)
+}
+
+// tfAttributeNumber returns the literal integer value of an attribute
+func tfAttributeNumber(body *hclsyntax.Body, name string) (int64, bool) {
+	attr, ok := body.Attributes[name]
+	if !ok {
+		return 0, false
+	}
+	value, ok := tfValue(attr.Expr)
+	if !ok || value.Type() != cty.Number {
+		return 0, false
+	}
+	number, _ := value.AsBigFloat().Int64()
+	return number, true
+}
+
+// tfObjectKeys returns the keys of an object constructor expression
+func tfObjectKeys(expr hclsyntax.Expression) (map[string]bool, bool) {
+	object, ok := expr.(*hclsyntax.ObjectConsExpr)
+	if !ok {
+		return nil, false
+	}
+	
+	keys := make(map[string]bool)
+	for _, item := range object.Items {
+		if keyword := hcl.ExprAsKeyword(item.KeyExpr); keyword != "" {
+			keys[keyword] = true
+			continue
+		}
+		if key, ok := tfString(item.KeyExpr); ok {
+			keys[key] = true
+			continue
+		}
+		// Computed keys could be anything
+		return nil, false
+	}
+	return keys, true
+}
+
+// hclDiagnosticIssues converts HCL parse diagnostics into issues
+func hclDiagnosticIssues(diags hcl.Diagnostics) []Issue {
+	issues := make([]Issue, 0, len(diags))
+	for _, diag := range diags {
+		issue := Issue{
+			Line:     1,
+			Message:  strings.TrimSpace(diag.Summary + ". " + diag.Detail),
+			Severity: "error",
+			RuleID:   "hcl-syntax-error",
+		}
+		if diag.Severity == hcl.DiagWarning {
+			issue.Severity = "warning"
+		}
+		if diag.Subject != nil {
+			column := diag.Subject.Start.Column
+			issue.Line = diag.Subject.Start.Line
+			issue.Column = &column
+		}
+		issues = append(issues, issue)
+	}
+	return issues
+}
+
+// tflintIssue is a single finding in tflint's JSON output
+type tflintIssue struct {
+	Rule *struct {
+		Name     string `json:"name"`
+		Severity string `json:"severity"`
+		Link     string `json:"link"`
+	} `json:"rule"`
+	Message  string `json:"message"`
+	Severity string `json:"severity"`
+	Range    struct {
+		Filename string `json:"filename"`
+		Start    struct {
+			Line   int `json:"line"`
+			Column int `json:"column"`
+		} `json:"start"`
+	} `json:"range"`
+}
+
+// runTflint runs tflint on the configuration and parses its JSON output
+func (a *TerraformAnalyzer) runTflint(ctx context.Context, code string, files map[string]string) ([]Issue, error) {
+	tmpDir, err := ioutil.TempDir("", "codehawk-terraform")
+	if err != nil {
+		return nil, a.WrapError(err, "failed to create temporary directory")
+	}
+	defer os.RemoveAll(tmpDir)
+	
+	moduleFiles := make(map[string]string)
+	for filePath, content := range files {
+		moduleFiles[filePath] = content
+	}
+	moduleFiles["main.tf"] = code
+	
+	if err := a.WriteFiles(tmpDir, moduleFiles); err != nil {
+		return nil, a.WrapError(err, "failed to write Terraform files")
+	}
+	
+	cmd := exec.CommandContext(
+		ctx,
+		a.tflintPath,
+		"--format=json",
+		"--no-color",
+		"--force",
+		"--chdir="+tmpDir,
+	)
+	
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	
+	if err := cmd.Run(); err != nil && stdout.Len() == 0 {
+		return nil, a.WrapError(fmt.Errorf("failed to run tflint: %w, stderr: %s", err, stderr.String()), "tflint execution error")
+	}
+	
+	var result struct {
+		Issues []tflintIssue `json:"issues"`
+		Errors []tflintIssue `json:"errors"`
+	}
+	if err := json.Unmarshal(stdout.Bytes(), &result); err != nil {
+		return nil, a.WrapError(err, "failed to parse tflint output")
+	}
+	
+	issues := make([]Issue, 0)
+	for _, finding := range append(result.Issues, result.Errors...) {
+		// Only report findings in the analyzed file
+		if filepath.Base(finding.Range.Filename) != "main.tf" {
+			continue
+		}
+		
+		ruleID := "tflint"
+		severity := finding.Severity
+		context := ""
+		if finding.Rule != nil {
+			ruleID = finding.Rule.Name
+			severity = finding.Rule.Severity
+			context = finding.Rule.Link
+		}
+		
+		issue := Issue{
+			Line:     finding.Range.Start.Line,
+			Message:  finding.Message,
+			Severity: a.MapSeverity(severity),
+			RuleID:   ruleID,
+			Context:  context,
+		}
+		if issue.Line == 0 {
+			issue.Line = 1
+		}
+		if finding.Range.Start.Column > 0 {
+			column := finding.Range.Start.Column
+			issue.Column = &column
+		}
+		
+		issues = append(issues, issue)
+	}
+	
+	return issues, nil
+}
+
+// SuggestFixes attempts to generate fixes for the identified issues
+func (a *TerraformAnalyzer) SuggestFixes(ctx context.Context, code string, issues []Issue) ([]Issue, error) {
+	// Infrastructure changes need a human decision, so no fixes are generated
+	return []Issue{}, nil
+}
@@ -0,0 +1,203 @@
+package analyzer
+
+import (
+	"context"
+	"testing"
+)
+
+func TestTerraformFindIssues(t *testing.T) {
+	tests := []struct {
+		name    string
+		code    string
+		options map[string]interface{}
+		want    []string
+	}{
+		{
+			name: "pinned providers and modules",
+			code: `terraform {
+  required_providers {
+    aws = { source = "hashicorp/aws", version = "~> 5.0" }
+  }
+}
+provider "aws" {
+  region = "eu-west-1"
+}
+module "vpc" {
+  source  = "terraform-aws-modules/vpc/aws"
+  version = "5.1.0"
+}
+module "git" {
+  source = "git::https://example.com/net.git?ref=v1.2.0"
+}
+`,
+			want: []string{},
+		},
+		{
+			name: "unpinned providers and modules",
+			code: `terraform {
+  required_providers {
+    aws    = { source = "hashicorp/aws" }
+    google = ">= 4.0"
+  }
+}
+provider "azurerm" {
+  features {}
+}
+module "vpc" {
+  source = "terraform-aws-modules/vpc/aws"
+}
+module "git" {
+  source = "github.com/example/net"
+}
+module "local" {
+  source = "./modules/net"
+}
+`,
+			want: []string{"tf-unpinned-provider", "tf-unpinned-provider", "tf-unpinned-provider", "tf-unpinned-module", "tf-unpinned-module"},
+		},
+		{
+			name: "provider required in another file",
+			code: "provider \"aws\" {\n  region = \"eu-west-1\"\n}\n",
+			options: map[string]interface{}{"files": map[string]interface{}{
+				"versions.tf": "terraform {\n  required_providers {\n    aws = { version = \"~> 5.0\" }\n  }\n}\n",
+			}},
+			want: []string{},
+		},
+		{
+			name: "public buckets",
+			code: `resource "aws_s3_bucket" "logs" {
+  acl  = "public-read"
+  tags = { Owner = "ops" }
+}
+resource "aws_s3_bucket_public_access_block" "logs" {
+  bucket              = aws_s3_bucket.logs.id
+  block_public_acls   = false
+  block_public_policy = true
+  ignore_public_acls  = true
+}
+resource "aws_s3_bucket_policy" "logs" {
+  policy = jsonencode({ Statement = [{ Effect = "Allow", Principal = "*" }] })
+}
+`,
+			want: []string{"tf-public-s3-bucket", "tf-public-s3-bucket", "tf-public-s3-bucket", "tf-public-s3-bucket"},
+		},
+		{
+			name: "open security groups",
+			code: `resource "aws_security_group" "web" {
+  tags = { Owner = "web" }
+  ingress {
+    from_port   = 443
+    to_port     = 443
+    protocol    = "tcp"
+    cidr_blocks = ["0.0.0.0/0"]
+  }
+  ingress {
+    from_port   = 22
+    to_port     = 22
+    protocol    = "tcp"
+    cidr_blocks = ["0.0.0.0/0"]
+  }
+  ingress {
+    from_port   = 8080
+    to_port     = 8080
+    protocol    = "tcp"
+    cidr_blocks = ["10.0.0.0/8"]
+  }
+}
+resource "aws_security_group_rule" "all" {
+  type             = "ingress"
+  protocol         = "-1"
+  from_port        = 0
+  to_port          = 0
+  ipv6_cidr_blocks = ["::/0"]
+}
+resource "aws_vpc_security_group_ingress_rule" "app" {
+  cidr_ipv4   = "0.0.0.0/0"
+  ip_protocol = "tcp"
+  from_port   = 8000
+  to_port     = 8100
+}
+`,
+			want: []string{"tf-open-security-group", "tf-open-security-group", "tf-open-security-group"},
+		},
+		{
+			name:    "tags",
+			code:    "resource \"aws_instance\" \"a\" {}\nresource \"aws_instance\" \"b\" {\n  tags = { Name = \"b\" }\n}\nresource \"aws_instance\" \"c\" {\n  tags = merge(var.tags, {})\n}\n",
+			options: map[string]interface{}{"requiredTags": "Name,Owner"},
+			want:    []string{"tf-missing-tags", "tf-missing-tags"},
+		},
+		{
+			name: "default tags cover AWS resources",
+			code: "provider \"aws\" {\n  version = \"~> 5.0\"\n  default_tags {\n    tags = { Owner = \"ops\" }\n  }\n}\nresource \"aws_instance\" \"a\" {}\n",
+			want: []string{},
+		},
+		{
+			name:    "disabled rules",
+			code:    "resource \"aws_instance\" \"a\" {}\n",
+			options: map[string]interface{}{"disabledRules": "tf-missing-tags"},
+			want:    []string{},
+		},
+		{
+			name: "syntax error",
+			code: "resource \"aws_instance\" \"a\" {\n",
+			want: []string{"hcl-syntax-error"},
+		},
+	}
+	
+	a := NewTerraformAnalyzer(map[string]string{})
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			options := map[string]interface{}{"tflint": false}
+			for key, value := range tt.options {
+				options[key] = value
+			}
+			
+			issues, err := a.findIssues(context.Background(), tt.code, options)
+			if err != nil {
+				t.Fatalf("findIssues() error = %v", err)
+			}
+			if len(issues) != len(tt.want) {
+				t.Fatalf("got %d issues, want %v: %+v", len(issues), tt.want, issues)
+			}
+			for i, issue := range issues {
+				if issue.RuleID != tt.want[i] {
+					t.Errorf("issue %d = %s (%s), want %s", i, issue.RuleID, issue.Message, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestTerraformRunTflint(t *testing.T) {
+	output := `{"issues": [
+		{"rule": {"name": "terraform_unused_declarations", "severity": "warning", "link": "https://github.com/terraform-linters/tflint-ruleset-terraform"},
+		 "message": "variable \"x\" is declared but not used", "range": {"filename": "main.tf", "start": {"line": 3, "column": 1}}},
+		{"rule": {"name": "terraform_typed_variables", "severity": "warning", "link": ""},
+		 "message": "other file", "range": {"filename": "variables.tf", "start": {"line": 1, "column": 1}}}
+	], "errors": [
+		{"message": "Failed to load configuration", "severity": "error", "range": {"filename": "main.tf", "start": {"line": 0, "column": 0}}}
+	]}`
+	a := NewTerraformAnalyzer(map[string]string{"tflintPath": fakeTool(t, output)})
+	
+	issues, err := a.runTflint(context.Background(), "variable \"x\" {}\n", map[string]string{"variables.tf": ""})
+	if err != nil {
+		t.Fatalf("runTflint() error = %v", err)
+	}
+	
+	tests := []struct {
+		ruleID   string
+		line     int
+		severity string
+	}{
+		{"terraform_unused_declarations", 3, "warning"},
+		{"tflint", 1, "error"},
+	}
+	if len(issues) != len(tests) {
+		t.Fatalf("got %d issues, want %d: %+v", len(issues), len(tests), issues)
+	}
+	for i, tt := range tests {
+		if issues[i].RuleID != tt.ruleID || issues[i].Line != tt.line || issues[i].Severity != tt.severity {
+			t.Errorf("issue %d = %+v, want %s at line %d (%s)", i, issues[i], tt.ruleID, tt.line, tt.severity)
+		}
+	}
+}
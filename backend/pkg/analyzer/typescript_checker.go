@@ -0,0 +1,302 @@
+package analyzer
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// TypeScript strictness levels accepted by the "typescriptStrictness" option
+const (
+	TypeScriptStrictnessBasic     = "basic"
+	TypeScriptStrictnessStrict    = "strict"
+	TypeScriptStrictnessStrictest = "strictest"
+)
+
+// tscDiagnosticPattern matches diagnostics printed by tsc with --pretty false,
+// e.g. "code.ts(3,7): error TS2322: Type 'string' is not assignable to type 'number'."
+var tscDiagnosticPattern = regexp.MustCompile(`^(.+)\((\d+),(\d+)\): (error|warning|message) (TS\d+): (.*)$`)
+
+// tscGlobalDiagnosticPattern matches diagnostics that are not tied to a file,
+// such as errors in the tsconfig itself
+var tscGlobalDiagnosticPattern = regexp.MustCompile(`^(error|warning|message) (TS\d+): (.*)$`)
+
+// runTypeCheck runs tsc --noEmit against the file in dir and converts its diagnostics to issues
+func (l *JavaScriptLinter) runTypeCheck(ctx context.Context, dir, fileName string, options map[string]interface{}) ([]Issue, error) {
+	// Write the tsconfig used for this run
+	tsconfig, err := l.buildTSConfig(dir, fileName, options)
+	if err != nil {
+		return nil, l.WrapError(err, "failed to build tsconfig")
+	}
+	
+	tsconfigPath := filepath.Join(dir, "tsconfig.json")
+	if err := ioutil.WriteFile(tsconfigPath, tsconfig, 0644); err != nil {
+		return nil, l.WrapError(err, "failed to write tsconfig")
+	}
+	
+	// Prepare tsc command
+	cmd := exec.CommandContext(
+		ctx,
+		l.tscPath,
+		"--noEmit",
+		"--pretty", "false",
+		"--project", tsconfigPath,
+	)
+	
+	cmd.Dir = dir
+	
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	
+	// Execute tsc
+	err = cmd.Run()
+	// tsc exits with status 1 or 2 when it reports diagnostics
+	if err != nil && !strings.Contains(err.Error(), "exit status 1") && !strings.Contains(err.Error(), "exit status 2") {
+		return nil, l.WrapError(fmt.Errorf("failed to run tsc: %w, stderr: %s", err, stderr.String()), "tsc execution error")
+	}
+	
+	return l.parseTSCOutput(stdout.String(), fileName), nil
+}
+
+// tsRequestCompilerOptions are the compiler options a tsconfig supplied with a request may set,
+// besides the strict*, noImplicit* and noUnused* checks. None of them names a file to read or write.
+var tsRequestCompilerOptions = map[string]bool{
+	"target":                             true,
+	"lib":                                true,
+	"jsx":                                true,
+	"module":                             true,
+	"moduleResolution":                   true,
+	"esModuleInterop":                    true,
+	"allowSyntheticDefaultImports":       true,
+	"alwaysStrict":                       true,
+	"exactOptionalPropertyTypes":         true,
+	"noFallthroughCasesInSwitch":         true,
+	"noUncheckedIndexedAccess":           true,
+	"noPropertyAccessFromIndexSignature": true,
+	"useUnknownInCatchVariables":         true,
+	"allowUnreachableCode":               true,
+	"allowUnusedLabels":                  true,
+	"experimentalDecorators":             true,
+	"emitDecoratorMetadata":              true,
+	"useDefineForClassFields":            true,
+}
+
+// buildTSConfig generates the tsconfig for a type-check run. A base tsconfig can only come from
+// the "tsconfig" key of the linter configuration, since compiler options such as extends,
+// typeRoots, paths and tsBuildInfoFile read or write any file they name. It is written next to
+// the code and extended, so that its compiler options apply while the set of checked files stays
+// limited to the submitted code. The "tsconfig" option of a request adds the compiler options
+// tsRequestCompilerOptions allows on top.
+func (l *JavaScriptLinter) buildTSConfig(dir, fileName string, options map[string]interface{}) ([]byte, error) {
+	requestOptions, err := tsRequestOptions(options["tsconfig"])
+	if err != nil {
+		return nil, err
+	}
+	
+	config := map[string]interface{}{
+		"files": []string{fileName},
+	}
+	
+	compilerOptions := map[string]interface{}{
+		"target":           "es2020",
+		"module":           "commonjs",
+		"moduleResolution": "node",
+		"esModuleInterop":  true,
+		"skipLibCheck":     true,
+		"types":            []string{},
+	}
+	
+	base := l.GetStringOption(nil, "tsconfig", "")
+	hasBase := base != ""
+	if hasBase {
+		if err := ioutil.WriteFile(filepath.Join(dir, "tsconfig.base.json"), []byte(base), 0644); err != nil {
+			return nil, fmt.Errorf("failed to write base tsconfig: %w", err)
+		}
+		
+		config["extends"] = "./tsconfig.base.json"
+		compilerOptions = map[string]interface{}{}
+	}
+	
+	for key, value := range requestOptions {
+		compilerOptions[key] = value
+	}
+	
+	// Apply the strictness level; an explicit level also overrides the supplied tsconfigs
+	_, explicitStrictness := options["typescriptStrictness"].(string)
+	if (!hasBase && requestOptions == nil) || explicitStrictness {
+		strictness := l.GetStringOption(options, "typescriptStrictness", TypeScriptStrictnessBasic)
+		for key, value := range tsStrictnessOptions(strictness) {
+			compilerOptions[key] = value
+		}
+	}
+	
+	// TSX only type-checks with JSX enabled; the supplied tsconfigs choose their own JSX mode
+	if _, ok := compilerOptions["jsx"]; !ok && !hasBase && filepath.Ext(fileName) == ".tsx" {
+		compilerOptions["jsx"] = "preserve"
+	}
+	
+	// Nothing is written, not even the build info of an incremental build
+	compilerOptions["noEmit"] = true
+	compilerOptions["incremental"] = false
+	compilerOptions["composite"] = false
+	config["compilerOptions"] = compilerOptions
+	
+	return json.MarshalIndent(config, "", "  ")
+}
+
+// tsRequestOptions returns the compiler options of the tsconfig supplied with a request, given as
+// JSON text or as an object, that tsRequestCompilerOptions allows. Everything else, including
+// extends and the options that name files, is dropped. It returns nil when no tsconfig is supplied.
+func tsRequestOptions(supplied interface{}) (map[string]interface{}, error) {
+	if supplied == nil {
+		return nil, nil
+	}
+	if text, ok := supplied.(string); ok {
+		if err := json.Unmarshal([]byte(text), &supplied); err != nil {
+			return nil, fmt.Errorf("invalid tsconfig: %w", err)
+		}
+	}
+	tsconfig, ok := supplied.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("invalid tsconfig: not an object")
+	}
+	
+	compilerOptions, _ := tsconfig["compilerOptions"].(map[string]interface{})
+	allowed := make(map[string]interface{})
+	for key, value := range compilerOptions {
+		checkOption := strings.HasPrefix(key, "strict") || strings.HasPrefix(key, "noImplicit") || strings.HasPrefix(key, "noUnused")
+		if !checkOption && !tsRequestCompilerOptions[key] {
+			continue
+		}
+		
+		// Only plain values, and lists of library names
+		switch value := value.(type) {
+		case bool, string:
+			allowed[key] = value
+		case []interface{}:
+			if key != "lib" {
+				continue
+			}
+			libs := make([]string, 0, len(value))
+			for _, lib := range value {
+				if name, ok := lib.(string); ok {
+					libs = append(libs, name)
+				}
+			}
+			allowed[key] = libs
+		}
+	}
+	return allowed, nil
+}
+
+// tsStrictnessOptions returns the compiler options for a strictness level
+func tsStrictnessOptions(level string) map[string]interface{} {
+	switch strings.ToLower(level) {
+	case TypeScriptStrictnessStrictest:
+		return map[string]interface{}{
+			"strict":                     true,
+			"noUnusedLocals":             true,
+			"noUnusedParameters":         true,
+			"noImplicitReturns":          true,
+			"noFallthroughCasesInSwitch": true,
+			"noUncheckedIndexedAccess":   true,
+			"noImplicitOverride":         true,
+		}
+	case TypeScriptStrictnessStrict:
+		return map[string]interface{}{
+			"strict": true,
+		}
+	default:
+		return map[string]interface{}{
+			"strict":        false,
+			"noImplicitAny": false,
+		}
+	}
+}
+
+// parseTSCOutput converts tsc diagnostics into CodeHawk issues
+func (l *JavaScriptLinter) parseTSCOutput(output, fileName string) []Issue {
+	issues := make([]Issue, 0)
+	
+	// Whether the diagnostic that indented lines continue was kept
+	kept := false
+	
+	scanner := bufio.NewScanner(strings.NewReader(output))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		
+		// Indented lines continue the message chain of the previous diagnostic
+		if strings.HasPrefix(line, " ") {
+			if !kept {
+				continue
+			}
+			last := &issues[len(issues)-1]
+			last.Message = last.Message + "\n" + strings.TrimSpace(line)
+			continue
+		}
+		
+		kept = false
+		if matches := tscDiagnosticPattern.FindStringSubmatch(line); matches != nil {
+			// Skip diagnostics reported against other files, e.g. the base tsconfig's lib settings
+			if filepath.Base(matches[1]) != fileName {
+				continue
+			}
+			
+			lineNum, _ := strconv.Atoi(matches[2])
+			column, _ := strconv.Atoi(matches[3])
+			
+			issues = append(issues, Issue{
+				Line:     lineNum,
+				Column:   &column,
+				Message:  matches[6],
+				Severity: tscSeverity(matches[4], matches[5]),
+				RuleID:   matches[5],
+				Context:  "tsc",
+			})
+			kept = true
+			continue
+		}
+		
+		if matches := tscGlobalDiagnosticPattern.FindStringSubmatch(line); matches != nil {
+			issues = append(issues, Issue{
+				Line:     1,
+				Message:  fmt.Sprintf("TypeScript configuration: %s", matches[3]),
+				Severity: tscSeverity(matches[1], matches[2]),
+				RuleID:   matches[2],
+				Context:  "tsconfig",
+			})
+			kept = true
+		}
+	}
+	
+	return issues
+}
+
+// tscSeverity maps a tsc diagnostic category to a CodeHawk severity
+func tscSeverity(category, code string) string {
+	// Unresolvable imports are expected for standalone snippets
+	if code == "TS2307" || code == "TS7016" {
+		return "warning"
+	}
+	
+	switch category {
+	case "error":
+		return "error"
+	case "warning":
+		return "warning"
+	default:
+		return "info"
+	}
+}
@@ -0,0 +1,229 @@
+package analyzer
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestParseTSCOutput(t *testing.T) {
+	tests := []struct {
+		name     string
+		output   string
+		want     []Issue
+		wantCols []int
+	}{
+		{
+			name:     "diagnostic in the analyzed file",
+			output:   "code.ts(3,7): error TS2322: Type 'string' is not assignable to type 'number'.\n",
+			want:     []Issue{{Line: 3, Message: "Type 'string' is not assignable to type 'number'.", Severity: "error", RuleID: "TS2322", Context: "tsc"}},
+			wantCols: []int{7},
+		},
+		{
+			name: "message chain continues the diagnostic",
+			output: "code.ts(5,3): error TS2345: Argument of type '{ a: string; }' is not assignable to parameter of type 'Options'.\n" +
+				"  Object literal may only specify known properties.\n",
+			want: []Issue{{
+				Line:     5,
+				Message:  "Argument of type '{ a: string; }' is not assignable to parameter of type 'Options'.\nObject literal may only specify known properties.",
+				Severity: "error",
+				RuleID:   "TS2345",
+				Context:  "tsc",
+			}},
+			wantCols: []int{3},
+		},
+		{
+			name: "continuation of a skipped diagnostic is dropped",
+			output: "code.ts(1,1): error TS2304: Cannot find name 'foo'.\n" +
+				"node_modules/lib/index.d.ts(2,5): error TS2322: Type 'A' is not assignable to type 'B'.\n" +
+				"  Types of property 'x' are incompatible.\n",
+			want:     []Issue{{Line: 1, Message: "Cannot find name 'foo'.", Severity: "error", RuleID: "TS2304", Context: "tsc"}},
+			wantCols: []int{1},
+		},
+		{
+			name:     "unresolvable import is a warning",
+			output:   "code.ts(1,21): error TS2307: Cannot find module 'lodash' or its corresponding type declarations.\n",
+			want:     []Issue{{Line: 1, Message: "Cannot find module 'lodash' or its corresponding type declarations.", Severity: "warning", RuleID: "TS2307", Context: "tsc"}},
+			wantCols: []int{21},
+		},
+		{
+			name:   "global diagnostic",
+			output: "error TS5023: Unknown compiler option 'strictest'.\n",
+			want:   []Issue{{Line: 1, Message: "TypeScript configuration: Unknown compiler option 'strictest'.", Severity: "error", RuleID: "TS5023", Context: "tsconfig"}},
+		},
+		{
+			name:   "no diagnostics",
+			output: "\n",
+			want:   []Issue{},
+		},
+	}
+	
+	l := NewTypeScriptLinter(map[string]string{})
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := l.parseTSCOutput(tt.output, "code.ts")
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %d issues, want %d: %+v", len(got), len(tt.want), got)
+			}
+			for i, issue := range got {
+				want := tt.want[i]
+				if issue.Line != want.Line || issue.Message != want.Message || issue.Severity != want.Severity || issue.RuleID != want.RuleID || issue.Context != want.Context {
+					t.Errorf("issue %d = %+v, want %+v", i, issue, want)
+				}
+				if i < len(tt.wantCols) && (issue.Column == nil || *issue.Column != tt.wantCols[i]) {
+					t.Errorf("issue %d column = %v, want %d", i, issue.Column, tt.wantCols[i])
+				}
+			}
+		})
+	}
+}
+
+func TestBuildTSConfig(t *testing.T) {
+	tests := []struct {
+		name        string
+		config      map[string]string
+		options     map[string]interface{}
+		wantExtends bool
+		wantOptions map[string]interface{}
+	}{
+		{
+			name:        "basic strictness by default",
+			options:     map[string]interface{}{},
+			wantOptions: map[string]interface{}{"strict": false, "noImplicitAny": false, "noEmit": true, "incremental": false},
+		},
+		{
+			name:        "strictest level",
+			options:     map[string]interface{}{"typescriptStrictness": "strictest"},
+			wantOptions: map[string]interface{}{"strict": true, "noUncheckedIndexedAccess": true, "noEmit": true},
+		},
+		{
+			name:        "configured tsconfig is extended",
+			config:      map[string]string{"tsconfig": `{"compilerOptions": {"strict": true, "incremental": true}}`},
+			options:     map[string]interface{}{},
+			wantExtends: true,
+			wantOptions: map[string]interface{}{"noEmit": true, "incremental": false, "composite": false},
+		},
+		{
+			name:        "explicit strictness overrides the configured tsconfig",
+			config:      map[string]string{"tsconfig": `{"compilerOptions": {"strict": false}}`},
+			options:     map[string]interface{}{"typescriptStrictness": "strict"},
+			wantExtends: true,
+			wantOptions: map[string]interface{}{"strict": true, "noEmit": true},
+		},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := NewTypeScriptLinter(tt.config)
+			encoded, err := l.buildTSConfig(t.TempDir(), "code.ts", tt.options)
+			if err != nil {
+				t.Fatalf("buildTSConfig() error = %v", err)
+			}
+			var config struct {
+				Files           []string               `json:"files"`
+				Extends         string                 `json:"extends"`
+				CompilerOptions map[string]interface{} `json:"compilerOptions"`
+			}
+			if err := json.Unmarshal(encoded, &config); err != nil {
+				t.Fatalf("invalid tsconfig: %v", err)
+			}
+			
+			if len(config.Files) != 1 || config.Files[0] != "code.ts" {
+				t.Errorf("files = %v, want [code.ts]", config.Files)
+			}
+			if (config.Extends != "") != tt.wantExtends {
+				t.Errorf("extends = %q, want extends %v", config.Extends, tt.wantExtends)
+			}
+			for key, want := range tt.wantOptions {
+				if got := config.CompilerOptions[key]; got != want {
+					t.Errorf("compilerOptions[%s] = %v, want %v", key, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestBuildTSConfigRequestTSConfig(t *testing.T) {
+	l := NewTypeScriptLinter(map[string]string{})
+	tests := []struct {
+		name        string
+		supplied    interface{}
+		options     map[string]interface{}
+		wantOptions map[string]interface{}
+		wantDropped []string
+		wantErr     bool
+	}{
+		{
+			name:        "checks and language options are kept",
+			supplied:    `{"compilerOptions": {"strict": true, "noImplicitReturns": true, "target": "es2022", "jsx": "react-jsx"}}`,
+			wantOptions: map[string]interface{}{"strict": true, "noImplicitReturns": true, "target": "es2022", "jsx": "react-jsx", "noEmit": true},
+			wantDropped: []string{"noImplicitAny"},
+		},
+		{
+			name: "options naming files are dropped",
+			supplied: map[string]interface{}{
+				"extends": "/etc/tsconfig.json",
+				"compilerOptions": map[string]interface{}{
+					"incremental":     true,
+					"tsBuildInfoFile": "/tmp/out",
+					"typeRoots":       []interface{}{"/etc"},
+					"paths":           map[string]interface{}{"*": []interface{}{"/etc/*"}},
+					"lib":             []interface{}{"es2020", "dom"},
+				},
+			},
+			wantOptions: map[string]interface{}{"incremental": false, "noEmit": true},
+			wantDropped: []string{"tsBuildInfoFile", "typeRoots", "paths"},
+		},
+		{
+			name:        "explicit strictness overrides the request tsconfig",
+			supplied:    `{"compilerOptions": {"strict": false}}`,
+			options:     map[string]interface{}{"typescriptStrictness": "strict"},
+			wantOptions: map[string]interface{}{"strict": true},
+		},
+		{
+			name:     "invalid tsconfig",
+			supplied: `{"compilerOptions": `,
+			wantErr:  true,
+		},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			options := map[string]interface{}{"tsconfig": tt.supplied}
+			for key, value := range tt.options {
+				options[key] = value
+			}
+			dir := t.TempDir()
+			encoded, err := l.buildTSConfig(dir, "code.tsx", options)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("buildTSConfig() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			
+			var config map[string]interface{}
+			if err := json.Unmarshal(encoded, &config); err != nil {
+				t.Fatalf("invalid tsconfig: %v", err)
+			}
+			if _, ok := config["extends"]; ok {
+				t.Errorf("tsconfig extends %v", config["extends"])
+			}
+			if _, err := os.Stat(filepath.Join(dir, "tsconfig.base.json")); err == nil {
+				t.Error("buildTSConfig() wrote the request tsconfig")
+			}
+			compilerOptions := config["compilerOptions"].(map[string]interface{})
+			for key, want := range tt.wantOptions {
+				if got := compilerOptions[key]; got != want {
+					t.Errorf("compilerOptions[%s] = %v, want %v", key, got, want)
+				}
+			}
+			for _, key := range tt.wantDropped {
+				if got, ok := compilerOptions[key]; ok {
+					t.Errorf("compilerOptions[%s] = %v, want it dropped", key, got)
+				}
+			}
+		})
+	}
+}